@@ -0,0 +1,29 @@
+package additional
+
+import (
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+// collectArtifacts returns the copies of the OCI artifacts of the ImageSetConfiguration.
+// Their source and destination are resolved like the ones of the additional images,
+// the artifact type tells the batch to copy them unchanged.
+func (o LocalStorageCollector) collectArtifacts() ([]v2alpha1.CopyImageSchema, []error) {
+	var copies []v2alpha1.CopyImageSchema
+	var errs []error
+	for _, artifact := range o.Config.Mirror.Artifacts {
+		img := v2alpha1.AdditionalImage{Name: artifact.Name, TargetRepo: artifact.TargetRepo, TargetTag: artifact.TargetTag}
+		src, dest, origin, err := o.resolveAdditionalImageSrcDest(img)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		o.Log.Debug(collectorPrefix+"artifact source %s destination %s", src, dest)
+		copies = append(copies, v2alpha1.CopyImageSchema{
+			Source:      src,
+			Destination: dest,
+			Origin:      origin,
+			Type:        v2alpha1.TypeArtifact,
+		})
+	}
+	return copies, errs
+}
@@ -0,0 +1,71 @@
+package additional
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+func TestAdditionalImagesCollectorArtifacts(t *testing.T) {
+	cfg := v2alpha1.ImageSetConfiguration{
+		ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+			Mirror: v2alpha1.Mirror{
+				AdditionalImages: []v2alpha1.AdditionalImage{
+					{Name: "quay.io/ourorg/proxy:1.0", Platforms: []v2alpha1.InstancePlatformFilter{{OS: "linux", Architecture: "amd64"}}},
+				},
+				Artifacts: []v2alpha1.Artifact{
+					{Name: "quay.io/ourorg/policies/opa-bundle:v1.4"},
+					{Name: "quay.io/ourorg/filters/ratelimit@sha256:6f0f4f1b0c9f1b3c8b2e7f6a5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c", TargetRepo: "wasm/ratelimit", TargetTag: "1.0"},
+				},
+			},
+		},
+	}
+	t.Run("mirrorToDisk should copy the artifacts to the cache without platform filters", func(t *testing.T) {
+		res, err := newTestCollector(mirror.MirrorToDisk, consts.FileProtocol+"/tmp/archive", cfg, nil).AdditionalImagesCollector(t.Context())
+		require.NoError(t, err)
+		require.Len(t, res.AllImages, 3)
+		assert.Equal(t, []v2alpha1.CopyImageSchema{
+			{
+				Source:      consts.DockerProtocol + "quay.io/ourorg/policies/opa-bundle:v1.4",
+				Destination: consts.DockerProtocol + "test.registry.com/ourorg/policies/opa-bundle:v1.4",
+				Origin:      "quay.io/ourorg/policies/opa-bundle:v1.4",
+				Type:        v2alpha1.TypeArtifact,
+			},
+			{
+				Source:      consts.DockerProtocol + "quay.io/ourorg/filters/ratelimit@sha256:6f0f4f1b0c9f1b3c8b2e7f6a5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c",
+				Destination: consts.DockerProtocol + "test.registry.com/wasm/ratelimit:1.0",
+				Origin:      "quay.io/ourorg/filters/ratelimit@sha256:6f0f4f1b0c9f1b3c8b2e7f6a5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c",
+				Type:        v2alpha1.TypeArtifact,
+			},
+		}, res.AllImages[1:])
+		assert.Len(t, res.PlatformFilters, 1)
+		assert.Contains(t, res.PlatformFilters, "quay.io/ourorg/proxy:1.0")
+	})
+
+	t.Run("diskToMirror should copy the artifacts from the cache", func(t *testing.T) {
+		res, err := newTestCollector(mirror.DiskToMirror, consts.DockerProtocol+"mirror.acme.com", cfg, nil).AdditionalImagesCollector(t.Context())
+		require.NoError(t, err)
+		require.Len(t, res.AllImages, 3)
+		assert.Equal(t, v2alpha1.CopyImageSchema{
+			Source:      consts.DockerProtocol + "test.registry.com/wasm/ratelimit:1.0",
+			Destination: consts.DockerProtocol + "mirror.acme.com/wasm/ratelimit:1.0",
+			Origin:      "quay.io/ourorg/filters/ratelimit@sha256:6f0f4f1b0c9f1b3c8b2e7f6a5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c",
+			Type:        v2alpha1.TypeArtifact,
+		}, res.AllImages[2])
+	})
+
+	t.Run("should collect the other artifacts when one is invalid", func(t *testing.T) {
+		col := newTestCollector(mirror.MirrorToMirror, consts.DockerProtocol+"mirror.acme.com", cfg, nil)
+		col.Config.Mirror.AdditionalImages = nil
+		col.Config.Mirror.Artifacts = append([]v2alpha1.Artifact{{Name: "quay.io/ourorg/policies/opa-bundle:v1.4", TargetRepo: "Policies//OPA"}}, cfg.Mirror.Artifacts[1:]...)
+		res, err := col.AdditionalImagesCollector(t.Context())
+		assert.ErrorContains(t, err, "invalid targetRepo Policies//OPA for image quay.io/ourorg/policies/opa-bundle:v1.4")
+		require.Len(t, res.AllImages, 1)
+		assert.Equal(t, consts.DockerProtocol+"mirror.acme.com/wasm/ratelimit:1.0", res.AllImages[0].Destination)
+	})
+}
@@ -23,6 +23,8 @@ type LocalStorageCollector struct {
 	LocalStorageFQDN   string
 	destReg            string
 	generateV1DestTags bool
+	// registryLister resolves the tag selectors and namespaces, the registries are queried when nil
+	registryLister registryLister
 }
 
 func WithV1Tags(o CollectorInterface) CollectorInterface {
@@ -44,16 +46,42 @@ func (o LocalStorageCollector) destinationRegistry() string {
 	return o.destReg
 }
 
-// AdditionalImagesCollector - this looks into the additional images field
+// AdditionalImagesCollector - this looks into the additional images and artifacts fields
 // taking into account the mode we are in (mirrorToDisk, diskToMirror)
 // the image is downloaded in oci format
 func (o LocalStorageCollector) AdditionalImagesCollector(ctx context.Context) (v2alpha1.CollectorSchema, error) {
 	var allImages []v2alpha1.CopyImageSchema
 	var allErrs []error
 	platformFilters := make(map[string][]v2alpha1.InstancePlatformFilter)
+	resolved := make(map[string][]v2alpha1.AdditionalImage)
 
 	o.Log.Debug(collectorPrefix+"setting copy option o.Opts.MultiArch=%s when collecting releases image", o.Opts.MultiArch)
+	var images []v2alpha1.AdditionalImage
 	for _, img := range o.Config.ImageSetConfigurationSpec.Mirror.AdditionalImages {
+		var expanded []v2alpha1.AdditionalImage
+		var err error
+		switch {
+		case img.IsNamespace():
+			expanded, err = o.expandNamespace(ctx, img)
+		case img.Tags != nil:
+			expanded, err = o.expandTagSelector(ctx, img)
+		default:
+			expanded = []v2alpha1.AdditionalImage{img}
+		}
+		if err != nil {
+			o.Log.Warn("%v : SKIPPING", err)
+			allErrs = append(allErrs, err)
+		}
+		if len(expanded) == 0 {
+			continue
+		}
+		if img.IsNamespace() || img.Tags != nil {
+			resolved[img.Name] = expanded
+		}
+		images = append(images, expanded...)
+	}
+
+	for _, img := range images {
 		src, dest, origin, err := o.resolveAdditionalImageSrcDest(img)
 		if err != nil {
 			allErrs = append(allErrs, err)
@@ -74,7 +102,12 @@ func (o LocalStorageCollector) AdditionalImagesCollector(ctx context.Context) (v
 			platformFilters[origin] = img.Platforms
 		}
 	}
-	cs := v2alpha1.CollectorSchema{AllImages: allImages, PlatformFilters: platformFilters}
+
+	artifacts, errs := o.collectArtifacts()
+	allImages = append(allImages, artifacts...)
+	allErrs = append(allErrs, errs...)
+
+	cs := v2alpha1.CollectorSchema{AllImages: allImages, PlatformFilters: platformFilters, ResolvedAdditionalImages: resolved}
 	return cs, errors.Join(allErrs...)
 }
 
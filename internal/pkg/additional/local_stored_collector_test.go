@@ -675,3 +675,32 @@ func (o MockManifest) ImageManifest(ctx context.Context, sourceCtx *types.System
 func (o MockManifest) GetManifestListDigests(ctx context.Context, sourceCtx *types.SystemContext, source string) ([]string, error) {
 	return nil, nil
 }
+
+// newTestCollector returns a collector of the additional images of cfg, which lists the tags
+// and the repositories of the registries with lister.
+func newTestCollector(mode, destination string, cfg v2alpha1.ImageSetConfiguration, lister registryLister) LocalStorageCollector {
+	global := &mirror.GlobalOptions{SecurePolicy: false}
+	_, sharedOpts := mirror.SharedImageFlags()
+	_, deprecatedTLSVerifyOpt := mirror.DeprecatedTLSVerifyFlags()
+	_, srcOpts := mirror.ImageSrcFlags(global, sharedOpts, deprecatedTLSVerifyOpt, "src-", "screds")
+
+	return LocalStorageCollector{
+		Log:              clog.New("debug"),
+		Config:           cfg,
+		LocalStorageFQDN: "test.registry.com",
+		registryLister:   lister,
+		Opts: mirror.CopyOptions{
+			Global:           global,
+			SrcImage:         srcOpts,
+			Mode:             mode,
+			Destination:      destination,
+			LocalStorageFQDN: "test.registry.com",
+		},
+	}
+}
+
+func additionalImagesConfig(images ...v2alpha1.AdditionalImage) v2alpha1.ImageSetConfiguration {
+	return v2alpha1.ImageSetConfiguration{ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+		Mirror: v2alpha1.Mirror{AdditionalImages: images},
+	}}
+}
@@ -0,0 +1,141 @@
+package additional
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+)
+
+// expandNamespace returns the images of the repositories of a namespace, with the tags selected
+// by the tag selector of img (all the tags when unset). The repositories are read from
+// img.RepositoriesFile, or listed with the catalog API of the source registry, or of the
+// local cache during diskToMirror. Their layout under the namespace is kept under img.TargetRepo.
+// The images of the other repositories are returned when some repositories fail.
+func (o LocalStorageCollector) expandNamespace(ctx context.Context, img v2alpha1.AdditionalImage) ([]v2alpha1.AdditionalImage, error) {
+	domain, namespace, err := splitNamespace(img.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []string
+	if img.RepositoriesFile != "" {
+		repos, err = readRepositoriesFile(img.RepositoriesFile)
+	} else {
+		repos, err = o.catalogRepositories(ctx, img, domain, namespace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("namespace %q: %w", img.Name, err)
+	}
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("namespace %q: no repository found", img.Name)
+	}
+
+	selector := img.Tags
+	if selector == nil {
+		selector = &v2alpha1.TagSelector{All: true}
+	}
+	var images []v2alpha1.AdditionalImage
+	var errs []error
+	for _, repo := range repos {
+		repoImg := v2alpha1.AdditionalImage{
+			Name:      path.Join(domain, namespace, repo),
+			Tags:      selector,
+			Platforms: img.Platforms,
+		}
+		if img.TargetRepo != "" {
+			repoImg.TargetRepo = path.Join(img.TargetRepo, repo)
+		}
+		repoImages, err := o.expandTagSelector(ctx, repoImg)
+		if errors.Is(err, errNoMatchingTag) {
+			// the selector is shared by all the repositories of the namespace
+			o.Log.Debug(collectorPrefix+"%v : SKIPPING", err)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		images = append(images, repoImages...)
+	}
+	o.Log.Info("%s: %d images selected in %d repositories", img.Name, len(images), len(repos))
+	return images, errors.Join(errs...)
+}
+
+// catalogRepositories lists the repositories of the namespace with the catalog API of the registry,
+// relative to the namespace. During diskToMirror, the repositories are mirrored in the local cache
+// under the target namespace.
+func (o LocalStorageCollector) catalogRepositories(ctx context.Context, img v2alpha1.AdditionalImage, domain, namespace string) ([]string, error) {
+	registry, prefix := domain, namespace
+	if o.Opts.IsDiskToMirror() || o.Opts.IsDelete() {
+		registry = o.LocalStorageFQDN
+		if img.TargetRepo != "" {
+			prefix = img.TargetRepo
+		}
+	}
+	sysCtx, err := o.registrySystemContext(registry)
+	if err != nil {
+		return nil, err
+	}
+	all, err := o.lister().Repositories(ctx, sysCtx, registry)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []string
+	for _, repo := range all {
+		if prefix == "" {
+			repos = append(repos, repo)
+		} else if rel, found := strings.CutPrefix(repo, prefix+"/"); found {
+			repos = append(repos, rel)
+		}
+	}
+	slices.Sort(repos)
+	return slices.Compact(repos), nil
+}
+
+// splitNamespace returns the registry and the namespace of a namespace name (registry/namespace/*).
+// The namespace is empty for all the repositories of the registry.
+func splitNamespace(name string) (string, string, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSuffix(name, v2alpha1.NamespaceWildcard), consts.DockerProtocol)
+	if strings.Contains(trimmed, "://") {
+		return "", "", fmt.Errorf("namespace %q: namespaces can only be mirrored from registries", name)
+	}
+	domain, namespace, _ := strings.Cut(trimmed, "/")
+	if domain == "" {
+		return "", "", fmt.Errorf("namespace %q: registry is empty", name)
+	}
+	return domain, namespace, nil
+}
+
+// readRepositoriesFile returns the repositories listed in a file, one per line.
+// Empty lines and lines starting with # are ignored.
+func readRepositoriesFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var repos []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		repos = append(repos, strings.Trim(line, "/"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", file, err)
+	}
+	slices.Sort(repos)
+	return slices.Compact(repos), nil
+}
@@ -0,0 +1,110 @@
+package additional
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+func TestAdditionalImagesCollectorNamespace(t *testing.T) {
+	registry := mockRegistry{
+		repos: map[string][]string{
+			"quay.io":           {"ourorg/api", "ourorg/team/web", "otherorg/api"},
+			"test.registry.com": {"mirrored/ourorg/api", "mirrored/ourorg/team/web", "ubi9/ubi"},
+		},
+		tags: map[string][]string{
+			"quay.io/ourorg/api":                         {"1.0.0", "1.1.0", "latest"},
+			"quay.io/ourorg/team/web":                    {"2.0.0", "latest"},
+			"quay.io/ourorg/legacy":                      {"latest"},
+			"test.registry.com/mirrored/ourorg/api":      {"1.1.0"},
+			"test.registry.com/mirrored/ourorg/team/web": {"2.0.0"},
+		},
+	}
+	origins := func(images []v2alpha1.CopyImageSchema) []string {
+		var res []string
+		for _, img := range images {
+			res = append(res, img.Origin)
+		}
+		return res
+	}
+
+	t.Run("mirrorToMirror should mirror the repositories of the namespace listed by the catalog API", func(t *testing.T) {
+		ns := v2alpha1.AdditionalImage{Name: "quay.io/ourorg/*", TargetRepo: "mirrored/ourorg", Tags: &v2alpha1.TagSelector{VersionRange: ">=1.1"}}
+		res, err := newTestCollector(mirror.MirrorToMirror, consts.DockerProtocol+"mirror.acme.com", additionalImagesConfig(ns), registry).AdditionalImagesCollector(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []v2alpha1.CopyImageSchema{
+			{
+				Source:      consts.DockerProtocol + "quay.io/ourorg/api:1.1.0",
+				Destination: consts.DockerProtocol + "mirror.acme.com/mirrored/ourorg/api:1.1.0",
+				Origin:      "quay.io/ourorg/api:1.1.0",
+				Type:        v2alpha1.TypeGeneric,
+			},
+			{
+				Source:      consts.DockerProtocol + "quay.io/ourorg/team/web:2.0.0",
+				Destination: consts.DockerProtocol + "mirror.acme.com/mirrored/ourorg/team/web:2.0.0",
+				Origin:      "quay.io/ourorg/team/web:2.0.0",
+				Type:        v2alpha1.TypeGeneric,
+			},
+		}, res.AllImages)
+		assert.Len(t, res.ResolvedAdditionalImages["quay.io/ourorg/*"], 2)
+	})
+
+	t.Run("mirrorToDisk should mirror all the tags of the repositories of the file", func(t *testing.T) {
+		reposFile := filepath.Join(t.TempDir(), "repos.txt")
+		require.NoError(t, os.WriteFile(reposFile, []byte("# our repositories\nteam/web\n\n/legacy/\n"), 0o600))
+		ns := v2alpha1.AdditionalImage{Name: "docker://quay.io/ourorg/*", RepositoriesFile: reposFile}
+		res, err := newTestCollector(mirror.MirrorToDisk, consts.OciProtocol+"test", additionalImagesConfig(ns), registry).AdditionalImagesCollector(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"quay.io/ourorg/legacy:latest", "quay.io/ourorg/team/web:2.0.0", "quay.io/ourorg/team/web:latest"}, origins(res.AllImages))
+		assert.Equal(t, consts.DockerProtocol+"test.registry.com/ourorg/team/web:2.0.0", res.AllImages[1].Destination)
+	})
+
+	t.Run("diskToMirror should list the repositories of the local cache", func(t *testing.T) {
+		ns := v2alpha1.AdditionalImage{Name: "quay.io/ourorg/*", TargetRepo: "mirrored/ourorg"}
+		res, err := newTestCollector(mirror.DiskToMirror, consts.DockerProtocol+"mirror.acme.com", additionalImagesConfig(ns), registry).AdditionalImagesCollector(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []v2alpha1.CopyImageSchema{
+			{
+				Source:      consts.DockerProtocol + "test.registry.com/mirrored/ourorg/api:1.1.0",
+				Destination: consts.DockerProtocol + "mirror.acme.com/mirrored/ourorg/api:1.1.0",
+				Origin:      "quay.io/ourorg/api:1.1.0",
+				Type:        v2alpha1.TypeGeneric,
+			},
+			{
+				Source:      consts.DockerProtocol + "test.registry.com/mirrored/ourorg/team/web:2.0.0",
+				Destination: consts.DockerProtocol + "mirror.acme.com/mirrored/ourorg/team/web:2.0.0",
+				Origin:      "quay.io/ourorg/team/web:2.0.0",
+				Type:        v2alpha1.TypeGeneric,
+			},
+		}, res.AllImages)
+	})
+
+	t.Run("should return the images of the other repositories when a repository fails", func(t *testing.T) {
+		reposFile := filepath.Join(t.TempDir(), "repos.txt")
+		require.NoError(t, os.WriteFile(reposFile, []byte("api\nmissing\n"), 0o600))
+		ns := v2alpha1.AdditionalImage{Name: "quay.io/ourorg/*", RepositoriesFile: reposFile, Tags: &v2alpha1.TagSelector{Include: []string{"latest"}}}
+		res, err := newTestCollector(mirror.MirrorToDisk, consts.OciProtocol+"test", additionalImagesConfig(ns), registry).AdditionalImagesCollector(t.Context())
+		assert.ErrorContains(t, err, `image "quay.io/ourorg/missing": repository not found`)
+		assert.Equal(t, []string{"quay.io/ourorg/api:latest"}, origins(res.AllImages))
+	})
+
+	t.Run("should fail when the registry does not serve the catalog API", func(t *testing.T) {
+		ns := v2alpha1.AdditionalImage{Name: "registry.example.com/ourorg/*"}
+		res, err := newTestCollector(mirror.MirrorToDisk, consts.OciProtocol+"test", additionalImagesConfig(ns), registry).AdditionalImagesCollector(t.Context())
+		assert.ErrorContains(t, err, `namespace "registry.example.com/ourorg/*": catalog API not supported`)
+		assert.Empty(t, res.AllImages)
+	})
+
+	t.Run("should fail when the namespace has no repository", func(t *testing.T) {
+		ns := v2alpha1.AdditionalImage{Name: "quay.io/emptyorg/*"}
+		_, err := newTestCollector(mirror.MirrorToDisk, consts.OciProtocol+"test", additionalImagesConfig(ns), registry).AdditionalImagesCollector(t.Context())
+		assert.ErrorContains(t, err, `namespace "quay.io/emptyorg/*": no repository found`)
+	})
+}
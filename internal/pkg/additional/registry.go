@@ -0,0 +1,115 @@
+package additional
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"go.podman.io/image/v5/docker"
+	dockerconfig "go.podman.io/image/v5/pkg/docker/config"
+	"go.podman.io/image/v5/types"
+)
+
+// registryLister lists the repositories of a registry, the tags of a repository
+// and gives the creation date of their images.
+type registryLister interface {
+	Repositories(ctx context.Context, sysCtx *types.SystemContext, registry string) ([]string, error)
+	RepositoryTags(ctx context.Context, sysCtx *types.SystemContext, repo string) ([]string, error)
+	ImageCreated(ctx context.Context, sysCtx *types.SystemContext, imgRef string) (time.Time, error)
+}
+
+// registryClient queries the registries.
+type registryClient struct{}
+
+// Repositories lists the repositories of a registry with its catalog API.
+// Registries may not serve it, or only list the repositories the credentials can access.
+func (registryClient) Repositories(ctx context.Context, sysCtx *types.SystemContext, registry string) ([]string, error) {
+	var nameOpts []name.Option
+	remoteOpts := []remote.Option{remote.WithContext(ctx)}
+	if sysCtx.DockerInsecureSkipTLSVerify == types.OptionalBoolTrue {
+		nameOpts = append(nameOpts, name.Insecure)
+		transport := remote.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12} //nolint:gosec // G402: registries configured as insecure
+		remoteOpts = append(remoteOpts, remote.WithTransport(transport))
+	}
+	reg, err := name.NewRegistry(registry, nameOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("parse registry %q: %w", registry, err)
+	}
+	creds, err := dockerconfig.GetCredentials(sysCtx, reg.RegistryStr())
+	if err != nil {
+		return nil, fmt.Errorf("get credentials of %s: %w", registry, err)
+	}
+	if creds.Username != "" || creds.IdentityToken != "" {
+		remoteOpts = append(remoteOpts, remote.WithAuth(authn.FromConfig(authn.AuthConfig{
+			Username:      creds.Username,
+			Password:      creds.Password,
+			IdentityToken: creds.IdentityToken,
+		})))
+	}
+	repos, err := remote.Catalog(ctx, reg, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("list repositories of %s: %w", registry, err)
+	}
+	return repos, nil
+}
+
+func (registryClient) RepositoryTags(ctx context.Context, sysCtx *types.SystemContext, repo string) ([]string, error) {
+	ref, err := docker.ParseReference("//" + repo)
+	if err != nil {
+		return nil, fmt.Errorf("parse repository %q: %w", repo, err)
+	}
+	tags, err := docker.GetRepositoryTags(ctx, sysCtx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("list tags of %s: %w", repo, err)
+	}
+	return tags, nil
+}
+
+func (registryClient) ImageCreated(ctx context.Context, sysCtx *types.SystemContext, imgRef string) (time.Time, error) {
+	ref, err := docker.ParseReference("//" + imgRef)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse image %q: %w", imgRef, err)
+	}
+	img, err := ref.NewImage(ctx, sysCtx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("read image %s: %w", imgRef, err)
+	}
+	defer img.Close()
+	info, err := img.Inspect(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("inspect image %s: %w", imgRef, err)
+	}
+	if info.Created == nil {
+		return time.Time{}, nil
+	}
+	return *info.Created, nil
+}
+
+// lister returns the registryLister of the collector, the registries are queried when unset.
+func (o LocalStorageCollector) lister() registryLister { //nolint:ireturn // the lister is replaced in tests
+	if o.registryLister == nil {
+		return registryClient{}
+	}
+	return o.registryLister
+}
+
+// registrySystemContext returns the system context used to query the registry of ref,
+// which is the source registry or the local cache.
+func (o LocalStorageCollector) registrySystemContext(ref string) (*types.SystemContext, error) {
+	sysCtx, err := o.Opts.SrcImage.NewSystemContext()
+	if err != nil {
+		return nil, err
+	}
+	if o.LocalStorageFQDN != "" && strings.HasPrefix(ref, o.LocalStorageFQDN) {
+		// the local cache is served over HTTP
+		sysCtx.DockerInsecureSkipTLSVerify = types.OptionalBoolTrue
+	}
+	return sysCtx, nil
+}
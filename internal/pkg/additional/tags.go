@@ -0,0 +1,184 @@
+package additional
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/image"
+)
+
+// errNoMatchingTag is returned when no tag of a repository matches the tag selector.
+var errNoMatchingTag = errors.New("no tag matches the tag selector")
+
+// signatureTagSuffixes are the suffixes of the tags holding the signatures
+// and attestations of the images, which are never selected.
+var signatureTagSuffixes = []string{".sig", ".att", ".sbom"}
+
+// expandTagSelector returns an additional image per tag selected by the tag selector of img.
+// During mirrorToDisk and mirrorToMirror the tags are listed on the source registry,
+// otherwise they are listed on the local cache, where they were mirrored.
+func (o LocalStorageCollector) expandTagSelector(ctx context.Context, img v2alpha1.AdditionalImage) ([]v2alpha1.AdditionalImage, error) {
+	imgSpec, err := image.ParseRef(img.Name + ":" + latestTag)
+	if err != nil {
+		return nil, fmt.Errorf("parse repository %q: %w", img.Name, err)
+	}
+	if imgSpec.Transport != consts.DockerProtocol {
+		return nil, fmt.Errorf("image %q: tags can only be selected on registries", img.Name)
+	}
+
+	repo := imgSpec.Name
+	if o.Opts.IsDiskToMirror() || o.Opts.IsDelete() {
+		targetRepo, _ := resolveTargetRepoTag(img, imgSpec)
+		repo = o.LocalStorageFQDN + "/" + targetRepo
+	}
+	sysCtx, err := o.registrySystemContext(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	lister := o.lister()
+	allTags, err := lister.RepositoryTags(ctx, sysCtx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("image %q: %w", img.Name, err)
+	}
+	tags, err := selectTags(allTags, *img.Tags, func(tag string) (time.Time, error) {
+		return lister.ImageCreated(ctx, sysCtx, repo+":"+tag)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("image %q: %w", img.Name, err)
+	}
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("image %q: %w of %s", img.Name, errNoMatchingTag, repo)
+	}
+	o.Log.Info("%s: %d tags selected (%s)", img.Name, len(tags), strings.Join(tags, ", "))
+
+	images := make([]v2alpha1.AdditionalImage, 0, len(tags))
+	for _, tag := range tags {
+		images = append(images, v2alpha1.AdditionalImage{
+			Name:       img.Name + ":" + tag,
+			TargetRepo: img.TargetRepo,
+			Platforms:  img.Platforms,
+		})
+	}
+	return images, nil
+}
+
+// selectTags returns the tags matching the tag selector, in lexical order.
+// created gives the creation date of the image of a tag, it is only called
+// for the candidates of Latest when the tags are sorted by creation date.
+func selectTags(tags []string, sel v2alpha1.TagSelector, created func(tag string) (time.Time, error)) ([]string, error) {
+	include, err := compileTagRegexps(sel.Include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := compileTagRegexps(sel.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	var constraint *semver.Constraints
+	if sel.VersionRange != "" {
+		if constraint, err = semver.NewConstraint(sel.VersionRange); err != nil {
+			return nil, fmt.Errorf("versionRange %q: %w", sel.VersionRange, err)
+		}
+	}
+
+	selected := []string{}
+	for _, tag := range tags {
+		if slices.ContainsFunc(signatureTagSuffixes, func(suffix string) bool { return strings.HasSuffix(tag, suffix) }) {
+			continue
+		}
+		if len(include) > 0 && !matchesAny(include, tag) {
+			continue
+		}
+		if matchesAny(exclude, tag) {
+			continue
+		}
+		if constraint != nil {
+			v, err := semver.NewVersion(tag)
+			if err != nil || !constraint.Check(v) {
+				continue
+			}
+		}
+		selected = append(selected, tag)
+	}
+
+	if sel.Latest > 0 {
+		if selected, err = latestTags(selected, sel, created); err != nil {
+			return nil, err
+		}
+	}
+	slices.Sort(selected)
+	return selected, nil
+}
+
+// latestTags returns the sel.Latest latest tags, ordered by sel.SortBy.
+func latestTags(tags []string, sel v2alpha1.TagSelector, created func(tag string) (time.Time, error)) ([]string, error) {
+	type candidate struct {
+		tag     string
+		version *semver.Version
+		created time.Time
+	}
+	candidates := []candidate{}
+	for _, tag := range tags {
+		c := candidate{tag: tag}
+		switch sel.SortBy {
+		case v2alpha1.TagSortingCreated:
+			date, err := created(tag)
+			if err != nil {
+				return nil, err
+			}
+			c.created = date
+		default:
+			v, err := semver.NewVersion(tag)
+			if err != nil {
+				// only versions can be ordered by semver
+				continue
+			}
+			c.version = v
+		}
+		candidates = append(candidates, c)
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		var order int
+		if sel.SortBy == v2alpha1.TagSortingCreated {
+			order = b.created.Compare(a.created)
+		} else {
+			order = b.version.Compare(a.version)
+		}
+		return cmp.Or(order, strings.Compare(a.tag, b.tag))
+	})
+
+	latest := []string{}
+	for _, c := range candidates[:min(sel.Latest, len(candidates))] {
+		latest = append(latest, c.tag)
+	}
+	return latest, nil
+}
+
+// compileTagRegexps compiles regular expressions matching whole tags.
+func compileTagRegexps(exprs []string) ([]*regexp.Regexp, error) {
+	regexps := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag regular expression %q: %w", expr, err)
+		}
+		regexps = append(regexps, re)
+	}
+	return regexps, nil
+}
+
+func matchesAny(regexps []*regexp.Regexp, tag string) bool {
+	return slices.ContainsFunc(regexps, func(re *regexp.Regexp) bool { return re.MatchString(tag) })
+}
@@ -0,0 +1,206 @@
+package additional
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/types"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+// mockRegistry serves the repositories of registries, the tags of repositories
+// and the creation date of their images
+type mockRegistry struct {
+	repos   map[string][]string
+	tags    map[string][]string
+	created map[string]time.Time
+}
+
+func (m mockRegistry) Repositories(_ context.Context, _ *types.SystemContext, registry string) ([]string, error) {
+	repos, ok := m.repos[registry]
+	if !ok {
+		return nil, errors.New("catalog API not supported")
+	}
+	return repos, nil
+}
+
+func (m mockRegistry) RepositoryTags(_ context.Context, _ *types.SystemContext, repo string) ([]string, error) {
+	tags, ok := m.tags[repo]
+	if !ok {
+		return nil, errors.New("repository not found")
+	}
+	return tags, nil
+}
+
+func (m mockRegistry) ImageCreated(_ context.Context, _ *types.SystemContext, imgRef string) (time.Time, error) {
+	created, ok := m.created[imgRef]
+	if !ok {
+		return time.Time{}, errors.New("manifest unknown")
+	}
+	return created, nil
+}
+
+func TestSelectTags(t *testing.T) {
+	tags := []string{"latest", "1.19.3", "1.20.0", "1.20.1-rc.1", "v1.21.2", "1.22", "1.22.1", "1.30.0", "nightly", "sha256-abc.sig", "sha256-abc.att"}
+	day := func(d int) time.Time { return time.Date(2026, time.January, d, 0, 0, 0, 0, time.UTC) }
+	created := map[string]time.Time{"latest": day(10), "1.22.1": day(8), "nightly": day(12), "1.30.0": day(2)}
+
+	cases := []struct {
+		name     string
+		selector v2alpha1.TagSelector
+		expected []string
+		err      string
+	}{
+		{
+			name:     "all tags except signatures",
+			selector: v2alpha1.TagSelector{All: true},
+			expected: []string{"1.19.3", "1.20.0", "1.20.1-rc.1", "1.22", "1.22.1", "1.30.0", "latest", "nightly", "v1.21.2"},
+		},
+		{
+			name:     "include and exclude match whole tags",
+			selector: v2alpha1.TagSelector{Include: []string{`v?1\.2[0-9]\..*`}, Exclude: []string{`.*-rc\..*`, "1.22"}},
+			expected: []string{"1.20.0", "1.22.1", "v1.21.2"},
+		},
+		{
+			name:     "exclude alone keeps the other tags",
+			selector: v2alpha1.TagSelector{Exclude: []string{`1\..*`, "v.*"}},
+			expected: []string{"latest", "nightly"},
+		},
+		{
+			name:     "semver constraint",
+			selector: v2alpha1.TagSelector{VersionRange: ">=1.20 <1.30"},
+			expected: []string{"1.20.0", "1.22", "1.22.1", "v1.21.2"},
+		},
+		{
+			name:     "latest by semver ignores the tags which are not versions",
+			selector: v2alpha1.TagSelector{Latest: 3},
+			expected: []string{"1.22", "1.22.1", "1.30.0"},
+		},
+		{
+			name:     "latest by semver within a range",
+			selector: v2alpha1.TagSelector{VersionRange: "<1.22", Latest: 2},
+			expected: []string{"1.20.0", "v1.21.2"},
+		},
+		{
+			name:     "latest by creation date",
+			selector: v2alpha1.TagSelector{Include: []string{"latest", "nightly", "1.22.1", "1.30.0"}, Latest: 2, SortBy: v2alpha1.TagSortingCreated},
+			expected: []string{"latest", "nightly"},
+		},
+		{
+			name:     "latest greater than the number of tags",
+			selector: v2alpha1.TagSelector{Include: []string{"1.19.3"}, Latest: 5},
+			expected: []string{"1.19.3"},
+		},
+		{
+			name:     "creation date not found",
+			selector: v2alpha1.TagSelector{Include: []string{"1.19.3"}, Latest: 1, SortBy: v2alpha1.TagSortingCreated},
+			err:      "manifest unknown",
+		},
+		{
+			name:     "invalid regular expression",
+			selector: v2alpha1.TagSelector{Include: []string{"1.(2"}},
+			err:      `invalid tag regular expression "1.(2"`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			lister := mockRegistry{created: map[string]time.Time{}}
+			for tag, date := range created {
+				lister.created["quay.io/example/base:"+tag] = date
+			}
+			res, err := selectTags(tags, c.selector, func(tag string) (time.Time, error) {
+				return lister.ImageCreated(t.Context(), nil, "quay.io/example/base:"+tag)
+			})
+			if c.err != "" {
+				assert.ErrorContains(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, res)
+		})
+	}
+}
+
+func TestAdditionalImagesCollectorTagSelector(t *testing.T) {
+	lister := mockRegistry{tags: map[string][]string{
+		"quay.io/example/base":            {"1.20.0", "1.21.0", "1.22.0", "latest"},
+		"test.registry.com/mirrored/base": {"1.21.0", "1.22.0"},
+		"quay.io/example/empty":           {"latest"},
+	}}
+	cfg := v2alpha1.ImageSetConfiguration{
+		ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+			Mirror: v2alpha1.Mirror{
+				AdditionalImages: []v2alpha1.AdditionalImage{
+					{Name: "quay.io/example/base", TargetRepo: "mirrored/base", Tags: &v2alpha1.TagSelector{Latest: 2}},
+				},
+			},
+		},
+	}
+	t.Run("mirrorToDisk should list the tags of the source registry", func(t *testing.T) {
+		res, err := newTestCollector(mirror.MirrorToDisk, consts.OciProtocol+"test", cfg, lister).AdditionalImagesCollector(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []v2alpha1.CopyImageSchema{
+			{
+				Source:      consts.DockerProtocol + "quay.io/example/base:1.21.0",
+				Destination: consts.DockerProtocol + "test.registry.com/mirrored/base:1.21.0",
+				Origin:      "quay.io/example/base:1.21.0",
+				Type:        v2alpha1.TypeGeneric,
+			},
+			{
+				Source:      consts.DockerProtocol + "quay.io/example/base:1.22.0",
+				Destination: consts.DockerProtocol + "test.registry.com/mirrored/base:1.22.0",
+				Origin:      "quay.io/example/base:1.22.0",
+				Type:        v2alpha1.TypeGeneric,
+			},
+		}, res.AllImages)
+		assert.Equal(t, map[string][]v2alpha1.AdditionalImage{"quay.io/example/base": {
+			{Name: "quay.io/example/base:1.21.0", TargetRepo: "mirrored/base"},
+			{Name: "quay.io/example/base:1.22.0", TargetRepo: "mirrored/base"},
+		}}, res.ResolvedAdditionalImages)
+	})
+
+	t.Run("diskToMirror should list the tags of the local cache", func(t *testing.T) {
+		d2mCfg := cfg
+		d2mCfg.Mirror.AdditionalImages = []v2alpha1.AdditionalImage{
+			{Name: "quay.io/example/base", TargetRepo: "mirrored/base", Tags: &v2alpha1.TagSelector{All: true}},
+		}
+		res, err := newTestCollector(mirror.DiskToMirror, consts.DockerProtocol+"mirror.acme.com", d2mCfg, lister).AdditionalImagesCollector(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, []v2alpha1.CopyImageSchema{
+			{
+				Source:      consts.DockerProtocol + "test.registry.com/mirrored/base:1.21.0",
+				Destination: consts.DockerProtocol + "mirror.acme.com/mirrored/base:1.21.0",
+				Origin:      "quay.io/example/base:1.21.0",
+				Type:        v2alpha1.TypeGeneric,
+			},
+			{
+				Source:      consts.DockerProtocol + "test.registry.com/mirrored/base:1.22.0",
+				Destination: consts.DockerProtocol + "mirror.acme.com/mirrored/base:1.22.0",
+				Origin:      "quay.io/example/base:1.22.0",
+				Type:        v2alpha1.TypeGeneric,
+			},
+		}, res.AllImages)
+	})
+
+	t.Run("should collect the other images when no tag matches the selector", func(t *testing.T) {
+		errCfg := cfg
+		errCfg.Mirror.AdditionalImages = []v2alpha1.AdditionalImage{
+			{Name: "quay.io/example/empty", Tags: &v2alpha1.TagSelector{VersionRange: ">=1.0"}},
+			{Name: "quay.io/example/missing", Tags: &v2alpha1.TagSelector{All: true}},
+			{Name: "registry.redhat.io/ubi9/ubi:latest"},
+		}
+		res, err := newTestCollector(mirror.MirrorToDisk, consts.OciProtocol+"test", errCfg, lister).AdditionalImagesCollector(t.Context())
+		assert.ErrorContains(t, err, `image "quay.io/example/empty": no tag matches the tag selector of quay.io/example/empty`)
+		assert.ErrorContains(t, err, `image "quay.io/example/missing": repository not found`)
+		require.Len(t, res.AllImages, 1)
+		assert.Equal(t, "registry.redhat.io/ubi9/ubi:latest", res.AllImages[0].Origin)
+		assert.Empty(t, res.ResolvedAdditionalImages)
+	})
+}
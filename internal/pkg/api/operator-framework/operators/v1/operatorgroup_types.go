@@ -0,0 +1,33 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GroupName is the group name used in this package.
+	GroupName = "operators.coreos.com"
+	// GroupVersion is the group version used in this package.
+	GroupVersion               = "v1"
+	OperatorGroupCRDAPIVersion = GroupName + "/" + GroupVersion
+	OperatorGroupKind          = "OperatorGroup"
+)
+
+// OperatorGroupSpec is the spec for an OperatorGroup resource.
+type OperatorGroupSpec struct {
+	// TargetNamespaces is an explicit set of namespaces to target.
+	// If it is empty, the OperatorGroup targets all namespaces.
+	// +optional
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+}
+
+// OperatorGroup is the unit of multitenancy for OLM managed operators.
+// It constrains the installation of operators in its namespace to a specified set of target namespaces.
+// Only the spec of the operator group is kept, as oc-mirror only generates it.
+type OperatorGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// +optional
+	Spec OperatorGroupSpec `json:"spec"`
+}
@@ -0,0 +1,51 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ClusterExtensionCRDAPIVersion = GroupName + "/" + GroupVersion
+	ClusterExtensionKind          = "ClusterExtension"
+
+	// SourceTypeCatalog is the source type of ClusterExtensions installed from ClusterCatalogs
+	SourceTypeCatalog = "Catalog"
+)
+
+// ClusterExtension is the Schema for the clusterextensions API.
+// Only the spec of the cluster extension is kept, as oc-mirror only generates it.
+type ClusterExtension struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterExtensionSpec `json:"spec,omitempty"`
+}
+
+// ClusterExtensionSpec defines the desired state of ClusterExtension
+type ClusterExtensionSpec struct {
+	// namespace is the namespace the extension is installed in.
+	Namespace string `json:"namespace"`
+	// serviceAccount is the service account used to manage the content of the extension.
+	ServiceAccount ServiceAccountReference `json:"serviceAccount"`
+	// source is where the content of the extension is found.
+	Source SourceConfig `json:"source"`
+}
+
+// ServiceAccountReference identifies the serviceAccount used to install a ClusterExtension.
+type ServiceAccountReference struct {
+	Name string `json:"name"`
+}
+
+// SourceConfig is a discriminated union which selects the installation source.
+type SourceConfig struct {
+	SourceType string         `json:"sourceType"`
+	Catalog    *CatalogFilter `json:"catalog,omitempty"`
+}
+
+// CatalogFilter defines the attributes used to identify and filter content from a catalog.
+type CatalogFilter struct {
+	PackageName string                `json:"packageName"`
+	Version     string                `json:"version,omitempty"`
+	Channels    []string              `json:"channels,omitempty"`
+	Selector    *metav1.LabelSelector `json:"selector,omitempty"`
+}
@@ -0,0 +1,37 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SubscriptionCRDAPIVersion = GroupName + "/" + GroupVersion
+	SubscriptionKind          = "Subscription"
+)
+
+// Approval is the user approval policy for an InstallPlan.
+type Approval string
+
+const (
+	ApprovalAutomatic Approval = "Automatic"
+	ApprovalManual    Approval = "Manual"
+)
+
+// SubscriptionSpec defines an Application that can be installed
+type SubscriptionSpec struct {
+	CatalogSource          string   `json:"source"`
+	CatalogSourceNamespace string   `json:"sourceNamespace"`
+	Package                string   `json:"name"`
+	Channel                string   `json:"channel,omitempty"`
+	StartingCSV            string   `json:"startingCSV,omitempty"`
+	InstallPlanApproval    Approval `json:"installPlanApproval,omitempty"`
+}
+
+// Subscription keeps operators up to date by tracking changes to Catalogs.
+// Only the spec of the subscription is kept, as oc-mirror only generates it.
+type Subscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec *SubscriptionSpec `json:"spec"`
+}
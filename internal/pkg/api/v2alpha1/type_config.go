@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
@@ -27,6 +28,9 @@ type ImageSetConfigurationSpec struct {
 	Mirror Mirror `json:"mirror"`
 	// ArchiveSize is the size of the segmented archive in GB
 	ArchiveSize int64 `json:"archiveSize,omitempty"`
+	// DestinationRewrites are the ordered rules rewriting the repository paths
+	// of the images on the destination registry.
+	DestinationRewrites []DestinationRewrite `json:"destinationRewrites,omitempty"`
 }
 
 // DeleteImageSetConfiguration object kind.
@@ -45,6 +49,9 @@ type DeleteImageSetConfiguration struct {
 type DeleteImageSetConfigurationSpec struct {
 	// Delete defines the configuration for content types within the imageset.
 	Delete Delete `json:"delete"`
+	// DestinationRewrites are the rules the images were mirrored with,
+	// see ImageSetConfigurationSpec.DestinationRewrites.
+	DestinationRewrites []DestinationRewrite `json:"destinationRewrites,omitempty"`
 }
 
 // Mirror defines the configuration for content types within the imageset.
@@ -56,15 +63,64 @@ type Mirror struct {
 	// AdditionalImages defines the configuration for a list
 	// of individual image content types.
 	AdditionalImages []AdditionalImage `json:"additionalImages,omitempty"`
+	// Artifacts defines the configuration for a list of OCI artifacts
+	// which are not container images.
+	Artifacts []Artifact `json:"artifacts,omitempty"`
 	// Helm define the configuration for Helm content types.
 	Helm Helm `json:"helm,omitempty,omitzero"`
 	// BlockedImages define a list of images that will be blocked
 	// from the mirroring process if they exist in other content
 	// types in the configuration.
 	BlockedImages []BlockedImage `json:"blockedImages,omitempty"`
-	// Samples defines the configuration for Sample content types.
-	// This is currently not implemented.
+	// Samples defines the configuration for Sample content types:
+	// the images of the imagestreams shipped by the cluster-samples-operator.
 	Samples []SampleImage `json:"samples,omitempty"`
+	// MergedCatalog combines the filtered content of several operator catalogs
+	// into a single catalog image.
+	MergedCatalog *MergedCatalog `json:"mergedCatalog,omitempty"`
+}
+
+// RewriteContentType is a content type whose destinations can be rewritten.
+type RewriteContentType string
+
+const (
+	RewriteOperator        RewriteContentType = "operator"
+	RewriteAdditionalImage RewriteContentType = "additionalImage"
+	RewriteArtifact        RewriteContentType = "artifact"
+	RewriteHelm            RewriteContentType = "helm"
+)
+
+// DestinationRewrite rewrites the repository path on the destination registry of the images
+// it selects. The first rule selecting an image applies, the images selected by none of them keep
+// the path computed from their source, TargetRepo or TargetCatalog. The release payload and the
+// samples are mirrored to fixed paths and are never rewritten.
+type DestinationRewrite struct {
+	// Registry selects the images of a source registry (i.e registry.redhat.io).
+	Registry string `json:"registry,omitempty"`
+	// Namespace selects the images of a source namespace and of the namespaces nested under it.
+	Namespace string `json:"namespace,omitempty"`
+	// ContentTypes selects the images of content types: operator, additionalImage, artifact or helm.
+	ContentTypes []RewriteContentType `json:"contentTypes,omitempty"`
+	// Match is a regular expression matching the whole source repository (registry/namespace/name).
+	// Its groups can be referenced in Target with $1 or ${name}.
+	Match string `json:"match,omitempty"`
+	// Target is the repository path of the images on the destination registry, relative to the
+	// destination. The placeholders {registry}, {namespace}, {name} and {repository} (namespace/name)
+	// are replaced by the parts of the source repository.
+	Target string `json:"target"`
+}
+
+// MergedCatalog defines a catalog image built from the filtered content
+// of several catalogs of mirror.operators.
+type MergedCatalog struct {
+	// Catalogs lists the catalog references of mirror.operators to merge, in precedence order:
+	// a package present in several of them is taken from the first one.
+	Catalogs []string `json:"catalogs"`
+	// TargetCatalog is the path of the merged catalog on the destination registry,
+	// with the same format as Operator.TargetCatalog.
+	TargetCatalog string `json:"targetCatalog"`
+	// TargetTag is the tag of the merged catalog. Defaults to latest.
+	TargetTag string `json:"targetTag,omitempty"`
 }
 
 // Delete defines the configuration for content types within the imageset.
@@ -76,10 +132,13 @@ type Delete struct {
 	// AdditionalImages defines the configuration for a list
 	// of individual image content types.
 	AdditionalImages []AdditionalImage `json:"additionalImages,omitempty"`
+	// Artifacts defines the configuration for a list of OCI artifacts
+	// which are not container images.
+	Artifacts []Artifact `json:"artifacts,omitempty"`
 	// Helm define the configuration for Helm content types.
 	Helm Helm `json:"helm,omitempty,omitzero"`
-	// Samples defines the configuration for Sample content types.
-	// This is currently not implemented.
+	// Samples defines the configuration for Sample content types:
+	// the images of the imagestreams shipped by the cluster-samples-operator.
 	Samples []SampleImage `json:"samples,omitempty"`
 }
 
@@ -113,6 +172,47 @@ type Platform struct {
 	// will be used to extract the kubeVirtContainer image
 	// from the release payload file 0000_50_installer_coreos-bootimages
 	KubeVirtContainer bool `json:"kubeVirtContainer,omitempty"`
+	// BootArtifacts lists the RHCOS boot artifacts (iso, pxe, qcow2, ova)
+	// to download from the coreos-bootimages stream of each release,
+	// for every requested architecture. Each file is verified against
+	// the sha256 published in the stream. Defaults to none.
+	BootArtifacts []BootArtifactFormat `json:"bootArtifacts,omitempty"`
+	// GraphData customizes the cincinnati graph data used to build
+	// the graph image when Graph is set to true.
+	GraphData GraphData `json:"graphData,omitempty,omitzero"`
+}
+
+// GraphData defines which cincinnati-graph-data revision is used to build
+// the graph image, and the local changes merged into it before the build.
+type GraphData struct {
+	// Revision pins the openshift/cincinnati-graph-data git revision
+	// (commit or tag) used to build the graph image.
+	// Defaults to the latest graph data published by the OpenShift update service.
+	Revision string `json:"revision,omitempty"`
+	// OverlayDir is a local directory merged over the graph data:
+	// its files replace or are added next to the upstream files
+	// with the same relative path (e.g. blocked-edges/4.14.1-custom.yaml).
+	OverlayDir string `json:"overlayDir,omitempty"`
+	// BlockedEdges are added to the upstream blocked edges.
+	BlockedEdges []GraphBlockedEdge `json:"blockedEdges,omitempty"`
+	// Channels restricts upstream channels to the listed versions.
+	Channels []GraphChannel `json:"channels,omitempty"`
+}
+
+// GraphBlockedEdge blocks the updates to a release.
+type GraphBlockedEdge struct {
+	// To is the release version updates are blocked to.
+	To string `json:"to"`
+	// From is a regular expression matching the versions updates are blocked from.
+	From string `json:"from"`
+}
+
+// GraphChannel restricts an upstream channel.
+type GraphChannel struct {
+	// Name of the upstream channel (e.g. stable-4.14).
+	Name string `json:"name"`
+	// Versions are the only upstream versions kept in the channel.
+	Versions []string `json:"versions"`
 }
 
 func (p Platform) DeepCopy() Platform {
@@ -171,6 +271,10 @@ type Operator struct {
 	// This image should be an exact image pin (registry/namespace/name@sha256:<hash>)
 	// but is not required to be.
 	Catalog string `json:"catalog"`
+	// Template is the path of a local FBC template (basic or semver), or of a directory
+	// of templates, the catalog is built from instead of being pulled from Catalog.
+	// TargetCatalog and TargetCatalogBaseImage are required to build the catalog image.
+	Template string `json:"template,omitempty"`
 	// TargetCatalog replaces TargetName and allows for specifying the exact URL of the target
 	// catalog, including any path-components (organization, namespace) of the target catalog's location
 	// on the disconnected registry.
@@ -194,13 +298,89 @@ type Operator struct {
 	// SkipDependencies will not include dependencies
 	// of bundles included in the diff if true.
 	SkipDependencies bool `json:"skipDependencies,omitempty"`
+	// CrossCatalogDependencies resolves the olm.gvk.required and olm.package.required
+	// dependencies of the mirrored bundles against all the catalogs of the configuration,
+	// adding the bundles providing them to the filtered catalog they belong to.
+	CrossCatalogDependencies bool `json:"crossCatalogDependencies,omitempty"`
 	// path on disk for a template to use to complete catalogSource custom resource
 	// generated by oc-mirror
 	TargetCatalogSourceTemplate string `json:"targetCatalogSourceTemplate,omitempty"`
+	// CatalogSource customizes the CatalogSource generated for the catalog.
+	// Its fields override the ones of TargetCatalogSourceTemplate.
+	CatalogSource *CatalogSourceOptions `json:"catalogSource,omitempty"`
+	// ClusterCatalog customizes the ClusterCatalog generated for the catalog.
+	ClusterCatalog *ClusterCatalogOptions `json:"clusterCatalog,omitempty"`
+	// TargetCatalogBaseImage is the opm image the filtered catalog is rebuilt on.
+	// If unset, the filtered catalog is rebuilt on top of the original catalog image.
+	TargetCatalogBaseImage string `json:"targetCatalogBaseImage,omitempty"`
+	// GenerateServeCache pre-generates the opm serve cache (/tmp/cache) in the
+	// rebuilt catalog, so that catalog pods do not build it when starting.
+	GenerateServeCache bool `json:"generateServeCache,omitempty"`
+	// GenerateInstallManifests generates, for each mirrored package, the manifests installing it
+	// from the mirrored catalog: Namespace, OperatorGroup and Subscription for OLM v0,
+	// and ClusterExtension for OLM v1.
+	GenerateInstallManifests bool `json:"generateInstallManifests,omitempty"`
 	// Platforms defines one or more OS/Architecture pairs to mirror
 	// for multi-architecture catalog and operator images. If empty, mirrors all platforms.
 	// Example: [{OS: "linux", Architecture: "amd64"}, {OS: "linux", Architecture: "arm64"}]
 	Platforms []InstancePlatformFilter `json:"platforms,omitempty"`
+	// Deprecations defines how the bundles and channels marked as deprecated
+	// by the olm.deprecations schema of the catalog are handled.
+	// If empty, they are mirrored like any other content.
+	Deprecations DeprecationPolicy `json:"deprecations,omitempty"`
+}
+
+// CatalogSourceOptions are the fields of the generated CatalogSource
+type CatalogSourceOptions struct {
+	// DisplayName is the name of the catalog shown in the console.
+	DisplayName string `json:"displayName,omitempty"`
+	// Publisher is the publisher of the catalog shown in the console.
+	Publisher string `json:"publisher,omitempty"`
+	// Priority weights the catalog during dependency resolution, higher is preferred.
+	Priority int `json:"priority,omitempty"`
+	// RegistryPollInterval is the interval (e.g. 30m) at which OLM checks for a newer catalog image.
+	RegistryPollInterval string `json:"registryPollInterval,omitempty"`
+	// GrpcPodConfig overrides the pod spec of the catalog pod.
+	GrpcPodConfig *CatalogSourceGrpcPodConfig `json:"grpcPodConfig,omitempty"`
+}
+
+// CatalogSourceGrpcPodConfig is the subset of the CatalogSource grpcPodConfig supported by oc-mirror
+type CatalogSourceGrpcPodConfig struct {
+	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
+	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
+	// SecurityContextConfig is either legacy or restricted.
+	SecurityContextConfig string `json:"securityContextConfig,omitempty"`
+	// MemoryTarget is the soft memory limit of the catalog pod, as a quantity (e.g. 100Mi).
+	MemoryTarget string `json:"memoryTarget,omitempty"`
+}
+
+// ClusterCatalogOptions are the fields of the generated ClusterCatalog
+type ClusterCatalogOptions struct {
+	// Priority is used by clients as a tie-breaker between catalogs, higher is preferred.
+	Priority int32 `json:"priority,omitempty"`
+	// Labels are added to the metadata of the ClusterCatalog.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// DeprecationPolicy is the handling of deprecated catalog content
+type DeprecationPolicy string
+
+const (
+	// DeprecationsExclude removes deprecated bundles and channels from the filtered catalog
+	DeprecationsExclude DeprecationPolicy = "exclude"
+	// DeprecationsWarn keeps deprecated content and logs a warning for it
+	DeprecationsWarn DeprecationPolicy = "warn"
+	// DeprecationsFail fails when an explicitly requested package, channel or bundle is deprecated
+	DeprecationsFail DeprecationPolicy = "fail"
+)
+
+// IsValid returns true when the policy is empty or one of the supported policies
+func (p DeprecationPolicy) IsValid() bool {
+	switch p {
+	case "", DeprecationsExclude, DeprecationsWarn, DeprecationsFail:
+		return true
+	}
+	return false
 }
 
 // GetUniqueName determines the catalog name that will
@@ -295,11 +475,23 @@ type Chart struct {
 type AdditionalImage struct {
 	// Name of the image. This should be an exact image pin (registry/namespace/name@sha256:<hash>)
 	// but is not required to be.
+	// When Tags is set, Name is the repository (registry/namespace/name) whose tags are selected.
+	// When Name ends with /* (registry/namespace/*), all the repositories of the namespace
+	// are mirrored, with the tags selected by Tags or all their tags when Tags is unset.
 	Name string `json:"name"`
+	// Tags selects the tags of the repository to mirror. They are discovered by listing
+	// the tags of the source registry, and recorded in the pinned ImageSetConfiguration.
+	Tags *TagSelector `json:"tags,omitempty"`
+	// RepositoriesFile is the path of a file listing the repositories of the namespace
+	// to mirror, relative to the namespace and one per line, when Name is a namespace.
+	// The repositories are listed with the catalog API of the registry when unset.
+	RepositoriesFile string `json:"repositoriesFile,omitempty"`
 	// TargetRepo replaces the repository path and allows for specifying the exact URL of the target
 	// image, including any path-components (organization, namespace) of the target image's location
 	// on the disconnected registry.
 	// This answers some customers requests regarding restrictions on where images can be placed.
+	// When Name is a namespace, TargetRepo replaces the namespace and the layout of the
+	// repositories under it is preserved.
 	// The targetRepo field consists of an optional namespace followed by the target image name,
 	// described in extended Backus–Naur form below:
 	//     target-repo    = [namespace '/'] target-name
@@ -326,6 +518,32 @@ func (i AdditionalImage) GetUniqueName() (string, error) {
 	return getUniqueNameWithTarget(i.Name, i.TargetRepo, i.TargetTag)
 }
 
+// Artifact is an OCI artifact which is not a container image, such as files pushed with ORAS,
+// WASM modules or policy bundles. The manifest of an artifact (artifactType, config media type,
+// subject and annotations) and its blobs are mirrored unchanged: they are never filtered by
+// platform nor converted to another manifest format.
+type Artifact struct {
+	// Name of the artifact (registry/namespace/name:tag or registry/namespace/name@sha256:<hash>),
+	// or the path of an OCI layout prefixed with oci://.
+	Name string `json:"name"`
+	// TargetRepo replaces the repository path of the artifact on the destination,
+	// with the same format as AdditionalImage.TargetRepo.
+	TargetRepo string `json:"targetRepo,omitempty"`
+	// TargetTag is the tag the artifact will be mirrored with. If unset, the artifact is
+	// mirrored with the tag of Name or a tag calculated from the partial digest.
+	TargetTag string `json:"targetTag,omitempty"`
+}
+
+// NamespaceWildcard ends the name of the additional images
+// naming all the repositories of a namespace.
+const NamespaceWildcard = "/*"
+
+// IsNamespace returns true when the additional image names all the
+// repositories of a namespace (registry/namespace/*) instead of an image.
+func (i AdditionalImage) IsNamespace() bool {
+	return strings.HasSuffix(i.Name, NamespaceWildcard)
+}
+
 // BlockedImage contains image information used for excluding images
 // from the mirroring process. The Name field is used as a regex pattern
 // to match against image references.
@@ -333,9 +551,48 @@ type BlockedImage struct {
 	Name string `json:"name"`
 }
 
+// TagSorting is the order of the tags used to keep the latest ones.
+type TagSorting string
+
+const (
+	// TagSortingSemver orders the tags by semantic version. Tags which are not versions are ignored.
+	TagSortingSemver TagSorting = "semver"
+	// TagSortingCreated orders the tags by creation date of their image.
+	TagSortingCreated TagSorting = "created"
+)
+
+// TagSelector selects tags of a repository.
+// Include, Exclude and VersionRange are applied first, then Latest.
+type TagSelector struct {
+	// All selects all the tags of the repository, except the Exclude ones.
+	All bool `json:"all,omitempty"`
+	// Include keeps the tags matching one of these regular expressions.
+	// The expressions match the whole tag.
+	Include []string `json:"include,omitempty"`
+	// Exclude drops the tags matching one of these regular expressions.
+	// The expressions match the whole tag.
+	Exclude []string `json:"exclude,omitempty"`
+	// VersionRange keeps the tags which are versions within this semver
+	// constraint expression (e.g. ">=1.20 <1.30").
+	VersionRange string `json:"versionRange,omitempty"`
+	// Latest keeps only the N latest tags, ordered by SortBy.
+	Latest int `json:"latest,omitempty"`
+	// SortBy is the order used by Latest: semver (default) or created.
+	SortBy TagSorting `json:"sortBy,omitempty"`
+}
+
 // SampleImage defines the configuration
 // for Sample content types.
-// Not implemented.
+// The imagestreams are discovered from the cluster-samples-operator
+// of the release payload, or read from local imagestream files.
 type SampleImage struct {
-	Name string `json:"name"`
+	// Name of the imagestream to mirror (i.e ruby).
+	// When Path is set, Name is optional and filters the imagestreams read from Path.
+	Name string `json:"name,omitempty"`
+	// Path of a local imagestream file, or of a directory containing imagestream files,
+	// used instead of the imagestreams of the release payload.
+	Path string `json:"path,omitempty"`
+	// Tags restricts the imagestream tags to mirror.
+	// All the tags of the imagestream are mirrored when empty.
+	Tags []string `json:"tags,omitempty"`
 }
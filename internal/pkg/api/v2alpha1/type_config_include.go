@@ -25,6 +25,26 @@ type IncludePackage struct {
 
 	// All channels containing these bundles are parsed for an upgrade graph.
 	IncludeBundle `json:",inline"`
+
+	// ExcludeRelatedImages lists related images of the bundles of the package that are not mirrored.
+	// The bundles are still mirrored, and considered complete without these images.
+	ExcludeRelatedImages []ExcludedRelatedImage `json:"excludeRelatedImages,omitempty" yaml:"excludeRelatedImages,omitempty"`
+
+	// InstallPlanApproval is the install plan approval (Manual or Automatic) of the Subscription
+	// generated with generateInstallManifests. Defaults to Manual.
+	InstallPlanApproval string `json:"installPlanApproval,omitempty" yaml:"installPlanApproval,omitempty"`
+}
+
+// ExcludedRelatedImage selects related images of bundles by name and/or by image reference.
+// When both Name and Pattern are set, a related image must match both to be excluded.
+type ExcludedRelatedImage struct {
+	// Name of the related image in the bundle (e.g. must-gather).
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Pattern is a regular expression matched against the related image reference.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Bundles restricts the exclusion to these bundles of the package (e.g. foo.v1.2.0).
+	// If empty, the exclusion applies to all the bundles of the package.
+	Bundles []string `json:"bundles,omitempty" yaml:"bundles,omitempty"`
 }
 
 // IncludeChannel contains a name (required) and versions (optional)
@@ -44,6 +64,13 @@ type IncludeBundle struct {
 	MinVersion string `json:"minVersion,omitempty" yaml:"minVersion,omitempty"`
 	// MaxVersion to include as the channel head version.
 	MaxVersion string `json:"maxVersion,omitempty" yaml:"maxVersion,omitempty"`
+	// VersionRange is a semver constraint expression selecting the versions to include
+	// (e.g. ">=4.14 <4.17, !=4.15.3"). It cannot be combined with MinVersion/MaxVersion.
+	VersionRange string `json:"versionRange,omitempty" yaml:"versionRange,omitempty"`
+	// Latest keeps only the N highest versions of each channel, within VersionRange
+	// or MinVersion/MaxVersion when set. It is resolved against the catalog and
+	// recorded as a VersionRange in the pinned ImageSetConfiguration.
+	Latest int `json:"latest,omitempty" yaml:"latest,omitempty"`
 	// MinBundle to include, plus all bundles in the upgrade graph to the channel head.
 	// Set this field only if the named bundle has no semantic version metadata.
 	// MinBundle string `json:"minBundle,omitempty" yaml:"minBundle,omitempty"`
@@ -20,6 +20,8 @@ const (
 	TypeGeneric
 	TypeKubeVirtContainer
 	TypeHelmImage
+	TypeSampleImage
+	TypeArtifact
 )
 
 // ImageTypeString defines the string
@@ -33,6 +35,8 @@ var imageTypeStrings = map[ImageType]string{
 	TypeOperatorRelatedImage: "operatorRelatedImage",
 	TypeGeneric:              "generic",
 	TypeHelmImage:            "helmImage",
+	TypeSampleImage:          "sampleImage",
+	TypeArtifact:             "artifact",
 }
 
 var imageStringsType = map[string]ImageType{
@@ -44,6 +48,8 @@ var imageStringsType = map[string]ImageType{
 	"operatorRelatedImage": TypeOperatorRelatedImage,
 	"generic":              TypeGeneric,
 	"helmImage":            TypeHelmImage,
+	"sampleImage":          TypeSampleImage,
+	"artifact":             TypeArtifact,
 }
 
 func (it ImageType) IsRelease() bool {
@@ -58,14 +64,26 @@ func (it ImageType) IsOperatorCatalog() bool {
 	return it == TypeOperatorCatalog
 }
 
+// IsAdditionalImage returns true for the content collected by the additional images
+// collector: the additional images and the OCI artifacts.
 func (it ImageType) IsAdditionalImage() bool {
-	return it == TypeGeneric
+	return it == TypeGeneric || it == TypeArtifact
+}
+
+// IsArtifact returns true for the OCI artifacts which are not container images.
+// They are copied as is, without platform filtering nor manifest conversion.
+func (it ImageType) IsArtifact() bool {
+	return it == TypeArtifact
 }
 
 func (it ImageType) IsHelmImage() bool {
 	return it == TypeHelmImage
 }
 
+func (it ImageType) IsSampleImage() bool {
+	return it == TypeSampleImage
+}
+
 // String returns the string representation
 // of an Image Type
 func (it ImageType) String() string {
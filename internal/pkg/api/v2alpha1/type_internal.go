@@ -195,11 +195,15 @@ type CollectorSchema struct {
 	// PlatformFilters maps image origin to its platform filter list.
 	// Populated by collectors; consumed by the batch worker to set InstancePlatforms per image.
 	PlatformFilters map[string][]InstancePlatformFilter
+	// ResolvedAdditionalImages maps the name of the additional images with a tag selector,
+	// or naming a namespace, to the images resolved for them.
+	ResolvedAdditionalImages map[string][]AdditionalImage
 }
 
 type CopyImageSchemaMap struct {
 	OperatorsByImage map[string]map[string]struct{} // key is the origin image name and value is an array of operators' name
 	BundlesByImage   map[string]map[string]string   // key is the image name and value is the bundle name
+	ChartsByImage    map[string]map[string]struct{} // key is the origin image name and value is an array of helm charts' name
 }
 
 // CopyImageSchema
@@ -240,8 +244,11 @@ type DeleteImageList struct {
 }
 
 type DeleteItem struct {
-	ImageName      string    `json:"imageName"`
-	ImageReference string    `json:"imageReference"`
+	ImageName      string `json:"imageName"`
+	ImageReference string `json:"imageReference"`
+	// CacheReference is the reference of the image in the local cache, when its repository
+	// differs from the one of ImageReference because the destination was rewritten.
+	CacheReference string    `json:"cacheReference,omitempty"`
 	Type           ImageType `json:"type"`
 }
 
@@ -20,7 +20,50 @@ type Metadata struct {
 }
 
 type Artifacts struct {
-	Kubevirt Kubevirt `json:"kubevirt"`
+	Kubevirt Kubevirt          `json:"kubevirt"`
+	Metal    PlatformArtifacts `json:"metal"`
+	Qemu     PlatformArtifacts `json:"qemu"`
+	Vmware   PlatformArtifacts `json:"vmware"`
+}
+
+// PlatformArtifacts lists the downloadable files published in the
+// coreos-bootimages stream for a single platform (metal, qemu, vmware...).
+// Formats is keyed by format name (iso, pxe, qcow2.gz, ova) and then
+// by file role (disk, kernel, initramfs, rootfs).
+type PlatformArtifacts struct {
+	Release string                             `json:"release"`
+	Formats map[string]map[string]ArtifactFile `json:"formats"`
+}
+
+// ArtifactFile is a single downloadable boot artifact and its checksum.
+type ArtifactFile struct {
+	Location           string `json:"location"`
+	Sha256             string `json:"sha256"`
+	UncompressedSha256 string `json:"uncompressed-sha256,omitempty"`
+}
+
+// BootArtifactFormat identifies a kind of RHCOS boot artifact that can be
+// downloaded from the coreos-bootimages stream of a release.
+type BootArtifactFormat string
+
+const (
+	// BootArtifactISO is the metal live ISO
+	BootArtifactISO BootArtifactFormat = "iso"
+	// BootArtifactPXE is the metal PXE kernel, initramfs and rootfs
+	BootArtifactPXE BootArtifactFormat = "pxe"
+	// BootArtifactQCOW2 is the qemu qcow2 disk image
+	BootArtifactQCOW2 BootArtifactFormat = "qcow2"
+	// BootArtifactOVA is the vmware OVA
+	BootArtifactOVA BootArtifactFormat = "ova"
+)
+
+// IsValid returns true when the format is one of the supported boot artifact formats
+func (f BootArtifactFormat) IsValid() bool {
+	switch f {
+	case BootArtifactISO, BootArtifactPXE, BootArtifactQCOW2, BootArtifactOVA:
+		return true
+	}
+	return false
 }
 
 type Kubevirt struct {
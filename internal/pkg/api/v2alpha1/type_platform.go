@@ -56,6 +56,16 @@ func (pt *PlatformType) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// ParsePlatformType returns the PlatformType matching its
+// string representation (ocp or okd)
+func ParsePlatformType(s string) (PlatformType, error) {
+	pt, ok := platformStringsType[s]
+	if !ok {
+		return TypeOCP, fmt.Errorf("unknown platform type %q: must be one of (ocp, okd)", s)
+	}
+	return pt, nil
+}
+
 func (pt PlatformType) validate() error {
 	if _, ok := platformTypeStrings[pt]; !ok {
 		return errors.New("unknown platform type")
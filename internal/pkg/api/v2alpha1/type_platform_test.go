@@ -69,3 +69,16 @@ func TestParseInstancePlatformFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePlatformType(t *testing.T) {
+	pt, err := ParsePlatformType("okd")
+	require.NoError(t, err)
+	assert.Equal(t, TypeOKD, pt)
+
+	pt, err = ParsePlatformType("ocp")
+	require.NoError(t, err)
+	assert.Equal(t, TypeOCP, pt)
+
+	_, err = ParsePlatformType("rhel")
+	assert.ErrorContains(t, err, `unknown platform type "rhel"`)
+}
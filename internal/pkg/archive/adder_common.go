@@ -5,6 +5,8 @@ import (
 	"io"
 	"io/fs"
 	"os"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/metrics"
 )
 
 func addFileToWriter(fi fs.FileInfo, pathToFile, pathInTar string, tarWriter *tar.Writer) error {
@@ -30,3 +32,10 @@ func addFileToWriter(fi fs.FileInfo, pathToFile, pathInTar string, tarWriter *ta
 	}
 	return nil
 }
+
+// observeChunk records the size of a chunk archive in the metrics, once it is closed.
+func observeChunk(recorder *metrics.Recorder, archivePath string) {
+	if fi, err := os.Stat(archivePath); err == nil {
+		recorder.ObserveArchiveChunk(fi.Size())
+	}
+}
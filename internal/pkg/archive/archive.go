@@ -15,6 +15,7 @@ import (
 	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/history"
 	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/metrics"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
 )
 
@@ -30,6 +31,7 @@ type MirrorArchive struct {
 	maxSize         int64
 	strictArchiving bool
 	logger          clog.PluggableLoggerInterface
+	metrics         *metrics.Recorder
 }
 
 // NewMirrorArchive creates a new MirrorArchive instance
@@ -57,6 +59,7 @@ func NewMirrorArchive(opts *mirror.CopyOptions, destination, iscPath, workingDir
 		maxSize:         maxSize,
 		strictArchiving: opts.Global.StrictArchiving,
 		logger:          logg,
+		metrics:         opts.Metrics,
 	}
 	return &ma, nil
 }
@@ -133,9 +136,9 @@ func (o *MirrorArchive) createTarball() error {
 	var adder archiveAdder
 
 	if o.strictArchiving {
-		adder, err = newStrictAdder(o.maxSize, o.destination, o.logger)
+		adder, err = newStrictAdder(o.maxSize, o.destination, o.logger, o.metrics)
 	} else {
-		adder, err = newPermissiveAdder(o.maxSize, o.destination, o.logger)
+		adder, err = newPermissiveAdder(o.maxSize, o.destination, o.logger, o.metrics)
 	}
 
 	if err != nil {
@@ -150,7 +153,11 @@ func (o *MirrorArchive) createTarball() error {
 func (o *MirrorArchive) addImagesDiff(ctx context.Context, schema v2alpha1.CollectorSchema, historyBlobs sets.Set[string]) (sets.Set[string], error) {
 	allAddedBlobs := sets.New[string]()
 	for _, img := range schema.AllImages {
-		platformFilters := schema.PlatformFilters[img.Origin]
+		var platformFilters []v2alpha1.InstancePlatformFilter
+		if !img.Type.IsArtifact() {
+			// artifacts are mirrored with all the manifests of their index
+			platformFilters = schema.PlatformFilters[img.Origin]
+		}
 		allowedPlatforms := make([]string, len(platformFilters))
 		for i, p := range platformFilters {
 			allowedPlatforms[i] = p.String()
@@ -232,7 +239,7 @@ func handleSignatureErrors(img v2alpha1.CopyImageSchema, err error) error {
 		return &ArchiveError{OperatorErr: sigErr.SigError}
 	case img.Type.IsRelease() && img.Type != v2alpha1.TypeCincinnatiGraph:
 		return &ArchiveError{ReleaseErr: sigErr.SigError}
-	case img.Type.IsAdditionalImage():
+	case img.Type.IsAdditionalImage() || img.Type.IsSampleImage():
 		return &ArchiveError{AdditionalImgErr: sigErr.SigError}
 	case img.Type.IsHelmImage():
 		return &ArchiveError{HelmErr: sigErr.SigError}
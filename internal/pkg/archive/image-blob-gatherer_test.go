@@ -405,3 +405,26 @@ func TestPlatformForDigest(t *testing.T) {
 	assert.Equal(t, "", platformForDigest(ml, digest.Digest(noPlat)))
 	assert.Equal(t, "", platformForDigest(ml, digest.Digest("sha256:dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd")))
 }
+
+func TestImageBlobsArtifact(t *testing.T) {
+	emptyConfig := "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"
+	wasmLayer := "sha256:eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+	subject := "sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+
+	artifactJSON := `{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.oci.image.manifest.v1+json",
+		"artifactType": "application/vnd.module.wasm.content.layer.v1+wasm",
+		"config": {"mediaType":"application/vnd.oci.empty.v1+json","digest":"` + emptyConfig + `","size":2},
+		"layers": [
+			{"mediaType":"application/vnd.module.wasm.content.layer.v1+wasm","digest":"` + wasmLayer + `","size":1024,"annotations":{"org.opencontainers.image.title":"ratelimit.wasm"}}
+		],
+		"subject": {"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"` + subject + `","size":1},
+		"annotations": {"org.opencontainers.image.created":"2026-01-01T00:00:00Z"}
+	}`
+
+	blobs, err := imageBlobs([]byte(artifactJSON), "application/vnd.oci.image.manifest.v1+json")
+	assert.NoError(t, err)
+	// the subject is the manifest the artifact refers to, which is mirrored on its own
+	assert.ElementsMatch(t, []string{wasmLayer, emptyConfig}, blobs)
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 
 	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/metrics"
 )
 
 type permissiveAdder struct {
@@ -20,13 +21,14 @@ type permissiveAdder struct {
 	sizeOfCurrentChunk int64
 	oversizedFiles     map[string]int64
 	logger             clog.PluggableLoggerInterface
+	metrics            *metrics.Recorder
 }
 
 // `newPermissiveAdder` initializes the permissiveAdder implementation for the `archiveAdder` interface.
 // This implementation allows  files to exceed the maxArchiveSize specified in the
 // imageSetConfig. It places them in special archive chunks, on their own, and keeps track of the list
 // of oversized files.
-func newPermissiveAdder(maxSize int64, destination string, logger clog.PluggableLoggerInterface) (*permissiveAdder, error) {
+func newPermissiveAdder(maxSize int64, destination string, logger clog.PluggableLoggerInterface, recorder *metrics.Recorder) (*permissiveAdder, error) {
 	chunk := 1
 	archiveFileName := fmt.Sprintf(archiveFileNameFormat, archiveFilePrefix, chunk)
 	err := os.MkdirAll(destination, 0755)
@@ -54,6 +56,7 @@ func newPermissiveAdder(maxSize int64, destination string, logger clog.Pluggable
 		archiveFile:        archiveFile,
 		tarWriter:          tarWriter,
 		logger:             logger,
+		metrics:            recorder,
 		oversizedFiles:     map[string]int64{},
 	}
 	return &p, nil
@@ -82,7 +85,11 @@ func (o *permissiveAdder) close() error {
 	if err != nil {
 		o.logger.Warn("error closing archive writer : %v", err)
 	}
-	return o.archiveFile.Close()
+	if err := o.archiveFile.Close(); err != nil {
+		return err
+	}
+	observeChunk(o.metrics, o.archiveFile.Name())
+	return nil
 
 }
 
@@ -189,6 +196,7 @@ func (o *permissiveAdder) nextChunk() error {
 	if err != nil {
 		return err
 	}
+	observeChunk(o.metrics, o.archiveFile.Name())
 
 	// next chunk init
 	o.currentChunkId += 1
@@ -228,6 +236,7 @@ func (o *permissiveAdder) exceptionChunk(oversizedFileInfo fs.FileInfo, oversize
 		exceptionTarWriter.Flush()
 		exceptionTarWriter.Close()
 		exceptionArchiveFile.Close()
+		observeChunk(o.metrics, exceptionArchivePath)
 	}()
 
 	// create the header for the file
@@ -15,7 +15,7 @@ import (
 func TestPermissiveAdder_NextChunk(t *testing.T) {
 	// Create a temporary test folder
 	testFolder := t.TempDir()
-	ma, err := newPermissiveAdder(defaultSegSize*segMultiplier, testFolder, clog.New("trace"))
+	ma, err := newPermissiveAdder(defaultSegSize*segMultiplier, testFolder, clog.New("trace"), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -33,7 +33,7 @@ func TestPermissiveAdder_NextChunk(t *testing.T) {
 func TestPermissiveAdder_ExceptionChunk(t *testing.T) {
 	// Create a temporary test folder
 	testFolder := t.TempDir()
-	ma, err := newPermissiveAdder(int64(10*1024), testFolder, clog.New("trace"))
+	ma, err := newPermissiveAdder(int64(10*1024), testFolder, clog.New("trace"), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -58,7 +58,7 @@ func TestPermissiveAdder_AddFile_BiggerThanMax(t *testing.T) {
 	t.Run("adding file exceeding maxSize: should fail", func(t *testing.T) {
 		testFolder := t.TempDir()
 		// use a maxArchiveSize of 10K
-		ma, err := newPermissiveAdder(int64(10*1024), testFolder, clog.New("trace"))
+		ma, err := newPermissiveAdder(int64(10*1024), testFolder, clog.New("trace"), nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -76,7 +76,7 @@ func TestPermissiveAdder_AddFile_BiggerThanMax(t *testing.T) {
 	t.Run("adding files: should pass", func(t *testing.T) {
 		testFolder := t.TempDir()
 		// use a maxArchiveSize of 10K
-		ma, err := newPermissiveAdder(int64(10*1024), testFolder, clog.New("trace"))
+		ma, err := newPermissiveAdder(int64(10*1024), testFolder, clog.New("trace"), nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -147,7 +147,7 @@ func TestPermissiveAdder_AddFolder_BiggerThanMax(t *testing.T) {
 		t.Run(aTestCase.caseName, func(t *testing.T) {
 			testFolder := t.TempDir()
 			// use a maxArchiveSize of 10K
-			ma, err := newPermissiveAdder(aTestCase.archiveSizeBytes, testFolder, clog.New("trace"))
+			ma, err := newPermissiveAdder(aTestCase.archiveSizeBytes, testFolder, clog.New("trace"), nil)
 			if err != nil {
 				t.Fatal(err)
 			}
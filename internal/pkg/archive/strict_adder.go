@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/metrics"
 )
 
 type strictAdder struct {
@@ -17,13 +18,14 @@ type strictAdder struct {
 	currentChunkId     int
 	sizeOfCurrentChunk int64
 	logger             clog.PluggableLoggerInterface
+	metrics            *metrics.Recorder
 }
 
 // `newStrictAdder` initializes the strictAdder implementation for the `archiveAdder` interface.
 // This implementation doesn't allow for any files to exceed the maxArchiveSize specified in the
 // imageSetConfig. It stops adding to the archive chunks if a file exceeds maxArchiveSize
 // and returns in error.
-func newStrictAdder(maxSize int64, destination string, logger clog.PluggableLoggerInterface) (*strictAdder, error) {
+func newStrictAdder(maxSize int64, destination string, logger clog.PluggableLoggerInterface, recorder *metrics.Recorder) (*strictAdder, error) {
 	chunk := 1
 	archiveFileName := fmt.Sprintf(archiveFileNameFormat, archiveFilePrefix, chunk)
 	err := os.MkdirAll(destination, 0755)
@@ -51,6 +53,7 @@ func newStrictAdder(maxSize int64, destination string, logger clog.PluggableLogg
 		archiveFile:        archiveFile,
 		tarWriter:          tarWriter,
 		logger:             logger,
+		metrics:            recorder,
 	}
 	return &p, nil
 }
@@ -64,7 +67,11 @@ func (o *strictAdder) close() error {
 	if err != nil {
 		o.logger.Warn("error closing archive writer : %v", err)
 	}
-	return o.archiveFile.Close()
+	if err := o.archiveFile.Close(); err != nil {
+		return err
+	}
+	observeChunk(o.metrics, o.archiveFile.Name())
+	return nil
 }
 
 // addFile copies the contents of the `pathToFile` file from the disk into
@@ -159,6 +166,7 @@ func (o *strictAdder) nextChunk() error {
 	if err != nil {
 		return err
 	}
+	observeChunk(o.metrics, o.archiveFile.Name())
 
 	// next chunk init
 	o.currentChunkId += 1
@@ -2,19 +2,22 @@ package archive
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
 	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/metrics"
 )
 
 func TestStrictAdder_NextChunk(t *testing.T) {
 	// Create a temporary test folder
 	testFolder := t.TempDir()
-	ma, err := newStrictAdder(defaultSegSize*segMultiplier, testFolder, clog.New("trace"))
+	ma, err := newStrictAdder(defaultSegSize*segMultiplier, testFolder, clog.New("trace"), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -33,7 +36,7 @@ func TestStrictAdder_AddFile_BiggerThanMax(t *testing.T) {
 	t.Run("adding file exceeding maxSize: should fail", func(t *testing.T) {
 		testFolder := t.TempDir()
 		// use a maxArchiveSize of 10K
-		ma, err := newStrictAdder(int64(10*1024), testFolder, clog.New("trace"))
+		ma, err := newStrictAdder(int64(10*1024), testFolder, clog.New("trace"), nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -48,7 +51,7 @@ func TestStrictAdder_AddFile_BiggerThanMax(t *testing.T) {
 	t.Run("adding files: should pass", func(t *testing.T) {
 		testFolder := t.TempDir()
 		// use a maxArchiveSize of 10K
-		ma, err := newStrictAdder(int64(10*1024), testFolder, clog.New("trace"))
+		ma, err := newStrictAdder(int64(10*1024), testFolder, clog.New("trace"), nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -115,7 +118,7 @@ func TestStrictAdder_AddFolder_BiggerThanMax(t *testing.T) {
 		t.Run(aTestCase.caseName, func(t *testing.T) {
 			testFolder := t.TempDir()
 			// use a maxArchiveSize of 10K
-			ma, err := newStrictAdder(aTestCase.archiveSizeBytes, testFolder, clog.New("trace"))
+			ma, err := newStrictAdder(aTestCase.archiveSizeBytes, testFolder, clog.New("trace"), nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -145,3 +148,21 @@ func TestStrictAdder_AddFolder_BiggerThanMax(t *testing.T) {
 		})
 	}
 }
+
+func TestStrictAdder_Metrics(t *testing.T) {
+	testFolder := t.TempDir()
+	textfile := filepath.Join(t.TempDir(), "oc-mirror.prom")
+	recorder := metrics.New(metrics.Options{Textfile: textfile}, "localhost:55000")
+	ma, err := newStrictAdder(int64(10*1024), testFolder, clog.New("trace"), recorder)
+	require.NoError(t, err)
+
+	require.NoError(t, ma.addFile(consts.TestFolder+"archive-test-data/0000_03_config-operator_01_proxy.crd.yaml", "file1"))
+	require.NoError(t, ma.nextChunk())
+	require.NoError(t, ma.close())
+
+	require.NoError(t, recorder.Stop(t.Context()))
+	data, err := os.ReadFile(textfile)
+	require.NoError(t, err)
+	// every chunk is observed once it is closed
+	assert.Contains(t, string(data), "oc_mirror_archive_chunk_size_bytes_count 2")
+}
@@ -0,0 +1,194 @@
+package batch
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/emoji"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+)
+
+const (
+	// minWindowImages is the minimum number of copies the parallelism is evaluated on
+	minWindowImages = 4
+	// maxErrorRate is the rate of failed copies above which the parallelism is decreased
+	maxErrorRate = 0.1
+	// minThroughputGain is the gain of throughput an increase of the parallelism must bring to be kept
+	minThroughputGain = 0.05
+	// holdWindows is the number of windows the parallelism is kept after a decrease, before probing again
+	holdWindows = 4
+)
+
+// imageSemaphore bounds the number of images copied at the same time. Its limit can be changed
+// while copies are running: the running copies above a lowered limit are not interrupted.
+type imageSemaphore struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	limit   int
+	running int
+}
+
+func newImageSemaphore(limit int) *imageSemaphore {
+	s := &imageSemaphore{limit: limit}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+func (s *imageSemaphore) acquire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.running >= s.limit {
+		s.cond.Wait()
+	}
+	s.running++
+}
+
+func (s *imageSemaphore) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running--
+	s.cond.Broadcast()
+}
+
+func (s *imageSemaphore) setLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = limit
+	s.cond.Broadcast()
+}
+
+// parallelismWindow holds the results of the copies run at the same parallelism.
+type parallelismWindow struct {
+	start      time.Time
+	limit      int
+	images     int
+	errors     int
+	timeouts   int
+	latency    time.Duration
+	throughput float64 // images per second, set once the window is over
+}
+
+// parallelismAdjustment is a change of the number of images copied in parallel.
+type parallelismAdjustment struct {
+	Time   time.Time `json:"time"`
+	From   int       `json:"from"`
+	To     int       `json:"to"`
+	Reason string    `json:"reason"`
+}
+
+// adaptiveParallelism adjusts the number of images copied in parallel, within [min, max],
+// from the results of the copies. It probes higher parallelisms one at a time while the
+// throughput improves, and backs off when copies time out, fail, or take too long
+// compared to the image timeout.
+type adaptiveParallelism struct {
+	log          clog.PluggableLoggerInterface
+	sem          *imageSemaphore
+	min          int
+	max          int
+	imageTimeout time.Duration
+	now          func() time.Time
+
+	window      parallelismWindow
+	previous    *parallelismWindow
+	increased   bool
+	hold        int
+	adjustments []parallelismAdjustment
+	timeAtLimit map[int]time.Duration
+}
+
+func newAdaptiveParallelism(log clog.PluggableLoggerInterface, sem *imageSemaphore, minimum, maximum int, imageTimeout time.Duration, now func() time.Time) *adaptiveParallelism {
+	a := &adaptiveParallelism{
+		log:          log,
+		sem:          sem,
+		min:          minimum,
+		max:          maximum,
+		imageTimeout: imageTimeout,
+		now:          now,
+		timeAtLimit:  map[int]time.Duration{},
+	}
+	a.window = parallelismWindow{start: now(), limit: sem.limit}
+	return a
+}
+
+// observe records the result of a copy, and adjusts the parallelism once enough copies were observed.
+func (a *adaptiveParallelism) observe(res GoroutineResult) {
+	a.window.images++
+	a.window.latency += res.duration
+	if res.timedOut {
+		a.window.timeouts++
+	} else if res.err != nil {
+		a.window.errors++
+	}
+	if a.window.images >= max(a.window.limit, minWindowImages) {
+		a.evaluate()
+	}
+}
+
+func (a *adaptiveParallelism) evaluate() {
+	now := a.now()
+	w := a.window
+	if elapsed := now.Sub(w.start); elapsed > 0 {
+		w.throughput = float64(w.images) / elapsed.Seconds()
+	}
+	meanLatency := w.latency / time.Duration(w.images)
+	errorRate := float64(w.errors) / float64(w.images)
+
+	next, reason := w.limit, ""
+	switch {
+	case w.timeouts > 0:
+		next, reason = w.limit/2, fmt.Sprintf("%d of %d images timed out", w.timeouts, w.images)
+		a.hold = holdWindows
+	case errorRate > maxErrorRate:
+		next, reason = w.limit-1, fmt.Sprintf("%d of %d images failed", w.errors, w.images)
+		a.hold = holdWindows
+	case a.imageTimeout > 0 && meanLatency > a.imageTimeout/2:
+		next, reason = w.limit-1, fmt.Sprintf("images took %s on average, over half of the image timeout", meanLatency.Round(time.Second))
+		a.hold = holdWindows
+	case a.increased && a.previous != nil && w.throughput < a.previous.throughput*(1+minThroughputGain):
+		next, reason = w.limit-1, fmt.Sprintf("throughput of %.2f images/s at %d is no better than %.2f images/s at %d", w.throughput, w.limit, a.previous.throughput, a.previous.limit)
+		a.hold = holdWindows
+	case a.hold > 0:
+		a.hold--
+	default:
+		next, reason = w.limit+1, fmt.Sprintf("probing, throughput of %.2f images/s at %d", w.throughput, w.limit)
+	}
+	next = min(max(next, a.min), a.max)
+
+	a.timeAtLimit[w.limit] += now.Sub(w.start)
+	a.increased = next > w.limit
+	a.previous = &w
+	a.window = parallelismWindow{start: now, limit: next}
+	if next == w.limit {
+		return
+	}
+	a.log.Info(emoji.Gear+" parallel images %d %s %d: %s", w.limit, emoji.RightArrow, next, reason)
+	a.adjustments = append(a.adjustments, parallelismAdjustment{Time: now, From: w.limit, To: next, Reason: reason})
+	a.sem.setLimit(next)
+}
+
+// settled returns the parallelism the copies ran at for the longest time.
+func (a *adaptiveParallelism) settled() int {
+	timeAtLimit := make(map[int]time.Duration, len(a.timeAtLimit)+1)
+	for limit, d := range a.timeAtLimit {
+		timeAtLimit[limit] = d
+	}
+	timeAtLimit[a.window.limit] += a.now().Sub(a.window.start)
+	settled := a.window.limit
+	for limit, d := range timeAtLimit {
+		if d > timeAtLimit[settled] || (d == timeAtLimit[settled] && limit < settled) {
+			settled = limit
+		}
+	}
+	return settled
+}
+
+// parallelImagesReport records in the run report the parallel images of the run.
+type parallelImagesReport struct {
+	Adaptive    bool                    `json:"adaptive"`
+	Initial     int                     `json:"initial"`
+	Min         int                     `json:"min,omitempty"`
+	Max         int                     `json:"max,omitempty"`
+	Settled     int                     `json:"settled"`
+	Adjustments []parallelismAdjustment `json:"adjustments,omitempty"`
+}
@@ -0,0 +1,193 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+func TestImageSemaphore(t *testing.T) {
+	s := newImageSemaphore(1)
+	s.acquire()
+
+	acquired := make(chan struct{})
+	go func() {
+		s.acquire()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("the limit is exceeded")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	s.setLimit(2)
+	<-acquired
+
+	// the running copies are not interrupted when the limit is lowered, the next ones wait
+	s.setLimit(1)
+	s.release()
+	acquired = make(chan struct{})
+	go func() {
+		s.acquire()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("the lowered limit is exceeded")
+	case <-time.After(20 * time.Millisecond):
+	}
+	s.release()
+	<-acquired
+}
+
+// fakeClock is advanced by the tests, to control the throughput seen by the adaptive parallelism.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestAdaptiveParallelism(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)}
+	sem := newImageSemaphore(2)
+	a := newAdaptiveParallelism(clog.New("debug"), sem, 1, 5, 10*time.Minute, clock.Now)
+
+	// copies the images of a window, each taking latency, at the given throughput
+	window := func(throughput float64, latency time.Duration, failed, timedOut int) {
+		t.Helper()
+		images := max(sem.limit, minWindowImages)
+		clock.now = clock.now.Add(time.Duration(float64(images) / throughput * float64(time.Second)))
+		for i := range images {
+			res := GoroutineResult{copied: true, duration: latency}
+			switch {
+			case i < timedOut:
+				res.timedOut = true
+				res.err = &mirrorErrorSchema{}
+			case i < timedOut+failed:
+				res.err = &mirrorErrorSchema{}
+			}
+			a.observe(res)
+		}
+	}
+
+	// probing while the throughput improves
+	window(1, time.Minute, 0, 0)
+	assert.Equal(t, 3, sem.limit)
+	window(1.5, time.Minute, 0, 0)
+	assert.Equal(t, 4, sem.limit)
+
+	// the throughput doesn't improve enough: back to the previous parallelism, which is kept
+	window(1.52, time.Minute, 0, 0)
+	assert.Equal(t, 3, sem.limit)
+	for range holdWindows {
+		window(1.5, time.Minute, 0, 0)
+		assert.Equal(t, 3, sem.limit)
+	}
+
+	// probing again, up to the upper bound
+	window(1.5, time.Minute, 0, 0)
+	assert.Equal(t, 4, sem.limit)
+	window(2, time.Minute, 0, 0)
+	assert.Equal(t, 5, sem.limit)
+	window(3, time.Minute, 0, 0)
+	assert.Equal(t, 5, sem.limit)
+
+	// timeouts halve the parallelism
+	window(3, time.Minute, 0, 1)
+	assert.Equal(t, 2, sem.limit)
+	for range holdWindows {
+		window(3, time.Minute, 0, 0)
+	}
+
+	// failures and copies close to the image timeout decrease it
+	window(3, time.Minute, 0, 0)
+	assert.Equal(t, 3, sem.limit)
+	window(4, time.Minute, 1, 0)
+	assert.Equal(t, 2, sem.limit)
+	for range holdWindows {
+		window(3, time.Minute, 0, 0)
+	}
+	window(3, 6*time.Minute, 0, 0)
+	assert.Equal(t, 1, sem.limit)
+	window(3, 6*time.Minute, 0, 0)
+	assert.Equal(t, 1, sem.limit, "the lower bound is kept")
+
+	require.Len(t, a.adjustments, 9)
+	assert.Equal(t, parallelismAdjustment{Time: a.adjustments[0].Time, From: 2, To: 3, Reason: "probing, throughput of 1.00 images/s at 2"}, a.adjustments[0])
+	assert.Equal(t, "throughput of 1.52 images/s at 4 is no better than 1.50 images/s at 3", a.adjustments[2].Reason)
+	assert.Equal(t, "1 of 5 images timed out", a.adjustments[5].Reason)
+	assert.Equal(t, "1 of 4 images failed", a.adjustments[7].Reason)
+	assert.Equal(t, "images took 6m0s on average, over half of the image timeout", a.adjustments[8].Reason)
+	// 17s at 2 parallel images, 14s at 3
+	assert.Equal(t, 2, a.settled())
+}
+
+func TestWorkerAdaptiveParallelImages(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	mirrorMock := new(MirrorMock)
+	mirrorMock.On("Run", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		n := running.Add(1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+	}).Return(nil)
+
+	var images []v2alpha1.CopyImageSchema
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		images = append(images, v2alpha1.CopyImageSchema{
+			Source:      "docker://quay.io/acme/" + name + ":latest",
+			Destination: "docker://mirror.acme.com/acme/" + name + ":latest",
+			Origin:      "docker://quay.io/acme/" + name + ":latest",
+			Type:        v2alpha1.TypeGeneric,
+		})
+	}
+	opts := mirror.CopyOptions{
+		Global:                 &mirror.GlobalOptions{CommandTimeout: time.Minute},
+		Mode:                   mirror.MirrorToMirror,
+		Function:               string(mirror.CopyMode),
+		AdaptiveParallelImages: true,
+		MinParallelImages:      2,
+		MaxParallelImages:      3,
+	}
+	logsDir := t.TempDir()
+	w := New(ChannelConcurrentWorker, clog.New("debug"), logsDir, mirrorMock, 1, "20261018_100000")
+
+	copied, err := w.Worker(context.Background(), v2alpha1.CollectorSchema{AllImages: images, TotalAdditionalImages: len(images)}, opts)
+	require.NoError(t, err)
+	assert.Len(t, copied.AllImages, len(images))
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+
+	data, err := os.ReadFile(filepath.Join(logsDir, "mirroring_report_20261018_100000.json"))
+	require.NoError(t, err)
+	var report runReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, 12, report.Images)
+	assert.Equal(t, 12, report.Completed)
+	assert.Zero(t, report.Failed)
+	assert.True(t, report.ParallelImages.Adaptive)
+	// --parallel-images is raised to the lower bound
+	assert.Equal(t, 2, report.ParallelImages.Initial)
+	assert.Equal(t, 2, report.ParallelImages.Min)
+	assert.Equal(t, 3, report.ParallelImages.Max)
+	assert.Contains(t, []int{2, 3}, report.ParallelImages.Settled)
+}
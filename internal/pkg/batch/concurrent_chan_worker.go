@@ -2,6 +2,7 @@ package batch
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -17,6 +18,7 @@ import (
 	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/emoji"
 	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/metrics"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/spinners"
 )
@@ -45,6 +47,14 @@ type GoroutineResult struct {
 	err     *mirrorErrorSchema
 	imgType v2alpha1.ImageType
 	img     v2alpha1.CopyImageSchema
+	// index is the index of the image in the collector schema
+	index int
+	// copied is set when the image was copied or deleted, not skipped
+	copied   bool
+	duration time.Duration
+	// stopped is set when the image was not copied because the time box of the run is over
+	stopped  bool
+	timedOut bool
 }
 
 // Worker - the main batch processor
@@ -79,33 +89,69 @@ func (o *ChannelConcurrentBatch) Worker(ctx context.Context, collectorSchema v2a
 	p := mpb.New(mpb.PopCompletedMode(), mpb.ContainerOptional(mpb.WithOutput(io.Discard), !opts.Global.IsTerminal))
 	results := make(chan GoroutineResult, total)
 	progressCh := make(chan int, total)
-	semaphore := make(chan struct{}, o.MaxGoroutines)
+	semaphore := newImageSemaphore(int(o.MaxGoroutines))
+	report := runReport{
+		Images:         total,
+		ParallelImages: parallelImagesReport{Initial: int(o.MaxGoroutines)},
+		ResolvedTags:   resolvedTagsReport(collectorSchema.ResolvedAdditionalImages),
+	}
+	var adaptive *adaptiveParallelism
+	if opts.AdaptiveParallelImages {
+		initial := min(max(int(o.MaxGoroutines), int(opts.MinParallelImages)), int(opts.MaxParallelImages))
+		semaphore = newImageSemaphore(initial)
+		adaptive = newAdaptiveParallelism(o.Log, semaphore, int(opts.MinParallelImages), int(opts.MaxParallelImages), opts.Global.CommandTimeout, time.Now)
+		report.ParallelImages = parallelImagesReport{Adaptive: true, Initial: initial, Min: adaptive.min, Max: adaptive.max}
+		o.Log.Info(emoji.Gear+" adapting the parallel images between %d and %d, starting at %d", adaptive.min, adaptive.max, initial)
+	}
+
+	scheduler := newUnitScheduler(collectorSchema, opts.ScheduleUnits, opts.UnitPriority)
+	if opts.ScheduleUnits {
+		o.Log.Info(emoji.Pushpin+" scheduling the images by unit: %d units", len(scheduler.units))
+	}
 
 	cancelCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	// once the time box is over, no image is launched, and the running copies are
+	// given the grace period to finish before being cancelled
+	launchCtx, copyCtx := context.Context(cancelCtx), context.Background()
+	if !opts.Deadline.IsZero() {
+		var stopLaunching, stopCopies context.CancelFunc
+		launchCtx, stopLaunching = context.WithDeadline(cancelCtx, opts.Deadline)
+		defer stopLaunching()
+		copyCtx, stopCopies = context.WithDeadline(copyCtx, opts.Deadline.Add(opts.MaxDurationGrace))
+		defer stopCopies()
+		report.TimeBox = &timeBoxReport{MaxDuration: opts.MaxDuration.String(), Deadline: opts.Deadline}
+		o.Log.Info(emoji.Hourglass+" no image is launched after %s", opts.Deadline.Format(time.DateTime))
+	}
+	stopScheduler := context.AfterFunc(launchCtx, scheduler.stop)
+	defer stopScheduler()
 
 	go func() {
 		defer close(results)
-		defer close(semaphore)
-
-		for _, img := range collectorSchema.AllImages {
 
+		for {
 			select {
-			case <-cancelCtx.Done():
+			case <-launchCtx.Done():
 				wg.Wait()
 				return
 			default:
 			}
 
-			semaphore <- struct{}{}
+			semaphore.acquire()
+			index, ok := scheduler.next()
+			if !ok {
+				semaphore.release()
+				break
+			}
+			img := collectorSchema.AllImages[index]
 
 			sp := newSpinner(img, opts.LocalStorageFQDN, p)
 
 			wg.Add(1)
-			go func(cancelCtx context.Context, semaphore chan struct{}, results chan<- GoroutineResult, spinner *mpb.Bar) {
+			go func(cancelCtx context.Context, semaphore *imageSemaphore, results chan<- GoroutineResult, spinner *mpb.Bar) {
 				defer wg.Done()
-				defer func() { <-semaphore }()
-				result := GoroutineResult{imgType: img.Type, img: img}
+				defer semaphore.release()
+				result := GoroutineResult{imgType: img.Type, img: img, index: index}
 
 				m.Lock()
 				skip, reason := shouldSkipImage(img, opts, errArray)
@@ -137,7 +183,15 @@ func (o *ChannelConcurrentBatch) Worker(ctx context.Context, collectorSchema v2a
 					default:
 						if !triggered {
 							triggered = true
-							timeoutCtx, _ := opts.Global.CommandTimeoutContext()
+							// waiting for the registries of the image doesn't count in its timeout
+							release, acquireErr := opts.Limiter.Acquire(launchCtx, img.Source, img.Destination)
+							if acquireErr != nil {
+								spinner.Abort(false)
+								result.stopped = true
+								results <- result
+								break loop
+							}
+							timeoutCtx, cancelTimeout := copyContext(copyCtx, opts.Global.CommandTimeout)
 
 							options := opts
 							if img.Type.IsOperatorCatalog() && img.RebuiltTag != "" {
@@ -153,8 +207,18 @@ func (o *ChannelConcurrentBatch) Worker(ctx context.Context, collectorSchema v2a
 								}
 								options.InstancePlatforms = strs
 							}
+							if img.Type.IsArtifact() {
+								artifactCopyOptions(&options)
+							}
 
+							start := time.Now()
 							err = o.Mirror.Run(timeoutCtx, img.Source, img.Destination, mirror.Mode(opts.Function), &options) //nolint:contextcheck
+							release()
+							result.copied = true
+							result.duration = time.Since(start)
+							result.stopped = err != nil && copyCtx.Err() != nil
+							result.timedOut = !result.stopped && errors.Is(timeoutCtx.Err(), context.DeadlineExceeded)
+							cancelTimeout()
 
 							// "no instances found for platform" is only emitted by the copy library
 							// for manifest lists (multi-arch indexes) when none of the instances
@@ -170,12 +234,14 @@ func (o *ChannelConcurrentBatch) Worker(ctx context.Context, collectorSchema v2a
 							switch {
 							case err == nil:
 								spinner.Increment()
+							case result.stopped:
+								spinner.Abort(false)
 							case img.Type.IsOperator():
 								operators := collectorSchema.CopyImageSchemaMap.OperatorsByImage[img.Origin]
 								bundles := collectorSchema.CopyImageSchemaMap.BundlesByImage[img.Origin]
 								result.err = &mirrorErrorSchema{image: img, err: err, operators: operators, bundles: bundles}
 								spinner.Abort(false)
-							case img.Type.IsRelease() || img.Type.IsAdditionalImage() || img.Type.IsHelmImage():
+							case img.Type.IsRelease() || img.Type.IsAdditionalImage() || img.Type.IsHelmImage() || img.Type.IsSampleImage():
 								result.err = &mirrorErrorSchema{image: img, err: err}
 								spinner.Abort(false)
 							}
@@ -196,7 +262,20 @@ func (o *ChannelConcurrentBatch) Worker(ctx context.Context, collectorSchema v2a
 
 	completed := 0
 	for completed < len(collectorSchema.AllImages) {
-		res := <-results
+		res, ok := <-results
+		if !ok {
+			// the images left were not launched
+			break
+		}
+		if res.stopped {
+			completed++
+			progressCh <- 1
+			continue
+		}
+		if adaptive != nil && res.copied {
+			adaptive.observe(res)
+		}
+		opts.Metrics.ObserveImage(res.img, imageResult(res), res.duration)
 		err := res.err
 		if err == nil {
 			logImageSuccess(o.Log, &res.img, &opts)
@@ -213,6 +292,8 @@ func (o *ChannelConcurrentBatch) Worker(ctx context.Context, collectorSchema v2a
 				break
 			}
 		}
+		// the scheduler learns about the failures once they are in errArray, for shouldSkipImage
+		scheduler.done(res.index, err != nil)
 
 		completed++
 		progressCh <- 1
@@ -223,8 +304,37 @@ func (o *ChannelConcurrentBatch) Worker(ctx context.Context, collectorSchema v2a
 
 	logResults(o.Log, opts.Function, &copiedImages, &collectorSchema)
 
+	report.Completed = len(copiedImages.AllImages)
+	report.Failed = len(errArray)
+	report.Duration = time.Since(startTime).Round(time.Second).String()
+	report.ParallelImages.Settled = report.ParallelImages.Initial
+	if adaptive != nil {
+		report.ParallelImages.Settled = adaptive.settled()
+		report.ParallelImages.Adjustments = adaptive.adjustments
+		o.Log.Info(emoji.Gear+" settled on %d parallel images", report.ParallelImages.Settled)
+	}
+	if opts.ScheduleUnits {
+		report.Units = scheduler.report()
+		logUnits(o.Log, report.Units)
+	}
+	var remaining []v2alpha1.CopyImageSchema
+	if report.TimeBox != nil && errors.Is(launchCtx.Err(), context.DeadlineExceeded) {
+		for _, i := range scheduler.remaining() {
+			remaining = append(remaining, collectorSchema.AllImages[i])
+			report.TimeBox.Remaining = append(report.TimeBox.Remaining, collectorSchema.AllImages[i].Origin)
+		}
+		report.TimeBox.Reached = len(remaining) > 0
+	}
+	if len(remaining) > 0 {
+		o.Log.Warn(emoji.Hourglass+" the max duration of %s is over: %d images remain to %s, they are listed in the run report", opts.MaxDuration, len(remaining), opts.Function)
+	}
+	if filename, err := saveReport(o.LogsDir, o.SynchedTimeStamp, report); err != nil {
+		o.Log.Warn(workerPrefix+"unable to save the run report in %s/%s: %v", o.LogsDir, filename, err)
+	}
+
+	var batchErr *BatchError
 	if len(errArray) > 0 {
-		batchErr := &BatchError{
+		batchErr = &BatchError{
 			releaseCountDiff:       collectorSchema.TotalReleaseImages - copiedImages.TotalReleaseImages,
 			operatorCountDiff:      collectorSchema.TotalOperatorImages - copiedImages.TotalOperatorImages,
 			additionalImgCountDiff: collectorSchema.TotalAdditionalImages - copiedImages.TotalAdditionalImages,
@@ -236,6 +346,11 @@ func (o *ChannelConcurrentBatch) Worker(ctx context.Context, collectorSchema v2a
 		} else {
 			batchErr.source = fmt.Errorf(errMsg, workerPrefix, o.LogsDir, filename)
 		}
+	}
+	if len(remaining) > 0 {
+		return copiedImages, &TimeBoxError{MaxDuration: opts.MaxDuration, Remaining: remaining, BatchErr: batchErr}
+	}
+	if batchErr != nil {
 		return copiedImages, batchErr
 	}
 	o.Log.Debug("concurrent channel worker time     : %v", time.Since(startTime))
@@ -278,6 +393,18 @@ func logResults(log clog.PluggableLoggerInterface, copyMode string, copiedImages
 	logResult(log, copyModeMsg, "helm", copiedImages.TotalHelmImages, collectorSchema.TotalHelmImages)
 }
 
+func logUnits(log clog.PluggableLoggerInterface, units []unitReport) {
+	complete := 0
+	for _, u := range units {
+		if u.Complete {
+			complete++
+		} else {
+			log.Warn(emoji.SpinnerCrossMark+" %s is incomplete: %d / %d images", u.Name, u.Copied, u.Images)
+		}
+	}
+	log.Info(emoji.SpinnerCheckMark+" %d / %d units complete", complete, len(units))
+}
+
 func logResult(log clog.PluggableLoggerInterface, copyMode, imageType string, copied, total int) {
 	if total != 0 {
 		if copied == total {
@@ -370,11 +497,23 @@ func runOverallProgress(overallProgress *mpb.Bar, cancelCtx context.Context, pro
 	}
 }
 
+// imageResult returns the result of an image, as recorded in the metrics.
+func imageResult(res GoroutineResult) string {
+	switch {
+	case !res.copied:
+		return metrics.ResultSkipped
+	case res.err != nil:
+		return metrics.ResultFailed
+	default:
+		return metrics.ResultSucceeded
+	}
+}
+
 func incrementTotals(imgType v2alpha1.ImageType, copiedImages *v2alpha1.CollectorSchema) {
 	switch imgType {
-	case v2alpha1.TypeCincinnatiGraph, v2alpha1.TypeOCPRelease, v2alpha1.TypeOCPReleaseContent:
+	case v2alpha1.TypeCincinnatiGraph, v2alpha1.TypeOCPRelease, v2alpha1.TypeOCPReleaseContent, v2alpha1.TypeSampleImage:
 		copiedImages.TotalReleaseImages++
-	case v2alpha1.TypeGeneric:
+	case v2alpha1.TypeGeneric, v2alpha1.TypeArtifact:
 		copiedImages.TotalAdditionalImages++
 	case v2alpha1.TypeOperatorBundle, v2alpha1.TypeOperatorCatalog, v2alpha1.TypeOperatorRelatedImage:
 		copiedImages.TotalOperatorImages++
@@ -383,6 +522,17 @@ func incrementTotals(imgType v2alpha1.ImageType, copiedImages *v2alpha1.Collecto
 	}
 }
 
+// artifactCopyOptions makes the copy of an OCI artifact faithful: all the instances of an index
+// are copied, since artifacts rarely declare a platform, and the manifests are neither converted
+// nor rewritten, so that the artifactType, config media type, subject and annotations are kept.
+func artifactCopyOptions(options *mirror.CopyOptions) {
+	options.InstancePlatforms = nil
+	options.MultiArch = ""
+	options.All = true
+	options.Format = ""
+	options.PreserveDigests = true
+}
+
 // shouldSkipImage helps determine whether the batch should perform the mirroring of the image
 // or if the image should be skipped.
 func shouldSkipImage(img v2alpha1.CopyImageSchema, opts mirror.CopyOptions, errArray []mirrorErrorSchema) (bool, error) {
@@ -2,16 +2,20 @@ package batch
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
 	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/metrics"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
 )
 
@@ -144,3 +148,111 @@ func TestOCPBUGS53455_RebuiltCatalogPreserveDigests(t *testing.T) {
 		})
 	}
 }
+
+func TestArtifactCopyOptions(t *testing.T) {
+	global := &mirror.GlobalOptions{SecurePolicy: false, Quiet: false}
+	_, sharedOpts := mirror.SharedImageFlags()
+	_, deprecatedTLSVerifyOpt := mirror.DeprecatedTLSVerifyFlags()
+	_, srcOpts := mirror.ImageSrcFlags(global, sharedOpts, deprecatedTLSVerifyOpt, "src-", "screds")
+	_, destOpts := mirror.ImageDestFlags(global, sharedOpts, deprecatedTLSVerifyOpt, "dest-", "dcreds")
+	_, retryOpts := mirror.RetryFlags()
+
+	artifact := v2alpha1.CopyImageSchema{
+		Source:      consts.DockerProtocol + "quay.io/ourorg/policies/opa-bundle:v1.4",
+		Origin:      "quay.io/ourorg/policies/opa-bundle:v1.4",
+		Destination: consts.DockerProtocol + "nexus:8082/ourorg/policies/opa-bundle:v1.4",
+		Type:        v2alpha1.TypeArtifact,
+	}
+
+	mirrorMock := new(MirrorMock)
+	var capturedOpts *mirror.CopyOptions
+	mirrorMock.On("Run", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(opts *mirror.CopyOptions) bool {
+		capturedOpts = opts
+		return true
+	})).Return(nil)
+
+	opts := mirror.CopyOptions{
+		Global:              global,
+		DeprecatedTLSVerify: deprecatedTLSVerifyOpt,
+		SrcImage:            srcOpts,
+		DestImage:           destOpts,
+		RetryOpts:           retryOpts,
+		Destination:         consts.DockerProtocol + "nexus:8082",
+		Mode:                mirror.MirrorToMirror,
+		Function:            "copy",
+		MultiArch:           "system",
+		Format:              "oci",
+	}
+	collectorSchema := v2alpha1.CollectorSchema{
+		AllImages: []v2alpha1.CopyImageSchema{artifact},
+		// an additional image with the same reference must not filter the platforms of the artifact
+		PlatformFilters: map[string][]v2alpha1.InstancePlatformFilter{artifact.Origin: {{OS: "linux", Architecture: "amd64"}}},
+	}
+
+	w := &ChannelConcurrentBatch{
+		Log:              clog.New("trace"),
+		LogsDir:          t.TempDir(),
+		Mirror:           mirrorMock,
+		MaxGoroutines:    1,
+		SynchedTimeStamp: time.Now().Format("20060102_150405"),
+	}
+	copied, err := w.Worker(context.Background(), collectorSchema, opts)
+	assert.NoError(t, err)
+	mirrorMock.AssertExpectations(t)
+
+	assert.NotNil(t, capturedOpts)
+	assert.Empty(t, capturedOpts.InstancePlatforms)
+	assert.Empty(t, capturedOpts.MultiArch)
+	assert.True(t, capturedOpts.All)
+	assert.Empty(t, capturedOpts.Format)
+	assert.True(t, capturedOpts.PreserveDigests)
+	assert.Equal(t, 1, copied.TotalAdditionalImages)
+}
+
+func TestWorkerMetrics(t *testing.T) {
+	ubi := v2alpha1.CopyImageSchema{Source: "docker://quay.io/acme/ubi:latest", Destination: "docker://mirror.acme.com/acme/ubi:latest", Origin: "docker://quay.io/acme/ubi:latest", Type: v2alpha1.TypeGeneric}
+	related := v2alpha1.CopyImageSchema{Source: "docker://quay.io/acme/foo-operator:v1", Destination: "docker://mirror.acme.com/acme/foo-operator:v1", Origin: "docker://quay.io/acme/foo-operator:v1", Type: v2alpha1.TypeOperatorRelatedImage}
+	bundle := v2alpha1.CopyImageSchema{Source: "docker://quay.io/acme/foo-bundle:v1", Destination: "docker://mirror.acme.com/acme/foo-bundle:v1", Origin: "docker://quay.io/acme/foo-bundle:v1", Type: v2alpha1.TypeOperatorBundle}
+	cs := v2alpha1.CollectorSchema{
+		AllImages:             []v2alpha1.CopyImageSchema{related, bundle, ubi},
+		TotalOperatorImages:   2,
+		TotalAdditionalImages: 1,
+		CopyImageSchemaMap: v2alpha1.CopyImageSchemaMap{
+			OperatorsByImage: map[string]map[string]struct{}{related.Origin: {"foo": {}}, bundle.Origin: {"foo": {}}},
+			BundlesByImage:   map[string]map[string]string{related.Origin: {"quay.io/acme/foo-bundle:v1": "foo.v1"}},
+		},
+	}
+
+	mirrorMock := new(MirrorMock)
+	mirrorMock.On("Run", mock.Anything, related.Source, mock.Anything, mock.Anything, mock.Anything).Return(assert.AnError)
+	mirrorMock.On("Run", mock.Anything, ubi.Source, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	textfile := filepath.Join(t.TempDir(), "oc-mirror.prom")
+	opts := mirror.CopyOptions{
+		Global:   &mirror.GlobalOptions{CommandTimeout: time.Minute},
+		Mode:     mirror.MirrorToMirror,
+		Function: string(mirror.CopyMode),
+		// the bundle waits for its related image, and is skipped once it failed
+		ScheduleUnits: true,
+		Metrics:       metrics.New(metrics.Options{Textfile: textfile}, "localhost:55000"),
+	}
+	w := New(ChannelConcurrentWorker, clog.New("debug"), t.TempDir(), mirrorMock, 1, "20261019_100000")
+	_, err := w.Worker(context.Background(), cs, opts)
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+
+	require.NoError(t, opts.Metrics.Stop(t.Context()))
+	data, err := os.ReadFile(textfile)
+	require.NoError(t, err)
+	for _, line := range []string{
+		`oc_mirror_images_processed_total{destination="mirror.acme.com",result="succeeded",type="generic"} 1`,
+		`oc_mirror_images_processed_total{destination="mirror.acme.com",result="failed",type="operatorRelatedImage"} 1`,
+		`oc_mirror_images_processed_total{destination="mirror.acme.com",result="skipped",type="operatorBundle"} 1`,
+		`oc_mirror_copy_duration_seconds_count{destination="mirror.acme.com",type="generic"} 1`,
+		`oc_mirror_copy_duration_seconds_count{destination="mirror.acme.com",type="operatorRelatedImage"} 1`,
+	} {
+		assert.Contains(t, string(data), line)
+	}
+	assert.NotContains(t, string(data), `oc_mirror_copy_duration_seconds_count{destination="mirror.acme.com",type="operatorBundle"}`)
+	// the images of the run are set by the collection, not by each batch worker
+	assert.NotContains(t, string(data), `oc_mirror_images{`)
+}
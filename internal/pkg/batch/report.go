@@ -0,0 +1,53 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+// runReport summarizes a run of the batch worker, to tune the next runs.
+type runReport struct {
+	Images         int                  `json:"images"`
+	Completed      int                  `json:"completed"`
+	Failed         int                  `json:"failed"`
+	Duration       string               `json:"duration"`
+	ParallelImages parallelImagesReport `json:"parallelImages"`
+	// Units are set when the images are scheduled by unit
+	Units []unitReport `json:"units,omitempty"`
+	// TimeBox is set when the run has a max duration
+	TimeBox *timeBoxReport `json:"timeBox,omitempty"`
+	// ResolvedTags maps the additional images with a tag selector, or naming a namespace,
+	// to the images resolved for them
+	ResolvedTags map[string][]string `json:"resolvedTags,omitempty"`
+}
+
+// resolvedTagsReport returns the names of the images resolved for each additional image.
+func resolvedTagsReport(resolved map[string][]v2alpha1.AdditionalImage) map[string][]string {
+	if len(resolved) == 0 {
+		return nil
+	}
+	report := make(map[string][]string, len(resolved))
+	for name, images := range resolved {
+		for _, img := range images {
+			report[name] = append(report[name], img.Name)
+		}
+	}
+	return report
+}
+
+// saveReport writes the run report in logsDir, and returns its file name.
+func saveReport(logsDir, timestamp string, report runReport) (string, error) {
+	filename := fmt.Sprintf("mirroring_report_%s.json", timestamp)
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return filename, fmt.Errorf("marshaling the run report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(logsDir, filename), data, 0o644); err != nil {
+		return filename, fmt.Errorf("writing the run report: %w", err)
+	}
+	return filename, nil
+}
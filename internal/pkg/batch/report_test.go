@@ -0,0 +1,49 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+func TestWorkerReportResolvedTags(t *testing.T) {
+	mirrorMock := new(MirrorMock)
+	mirrorMock.On("Run", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	cs := v2alpha1.CollectorSchema{
+		AllImages: []v2alpha1.CopyImageSchema{
+			{Source: "docker://quay.io/acme/a:1.0", Destination: "docker://mirror.acme.com/acme/a:1.0", Origin: "docker://quay.io/acme/a:1.0", Type: v2alpha1.TypeGeneric},
+			{Source: "docker://quay.io/acme/a:1.1", Destination: "docker://mirror.acme.com/acme/a:1.1", Origin: "docker://quay.io/acme/a:1.1", Type: v2alpha1.TypeGeneric},
+		},
+		TotalAdditionalImages: 2,
+		ResolvedAdditionalImages: map[string][]v2alpha1.AdditionalImage{
+			"quay.io/acme/a": {{Name: "quay.io/acme/a:1.0"}, {Name: "quay.io/acme/a:1.1"}},
+		},
+	}
+	opts := mirror.CopyOptions{
+		Global:   &mirror.GlobalOptions{CommandTimeout: time.Minute},
+		Mode:     mirror.MirrorToMirror,
+		Function: string(mirror.CopyMode),
+	}
+	logsDir := t.TempDir()
+	w := New(ChannelConcurrentWorker, clog.New("debug"), logsDir, mirrorMock, 1, "20261018_100000")
+
+	_, err := w.Worker(context.Background(), cs, opts)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(logsDir, "mirroring_report_20261018_100000.json"))
+	require.NoError(t, err)
+	var report runReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, map[string][]string{"quay.io/acme/a": {"quay.io/acme/a:1.0", "quay.io/acme/a:1.1"}}, report.ResolvedTags)
+}
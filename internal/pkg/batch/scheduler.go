@@ -0,0 +1,374 @@
+package batch
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/image"
+)
+
+// The kinds of the scheduling units, in their default order.
+const (
+	UnitRelease    = "release"
+	UnitOperator   = "operator"
+	UnitHelm       = "helm"
+	UnitAdditional = "additional"
+)
+
+var unitKinds = []string{UnitRelease, UnitOperator, UnitHelm, UnitAdditional}
+
+// schedulingUnit is a logical unit of images: the release payload, an operator package
+// with its bundles and related images, an operator catalog, a helm chart, or an additional image.
+type schedulingUnit struct {
+	kind string
+	name string
+	// catalog is set on the unit of an operator catalog, which is shared by its packages
+	catalog bool
+	// images are the indexes of all the images of the unit
+	images []int
+	// owned are the indexes of the images dispatched with the unit, which didn't
+	// belong to a unit of higher priority
+	owned  []int
+	cursor int
+}
+
+func (u *schedulingUnit) String() string {
+	if u.name == "" {
+		return u.kind
+	}
+	return u.kind + " " + u.name
+}
+
+// unitScheduler hands out the images to copy unit by unit, in priority order, so that
+// the units are completed one after the other. The next unit is started while the last
+// images of the previous one are being copied. Within a unit, the bundles are handed out
+// once the related images are done. A catalog is a unit of its own, scheduled after the
+// packages of the same priority, which waits for the images of all its packages, so that
+// it never references packages which were not mirrored. A package is thus complete once its
+// own images are copied, whether or not the other packages of its catalog are.
+type unitScheduler struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	units   []*schedulingUnit
+	waitFor [][]int
+	// dispatched and finished are set once an image is handed out and once its copy is over
+	dispatched []bool
+	finished   []bool
+	failed     []bool
+	first      int
+	stopped    bool
+}
+
+// newUnitScheduler returns the scheduler of the images of the collector schema. When grouped
+// is false, all the images are a single unit, and are handed out in the order of the schema.
+func newUnitScheduler(cs v2alpha1.CollectorSchema, grouped bool, priority []string) *unitScheduler {
+	n := len(cs.AllImages)
+	s := &unitScheduler{
+		waitFor:    make([][]int, n),
+		dispatched: make([]bool, n),
+		finished:   make([]bool, n),
+		failed:     make([]bool, n),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	if !grouped {
+		all := &schedulingUnit{kind: "all images"}
+		for i := range cs.AllImages {
+			all.images = append(all.images, i)
+		}
+		all.owned = all.images
+		s.units = []*schedulingUnit{all}
+		return s
+	}
+
+	s.units = buildUnits(cs, priority)
+	owner := make([]*schedulingUnit, n)
+	for _, u := range s.units {
+		for _, i := range u.images {
+			if owner[i] == nil {
+				owner[i] = u
+				u.owned = append(u.owned, i)
+			}
+		}
+	}
+	// the images shared by units wait for the images of all of them
+	packageUnits := map[string]*schedulingUnit{}
+	for _, u := range s.units {
+		if u.kind == UnitOperator && !u.catalog {
+			packageUnits[u.name] = u
+		}
+		for _, i := range u.images {
+			for _, j := range u.images {
+				if unitStage(cs.AllImages[j].Type) < unitStage(cs.AllImages[i].Type) {
+					s.waitFor[i] = append(s.waitFor[i], j)
+				}
+			}
+		}
+	}
+	// the catalogs wait for the images of their packages
+	for _, u := range s.units {
+		if !u.catalog {
+			continue
+		}
+		for _, i := range u.images {
+			for _, pkg := range imagePackages(cs, cs.AllImages[i]) {
+				if pu, ok := packageUnits[pkg]; ok {
+					s.waitFor[i] = append(s.waitFor[i], pu.images...)
+				}
+			}
+		}
+	}
+	for i := range s.waitFor {
+		slices.Sort(s.waitFor[i])
+		s.waitFor[i] = slices.Compact(s.waitFor[i])
+	}
+	return s
+}
+
+// unitStage orders the images within a unit: the bundles reference the related images,
+// and the catalogs reference the bundles.
+func unitStage(t v2alpha1.ImageType) int {
+	switch t {
+	case v2alpha1.TypeOperatorBundle:
+		return 1
+	case v2alpha1.TypeOperatorCatalog:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// buildUnits groups the images of the collector schema by unit, sorted by priority.
+func buildUnits(cs v2alpha1.CollectorSchema, priority []string) []*schedulingUnit {
+	var units []*schedulingUnit
+	byName := map[string]*schedulingUnit{}
+	add := func(kind, name string, i int) *schedulingUnit {
+		key := kind + " " + name
+		u, ok := byName[key]
+		if !ok {
+			u = &schedulingUnit{kind: kind, name: name}
+			byName[key] = u
+			units = append(units, u)
+		}
+		u.images = append(u.images, i)
+		return u
+	}
+
+	for i, img := range cs.AllImages {
+		switch {
+		case img.Type.IsRelease() || img.Type.IsSampleImage():
+			add(UnitRelease, "", i)
+		case img.Type == v2alpha1.TypeOperatorCatalog:
+			add(UnitOperator, unitImageName(img), i).catalog = true
+		case img.Type.IsOperator():
+			packages := imagePackages(cs, img)
+			if len(packages) == 0 {
+				packages = []string{unitImageName(img)}
+			}
+			for _, pkg := range packages {
+				add(UnitOperator, pkg, i)
+			}
+		case img.Type.IsHelmImage():
+			charts := slices.Sorted(maps.Keys(cs.CopyImageSchemaMap.ChartsByImage[img.Origin]))
+			if len(charts) == 0 {
+				charts = []string{unitImageName(img)}
+			}
+			for _, chart := range charts {
+				add(UnitHelm, chart, i)
+			}
+		default:
+			add(UnitAdditional, unitImageName(img), i)
+		}
+	}
+
+	rank := func(u *schedulingUnit) int {
+		for r, entry := range priority {
+			if unitMatches(entry, u) {
+				return r
+			}
+		}
+		return len(priority) + slices.Index(unitKinds, u.kind)
+	}
+	slices.SortStableFunc(units, func(a, b *schedulingUnit) int {
+		if r := rank(a) - rank(b); r != 0 {
+			return r
+		}
+		// the catalogs come after the packages of the same priority
+		return compareBool(a.catalog, b.catalog)
+	})
+	return units
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// unitImageName names the unit of a catalog, or of an image which doesn't belong to a package or a chart.
+func unitImageName(img v2alpha1.CopyImageSchema) string {
+	return strings.TrimPrefix(img.Origin, consts.DockerProtocol)
+}
+
+// imagePackages returns the operator packages an operator image belongs to.
+func imagePackages(cs v2alpha1.CollectorSchema, img v2alpha1.CopyImageSchema) []string {
+	if img.Type != v2alpha1.TypeOperatorCatalog {
+		return slices.Sorted(maps.Keys(cs.CopyImageSchemaMap.OperatorsByImage[img.Origin]))
+	}
+	ref, err := image.ParseRef(img.Origin)
+	if err != nil {
+		return nil
+	}
+	result, ok := cs.CatalogToFBCMap[ref.ReferenceWithTransport]
+	if !ok || result.DeclConfig == nil {
+		return nil
+	}
+	var packages []string
+	for _, pkg := range result.DeclConfig.Packages {
+		packages = append(packages, pkg.Name)
+	}
+	slices.Sort(packages)
+	return packages
+}
+
+// unitMatches tells whether an entry of the unit priority, <kind> or <kind>:<name>,
+// selects a unit. The name may contain * wildcards.
+func unitMatches(entry string, u *schedulingUnit) bool {
+	kind, name, hasName := strings.Cut(entry, ":")
+	if kind != u.kind {
+		return false
+	}
+	if !hasName {
+		return true
+	}
+	return unitNamePattern(name).MatchString(u.name)
+}
+
+func unitNamePattern(name string) *regexp.Regexp {
+	return regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(name), `\*`, ".*") + "$")
+}
+
+// ValidateUnitPriority checks the entries of the unit priority.
+func ValidateUnitPriority(priority []string) error {
+	for _, entry := range priority {
+		kind, name, hasName := strings.Cut(entry, ":")
+		if !slices.Contains(unitKinds, kind) {
+			return fmt.Errorf("invalid unit priority %q: the kind must be one of %s", entry, strings.Join(unitKinds, ", "))
+		}
+		if hasName && name == "" {
+			return fmt.Errorf("invalid unit priority %q: the name is missing", entry)
+		}
+	}
+	return nil
+}
+
+// next returns the index of the next image to copy. It waits while the remaining images
+// of the started units wait for images being copied. It returns false once all the images
+// were handed out, or the scheduler is stopped.
+func (s *unitScheduler) next() (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		if s.stopped {
+			return 0, false
+		}
+		remaining := false
+		for _, u := range s.units[s.first:] {
+			for u.cursor < len(u.owned) && s.dispatched[u.owned[u.cursor]] {
+				u.cursor++
+			}
+			for _, i := range u.owned[u.cursor:] {
+				if s.dispatched[i] {
+					continue
+				}
+				remaining = true
+				if s.ready(i) {
+					s.dispatched[i] = true
+					return i, true
+				}
+			}
+		}
+		for s.first < len(s.units) && s.units[s.first].cursor == len(s.units[s.first].owned) {
+			s.first++
+		}
+		if !remaining {
+			return 0, false
+		}
+		s.cond.Wait()
+	}
+}
+
+func (s *unitScheduler) ready(i int) bool {
+	for _, j := range s.waitFor[i] {
+		if !s.finished[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// done records the end of the copy of an image.
+func (s *unitScheduler) done(i int, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finished[i] = true
+	s.failed[i] = failed
+	s.cond.Broadcast()
+}
+
+// stop makes next return false, once the batch is cancelled.
+func (s *unitScheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	s.cond.Broadcast()
+}
+
+// remaining returns the indexes of the images whose copy is not over, in the order of the schema.
+func (s *unitScheduler) remaining() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var remaining []int
+	for i, finished := range s.finished {
+		if !finished {
+			remaining = append(remaining, i)
+		}
+	}
+	return remaining
+}
+
+// unitReport is the state of a scheduling unit at the end of a run.
+type unitReport struct {
+	Name     string `json:"name"`
+	Images   int    `json:"images"`
+	Copied   int    `json:"copied"`
+	Complete bool   `json:"complete"`
+}
+
+// report returns the state of the units, in the order they were scheduled.
+func (s *unitScheduler) report() []unitReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reports := make([]unitReport, 0, len(s.units))
+	for _, u := range s.units {
+		r := unitReport{Name: u.String(), Images: len(u.images)}
+		for _, i := range u.images {
+			if s.finished[i] && !s.failed[i] {
+				r.Copied++
+			}
+		}
+		r.Complete = r.Copied == r.Images
+		reports = append(reports, r)
+	}
+	return reports
+}
@@ -0,0 +1,286 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+// unitsSchema returns a collector schema with a release, two operator packages sharing
+// a related image and a catalog, a helm chart and an additional image.
+func unitsSchema() v2alpha1.CollectorSchema {
+	img := func(origin string, t v2alpha1.ImageType) v2alpha1.CopyImageSchema {
+		return v2alpha1.CopyImageSchema{
+			Source:      origin,
+			Destination: "docker://mirror.acme.com/" + origin[len("docker://"):],
+			Origin:      origin,
+			Type:        t,
+		}
+	}
+	return v2alpha1.CollectorSchema{
+		AllImages: []v2alpha1.CopyImageSchema{
+			0: img("docker://quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:4d0e0d8e5a9a11b9f5d4f1e5c6b7a8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5", v2alpha1.TypeOCPReleaseContent),
+			1: img("docker://quay.io/openshift-release-dev/ocp-release:4.18.1-x86_64", v2alpha1.TypeOCPRelease),
+			2: img("docker://quay.io/acme/foo-operator:v1", v2alpha1.TypeOperatorRelatedImage),
+			3: img("docker://quay.io/acme/bar-operator:v1", v2alpha1.TypeOperatorRelatedImage),
+			4: img("docker://quay.io/acme/kube-rbac-proxy:v1", v2alpha1.TypeOperatorRelatedImage),
+			5: img("docker://quay.io/acme/foo-bundle:v1", v2alpha1.TypeOperatorBundle),
+			6: img("docker://quay.io/acme/bar-bundle:v1", v2alpha1.TypeOperatorBundle),
+			7: img("docker://quay.io/acme/catalog:v1", v2alpha1.TypeOperatorCatalog),
+			8: img("docker://docker.io/bitnami/nginx:1.27", v2alpha1.TypeHelmImage),
+			9: img("docker://registry.redhat.io/ubi9/ubi:latest", v2alpha1.TypeGeneric),
+		},
+		CopyImageSchemaMap: v2alpha1.CopyImageSchemaMap{
+			OperatorsByImage: map[string]map[string]struct{}{
+				"docker://quay.io/acme/foo-operator:v1":    {"foo": {}},
+				"docker://quay.io/acme/bar-operator:v1":    {"bar": {}},
+				"docker://quay.io/acme/kube-rbac-proxy:v1": {"foo": {}, "bar": {}},
+				"docker://quay.io/acme/foo-bundle:v1":      {"foo": {}},
+				"docker://quay.io/acme/bar-bundle:v1":      {"bar": {}},
+			},
+			ChartsByImage: map[string]map[string]struct{}{
+				"docker://docker.io/bitnami/nginx:1.27": {"bitnami/nginx": {}},
+			},
+		},
+		CatalogToFBCMap: map[string]v2alpha1.CatalogFilterResult{
+			"docker://quay.io/acme/catalog:v1": {
+				DeclConfig: &declcfg.DeclarativeConfig{Packages: []declcfg.Package{{Name: "bar"}, {Name: "foo"}}},
+			},
+		},
+	}
+}
+
+func unitNames(s *unitScheduler) []string {
+	var names []string
+	for _, u := range s.units {
+		names = append(names, u.String())
+	}
+	return names
+}
+
+// drain hands out all the images one at a time, as a single copy running at once would.
+func drain(s *unitScheduler, failed ...int) []int {
+	var order []int
+	for {
+		i, ok := s.next()
+		if !ok {
+			return order
+		}
+		order = append(order, i)
+		s.done(i, slices.Contains(failed, i))
+	}
+}
+
+func TestUnitSchedulerUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority []string
+		units    []string
+		order    []int
+	}{
+		{
+			name:  "default priority",
+			units: []string{"release", "operator foo", "operator bar", "operator quay.io/acme/catalog:v1", "helm bitnami/nginx", "additional registry.redhat.io/ubi9/ubi:latest"},
+			// the bundles come after the related images, the catalog after the images of all its packages
+			order: []int{0, 1, 2, 4, 5, 3, 6, 7, 8, 9},
+		},
+		{
+			name:     "user priority",
+			priority: []string{"helm", "operator:ba*", "additional:registry.redhat.io/*"},
+			units:    []string{"helm bitnami/nginx", "operator bar", "additional registry.redhat.io/ubi9/ubi:latest", "release", "operator foo", "operator quay.io/acme/catalog:v1"},
+			order:    []int{8, 3, 4, 6, 9, 0, 1, 2, 5, 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newUnitScheduler(unitsSchema(), true, tt.priority)
+			assert.Equal(t, tt.units, unitNames(s))
+			assert.Equal(t, tt.order, drain(s))
+		})
+	}
+
+	t.Run("not grouped", func(t *testing.T) {
+		s := newUnitScheduler(unitsSchema(), false, nil)
+		assert.Equal(t, []string{"all images"}, unitNames(s))
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, drain(s))
+	})
+
+	t.Run("images without package nor chart", func(t *testing.T) {
+		cs := unitsSchema()
+		cs.CopyImageSchemaMap = v2alpha1.CopyImageSchemaMap{}
+		cs.CatalogToFBCMap = nil
+		s := newUnitScheduler(cs, true, nil)
+		assert.Contains(t, unitNames(s), "operator quay.io/acme/foo-operator:v1")
+		assert.Contains(t, unitNames(s), "helm docker.io/bitnami/nginx:1.27")
+		assert.Len(t, drain(s), 10)
+	})
+}
+
+func TestUnitSchedulerPipelining(t *testing.T) {
+	s := newUnitScheduler(unitsSchema(), true, nil)
+
+	// without any copy over, the next units are started while the bundles and the catalog wait
+	var handed []int
+	for range 7 {
+		i, ok := s.next()
+		require.True(t, ok)
+		handed = append(handed, i)
+	}
+	assert.Equal(t, []int{0, 1, 2, 4, 3, 8, 9}, handed)
+
+	next := make(chan int)
+	go func() {
+		i, _ := s.next()
+		next <- i
+	}()
+	s.done(2, false)
+	select {
+	case i := <-next:
+		t.Fatalf("image %d is handed out before the related images are copied", i)
+	case <-time.After(20 * time.Millisecond):
+	}
+	s.done(4, false)
+	assert.Equal(t, 5, <-next)
+
+	// the bundle of bar waits for its related images, the shared one included
+	s.done(3, false)
+	i, ok := s.next()
+	require.True(t, ok)
+	assert.Equal(t, 6, i)
+
+	stopped := make(chan bool)
+	go func() {
+		_, ok := s.next()
+		stopped <- !ok
+	}()
+	s.stop()
+	assert.True(t, <-stopped, "stop releases the waiting callers")
+	_, ok = s.next()
+	assert.False(t, ok)
+}
+
+func TestUnitSchedulerSharedCatalog(t *testing.T) {
+	s := newUnitScheduler(unitsSchema(), true, nil)
+
+	// a partial run: foo is copied, but the bundle of bar is not
+	for _, want := range []int{0, 1, 2, 4, 5, 3} {
+		i, ok := s.next()
+		require.True(t, ok)
+		require.Equal(t, want, i)
+		s.done(i, false)
+	}
+	for _, want := range []int{6, 8, 9} {
+		i, ok := s.next()
+		require.True(t, ok)
+		require.Equal(t, want, i)
+	}
+	s.done(8, false)
+	s.done(9, false)
+
+	next := make(chan int)
+	go func() {
+		i, ok := s.next()
+		if !ok {
+			i = -1
+		}
+		next <- i
+	}()
+	select {
+	case i := <-next:
+		t.Fatalf("image %d is handed out before the bundle of bar is copied", i)
+	case <-time.After(20 * time.Millisecond):
+	}
+	// the run is time-boxed before the bundle of bar is copied
+	s.stop()
+	assert.Equal(t, -1, <-next)
+
+	assert.Equal(t, []int{6, 7}, s.remaining())
+	// foo is complete with its own images, the catalog shared with bar is reported on its own
+	assert.Equal(t, []unitReport{
+		{Name: "release", Images: 2, Copied: 2, Complete: true},
+		{Name: "operator foo", Images: 3, Copied: 3, Complete: true},
+		{Name: "operator bar", Images: 3, Copied: 2},
+		{Name: "operator quay.io/acme/catalog:v1", Images: 1},
+		{Name: "helm bitnami/nginx", Images: 1, Copied: 1, Complete: true},
+		{Name: "additional registry.redhat.io/ubi9/ubi:latest", Images: 1, Copied: 1, Complete: true},
+	}, s.report())
+}
+
+func TestUnitSchedulerReport(t *testing.T) {
+	s := newUnitScheduler(unitsSchema(), true, nil)
+	drain(s, 3)
+	assert.Equal(t, []unitReport{
+		{Name: "release", Images: 2, Copied: 2, Complete: true},
+		{Name: "operator foo", Images: 3, Copied: 3, Complete: true},
+		// the failed related image only belongs to bar
+		{Name: "operator bar", Images: 3, Copied: 2},
+		{Name: "operator quay.io/acme/catalog:v1", Images: 1, Copied: 1, Complete: true},
+		{Name: "helm bitnami/nginx", Images: 1, Copied: 1, Complete: true},
+		{Name: "additional registry.redhat.io/ubi9/ubi:latest", Images: 1, Copied: 1, Complete: true},
+	}, s.report())
+}
+
+func TestValidateUnitPriority(t *testing.T) {
+	require.NoError(t, ValidateUnitPriority(nil))
+	require.NoError(t, ValidateUnitPriority([]string{"release", "operator:odf-*", "helm:bitnami/nginx", "additional"}))
+	require.EqualError(t, ValidateUnitPriority([]string{"release", "operators"}), `invalid unit priority "operators": the kind must be one of release, operator, helm, additional`)
+	require.EqualError(t, ValidateUnitPriority([]string{"operator:"}), `invalid unit priority "operator:": the name is missing`)
+}
+
+func TestWorkerScheduleUnits(t *testing.T) {
+	cs := unitsSchema()
+	cs.TotalReleaseImages = 2
+	cs.TotalOperatorImages = 6
+	cs.TotalHelmImages = 1
+	cs.TotalAdditionalImages = 1
+
+	var order []string
+	mirrorMock := new(MirrorMock)
+	mirrorMock.On("Run", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		order = append(order, args.String(1))
+	}).Return(nil)
+	opts := mirror.CopyOptions{
+		Global:        &mirror.GlobalOptions{CommandTimeout: time.Minute},
+		Mode:          mirror.MirrorToMirror,
+		Function:      string(mirror.CopyMode),
+		ScheduleUnits: true,
+		UnitPriority:  []string{"additional", "operator:bar"},
+	}
+	logsDir := t.TempDir()
+	w := New(ChannelConcurrentWorker, clog.New("debug"), logsDir, mirrorMock, 1, "20261018_100000")
+
+	copied, err := w.Worker(context.Background(), cs, opts)
+	require.NoError(t, err)
+	assert.Len(t, copied.AllImages, 10)
+	// a single image is copied at a time, the next unit is started while the bundle waits
+	assert.Equal(t, []string{
+		"docker://registry.redhat.io/ubi9/ubi:latest",
+		"docker://quay.io/acme/bar-operator:v1",
+		"docker://quay.io/acme/kube-rbac-proxy:v1",
+	}, order[:3])
+	assert.Less(t, slices.Index(order, "docker://quay.io/acme/kube-rbac-proxy:v1"), slices.Index(order, "docker://quay.io/acme/bar-bundle:v1"))
+	assert.Less(t, slices.Index(order, "docker://quay.io/acme/bar-bundle:v1"), slices.Index(order, "docker://quay.io/acme/catalog:v1"))
+
+	data, err := os.ReadFile(filepath.Join(logsDir, "mirroring_report_20261018_100000.json"))
+	require.NoError(t, err)
+	var report runReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	require.Len(t, report.Units, 6)
+	assert.Equal(t, unitReport{Name: "additional registry.redhat.io/ubi9/ubi:latest", Images: 1, Copied: 1, Complete: true}, report.Units[0])
+	for _, u := range report.Units {
+		assert.True(t, u.Complete, u.Name)
+	}
+}
@@ -0,0 +1,60 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/errcode"
+)
+
+// TimeBoxError is returned by the worker, along with the copied images, when the max
+// duration of the run is over before all the images are copied.
+type TimeBoxError struct {
+	MaxDuration time.Duration
+	// Remaining are the images left for the next run
+	Remaining []v2alpha1.CopyImageSchema
+	// BatchErr is set when some of the launched images failed
+	BatchErr *BatchError
+}
+
+func (err *TimeBoxError) Error() string {
+	msg := fmt.Sprintf("the max duration of %s is over, %d images remain", err.MaxDuration, len(err.Remaining))
+	if err.BatchErr != nil {
+		return msg + ": " + err.BatchErr.Error()
+	}
+	return msg
+}
+
+func (err *TimeBoxError) Unwrap() error {
+	if err.BatchErr == nil {
+		return nil
+	}
+	return err.BatchErr
+}
+
+func (err *TimeBoxError) ExitCode() int {
+	if err == nil {
+		return 0
+	}
+	return errcode.TimeBoxErr | err.BatchErr.ExitCode()
+}
+
+// timeBoxReport is the part of the run report about the max duration.
+type timeBoxReport struct {
+	MaxDuration string    `json:"maxDuration"`
+	Deadline    time.Time `json:"deadline"`
+	Reached     bool      `json:"reached"`
+	// Remaining are the origins of the images left for the next run
+	Remaining []string `json:"remaining,omitempty"`
+}
+
+// copyContext returns the context of the copy of an image, which ends with the image
+// timeout or with parent.
+func copyContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(parent, timeout)
+	}
+	return context.WithCancel(parent)
+}
@@ -0,0 +1,120 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/errcode"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+func TestWorkerMaxDuration(t *testing.T) {
+	var images []v2alpha1.CopyImageSchema
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		images = append(images, v2alpha1.CopyImageSchema{
+			Source:      "docker://quay.io/acme/" + name + ":latest",
+			Destination: "docker://mirror.acme.com/acme/" + name + ":latest",
+			Origin:      "docker://quay.io/acme/" + name + ":latest",
+			Type:        v2alpha1.TypeGeneric,
+		})
+	}
+	cs := v2alpha1.CollectorSchema{AllImages: images, TotalAdditionalImages: len(images)}
+
+	run := func(t *testing.T, mirrorMock *MirrorMock, grace time.Duration) (v2alpha1.CollectorSchema, runReport, error) {
+		t.Helper()
+		opts := mirror.CopyOptions{
+			Global:           &mirror.GlobalOptions{CommandTimeout: time.Minute},
+			Mode:             mirror.MirrorToMirror,
+			Function:         string(mirror.CopyMode),
+			MaxDuration:      100 * time.Millisecond,
+			MaxDurationGrace: grace,
+			Deadline:         time.Now().Add(100 * time.Millisecond),
+		}
+		logsDir := t.TempDir()
+		w := New(ChannelConcurrentWorker, clog.New("debug"), logsDir, mirrorMock, 2, "20261018_100000")
+		copied, err := w.Worker(context.Background(), cs, opts)
+
+		data, readErr := os.ReadFile(filepath.Join(logsDir, "mirroring_report_20261018_100000.json"))
+		require.NoError(t, readErr)
+		var report runReport
+		require.NoError(t, json.Unmarshal(data, &report))
+		return copied, report, err
+	}
+
+	t.Run("running copies finish within the grace period", func(t *testing.T) {
+		mirrorMock := new(MirrorMock)
+		mirrorMock.On("Run", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+			time.Sleep(40 * time.Millisecond)
+		}).Return(nil)
+
+		copied, report, err := run(t, mirrorMock, time.Minute)
+		var timeBoxErr *TimeBoxError
+		require.ErrorAs(t, err, &timeBoxErr)
+		assert.Nil(t, timeBoxErr.BatchErr)
+		assert.Equal(t, 100*time.Millisecond, timeBoxErr.MaxDuration)
+
+		// 2 images at a time, launched until 100ms
+		assert.NotEmpty(t, copied.AllImages)
+		assert.NotEmpty(t, timeBoxErr.Remaining)
+		assert.Len(t, images, len(copied.AllImages)+len(timeBoxErr.Remaining))
+		assert.Equal(t, len(copied.AllImages), copied.TotalAdditionalImages)
+		assert.Equal(t, images[len(copied.AllImages):], timeBoxErr.Remaining)
+
+		require.NotNil(t, report.TimeBox)
+		assert.True(t, report.TimeBox.Reached)
+		assert.Equal(t, "100ms", report.TimeBox.MaxDuration)
+		assert.Len(t, report.TimeBox.Remaining, len(timeBoxErr.Remaining))
+		assert.Zero(t, report.Failed)
+	})
+
+	t.Run("running copies are cancelled after the grace period", func(t *testing.T) {
+		mirrorMock := new(MirrorMock)
+		mirrorMock.On("Run", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).Return(context.DeadlineExceeded)
+
+		copied, report, err := run(t, mirrorMock, 50*time.Millisecond)
+		var timeBoxErr *TimeBoxError
+		require.ErrorAs(t, err, &timeBoxErr)
+		// the cancelled copies are not failures, they are left for the next run
+		assert.Nil(t, timeBoxErr.BatchErr)
+		assert.Empty(t, copied.AllImages)
+		assert.Equal(t, images, timeBoxErr.Remaining)
+		assert.Zero(t, report.Failed)
+	})
+
+	t.Run("run over before the max duration", func(t *testing.T) {
+		mirrorMock := new(MirrorMock)
+		mirrorMock.On("Run", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		copied, report, err := run(t, mirrorMock, time.Minute)
+		require.NoError(t, err)
+		assert.Len(t, copied.AllImages, len(images))
+		require.NotNil(t, report.TimeBox)
+		assert.False(t, report.TimeBox.Reached)
+		assert.Empty(t, report.TimeBox.Remaining)
+	})
+}
+
+func TestTimeBoxError(t *testing.T) {
+	err := &TimeBoxError{MaxDuration: 6 * time.Hour, Remaining: make([]v2alpha1.CopyImageSchema, 3)}
+	assert.EqualError(t, err, "the max duration of 6h0m0s is over, 3 images remain")
+	assert.Equal(t, errcode.TimeBoxErr, err.ExitCode())
+	assert.NoError(t, err.Unwrap())
+
+	err.BatchErr = &BatchError{source: assert.AnError, operatorCountDiff: 1}
+	assert.EqualError(t, err, "the max duration of 6h0m0s is over, 3 images remain: "+assert.AnError.Error())
+	assert.Equal(t, errcode.TimeBoxErr|errcode.OperatorErr, err.ExitCode())
+	var batchErr *BatchError
+	assert.ErrorAs(t, err, &batchErr)
+}
@@ -15,10 +15,13 @@ const (
 	dryRunOutDir              string = "dry-run"
 	mappingFile               string = "mapping.txt"
 	missingImgsFile           string = "missing.txt"
+	remainingImgsFile         string = "remaining-images.json"
 	clusterResourcesDir       string = "cluster-resources"
 	helmDir                   string = "helm"
 	helmChartDir              string = "charts"
 	helmIndexesDir            string = "indexes"
 	maxParallelLayerDownloads uint   = 5
 	maxParallelImageDownloads uint   = 4
+	maxAdaptiveParallelImages uint   = 16
+	parallelImagesUpperLimit  uint   = 32
 )
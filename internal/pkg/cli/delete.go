@@ -170,8 +170,11 @@ func (o *DeleteSchema) CompleteDelete(args []string) error {
 					Platform:         converted.Delete.Platform,
 					Operators:        converted.Delete.Operators,
 					AdditionalImages: converted.Delete.AdditionalImages,
+					Artifacts:        converted.Delete.Artifacts,
 					Helm:             converted.Delete.Helm,
+					Samples:          converted.Delete.Samples,
 				},
+				DestinationRewrites: converted.DestinationRewrites,
 			},
 		}
 		o.Config = isc
@@ -296,6 +299,12 @@ func (o *DeleteSchema) RunDelete(cmd *cobra.Command) error {
 
 func (o *DeleteSchema) generateDeleteFile(ctx context.Context) error {
 	collectorSchema, collectErr := o.CollectAll(ctx)
+	// the images are deleted from the repositories they were mirrored to
+	rewritten, err := o.rewriteDestinations(collectorSchema.AllImages)
+	if err != nil {
+		return errors.Join(collectErr, err)
+	}
+	collectorSchema.AllImages = rewritten
 
 	// It could be the case that collection finishes with errors (e.g. some
 	// images in the ISC cannot be found anymore). As long as images were
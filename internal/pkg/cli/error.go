@@ -3,6 +3,8 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/errcode"
 )
@@ -63,3 +65,52 @@ func (e *CollectionError) ExitCode() int {
 	}
 	return exitCode
 }
+
+// DestinationsError is an aggregator of the errors of a mirrorToMirror run mirroring to several destinations.
+type DestinationsError struct {
+	// CacheErr is the error pulling the images to the local cache, which affects all the destinations
+	CacheErr error
+	// Errs are the errors pushing the images, per destination
+	Errs map[string]error
+}
+
+func (e *DestinationsError) Error() string {
+	errs := []error{}
+	if e.CacheErr != nil {
+		errs = append(errs, fmt.Errorf("local cache: %w", e.CacheErr))
+	}
+	for _, dest := range slices.Sorted(maps.Keys(e.Errs)) {
+		errs = append(errs, fmt.Errorf("%s: %w", dest, e.Errs[dest]))
+	}
+	return fmt.Sprintf("destination errors: %s", errors.Join(errs...))
+}
+
+func (e *DestinationsError) ExitCode() int {
+	if e == nil {
+		return 0
+	}
+
+	exitCode := 0
+	for _, err := range append(slices.Collect(maps.Values(e.Errs)), e.CacheErr) {
+		if err != nil {
+			exitCode |= destinationExitCode(err)
+		}
+	}
+	return exitCode
+}
+
+// destinationExitCode returns the exit code of the error of a destination, which joins
+// its mirroring and cluster resources errors when both failed.
+func destinationExitCode(err error) int {
+	if exiter, ok := err.(CodeExiter); ok {
+		return exiter.ExitCode()
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		exitCode := 0
+		for _, err := range joined.Unwrap() {
+			exitCode |= destinationExitCode(err)
+		}
+		return exitCode
+	}
+	return errcode.GenericErr
+}
@@ -54,10 +54,13 @@ import (
 	"github.com/openshift/oc-mirror/v2/internal/pkg/imagebuilder"
 	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/manifest"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/metrics"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/operator"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/ratelimit"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/registriesd"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/release"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/rewrite"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/spinners"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/version"
 )
@@ -107,6 +110,9 @@ oc-mirror -c ./isc.yaml --from file:///home/<user>/oc-mirror/mirror1 docker://lo
 # Mirror To Mirror
 oc-mirror -c ./isc.yaml --workspace file:///home/<user>/oc-mirror/mirror1 docker://localhost:6000 --v2
 
+# Mirror To Mirror, to several registries
+oc-mirror -c ./isc.yaml --workspace file:///home/<user>/oc-mirror/mirror1 docker://localhost:6000 docker://localhost:7000 --v2
+
 # Delete Phase 1 (--generate)
 oc-mirror delete -c ./delete-isc.yaml --generate --workspace file:///home/<user>/oc-mirror/delete1 --delete-id delete1-test docker://localhost:6000 --v2
 
@@ -151,7 +157,9 @@ type ExecutorSchema struct {
 	MakeDir              MakeDirInterface
 	Delete               delete.DeleteInterface
 	MirrorStartTimeStamp string
-	stopRegistryOnce     sync.Once
+	// Destinations are the registries a mirrorToMirror run mirrors to, Opts.Destination being the first one
+	Destinations     []string
+	stopRegistryOnce sync.Once
 }
 
 type MakeDirInterface interface {
@@ -223,8 +231,11 @@ func NewMirrorCmd(log clog.PluggableLoggerInterface) *cobra.Command {
 			cmd.SetOutput(ex.logFile)
 
 			// NOTE: this is not in the `ex.Validate` function because it breaks unit tests
-			if strings.Contains(args[0], consts.DockerProtocol) {
-				registry := strings.TrimPrefix(args[0], consts.DockerProtocol)
+			for _, dest := range args {
+				if !strings.Contains(dest, consts.DockerProtocol) {
+					continue
+				}
+				registry := strings.TrimPrefix(dest, consts.DockerProtocol)
 				if err := ex.checkRegistryAccess(cmd.Context(), registry); err != nil {
 					return fmt.Errorf("checking registry %q access: %w", registry, err)
 				}
@@ -254,6 +265,11 @@ func NewMirrorCmd(log clog.PluggableLoggerInterface) *cobra.Command {
 	cmd.PersistentFlags().BoolVar(&opts.Global.V2, "v2", false, "Redirect the flow to oc-mirror v2")
 	cmd.PersistentFlags().UintVar(&opts.ParallelLayerImages, "parallel-layers", maxParallelLayerDownloads, "Indicates the number of image layers mirrored in parallel")
 	cmd.PersistentFlags().UintVar(&opts.ParallelImages, "parallel-images", maxParallelImageDownloads, "Indicates the number of images mirrored in parallel")
+	cmd.PersistentFlags().BoolVar(&opts.AdaptiveParallelImages, "adaptive-parallel-images", false, "Adjust the number of images mirrored in parallel from the observed throughput, errors and copy times, starting from --parallel-images")
+	cmd.PersistentFlags().UintVar(&opts.MinParallelImages, "min-parallel-images", 1, "Lower bound of the number of images mirrored in parallel, with --adaptive-parallel-images")
+	cmd.PersistentFlags().UintVar(&opts.MaxParallelImages, "max-parallel-images", maxAdaptiveParallelImages, "Upper bound of the number of images mirrored in parallel, with --adaptive-parallel-images")
+	cmd.Flags().BoolVar(&opts.ScheduleUnits, "schedule-units", false, "Mirror the images unit by unit (release, operator package, helm chart, additional image), so that interrupted runs leave complete units")
+	cmd.Flags().StringSliceVar(&opts.UnitPriority, "unit-priority", nil, "Order of the units with --schedule-units, as <kind> or <kind>:<name> entries (e.g. operator:odf-*,release)")
 	cmd.PersistentFlags().BoolVar(&opts.Global.CpuProf, "cpu-prof", false, "Enable CPU profiling")
 	cmd.PersistentFlags().BoolVar(&opts.Global.MemProf, "mem-prof", false, "Enable Memory profiling")
 	cmd.PersistentFlags().StringVar(&opts.Global.RegistriesDirPath, "registries.d", "", "use registry configuration files in `DIR` (e.g. for container signature storage)")
@@ -271,12 +287,19 @@ func NewMirrorCmd(log clog.PluggableLoggerInterface) *cobra.Command {
 	cmd.Flags().BoolVarP(&opts.Global.Force, "force", "f", false, "Force the copy and mirror functionality")
 	cmd.Flags().StringVar(&opts.Global.SinceString, "since", "", "Include all new content since specified date (format yyyy-MM-dd). When not provided, new content since previous mirroring is mirrored")
 	cmd.Flags().DurationVar(&opts.Global.CommandTimeout, "image-timeout", 10*time.Minute, "Timeout for mirroring an image")
+	cmd.Flags().DurationVar(&opts.MaxDuration, "max-duration", 0, "Time box of mirrorToDisk and mirrorToMirror runs: no image is launched once it is over, and the images mirrored so far are archived")
+	cmd.Flags().DurationVar(&opts.MaxDurationGrace, "max-duration-grace", 5*time.Minute, "Time given to the images being mirrored at the end of --max-duration to finish, before they are cancelled")
 	cmd.Flags().BoolVar(&opts.Global.SecurePolicy, "secure-policy", false, "If set, will enable signature verification (secure policy for signature verification)")
 	cmd.Flags().IntVar(&opts.Global.MaxNestedPaths, "max-nested-paths", 0, "Number of nested paths, for destination registries that limit nested paths")
 	cmd.Flags().BoolVar(&opts.Global.StrictArchiving, "strict-archive", false, "If set, generates archives that are strictly less than archiveSize (set in the imageSetConfig). Mirroring will exit in error if a file being archived exceed archiveSize(GB)")
 	cmd.Flags().StringVar(&opts.RootlessStoragePath, "rootless-storage-path", "", "Override the default container rootless storage path (usually in etc/containers/storage.conf)")
 	cmd.Flags().BoolVar(&opts.RemoveSignatures, "remove-signatures", false, "Do not copy image signature")
 	cmd.Flags().BoolVar(&opts.Global.IgnoreReleaseSignature, "ignore-release-signature", false, "Ignore release signature")
+	cmd.Flags().StringVar(&opts.Global.Limits.MaxBandwidth, "max-bandwidth", "", "Bandwidth cap of all the image copies, in bytes per second (e.g. 50Mi)")
+	cmd.Flags().StringToStringVar(&opts.Global.Limits.RegistryMaxBandwidth, "registry-max-bandwidth", nil, "Bandwidth cap of the image copies from or to a registry, in bytes per second (e.g. quay.io=20Mi)")
+	cmd.Flags().StringToIntVar(&opts.Global.Limits.RegistryMaxParallelImages, "registry-max-parallel-images", nil, "Number of images copied from or to a registry in parallel (e.g. quay.io=2)")
+	cmd.Flags().StringVar(&opts.Global.Metrics.Address, "metrics-address", "", "Address to serve the Prometheus metrics of the run on, at /metrics (e.g. :9100)")
+	cmd.Flags().StringVar(&opts.Global.Metrics.Textfile, "metrics-textfile", "", "Path of the node-exporter textfile (.prom) the Prometheus metrics of the run are written to at its end")
 	HideFlags(cmd)
 
 	ex.Opts.Stdout = cmd.OutOrStdout()
@@ -381,6 +404,17 @@ func (o *ExecutorSchema) Validate(dest []string) error { //nolint:cyclop // pre-
 	if len(o.Opts.Global.ConfigPath) == 0 {
 		return fmt.Errorf("use the --config flag it is mandatory")
 	}
+	if len(dest) > 1 {
+		if err := o.validateDestinations(dest); err != nil {
+			return err
+		}
+	}
+	if err := o.Opts.Global.Limits.Validate(); err != nil {
+		return err
+	}
+	if err := o.Opts.Global.Metrics.Validate(); err != nil {
+		return err
+	}
 	if strings.Contains(dest[0], consts.FileProtocol) && o.Opts.Global.From != "" {
 		return fmt.Errorf("when destination is file://, mirrorToDisk workflow is assumed, and the --from argument is not needed")
 	}
@@ -421,6 +455,26 @@ func (o *ExecutorSchema) Validate(dest []string) error { //nolint:cyclop // pre-
 	if o.Opts.ParallelLayerImages > 10 || o.Opts.ParallelLayerImages < 1 {
 		return fmt.Errorf("the flag parallel-layers must be between the range 1 to 10")
 	}
+	if len(o.Opts.UnitPriority) > 0 && !o.Opts.ScheduleUnits {
+		return fmt.Errorf("--unit-priority can only be used with --schedule-units")
+	}
+	if err := batch.ValidateUnitPriority(o.Opts.UnitPriority); err != nil {
+		return err
+	}
+	if o.Opts.MaxDuration < 0 || o.Opts.MaxDurationGrace < 0 {
+		return fmt.Errorf("the flags max-duration and max-duration-grace must not be negative")
+	}
+	if o.Opts.MaxDuration > 0 && o.Opts.Global.From != "" {
+		return fmt.Errorf("--max-duration can only be used in the mirrorToDisk and mirrorToMirror workflows")
+	}
+	if o.Opts.AdaptiveParallelImages {
+		if o.Opts.MinParallelImages < 1 || o.Opts.MaxParallelImages > parallelImagesUpperLimit || o.Opts.MinParallelImages > o.Opts.MaxParallelImages {
+			return fmt.Errorf("the flags min-parallel-images and max-parallel-images must be between the range 1 to %d, min-parallel-images not above max-parallel-images", parallelImagesUpperLimit)
+		}
+		if o.Opts.ParallelImages < o.Opts.MinParallelImages || o.Opts.ParallelImages > o.Opts.MaxParallelImages {
+			return fmt.Errorf("the flag parallel-images must be between min-parallel-images and max-parallel-images with --adaptive-parallel-images")
+		}
+	}
 	if strings.Contains(dest[0], consts.FileProtocol) && o.Opts.Global.WorkingDir != "" {
 		return fmt.Errorf("when destination is file://, mirrorToDisk workflow is assumed, and the --workspace argument is not needed")
 	}
@@ -541,6 +595,7 @@ func (o *ExecutorSchema) Complete(args []string) error {
 			return fmt.Errorf("mirror to mirror workflow detected. --workspace is mandatory to provide in the command arguments")
 		}
 		o.Opts.Global.WorkingDir = strings.TrimPrefix(o.Opts.Global.WorkingDir, consts.FileProtocol)
+		o.Destinations = args
 	default:
 		o.Log.Error("unable to determine the mode (the destination must be either file:// or docker://)")
 	}
@@ -577,6 +632,11 @@ func (o *ExecutorSchema) Complete(args []string) error {
 		return fmt.Errorf("%d is already bound and cannot be used", o.Opts.Global.Port)
 	}
 	o.Opts.LocalStorageFQDN = "localhost:" + strconv.Itoa(int(o.Opts.Global.Port))
+	o.Opts.Limiter, err = ratelimit.New(o.Opts.Global.Limits, o.Opts.LocalStorageFQDN)
+	if err != nil {
+		return err
+	}
+	o.Opts.Metrics = metrics.New(o.Opts.Global.Metrics, o.Opts.LocalStorageFQDN)
 
 	err = o.setupWorkingDir()
 	if err != nil {
@@ -590,6 +650,15 @@ func (o *ExecutorSchema) Complete(args []string) error {
 
 	client, _ := release.NewOCPClient(uuid.New(), o.Log)
 
+	o.ImageBuilder = imagebuilder.NewBuilder(o.Log, *o.Opts)
+	o.CatalogBuilder = imagebuilder.NewGCRCatalogBuilder(o.Log, *o.Opts)
+
+	// catalogs built from FBC templates are then handled as catalogs on disk
+	o.Config, err = operator.BuildTemplateCatalogs(context.Background(), o.Log, o.Config, *o.Opts, o.CatalogBuilder)
+	if err != nil {
+		return err
+	}
+
 	// OCPBUGS-81712: Pin all operator catalogs to digest before initializing collectors
 	// This prevents race conditions in M2D/M2M where catalog tags might change during execution
 	// Best-effort approach: failures are logged as warnings, invalid catalogs handled during collection
@@ -598,8 +667,6 @@ func (o *ExecutorSchema) Complete(args []string) error {
 		o.Config = config.PinCatalogDigests(context.Background(), o.Config, o.Manifest, o.Opts, o.Log)
 	}
 
-	o.ImageBuilder = imagebuilder.NewBuilder(o.Log, *o.Opts)
-	o.CatalogBuilder = imagebuilder.NewGCRCatalogBuilder(o.Log, *o.Opts)
 	signature := release.NewSignatureClient(o.Log, o.Config, *o.Opts)
 	cn := release.NewCincinnati(o.Log, o.Manifest, &o.Config, *o.Opts, client, false, signature)
 	o.Release = release.New(o.Log, o.LogsDir, o.Config, *o.Opts, o.Mirror, o.Manifest, cn, o.ImageBuilder)
@@ -631,6 +698,12 @@ func (o *ExecutorSchema) Run(cmd *cobra.Command, args []string) error {
 	var err error
 
 	startTime := time.Now()
+	if o.Opts.MaxDuration > 0 {
+		o.Opts.Deadline = startTime.Add(o.Opts.MaxDuration)
+	}
+	if err := o.Opts.Metrics.Start(); err != nil {
+		return err
+	}
 	go o.startLocalRegistry()
 
 	switch {
@@ -643,6 +716,7 @@ func (o *ExecutorSchema) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	o.stopLocalRegistry(cmd.Context())
+	o.stopMetrics(cmd.Context())
 
 	o.Log.Info("mirror time     : %v", time.Since(startTime))
 	o.Log.Info(emoji.WavingHandSign + " Goodbye, thank you for using oc-mirror")
@@ -675,6 +749,11 @@ http:
   addr: :{{ .LocalStoragePort }}
   headers:
     X-Content-Type-Options: [nosniff]
+{{- if .Metrics }}
+  debug:
+    prometheus:
+      enabled: true
+{{- end }}
       #auth:
       #htpasswd:
       #realm: basic-realm
@@ -691,6 +770,7 @@ validation:
 		LocalStoragePort int
 		LogLevel         string
 		LogAccessOff     bool
+		Metrics          bool
 	}
 
 	rc := RegistryConfig{
@@ -698,6 +778,8 @@ validation:
 		LocalStoragePort: int(o.Opts.Global.Port),
 		LogLevel:         o.Opts.Global.LogLevel,
 		LogAccessOff:     true,
+		// the statistics of the registry are exposed with the metrics of the run
+		Metrics: o.Opts.Metrics != nil,
 	}
 
 	if o.Opts.Global.LogLevel == "debug" || o.Opts.Global.LogLevel == "trace" {
@@ -784,6 +866,16 @@ func (o *ExecutorSchema) stopLocalRegistry(ctx context.Context) {
 	})
 }
 
+// stopMetrics - stops serving the metrics of the run, and writes them
+// to the node-exporter textfile
+func (o *ExecutorSchema) stopMetrics(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := o.Opts.Metrics.Stop(ctx); err != nil {
+		o.Log.Warn("Metrics failure: %v", err)
+	}
+}
+
 // isLocalStoragePortBound - private utility to check if port is bound
 func (o *ExecutorSchema) isLocalStoragePortBound() bool {
 	// Check if the port is already bound
@@ -926,11 +1018,16 @@ func (o *ExecutorSchema) RunMirrorToDisk(cmd *cobra.Command, args []string) erro
 		}
 	}
 
-	if batchError != nil {
+	// a time-boxed run still archives the images mirrored so far, unless some of them failed
+	var timeBoxErr *batch.TimeBoxError
+	if batchError != nil && (!errors.As(batchError, &timeBoxErr) || timeBoxErr.BatchErr != nil) {
 		return batchError
 	}
+	if err := o.saveRemainingImages(timeBoxErr); err != nil {
+		return err
+	}
 
-	o.createConfigsWithPinnedCatalogs()
+	o.createConfigsWithPinnedCatalogs(collectorSchema)
 
 	if err := version.WriteVersionMetadata(o.Opts.Global.WorkingDir, version.Get()); err != nil {
 		o.Log.Warn("unable to write oc-mirror version metadata: %v", err)
@@ -941,9 +1038,12 @@ func (o *ExecutorSchema) RunMirrorToDisk(cmd *cobra.Command, args []string) erro
 	o.Log.Info(emoji.Package + " Preparing the tarball archive...")
 	// The registry is stopped via callback once BuildArchive no longer needs it (see
 	// BuildArchive's doc comment), and before it archives working-dir/logs/registry-*.log.
-	return o.MirrorArchiver.BuildArchive(cmd.Context(), copiedSchema, func() {
+	if err := o.MirrorArchiver.BuildArchive(cmd.Context(), copiedSchema, func() {
 		o.stopLocalRegistry(cmd.Context())
-	})
+	}); err != nil {
+		return err
+	}
+	return batchError
 }
 
 // RunMirrorToMirror - execute the mirror to mirror functionality
@@ -965,6 +1065,10 @@ func (o *ExecutorSchema) RunMirrorToMirror(cmd *cobra.Command, args []string) er
 		}
 	}
 
+	if collectorSchema.AllImages, err = o.rewriteDestinations(collectorSchema.AllImages); err != nil {
+		return err
+	}
+
 	// Apply max-nested-paths processing if MaxNestedPaths>0
 	if o.Opts.Global.MaxNestedPaths > 0 {
 		collectorSchema.AllImages, err = withMaxNestedPaths(collectorSchema.AllImages, o.Opts.Global.MaxNestedPaths)
@@ -984,13 +1088,17 @@ func (o *ExecutorSchema) RunMirrorToMirror(cmd *cobra.Command, args []string) er
 	// OCPBUGS-52470
 	operator.TagRebuiltCatalogByDigestOnly(&collectorSchema, o.Opts.LocalStorageFQDN, o.Opts.Global.WorkingDir)
 
+	if len(o.Destinations) > 1 {
+		return o.fanOut(cmd.Context(), collectorSchema)
+	}
+
 	// call the batch worker
 	// NOTE: we will check for batch errors at the end
 	copiedSchema, batchError := o.Batch.Worker(cmd.Context(), collectorSchema, *o.Opts)
 
-	o.createConfigsWithPinnedCatalogs()
+	o.createConfigsWithPinnedCatalogs(collectorSchema)
 
-	if err := o.generateClusterResources(cmd.Context(), copiedSchema.AllImages); err != nil {
+	if err := o.generateClusterResources(cmd.Context(), copiedSchema.AllImages, collectorSchema.CatalogToFBCMap); err != nil {
 		return err
 	}
 
@@ -1029,6 +1137,10 @@ func (o *ExecutorSchema) RunDiskToMirror(cmd *cobra.Command, args []string) erro
 		}
 	}
 
+	if collectorSchema.AllImages, err = o.rewriteDestinations(collectorSchema.AllImages); err != nil {
+		return err
+	}
+
 	// apply max-nested-paths processing if MaxNestedPaths>0
 	if o.Opts.Global.MaxNestedPaths > 0 {
 		collectorSchema.AllImages, err = withMaxNestedPaths(collectorSchema.AllImages, o.Opts.Global.MaxNestedPaths)
@@ -1037,6 +1149,10 @@ func (o *ExecutorSchema) RunDiskToMirror(cmd *cobra.Command, args []string) erro
 		}
 	}
 
+	if collectorSchema.AllImages, err = o.skipRemainingImages(collectorSchema.AllImages); err != nil {
+		return err
+	}
+
 	if o.Opts.IsDryRun {
 		return o.DryRun(cmd.Context(), collectorSchema.AllImages)
 	}
@@ -1045,7 +1161,7 @@ func (o *ExecutorSchema) RunDiskToMirror(cmd *cobra.Command, args []string) erro
 	// NOTE: we will check for batch errors at the end
 	copiedSchema, batchError := o.Batch.Worker(cmd.Context(), collectorSchema, *o.Opts)
 
-	if err := o.generateClusterResources(cmd.Context(), copiedSchema.AllImages); err != nil {
+	if err := o.generateClusterResources(cmd.Context(), copiedSchema.AllImages, collectorSchema.CatalogToFBCMap); err != nil {
 		return err
 	}
 
@@ -1053,26 +1169,40 @@ func (o *ExecutorSchema) RunDiskToMirror(cmd *cobra.Command, args []string) erro
 }
 
 // generateClusterResources generates the following cluster resources:
-// IDMS/ITMS, CatalogSource, ClusterCatalog, UpdateService and SignatureConfigMap.
-func (o *ExecutorSchema) generateClusterResources(ctx context.Context, images []v2alpha1.CopyImageSchema) error {
-	if o.ClusterResources == nil {
+// IDMS/ITMS, CatalogSource, ClusterCatalog, operator install manifests, samples Config, UpdateService and SignatureConfigMap.
+// catalogToFBC holds the filtered catalogs the install manifests are generated from, when available.
+func (o *ExecutorSchema) generateClusterResources(ctx context.Context, images []v2alpha1.CopyImageSchema, catalogToFBC map[string]v2alpha1.CatalogFilterResult) error {
+	return o.generateDestinationResources(ctx, o.ClusterResources, o.Opts.Destination, images, catalogToFBC)
+}
+
+// generateDestinationResources generates the cluster resources of the images mirrored to destination with generator.
+func (o *ExecutorSchema) generateDestinationResources(ctx context.Context, generator clusterresources.GeneratorInterface, destination string, images []v2alpha1.CopyImageSchema, catalogToFBC map[string]v2alpha1.CatalogFilterResult) error {
+	if generator == nil {
 		return fmt.Errorf("cluster resources generator is not initialized")
 	}
 	o.Log.Info(emoji.PageFacingUp + " Generating cluster resources...")
 
-	if err := o.generateImageMirrorSet(images); err != nil {
+	if err := o.generateImageMirrorSet(generator, images); err != nil {
 		return err
 	}
 
-	if err := o.generateCatalogResources(images); err != nil {
+	if err := generateCatalogResources(generator, images); err != nil {
 		return err
 	}
 
-	if err := o.ClusterResources.GenerateSignatureConfigMap(images); err != nil {
+	if err := generator.InstallManifestsGenerator(images, catalogToFBC); err != nil {
+		return err
+	}
+
+	if err := generator.SamplesConfigGenerator(images, o.Release.SkippedImageStreams()); err != nil {
+		return err
+	}
+
+	if err := generator.GenerateSignatureConfigMap(images); err != nil {
 		o.Log.Warn("Failed to generate signature ConfigMap: %v", err)
 	}
 
-	if err := o.generateUpdateServiceResource(ctx); err != nil {
+	if err := o.generateUpdateServiceResource(ctx, generator, destination); err != nil {
 		return err
 	}
 
@@ -1080,26 +1210,27 @@ func (o *ExecutorSchema) generateClusterResources(ctx context.Context, images []
 }
 
 // generateImageMirrorSet generates IDMS/ITMS resources
-func (o *ExecutorSchema) generateImageMirrorSet(images []v2alpha1.CopyImageSchema) error {
-	forceRepositoryScope := o.Opts.Global.MaxNestedPaths > 0
-	if err := o.ClusterResources.IDMS_ITMSGenerator(images, forceRepositoryScope); err != nil {
+func (o *ExecutorSchema) generateImageMirrorSet(generator clusterresources.GeneratorInterface, images []v2alpha1.CopyImageSchema) error {
+	// rewritten paths don't preserve the namespaces of the sources
+	forceRepositoryScope := o.Opts.Global.MaxNestedPaths > 0 || len(o.Config.DestinationRewrites) > 0
+	if err := generator.IDMS_ITMSGenerator(images, forceRepositoryScope); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (o *ExecutorSchema) generateCatalogResources(images []v2alpha1.CopyImageSchema) error {
-	if err := o.ClusterResources.CatalogSourceGenerator(images); err != nil {
+func generateCatalogResources(generator clusterresources.GeneratorInterface, images []v2alpha1.CopyImageSchema) error {
+	if err := generator.CatalogSourceGenerator(images); err != nil {
 		return err
 	}
 
-	if err := o.ClusterResources.ClusterCatalogGenerator(images); err != nil {
+	if err := generator.ClusterCatalogGenerator(images); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (o *ExecutorSchema) generateUpdateServiceResource(ctx context.Context) error {
+func (o *ExecutorSchema) generateUpdateServiceResource(ctx context.Context, generator clusterresources.GeneratorInterface, destination string) error {
 	if !o.Config.Mirror.Platform.Graph {
 		return nil
 	}
@@ -1114,7 +1245,11 @@ func (o *ExecutorSchema) generateUpdateServiceResource(ctx context.Context) erro
 		return err
 	}
 
-	if err := o.ClusterResources.UpdateServiceGenerator(graphImage, releaseImage); err != nil {
+	// the release collector references the images pushed to the first destination
+	graphImage = rebaseDestination(graphImage, o.Opts.Destination, destination)
+	releaseImage = rebaseDestination(releaseImage, o.Opts.Destination, destination)
+
+	if err := generator.UpdateServiceGenerator(graphImage, releaseImage); err != nil {
 		return err
 	}
 
@@ -1170,6 +1305,7 @@ func (o *ExecutorSchema) CollectAll(ctx context.Context) (v2alpha1.CollectorSche
 	collectorSchema.PlatformFilters = make(map[string][]v2alpha1.InstancePlatformFilter)
 
 	// collect releases
+	collectorStart := time.Now()
 	releaseCS, err := o.Release.ReleaseImageCollector(ctx)
 	if err != nil {
 		if !o.Opts.IsDelete() {
@@ -1181,6 +1317,7 @@ func (o *ExecutorSchema) CollectAll(ctx context.Context) (v2alpha1.CollectorSche
 	releaseImgs := excludeImages(releaseCS.AllImages, o.Config.Mirror.BlockedImages)
 	releaseImgs = removeDuplicatedImages(releaseImgs, o.Opts.Function)
 	collectorSchema.TotalReleaseImages = len(releaseImgs)
+	o.Opts.Metrics.ObserveCollection(batch.UnitRelease, len(releaseImgs), time.Since(collectorStart))
 	o.Log.Debug(collecAllPrefix+"total release images to %s %d ", o.Opts.Function, collectorSchema.TotalReleaseImages)
 	allRelatedImages = append(allRelatedImages, releaseImgs...)
 	mergePlatformFilters(collectorSchema.PlatformFilters, releaseCS.PlatformFilters)
@@ -1189,6 +1326,7 @@ func (o *ExecutorSchema) CollectAll(ctx context.Context) (v2alpha1.CollectorSche
 		o.Log.Info(emoji.LeftPointingMagnifyingGlass + " collecting operator images...")
 	}
 	// collect operators
+	collectorStart = time.Now()
 	operatorImgs, err := o.Operator.OperatorImageCollector(ctx)
 	if err != nil {
 		operatorErr = err
@@ -1196,6 +1334,7 @@ func (o *ExecutorSchema) CollectAll(ctx context.Context) (v2alpha1.CollectorSche
 		oImgs := excludeImages(operatorImgs.AllImages, o.Config.Mirror.BlockedImages)
 		oImgs = removeDuplicatedImages(oImgs, o.Opts.Function)
 		collectorSchema.TotalOperatorImages = len(oImgs)
+		o.Opts.Metrics.ObserveCollection(batch.UnitOperator, len(oImgs), time.Since(collectorStart))
 		o.Log.Debug(collecAllPrefix+"total operator images to %s %d ", o.Opts.Function, collectorSchema.TotalOperatorImages)
 		allRelatedImages = append(allRelatedImages, oImgs...)
 		collectorSchema.CopyImageSchemaMap = operatorImgs.CopyImageSchemaMap
@@ -1204,10 +1343,11 @@ func (o *ExecutorSchema) CollectAll(ctx context.Context) (v2alpha1.CollectorSche
 		mergePlatformFilters(collectorSchema.PlatformFilters, operatorImgs.PlatformFilters)
 	}
 
-	if len(o.Config.Mirror.AdditionalImages) > 0 {
+	if len(o.Config.Mirror.AdditionalImages) > 0 || len(o.Config.Mirror.Artifacts) > 0 {
 		o.Log.Info(emoji.LeftPointingMagnifyingGlass + " collecting additional images...")
 	}
 	// collect additionalImages
+	collectorStart = time.Now()
 	additionalCS, err := o.AdditionalImages.AdditionalImagesCollector(ctx)
 	if err != nil {
 		additionalImgErr = err
@@ -1215,13 +1355,16 @@ func (o *ExecutorSchema) CollectAll(ctx context.Context) (v2alpha1.CollectorSche
 	aImgs := excludeImages(additionalCS.AllImages, o.Config.Mirror.BlockedImages)
 	aImgs = removeDuplicatedImages(aImgs, o.Opts.Function)
 	collectorSchema.TotalAdditionalImages = len(aImgs)
+	o.Opts.Metrics.ObserveCollection(batch.UnitAdditional, len(aImgs), time.Since(collectorStart))
 	o.Log.Debug(collecAllPrefix+"total additional images to %s %d ", o.Opts.Function, collectorSchema.TotalAdditionalImages)
 	allRelatedImages = append(allRelatedImages, aImgs...)
 	mergePlatformFilters(collectorSchema.PlatformFilters, additionalCS.PlatformFilters)
+	collectorSchema.ResolvedAdditionalImages = additionalCS.ResolvedAdditionalImages
 
 	if len(o.Config.Mirror.Helm.Repositories) > 0 || len(o.Config.Mirror.Helm.Local) > 0 {
 		o.Log.Info(emoji.LeftPointingMagnifyingGlass + " collecting helm images...")
 	}
+	collectorStart = time.Now()
 	helmCS, err := o.HelmCollector.HelmImageCollector(ctx)
 	if err != nil {
 		helmErr = err
@@ -1229,14 +1372,17 @@ func (o *ExecutorSchema) CollectAll(ctx context.Context) (v2alpha1.CollectorSche
 		hImgs := excludeImages(helmCS.AllImages, o.Config.Mirror.BlockedImages)
 		hImgs = removeDuplicatedImages(hImgs, o.Opts.Function)
 		collectorSchema.TotalHelmImages = len(hImgs)
+		o.Opts.Metrics.ObserveCollection(batch.UnitHelm, len(hImgs), time.Since(collectorStart))
 		o.Log.Debug(collecAllPrefix+"total helm images to %s %d ", o.Opts.Function, collectorSchema.TotalHelmImages)
 		allRelatedImages = append(allRelatedImages, hImgs...)
 		mergePlatformFilters(collectorSchema.PlatformFilters, helmCS.PlatformFilters)
+		collectorSchema.CopyImageSchemaMap.ChartsByImage = helmCS.CopyImageSchemaMap.ChartsByImage
 	}
 
 	sort.Sort(customsort.ByTypePriority(allRelatedImages))
 
 	collectorSchema.AllImages = allRelatedImages
+	o.Opts.Metrics.SetImages(allRelatedImages)
 
 	o.Log.Debug("collection time     : %v", time.Since(startTime))
 
@@ -1315,7 +1461,11 @@ func (o *ExecutorSchema) RebuildCatalogs(ctx context.Context, operatorImgs v2alp
 					spinner.Abort(false)
 					return fmt.Errorf("unable to rebuild catalog %s: filtered declarative config not found", copyImage.Origin)
 				}
-				err = o.CatalogBuilder.RebuildCatalog(ctx, copyImage, filteredConfigPath)
+				rebuildOpts := imagebuilder.CatalogRebuildOptions{
+					BaseImage:  ctlgFilterResult.OperatorFilter.TargetCatalogBaseImage,
+					ServeCache: ctlgFilterResult.OperatorFilter.GenerateServeCache,
+				}
+				err = o.CatalogBuilder.RebuildCatalog(ctx, copyImage, filteredConfigPath, rebuildOpts)
 				if err != nil {
 					spinner.Abort(false)
 					return fmt.Errorf("unable to rebuild catalog %s: %v", copyImage.Origin, err)
@@ -1328,6 +1478,19 @@ func (o *ExecutorSchema) RebuildCatalogs(ctx context.Context, operatorImgs v2alp
 	return nil
 }
 
+// rewriteDestinations applies the destination rewrite rules of the ImageSetConfiguration
+// to the images mirrored to the destination registry.
+func (o *ExecutorSchema) rewriteDestinations(images []v2alpha1.CopyImageSchema) ([]v2alpha1.CopyImageSchema, error) {
+	if len(o.Config.DestinationRewrites) == 0 {
+		return images, nil
+	}
+	rewriter, err := rewrite.New(o.Config.DestinationRewrites)
+	if err != nil {
+		return nil, err
+	}
+	return rewriter.Destinations(images, o.Opts.Destination)
+}
+
 func withMaxNestedPaths(in []v2alpha1.CopyImageSchema, maxNestedPaths int) ([]v2alpha1.CopyImageSchema, error) {
 	out := []v2alpha1.CopyImageSchema{}
 	for _, img := range in {
@@ -1437,11 +1600,13 @@ func removeDuplicatedImages(allRelatedImages []v2alpha1.CopyImageSchema, mode st
 }
 
 // createConfigsWithPinnedCatalogs generates and writes pinned ISC and DISC configurations.
-// Catalogs are already pinned by pinOperatorCatalogs() at workflow start for M2D/M2M modes.
-func (o *ExecutorSchema) createConfigsWithPinnedCatalogs() {
+// Catalogs are already pinned by pinOperatorCatalogs() at workflow start for M2D/M2M modes,
+// the package selections and the additional images resolved during the collection are pinned here.
+func (o *ExecutorSchema) createConfigsWithPinnedCatalogs(collectorSchema v2alpha1.CollectorSchema) {
 	o.Log.Info("Generating pinned configurations...")
+	pinnedCfg := config.PinOperatorSelections(o.Config, collectorSchema.CatalogToFBCMap)
 	iscPath, discPath, err := config.WriteISCAndDSC(
-		o.Config,
+		config.PinAdditionalImages(pinnedCfg, collectorSchema.ResolvedAdditionalImages),
 		o.Opts,
 		o.Log,
 	)
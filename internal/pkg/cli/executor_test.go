@@ -21,10 +21,13 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/batch"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/config"
 	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/metrics"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
 )
 
@@ -123,6 +126,42 @@ func TestExecutorMirroring(t *testing.T) {
 		}
 	})
 
+	t.Run("Testing Executor : mirrorToDisk stopped by its max duration should archive the copied images", func(t *testing.T) {
+		collector := &Collector{Log: log, Config: cfg, Opts: *opts, Fail: false}
+		timeBoxBatch := &Batch{Log: log, Config: cfg, Opts: *opts, TimeBox: true}
+		archiver := &RecordingArchiver{}
+
+		timeBoxOpts := *opts
+		timeBoxOpts.MaxDuration = 6 * time.Hour
+		ex := &ExecutorSchema{
+			Log:                 log,
+			Config:              cfg,
+			Opts:                &timeBoxOpts,
+			Operator:            collector,
+			Release:             collector,
+			AdditionalImages:    collector,
+			HelmCollector:       collector,
+			Batch:               timeBoxBatch,
+			MirrorArchiver:      archiver,
+			LocalStorageService: *reg,
+			MakeDir:             MakeDir{},
+			LogsDir:             "/tmp/",
+			ClusterResources:    cr,
+		}
+		defer os.Remove(filepath.Join(workDir, remainingImgsFile))
+
+		res := &cobra.Command{}
+		res.SetContext(context.Background())
+		res.SilenceUsage = true
+		ex.Opts.Mode = mirror.MirrorToDisk
+		err := ex.Run(res, []string{consts.FileProtocol + testFolder})
+		var timeBoxErr *batch.TimeBoxError
+		require.ErrorAs(t, err, &timeBoxErr)
+		assert.False(t, ex.Opts.Deadline.IsZero())
+		require.Len(t, archiver.archived, 1)
+		assert.FileExists(t, filepath.Join(workDir, remainingImgsFile))
+	})
+
 	t.Run("Testing Executor : mirrorToDisk --dry-run should pass", func(t *testing.T) {
 		opts.IsDryRun = true
 
@@ -482,6 +521,9 @@ func TestExecutorValidate(t *testing.T) {
 		opts.Global.From = "" // reset
 		opts.Global.WorkingDir = consts.FileProtocol + "test"
 		assert.NoError(t, ex.Validate([]string{consts.DockerProtocol + "test"}))
+
+		// should be able to run mirror-to-mirror to several destinations
+		assert.NoError(t, ex.Validate([]string{consts.DockerProtocol + "test", consts.DockerProtocol + "test2:5000/ocp"}))
 	})
 
 	t.Run("Testing Executor : validate should fail", func(t *testing.T) {
@@ -536,6 +578,52 @@ func TestExecutorValidate(t *testing.T) {
 		opts.ParallelImages = 5
 		opts.ParallelLayerImages = 4
 
+		// check the bounds of the adaptive parallel images
+		opts.AdaptiveParallelImages = true
+		opts.MinParallelImages = 2
+		opts.MaxParallelImages = 33
+		err = ex.Validate([]string{consts.FileProtocol + "test"})
+		assert.EqualError(t, err, "the flags min-parallel-images and max-parallel-images must be between the range 1 to 32, min-parallel-images not above max-parallel-images")
+
+		opts.MaxParallelImages = 1
+		err = ex.Validate([]string{consts.FileProtocol + "test"})
+		assert.EqualError(t, err, "the flags min-parallel-images and max-parallel-images must be between the range 1 to 32, min-parallel-images not above max-parallel-images")
+
+		opts.MaxParallelImages = 4
+		err = ex.Validate([]string{consts.FileProtocol + "test"})
+		assert.EqualError(t, err, "the flag parallel-images must be between min-parallel-images and max-parallel-images with --adaptive-parallel-images")
+		opts.AdaptiveParallelImages = false
+
+		// check the unit priority
+		opts.UnitPriority = []string{"operator:odf-*", "release"}
+		err = ex.Validate([]string{consts.FileProtocol + "test"})
+		assert.EqualError(t, err, "--unit-priority can only be used with --schedule-units")
+
+		opts.ScheduleUnits = true
+		opts.UnitPriority = []string{"operators"}
+		err = ex.Validate([]string{consts.FileProtocol + "test"})
+		assert.EqualError(t, err, `invalid unit priority "operators": the kind must be one of release, operator, helm, additional`)
+		opts.ScheduleUnits = false
+		opts.UnitPriority = nil
+
+		// check the max duration
+		opts.MaxDuration = -time.Hour
+		err = ex.Validate([]string{consts.FileProtocol + "test"})
+		assert.EqualError(t, err, "the flags max-duration and max-duration-grace must not be negative")
+
+		opts.MaxDuration = 6 * time.Hour
+		opts.Global.From = consts.FileProtocol + "test"
+		err = ex.Validate([]string{consts.DockerProtocol + "test"})
+		assert.EqualError(t, err, "--max-duration can only be used in the mirrorToDisk and mirrorToMirror workflows")
+		opts.MaxDuration = 0
+		opts.Global.From = ""
+
+		// check the metrics outputs
+		opts.Global.Metrics.Textfile = "/var/lib/node_exporter/oc-mirror.txt"
+		err = ex.Validate([]string{consts.FileProtocol + "test"})
+		assert.EqualError(t, err, `invalid --metrics-textfile "/var/lib/node_exporter/oc-mirror.txt": the node-exporter textfile collector only reads .prom files`)
+		opts.Global.Metrics.Textfile = ""
+
 		// check for config path error
 		opts.Global.ConfigPath = ""
 		err = ex.Validate([]string{consts.FileProtocol + "test"})
@@ -587,6 +675,23 @@ func TestExecutorValidate(t *testing.T) {
 		opts.Global.WorkingDir = "" // reset
 		err = ex.Validate([]string{consts.DockerProtocol + "test"})
 		assert.EqualError(t, err, "when destination is docker://, either --from (assumes disk to mirror workflow) or --workspace (assumes mirror to mirror workflow) need to be provided")
+
+		// several destinations are only supported by mirror-to-mirror
+		err = ex.Validate([]string{consts.DockerProtocol + "test", consts.DockerProtocol + "test2"})
+		assert.EqualError(t, err, "several destinations can only be set in the mirror to mirror workflow (with --workspace)")
+
+		opts.Global.WorkingDir = consts.FileProtocol + "test"
+		err = ex.Validate([]string{consts.DockerProtocol + "test", consts.FileProtocol + "test2"})
+		assert.EqualError(t, err, "destination file://test2: all the destinations must have the docker:// prefix")
+
+		err = ex.Validate([]string{consts.DockerProtocol + "test", consts.DockerProtocol + "test/"})
+		assert.EqualError(t, err, "destination docker://test/ is set more than once")
+
+		opts.IsDryRun = true
+		err = ex.Validate([]string{consts.DockerProtocol + "test", consts.DockerProtocol + "test2"})
+		assert.EqualError(t, err, "--dry-run cannot be used with several destinations")
+		opts.IsDryRun = false
+		opts.Global.WorkingDir = "" // reset
 	})
 }
 
@@ -703,6 +808,25 @@ func TestExecutorSetupLocalStorage(t *testing.T) {
 	})
 }
 
+func TestExecutorSetupLocalRegistryConfig(t *testing.T) {
+	ex := &ExecutorSchema{
+		Log:              clog.New("info"),
+		Opts:             &mirror.CopyOptions{Global: &mirror.GlobalOptions{Port: 7777, LogLevel: "info"}},
+		LocalStorageDisk: consts.TestFolder + "cache-fake",
+	}
+	config, err := ex.setupLocalRegistryConfig()
+	require.NoError(t, err)
+	assert.Equal(t, ":7777", config.HTTP.Addr)
+	assert.False(t, config.HTTP.Debug.Prometheus.Enabled)
+
+	// the statistics of the local cache are exposed with the metrics of the run
+	ex.Opts.Metrics = metrics.New(metrics.Options{Address: ":9100"}, "localhost:7777")
+	config, err = ex.setupLocalRegistryConfig()
+	require.NoError(t, err)
+	assert.True(t, config.HTTP.Debug.Prometheus.Enabled)
+	assert.Empty(t, config.HTTP.Debug.Addr)
+}
+
 func TestExecutorEnvironmentSetup(t *testing.T) {
 	const logLevel string = "debug"
 
@@ -1143,6 +1267,8 @@ type Batch struct {
 	Config v2alpha1.ImageSetConfiguration
 	Opts   mirror.CopyOptions
 	Fail   bool
+	// TimeBox makes the worker stop after the first image, as when the max duration is over
+	TimeBox bool
 }
 
 type Diff struct {
@@ -1157,6 +1283,19 @@ type MockArchiver struct {
 	destination string
 }
 
+// RecordingArchiver records the images it archives.
+type RecordingArchiver struct {
+	archived []v2alpha1.CopyImageSchema
+}
+
+func (o *RecordingArchiver) BuildArchive(ctx context.Context, schema v2alpha1.CollectorSchema, onBlobsGathered func()) error {
+	o.archived = schema.AllImages
+	if onBlobsGathered != nil {
+		onBlobsGathered()
+	}
+	return nil
+}
+
 type MockMirrorUnArchiver struct {
 	Fail bool
 }
@@ -1217,6 +1356,14 @@ func (o MockClusterResources) ClusterCatalogGenerator(allRelatedImages []v2alpha
 	return nil
 }
 
+func (o MockClusterResources) InstallManifestsGenerator(allRelatedImages []v2alpha1.CopyImageSchema, catalogToFBC map[string]v2alpha1.CatalogFilterResult) error {
+	return nil
+}
+
+func (o MockClusterResources) SamplesConfigGenerator(allRelatedImages []v2alpha1.CopyImageSchema, skippedImageStreams []string) error {
+	return nil
+}
+
 func (o Batch) Worker(ctx context.Context, collectorSchema v2alpha1.CollectorSchema, opts mirror.CopyOptions) (v2alpha1.CollectorSchema, error) {
 	copiedImages := v2alpha1.CollectorSchema{
 		AllImages:             []v2alpha1.CopyImageSchema{},
@@ -1227,6 +1374,10 @@ func (o Batch) Worker(ctx context.Context, collectorSchema v2alpha1.CollectorSch
 	if o.Fail {
 		return copiedImages, fmt.Errorf("forced error")
 	}
+	if o.TimeBox {
+		copiedImages.AllImages = collectorSchema.AllImages[:1]
+		return copiedImages, &batch.TimeBoxError{MaxDuration: opts.MaxDuration, Remaining: collectorSchema.AllImages[1:]}
+	}
 	return collectorSchema, nil
 }
 
@@ -1268,6 +1419,10 @@ func (o *Collector) ReleaseImage(ctx context.Context) (string, error) {
 	return "quay.io/openshift-release-dev/ocp-release:4.13.10-x86_64", nil
 }
 
+func (o *Collector) SkippedImageStreams() []string {
+	return nil
+}
+
 func (o *Collector) AdditionalImagesCollector(ctx context.Context) (v2alpha1.CollectorSchema, error) {
 	if o.Fail {
 		return v2alpha1.CollectorSchema{}, fmt.Errorf("forced error additionalImages collector")
@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/batch"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/clusterresources"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/emoji"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+// fanOut mirrors the images collected for the first destination of a mirrorToMirror run
+// to all its destinations. The images are pulled once to the local cache, then pushed
+// from the cache to the destinations concurrently, each with its own batch worker.
+func (o *ExecutorSchema) fanOut(ctx context.Context, collectorSchema v2alpha1.CollectorSchema) error {
+	cacheSchema := collectorSchema
+	cacheSchema.AllImages = cacheCopies(collectorSchema.AllImages, o.Opts.Destination, o.Opts.LocalStorageFQDN)
+
+	cacheOpts := *o.Opts
+	// the local cache is the destination, as in the mirrorToDisk workflow
+	cacheOpts.Mode = mirror.MirrorToDisk
+	o.Log.Info(emoji.Package+" pulling %d images to the local cache for %d destinations", len(cacheSchema.AllImages), len(o.Destinations))
+	cachedSchema, cacheErr := o.Batch.Worker(ctx, cacheSchema, cacheOpts)
+
+	cached := make(map[string]bool, len(cachedSchema.AllImages))
+	for _, img := range cachedSchema.AllImages {
+		cached[img.Destination] = true
+	}
+
+	type result struct {
+		copied v2alpha1.CollectorSchema
+		err    error
+	}
+	results := make([]result, len(o.Destinations))
+	var wg sync.WaitGroup
+	for i, dest := range o.Destinations {
+		logsDir := filepath.Join(o.LogsDir, destinationDirName(dest))
+		if err := o.MakeDir.makeDirAll(logsDir, 0o755); err != nil {
+			results[i].err = err
+			continue
+		}
+
+		destSchema := collectorSchema
+		destSchema.AllImages = destinationCopies(collectorSchema.AllImages, cached, o.Opts.Destination, dest, o.Opts.LocalStorageFQDN)
+
+		opts := *o.Opts
+		opts.Destination = dest
+		if dest != o.Opts.Destination {
+			// the images are pushed from the cache, and the graph image from the first destination
+			opts.Mode = mirror.DiskToMirror
+		}
+		// the progress bars of concurrent workers would overwrite each other
+		global := *o.Opts.Global
+		global.IsTerminal = false
+		opts.Global = &global
+
+		worker := batch.New(batch.ChannelConcurrentWorker, o.Log, logsDir, o.Mirror, o.Opts.ParallelImages, o.MirrorStartTimeStamp)
+		wg.Add(1)
+		go func(i int, dest string) {
+			defer wg.Done()
+			o.Log.Info(emoji.Rocket+" pushing %d images to %s", len(destSchema.AllImages), dest)
+			results[i].copied, results[i].err = worker.Worker(ctx, destSchema, opts)
+		}(i, dest)
+	}
+	wg.Wait()
+
+	o.createConfigsWithPinnedCatalogs(collectorSchema)
+
+	// a destination failing does not prevent the cluster resources of the others from being generated
+	destErr := &DestinationsError{CacheErr: cacheErr, Errs: map[string]error{}}
+	for i, dest := range o.Destinations {
+		errs := []error{}
+		if results[i].err != nil {
+			errs = append(errs, results[i].err)
+		}
+		if err := o.generateResourcesForDestination(ctx, dest, results[i].copied.AllImages, collectorSchema.CatalogToFBCMap); err != nil {
+			errs = append(errs, err)
+		}
+		switch len(errs) {
+		case 0:
+		case 1:
+			destErr.Errs[dest] = errs[0]
+		default:
+			destErr.Errs[dest] = errors.Join(errs...)
+		}
+	}
+
+	if destErr.CacheErr != nil || len(destErr.Errs) > 0 {
+		return destErr
+	}
+	return nil
+}
+
+// generateResourcesForDestination generates the cluster resources of the images mirrored to dest.
+func (o *ExecutorSchema) generateResourcesForDestination(ctx context.Context, dest string, images []v2alpha1.CopyImageSchema, catalogToFBC map[string]v2alpha1.CatalogFilterResult) error {
+	dir := destinationDirName(dest)
+	if err := o.MakeDir.makeDirAll(filepath.Join(o.Opts.Global.WorkingDir, clusterResourcesDir, dir), 0o755); err != nil {
+		return err
+	}
+	generator := clusterresources.NewForDestination(o.Log, o.Opts.Global.WorkingDir, dir, o.Config, o.Opts.LocalStorageFQDN)
+	if err := o.generateDestinationResources(ctx, generator, dest, images, catalogToFBC); err != nil {
+		return fmt.Errorf("generating the cluster resources: %w", err)
+	}
+	return nil
+}
+
+// cacheCopies returns the copies pulling to the local cache the images mirrored under destination.
+func cacheCopies(images []v2alpha1.CopyImageSchema, destination, localStorageFQDN string) []v2alpha1.CopyImageSchema {
+	var copies []v2alpha1.CopyImageSchema
+	for _, img := range images {
+		if ref, ok := cacheRef(img, destination, localStorageFQDN); ok {
+			img.Destination = ref
+			copies = append(copies, img)
+		}
+	}
+	return copies
+}
+
+// destinationCopies returns the copies pushing to dest the images mirrored under primary.
+// The images pulled to the local cache are pushed from it, cached holding the references
+// of the cache which were successfully pulled: the images missing from it are not pushed.
+func destinationCopies(images []v2alpha1.CopyImageSchema, cached map[string]bool, primary, dest, localStorageFQDN string) []v2alpha1.CopyImageSchema {
+	copies := make([]v2alpha1.CopyImageSchema, 0, len(images))
+	for _, img := range images {
+		if ref, ok := cacheRef(img, primary, localStorageFQDN); ok {
+			if !cached[ref] {
+				continue
+			}
+			img.Source = ref
+		}
+		img.Destination = rebaseDestination(img.Destination, primary, dest)
+		copies = append(copies, img)
+	}
+	return copies
+}
+
+// cacheRef returns the reference of the local cache an image mirrored under destination is pulled to.
+// It returns false for the images which are already in the cache, or which the collectors pushed to the destination.
+func cacheRef(img v2alpha1.CopyImageSchema, destination, localStorageFQDN string) (string, bool) {
+	if img.Source == img.Destination || strings.Contains(img.Source, localStorageFQDN) {
+		return "", false
+	}
+	return rebase(img.Destination, destination, consts.DockerProtocol+localStorageFQDN)
+}
+
+// rebaseDestination moves ref, an image reference under the from destination, under the to destination.
+// References under other locations are returned untouched.
+func rebaseDestination(ref, from, to string) string {
+	if rebased, ok := rebase(ref, from, to); ok {
+		return rebased
+	}
+	return ref
+}
+
+func rebase(ref, from, to string) (string, bool) {
+	transport := ""
+	if strings.HasPrefix(ref, consts.DockerProtocol) {
+		transport = consts.DockerProtocol
+		ref = strings.TrimPrefix(ref, consts.DockerProtocol)
+	}
+	from = strings.TrimSuffix(strings.TrimPrefix(from, consts.DockerProtocol), "/")
+	to = strings.TrimSuffix(strings.TrimPrefix(to, consts.DockerProtocol), "/")
+	path, ok := strings.CutPrefix(ref, from+"/")
+	if !ok {
+		return transport + ref, false
+	}
+	return transport + to + "/" + path, true
+}
+
+// destinationDirName returns the name of the subdirectory holding the cluster resources
+// and the reports of a destination.
+func destinationDirName(dest string) string {
+	dest = strings.Trim(strings.TrimPrefix(dest, consts.DockerProtocol), "/")
+	return strings.NewReplacer("/", "_", ":", "_").Replace(dest)
+}
+
+// validateDestinations checks the destinations of a mirrorToMirror run mirroring to several registries.
+func (o *ExecutorSchema) validateDestinations(dest []string) error {
+	if o.Opts.Global.From != "" || o.Opts.Global.WorkingDir == "" {
+		return fmt.Errorf("several destinations can only be set in the mirror to mirror workflow (with --workspace)")
+	}
+	if o.Opts.IsDryRun || o.Opts.IsDryRunManifestLists {
+		return fmt.Errorf("--dry-run cannot be used with several destinations")
+	}
+	seen := make(map[string]bool, len(dest))
+	for _, d := range dest {
+		if !strings.HasPrefix(d, consts.DockerProtocol) {
+			return fmt.Errorf("destination %s: all the destinations must have the docker:// prefix", d)
+		}
+		name := destinationDirName(d)
+		if seen[name] {
+			return fmt.Errorf("destination %s is set more than once", d)
+		}
+		seen[name] = true
+	}
+	return nil
+}
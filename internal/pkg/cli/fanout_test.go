@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/batch"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/errcode"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/metrics"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+func TestFanOutCopies(t *testing.T) {
+	const (
+		primary = "docker://mirror.eu.acme.com:5000/ocp"
+		cache   = "localhost:55000"
+	)
+	images := []v2alpha1.CopyImageSchema{
+		{
+			Source:      "docker://registry.redhat.io/ubi9/ubi:latest",
+			Destination: "docker://mirror.eu.acme.com:5000/ocp/ubi9/ubi:latest",
+			Origin:      "docker://registry.redhat.io/ubi9/ubi:latest",
+			Type:        v2alpha1.TypeGeneric,
+		},
+		{
+			Source:      "docker://quay.io/openshift-release-dev/ocp-release@sha256:a1b2c3",
+			Destination: "docker://mirror.eu.acme.com:5000/ocp/openshift/release-images@sha256:a1b2c3",
+			Origin:      "docker://quay.io/openshift-release-dev/ocp-release@sha256:a1b2c3",
+			Type:        v2alpha1.TypeOCPRelease,
+		},
+		{
+			// rebuilt catalogs are already in the cache
+			Source:      "docker://localhost:55000/redhat/redhat-operator-index:v4.18",
+			Destination: "docker://mirror.eu.acme.com:5000/ocp/redhat/redhat-operator-index:v4.18",
+			Origin:      "docker://registry.redhat.io/redhat/redhat-operator-index:v4.18",
+			Type:        v2alpha1.TypeOperatorCatalog,
+		},
+		{
+			// the graph image is pushed to the first destination by the release collector
+			Source:      "docker://mirror.eu.acme.com:5000/ocp/openshift/graph-image:latest",
+			Destination: "docker://mirror.eu.acme.com:5000/ocp/openshift/graph-image:latest",
+			Origin:      "docker://mirror.eu.acme.com:5000/ocp/openshift/graph-image:latest",
+			Type:        v2alpha1.TypeCincinnatiGraph,
+		},
+	}
+
+	copies := cacheCopies(images, primary, cache)
+	assert.Equal(t, []v2alpha1.CopyImageSchema{
+		{
+			Source:      "docker://registry.redhat.io/ubi9/ubi:latest",
+			Destination: "docker://localhost:55000/ubi9/ubi:latest",
+			Origin:      "docker://registry.redhat.io/ubi9/ubi:latest",
+			Type:        v2alpha1.TypeGeneric,
+		},
+		{
+			Source:      "docker://quay.io/openshift-release-dev/ocp-release@sha256:a1b2c3",
+			Destination: "docker://localhost:55000/openshift/release-images@sha256:a1b2c3",
+			Origin:      "docker://quay.io/openshift-release-dev/ocp-release@sha256:a1b2c3",
+			Type:        v2alpha1.TypeOCPRelease,
+		},
+	}, copies)
+
+	// the release image failed to be pulled to the cache
+	cached := map[string]bool{"docker://localhost:55000/ubi9/ubi:latest": true}
+	pushes := destinationCopies(images, cached, primary, "docker://mirror.us.acme.com/", cache)
+	assert.Equal(t, []v2alpha1.CopyImageSchema{
+		{
+			Source:      "docker://localhost:55000/ubi9/ubi:latest",
+			Destination: "docker://mirror.us.acme.com/ubi9/ubi:latest",
+			Origin:      "docker://registry.redhat.io/ubi9/ubi:latest",
+			Type:        v2alpha1.TypeGeneric,
+		},
+		{
+			Source:      "docker://localhost:55000/redhat/redhat-operator-index:v4.18",
+			Destination: "docker://mirror.us.acme.com/redhat/redhat-operator-index:v4.18",
+			Origin:      "docker://registry.redhat.io/redhat/redhat-operator-index:v4.18",
+			Type:        v2alpha1.TypeOperatorCatalog,
+		},
+		{
+			Source:      "docker://mirror.eu.acme.com:5000/ocp/openshift/graph-image:latest",
+			Destination: "docker://mirror.us.acme.com/openshift/graph-image:latest",
+			Origin:      "docker://mirror.eu.acme.com:5000/ocp/openshift/graph-image:latest",
+			Type:        v2alpha1.TypeCincinnatiGraph,
+		},
+	}, pushes)
+}
+
+func TestRebaseDestination(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		expected string
+	}{
+		{
+			name:     "docker reference",
+			ref:      "docker://mirror.eu.acme.com:5000/ocp/openshift/graph-image:latest",
+			expected: "docker://mirror.us.acme.com/openshift/graph-image:latest",
+		},
+		{
+			name:     "reference without transport",
+			ref:      "mirror.eu.acme.com:5000/ocp/openshift/release-images@sha256:a1b2c3",
+			expected: "mirror.us.acme.com/openshift/release-images@sha256:a1b2c3",
+		},
+		{
+			name:     "reference under another location",
+			ref:      "docker://mirror.eu.acme.com:5000/ocpx/openshift/graph-image:latest",
+			expected: "docker://mirror.eu.acme.com:5000/ocpx/openshift/graph-image:latest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rebaseDestination(tt.ref, "docker://mirror.eu.acme.com:5000/ocp", "docker://mirror.us.acme.com"))
+		})
+	}
+}
+
+func TestDestinationDirName(t *testing.T) {
+	assert.Equal(t, "mirror.eu.acme.com_5000_ocp", destinationDirName("docker://mirror.eu.acme.com:5000/ocp/"))
+	assert.Equal(t, "mirror.us.acme.com", destinationDirName("docker://mirror.us.acme.com"))
+}
+
+func TestDestinationsError(t *testing.T) {
+	err := &DestinationsError{
+		CacheErr: errors.New("pull failed"),
+		Errs: map[string]error{
+			"docker://mirror.us.acme.com": &CollectionError{OperatorErr: errors.New("push failed")},
+			"docker://mirror.eu.acme.com": errors.New("unauthorized"),
+		},
+	}
+	assert.EqualError(t, err, "destination errors: local cache: pull failed\n"+
+		"docker://mirror.eu.acme.com: unauthorized\n"+
+		"docker://mirror.us.acme.com: collection error: push failed")
+	assert.Equal(t, errcode.GenericErr|errcode.OperatorErr, err.ExitCode())
+}
+
+func TestFanOutDestinationErrors(t *testing.T) {
+	opts := &mirror.CopyOptions{
+		Global:           &mirror.GlobalOptions{WorkingDir: t.TempDir()},
+		Mode:             mirror.MirrorToMirror,
+		Destination:      "docker://mirror.eu.acme.com",
+		LocalStorageFQDN: "localhost:55000",
+		ParallelImages:   1,
+	}
+	ex := &ExecutorSchema{
+		Log:          clog.New("debug"),
+		Opts:         opts,
+		LogsDir:      t.TempDir(),
+		Batch:        Batch{Fail: true},
+		Mirror:       Mirror{},
+		MakeDir:      MockMakeDir{Fail: true, Dir: "cluster-resources"},
+		Destinations: []string{"docker://mirror.eu.acme.com", "docker://mirror.us.acme.com"},
+	}
+
+	err := ex.fanOut(t.Context(), v2alpha1.CollectorSchema{})
+	var destErr *DestinationsError
+	require.ErrorAs(t, err, &destErr)
+	assert.EqualError(t, destErr.CacheErr, "forced error")
+	// the cluster resources of each destination failed, not only the first one
+	require.Len(t, destErr.Errs, 2)
+	assert.EqualError(t, destErr.Errs["docker://mirror.eu.acme.com"], "forced mkdir cluster-resources error")
+	assert.EqualError(t, destErr.Errs["docker://mirror.us.acme.com"], "forced mkdir cluster-resources error")
+}
+
+func TestDestinationsErrorJoined(t *testing.T) {
+	err := &DestinationsError{
+		Errs: map[string]error{
+			"docker://mirror.us.acme.com": errors.Join(&CollectionError{OperatorErr: errors.New("push failed")}, errors.New("generating the cluster resources: failed")),
+		},
+	}
+	assert.Equal(t, errcode.GenericErr|errcode.OperatorErr, err.ExitCode())
+}
+
+func TestFanOutMetrics(t *testing.T) {
+	const cache = "localhost:55000"
+	textfile := filepath.Join(t.TempDir(), "oc-mirror.prom")
+	log := clog.New("debug")
+	opts := &mirror.CopyOptions{
+		Global:           &mirror.GlobalOptions{WorkingDir: t.TempDir(), CommandTimeout: time.Minute},
+		Mode:             mirror.MirrorToMirror,
+		Function:         string(mirror.CopyMode),
+		Destination:      "docker://mirror.eu.acme.com",
+		LocalStorageFQDN: cache,
+		ParallelImages:   1,
+		Metrics:          metrics.New(metrics.Options{Textfile: textfile}, cache),
+	}
+	ex := &ExecutorSchema{
+		Log:          log,
+		Opts:         opts,
+		LogsDir:      t.TempDir(),
+		Batch:        batch.New(batch.ChannelConcurrentWorker, log, t.TempDir(), Mirror{}, 1, "20261019_100000"),
+		Mirror:       Mirror{},
+		// the cluster resources are not generated
+		MakeDir:      MockMakeDir{Fail: true, Dir: "cluster-resources"},
+		Destinations: []string{"docker://mirror.eu.acme.com", "docker://mirror.us.acme.com"},
+	}
+	cs := v2alpha1.CollectorSchema{
+		AllImages: []v2alpha1.CopyImageSchema{{
+			Source:      "docker://registry.redhat.io/ubi9/ubi:latest",
+			Destination: "docker://mirror.eu.acme.com/ubi9/ubi:latest",
+			Origin:      "docker://registry.redhat.io/ubi9/ubi:latest",
+			Type:        v2alpha1.TypeGeneric,
+		}},
+		TotalAdditionalImages: 1,
+	}
+	// as set by CollectAll
+	opts.Metrics.SetImages(cs.AllImages)
+
+	_ = ex.fanOut(t.Context(), cs)
+
+	require.NoError(t, opts.Metrics.Stop(t.Context()))
+	data, err := os.ReadFile(textfile)
+	require.NoError(t, err)
+	// the image is counted once, and processed once by the cache pull and by each destination
+	for _, line := range []string{
+		`oc_mirror_images{type="generic"} 1`,
+		`oc_mirror_images_processed_total{destination="cache",result="succeeded",type="generic"} 1`,
+		`oc_mirror_images_processed_total{destination="mirror.eu.acme.com",result="succeeded",type="generic"} 1`,
+		`oc_mirror_images_processed_total{destination="mirror.us.acme.com",result="succeeded",type="generic"} 1`,
+	} {
+		assert.Contains(t, string(data), line)
+	}
+}
@@ -0,0 +1,203 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/distribution/distribution/v3"
+	"github.com/distribution/distribution/v3/registry/storage"
+	"github.com/distribution/distribution/v3/registry/storage/driver/factory"
+	_ "github.com/distribution/distribution/v3/registry/storage/driver/filesystem"
+	"github.com/distribution/reference"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/opencontainers/go-digest"
+	specv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	bundleMediaTypeLabel = "operators.operatorframework.io.bundle.mediatype.v1"
+	bundlePackageLabel   = "operators.operatorframework.io.bundle.package.v1"
+	bundleMediaType      = "registry+v1"
+)
+
+// cachedBundle is an operator bundle image stored in the local cache
+type cachedBundle struct {
+	// repository and tag of the image in the cache
+	repository string
+	tag        string
+	pkg        string
+	image      gcrv1.Image
+}
+
+func (b cachedBundle) reference() string {
+	return b.repository + ":" + b.tag
+}
+
+// cachedBundles returns the bundle images of the local cache stored in cacheDir,
+// optionally limited to the bundles of packages
+func cachedBundles(ctx context.Context, cacheDir string, packages []string) ([]cachedBundle, error) {
+	driver, err := factory.Create(ctx, "filesystem", map[string]any{"rootdirectory": cacheDir})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the local cache %s: %w", cacheDir, err)
+	}
+	namespace, err := storage.NewRegistry(ctx, driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the local cache %s: %w", cacheDir, err)
+	}
+	enumerator, ok := namespace.(distribution.RepositoryEnumerator)
+	if !ok {
+		return nil, errors.New("the local cache repositories cannot be listed")
+	}
+
+	bundles := []cachedBundle{}
+	err = enumerator.Enumerate(ctx, func(repoName string) error {
+		named, err := reference.WithName(repoName)
+		if err != nil {
+			return err
+		}
+		repo, err := namespace.Repository(ctx, named)
+		if err != nil {
+			return err
+		}
+		tags, err := repo.Tags(ctx).All(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list the tags of %s: %w", repoName, err)
+		}
+		slices.Sort(tags)
+		for _, tag := range tags {
+			desc, err := repo.Tags(ctx).Get(ctx, tag)
+			if err != nil {
+				return fmt.Errorf("failed to resolve %s:%s: %w", repoName, tag, err)
+			}
+			img, labels, err := cachedImage(ctx, repo, desc.Digest)
+			if err != nil {
+				return fmt.Errorf("failed to read %s:%s: %w", repoName, tag, err)
+			}
+			if img == nil || labels[bundleMediaTypeLabel] != bundleMediaType {
+				continue
+			}
+			if len(packages) > 0 && !slices.Contains(packages, labels[bundlePackageLabel]) {
+				continue
+			}
+			bundles = append(bundles, cachedBundle{repository: repoName, tag: tag, pkg: labels[bundlePackageLabel], image: img})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bundles, nil
+}
+
+// cachedImage returns the image of the manifest dgst along with its labels.
+// For an index, the first of its manifests available in the cache is used:
+// all the manifests of a bundle index have the same content.
+// A nil image is returned when no manifest of the index is available.
+func cachedImage(ctx context.Context, repo distribution.Repository, dgst digest.Digest) (gcrv1.Image, map[string]string, error) { //nolint:ireturn // interface type is required by go-containerregistry
+	manifests, err := repo.Manifests(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, err := manifests.Get(ctx, dgst)
+	if err != nil {
+		return nil, nil, err
+	}
+	mediaType, payload, err := m.Payload()
+	if err != nil {
+		return nil, nil, err
+	}
+	switch mediaType {
+	case specv1.MediaTypeImageIndex, string(types.DockerManifestList):
+		for _, ref := range m.References() {
+			if exists, err := manifests.Exists(ctx, ref.Digest); err != nil || !exists {
+				continue
+			}
+			return cachedImage(ctx, repo, ref.Digest)
+		}
+		return nil, nil, nil
+	}
+
+	var imgManifest specv1.Manifest
+	if err := json.Unmarshal(payload, &imgManifest); err != nil {
+		return nil, nil, err
+	}
+	config, err := repo.Blobs(ctx).Get(ctx, imgManifest.Config.Digest)
+	if err != nil {
+		return nil, nil, err
+	}
+	var imgConfig specv1.Image
+	if err := json.Unmarshal(config, &imgConfig); err != nil {
+		return nil, nil, err
+	}
+	img, err := partial.CompressedToImage(&cacheImageCore{
+		ctx:       ctx,
+		blobs:     repo.Blobs(ctx),
+		mediaType: types.MediaType(mediaType),
+		manifest:  payload,
+		config:    config,
+		layers:    imgManifest.Layers,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return img, imgConfig.Config.Labels, nil
+}
+
+// cacheImageCore reads an image from the blob store of the local cache
+type cacheImageCore struct {
+	ctx       context.Context //nolint:containedctx // go-containerregistry interfaces do not take a context
+	blobs     distribution.BlobStore
+	mediaType types.MediaType
+	manifest  []byte
+	config    []byte
+	layers    []specv1.Descriptor
+}
+
+func (c *cacheImageCore) RawConfigFile() ([]byte, error) {
+	return c.config, nil
+}
+
+func (c *cacheImageCore) MediaType() (types.MediaType, error) {
+	return c.mediaType, nil
+}
+
+func (c *cacheImageCore) RawManifest() ([]byte, error) {
+	return c.manifest, nil
+}
+
+func (c *cacheImageCore) LayerByDigest(h gcrv1.Hash) (partial.CompressedLayer, error) { //nolint:ireturn // interface type is required by go-containerregistry
+	for _, layer := range c.layers {
+		if layer.Digest.String() == h.String() {
+			return &cacheLayer{core: c, desc: layer}, nil
+		}
+	}
+	return nil, fmt.Errorf("layer %s not found in manifest", h)
+}
+
+// cacheLayer is a compressed layer of an image of the local cache
+type cacheLayer struct {
+	core *cacheImageCore
+	desc specv1.Descriptor
+}
+
+func (l *cacheLayer) Digest() (gcrv1.Hash, error) {
+	return gcrv1.NewHash(l.desc.Digest.String())
+}
+
+func (l *cacheLayer) Compressed() (io.ReadCloser, error) {
+	return l.core.blobs.Open(l.core.ctx, l.desc.Digest)
+}
+
+func (l *cacheLayer) Size() (int64, error) {
+	return l.desc.Size, nil
+}
+
+func (l *cacheLayer) MediaType() (types.MediaType, error) {
+	return types.MediaType(l.desc.MediaType), nil
+}
@@ -0,0 +1,398 @@
+package list
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/spf13/cobra"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/archive"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/manifest"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+const (
+	// localCacheDir is the location of the local cache in the cache directory
+	localCacheDir = ".oc-mirror/.cache"
+	// bundleManifestsDir is the directory of the bundle images holding the manifests
+	bundleManifestsDir = "manifests"
+	// anySCC is reported when the use of all the SecurityContextConstraints is granted
+	anySCC = "*"
+)
+
+type bundleContentsOptions struct {
+	from       string
+	packages   []string
+	extractDir string
+	output     string
+	copyOpts   *mirror.CopyOptions
+}
+
+// rbacRules are the cluster-scoped rules granted by a bundle
+type rbacRules struct {
+	// Source is the service account of the CSV or the ClusterRole granting the rules
+	Source string              `json:"source"`
+	Rules  []rbacv1.PolicyRule `json:"rules"`
+}
+
+// webhookInfo is an admission or conversion webhook of a bundle
+type webhookInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// bundleContents is the structured output of `list bundle-contents`, for each bundle
+type bundleContents struct {
+	Image         string        `json:"image"`
+	Package       string        `json:"package"`
+	Name          string        `json:"name,omitempty"`
+	Version       string        `json:"version,omitempty"`
+	OwnedCRDs     []string      `json:"ownedCRDs,omitempty"`
+	RequiredCRDs  []string      `json:"requiredCRDs,omitempty"`
+	ClusterRBAC   []rbacRules   `json:"clusterRBAC,omitempty"`
+	Webhooks      []webhookInfo `json:"webhooks,omitempty"`
+	SCCs          []string      `json:"securityContextConstraints,omitempty"`
+	Deployments   []string      `json:"deployments,omitempty"`
+	RelatedImages []string      `json:"relatedImages,omitempty"`
+	ManifestsDir  string        `json:"manifestsDir,omitempty"`
+	OtherObjects  []string      `json:"otherObjects,omitempty"`
+}
+
+// NewBundleContentsCommand returns a `list bundle-contents` command
+func NewBundleContentsCommand(log clog.PluggableLoggerInterface, copyOpts *mirror.CopyOptions) *cobra.Command {
+	opts := bundleContentsOptions{copyOpts: copyOpts}
+	cmd := &cobra.Command{
+		Use:   "bundle-contents",
+		Short: "List what the mirrored operator bundles install on a cluster",
+		Long: templates.LongDesc(`
+			List, for each operator bundle image of the local cache or of a mirror archive,
+			what it installs on a cluster: owned and required CRDs, cluster-scoped RBAC,
+			webhooks, requested SecurityContextConstraints, deployments and related images.
+
+			The bundles are read from the local cache of --cache-dir, or from the archives
+			of --from, which are extracted to a temporary directory.
+			With --extract-dir, the raw manifests of each bundle are also extracted,
+			to <extract-dir>/<repository>/<tag>/manifests.
+		`),
+		Example: templates.Examples(`
+			# List the contents of the bundles of the local cache
+			oc-mirror --v2 list bundle-contents
+
+			# List the contents of the bundles of a package in a mirror archive, and extract their manifests
+			oc-mirror --v2 list bundle-contents --from file:///home/<user>/mirror --package aws-load-balancer-operator --extract-dir ./bundles
+
+			# List the contents of the bundles of the local cache as json
+			oc-mirror --v2 list bundle-contents -o json
+		`),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if opts.from != "" && !strings.HasPrefix(opts.from, consts.FileProtocol) {
+				return fmt.Errorf("--from %q must start with %s", opts.from, consts.FileProtocol)
+			}
+			return validateOutputFormat(opts.output)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// NOTE: we don't want help output on errors from here onwards
+			cmd.SilenceUsage = true
+			return runBundleContents(cmd.Context(), log, os.Stdout, &opts)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&opts.from, "from", "", "Directory of the mirror archives (file://) to read the bundles from, instead of the local cache.")
+	fs.StringSliceVar(&opts.packages, "package", nil, "Only list the bundles of these packages.")
+	fs.StringVar(&opts.extractDir, "extract-dir", "", "Directory the raw manifests of the bundles are extracted to.")
+	addOutputFlag(cmd, &opts.output)
+
+	return cmd
+}
+
+func runBundleContents(ctx context.Context, log clog.PluggableLoggerInterface, w io.Writer, opts *bundleContentsOptions) error {
+	cacheDir := filepath.Join(opts.copyOpts.Global.CacheDir, localCacheDir)
+	if opts.from != "" {
+		tmpDir, err := os.MkdirTemp("", "oc-mirror-bundles-")
+		if err != nil {
+			return fmt.Errorf("failed to create archive extraction dir: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+		cacheDir = filepath.Join(tmpDir, localCacheDir)
+		extractor, err := archive.NewArchiveExtractor(strings.TrimPrefix(opts.from, consts.FileProtocol), filepath.Join(tmpDir, workingDirName), cacheDir)
+		if err != nil {
+			return err
+		}
+		if err := extractor.Unarchive(); err != nil {
+			return fmt.Errorf("failed to extract the archives of %s: %w", opts.from, err)
+		}
+	}
+
+	bundles, err := cachedBundles(ctx, cacheDir, opts.packages)
+	if err != nil {
+		return err
+	}
+
+	manifestsRoot := opts.extractDir
+	if manifestsRoot == "" {
+		if manifestsRoot, err = os.MkdirTemp("", "oc-mirror-bundle-manifests-"); err != nil {
+			return fmt.Errorf("failed to create manifests extraction dir: %w", err)
+		}
+		defer os.RemoveAll(manifestsRoot)
+	}
+
+	extractor := manifest.New(log)
+	contents := make([]bundleContents, 0, len(bundles))
+	for _, bundle := range bundles {
+		bundleDir := filepath.Join(manifestsRoot, bundle.repository, bundle.tag)
+		if err := extractor.ExtractOCILayers(bundle.image, bundleDir, bundleManifestsDir); err != nil {
+			return fmt.Errorf("failed to extract the manifests of %s: %w", bundle.reference(), err)
+		}
+		c, err := summarizeBundleManifests(filepath.Join(bundleDir, bundleManifestsDir))
+		if err != nil {
+			return fmt.Errorf("failed to read the manifests of %s: %w", bundle.reference(), err)
+		}
+		c.Image = bundle.reference()
+		c.Package = bundle.pkg
+		if opts.extractDir != "" {
+			c.ManifestsDir = filepath.Join(bundleDir, bundleManifestsDir)
+		}
+		contents = append(contents, c)
+	}
+
+	if isStructured(opts.output) {
+		return printStructured(w, opts.output, contents)
+	}
+	printBundleContents(w, contents)
+	return nil
+}
+
+// summarizeBundleManifests reads the manifests of a bundle extracted to dir
+func summarizeBundleManifests(dir string) (bundleContents, error) {
+	c := bundleContents{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+		for {
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return fmt.Errorf("%s: %w", filepath.Base(path), err)
+			}
+			if len(raw) == 0 || string(raw) == "null" {
+				continue
+			}
+			if err := c.addObject(raw); err != nil {
+				return fmt.Errorf("%s: %w", filepath.Base(path), err)
+			}
+		}
+	})
+	if err != nil {
+		return c, err
+	}
+	for _, list := range [][]string{c.OwnedCRDs, c.RequiredCRDs, c.SCCs, c.Deployments, c.RelatedImages, c.OtherObjects} {
+		slices.Sort(list)
+	}
+	c.OwnedCRDs = slices.Compact(c.OwnedCRDs)
+	c.SCCs = slices.Compact(c.SCCs)
+	c.RelatedImages = slices.Compact(c.RelatedImages)
+	return c, nil
+}
+
+// addObject adds the kubernetes object raw to the contents of the bundle
+func (c *bundleContents) addObject(raw json.RawMessage) error {
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return err
+	}
+	switch typeMeta.Kind {
+	case v1alpha1.ClusterServiceVersionKind:
+		var csv v1alpha1.ClusterServiceVersion
+		if err := json.Unmarshal(raw, &csv); err != nil {
+			return err
+		}
+		c.addCSV(csv)
+	case "CustomResourceDefinition":
+		var crd apiextensionsv1.CustomResourceDefinition
+		if err := json.Unmarshal(raw, &crd); err != nil {
+			return err
+		}
+		c.OwnedCRDs = append(c.OwnedCRDs, crd.Name)
+	case "ClusterRole":
+		var role rbacv1.ClusterRole
+		if err := json.Unmarshal(raw, &role); err != nil {
+			return err
+		}
+		c.addRules("ClusterRole "+role.Name, role.Rules)
+	case "ValidatingWebhookConfiguration":
+		var config admissionregistrationv1.ValidatingWebhookConfiguration
+		if err := json.Unmarshal(raw, &config); err != nil {
+			return err
+		}
+		for _, webhook := range config.Webhooks {
+			c.Webhooks = append(c.Webhooks, webhookInfo{Name: webhook.Name, Type: string(v1alpha1.ValidatingAdmissionWebhook)})
+		}
+	case "MutatingWebhookConfiguration":
+		var config admissionregistrationv1.MutatingWebhookConfiguration
+		if err := json.Unmarshal(raw, &config); err != nil {
+			return err
+		}
+		for _, webhook := range config.Webhooks {
+			c.Webhooks = append(c.Webhooks, webhookInfo{Name: webhook.Name, Type: string(v1alpha1.MutatingAdmissionWebhook)})
+		}
+	default:
+		var objMeta metav1.ObjectMeta
+		if err := json.Unmarshal(raw, &struct {
+			Metadata *metav1.ObjectMeta `json:"metadata"`
+		}{Metadata: &objMeta}); err != nil {
+			return err
+		}
+		c.OtherObjects = append(c.OtherObjects, typeMeta.Kind+" "+objMeta.Name)
+	}
+	return nil
+}
+
+func (c *bundleContents) addCSV(csv v1alpha1.ClusterServiceVersion) {
+	c.Name = csv.Name
+	c.Version = csv.Spec.Version.String()
+	for _, crd := range csv.Spec.CustomResourceDefinitions.Owned {
+		c.OwnedCRDs = append(c.OwnedCRDs, crd.Name)
+	}
+	for _, crd := range csv.Spec.CustomResourceDefinitions.Required {
+		c.RequiredCRDs = append(c.RequiredCRDs, crd.Name)
+	}
+	strategy := csv.Spec.InstallStrategy.StrategySpec
+	for _, perm := range strategy.ClusterPermissions {
+		c.addRules("ServiceAccount "+perm.ServiceAccountName, perm.Rules)
+	}
+	// SCCs are cluster-scoped, but their use can be granted by namespaced roles
+	for _, perm := range strategy.Permissions {
+		c.SCCs = append(c.SCCs, requestedSCCs(perm.Rules)...)
+	}
+	for _, deployment := range strategy.DeploymentSpecs {
+		c.Deployments = append(c.Deployments, deployment.Name)
+	}
+	for _, webhook := range csv.Spec.WebhookDefinitions {
+		c.Webhooks = append(c.Webhooks, webhookInfo{Name: webhook.GenerateName, Type: string(webhook.Type)})
+	}
+	for _, ri := range csv.Spec.RelatedImages {
+		c.RelatedImages = append(c.RelatedImages, ri.Image)
+	}
+}
+
+func (c *bundleContents) addRules(source string, rules []rbacv1.PolicyRule) {
+	c.ClusterRBAC = append(c.ClusterRBAC, rbacRules{Source: source, Rules: rules})
+	c.SCCs = append(c.SCCs, requestedSCCs(rules)...)
+}
+
+// requestedSCCs returns the SecurityContextConstraints whose use is granted by the rules
+func requestedSCCs(rules []rbacv1.PolicyRule) []string {
+	sccs := []string{}
+	for _, rule := range rules {
+		if !matchesAny(rule.APIGroups, "security.openshift.io") || !matchesAny(rule.Resources, "securitycontextconstraints") || !matchesAny(rule.Verbs, "use") {
+			continue
+		}
+		if len(rule.ResourceNames) == 0 {
+			sccs = append(sccs, anySCC)
+			continue
+		}
+		sccs = append(sccs, rule.ResourceNames...)
+	}
+	return sccs
+}
+
+func matchesAny(values []string, value string) bool {
+	return slices.Contains(values, value) || slices.Contains(values, rbacv1.ResourceAll)
+}
+
+// describeRule returns a kubectl-like description of a policy rule: verbs on resources
+func describeRule(rule rbacv1.PolicyRule) string {
+	resources := []string{}
+	for _, resource := range rule.Resources {
+		for _, group := range rule.APIGroups {
+			if group == "" {
+				resources = append(resources, resource)
+			} else {
+				resources = append(resources, resource+"."+group)
+			}
+		}
+	}
+	resources = append(resources, rule.NonResourceURLs...)
+	description := strings.Join(rule.Verbs, ",") + " " + strings.Join(resources, ",")
+	if len(rule.ResourceNames) > 0 {
+		description += " (" + strings.Join(rule.ResourceNames, ",") + ")"
+	}
+	return description
+}
+
+func printBundleContents(w io.Writer, contents []bundleContents) {
+	if len(contents) == 0 {
+		fmt.Fprintln(w, "No operator bundle found")
+		return
+	}
+	printList := func(title string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "    %s:\n", title)
+		for _, v := range values {
+			fmt.Fprintf(w, "        %s\n", v)
+		}
+	}
+	for _, c := range contents {
+		fmt.Fprintf(w, "bundle %s (package %s)\n", c.Image, c.Package)
+		if c.Name != "" {
+			fmt.Fprintf(w, "    csv: %s %s\n", c.Name, c.Version)
+		}
+		printList("owned CRDs", c.OwnedCRDs)
+		printList("required CRDs", c.RequiredCRDs)
+		if len(c.ClusterRBAC) > 0 {
+			fmt.Fprintln(w, "    cluster RBAC:")
+			for _, rbac := range c.ClusterRBAC {
+				fmt.Fprintf(w, "        %s\n", rbac.Source)
+				for _, rule := range rbac.Rules {
+					fmt.Fprintf(w, "            %s\n", describeRule(rule))
+				}
+			}
+		}
+		webhooks := make([]string, 0, len(c.Webhooks))
+		for _, webhook := range c.Webhooks {
+			webhooks = append(webhooks, webhook.Name+" ("+webhook.Type+")")
+		}
+		printList("webhooks", webhooks)
+		printList("security context constraints", c.SCCs)
+		printList("deployments", c.Deployments)
+		printList("related images", c.RelatedImages)
+		printList("other objects", c.OtherObjects)
+		if c.ManifestsDir != "" {
+			fmt.Fprintf(w, "    manifests: %s\n", c.ManifestsDir)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package list
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/distribution/distribution/v3"
+	"github.com/distribution/distribution/v3/manifest/ocischema"
+	"github.com/distribution/distribution/v3/registry/storage"
+	"github.com/distribution/distribution/v3/registry/storage/driver/factory"
+	"github.com/distribution/reference"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	specv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+const testCSV = `apiVersion: operators.coreos.com/v1alpha1
+kind: ClusterServiceVersion
+metadata:
+  name: foo.v1.0.0
+spec:
+  version: 1.0.0
+  customresourcedefinitions:
+    owned:
+      - name: foos.example.com
+        version: v1
+        kind: Foo
+    required:
+      - name: bars.example.com
+        version: v1
+        kind: Bar
+  install:
+    strategy: deployment
+    spec:
+      clusterPermissions:
+        - serviceAccountName: foo-operator
+          rules:
+            - apiGroups: [""]
+              resources: [nodes]
+              verbs: [get, list]
+      permissions:
+        - serviceAccountName: foo-operator
+          rules:
+            - apiGroups: [security.openshift.io]
+              resources: [securitycontextconstraints]
+              resourceNames: [privileged]
+              verbs: [use]
+      deployments:
+        - name: foo-operator
+          spec:
+            selector:
+              matchLabels:
+                app: foo
+            template:
+              spec:
+                containers:
+                  - name: manager
+                    image: quay.io/example/foo:v1.0.0
+  webhookdefinitions:
+    - generateName: vfoo.example.com
+      type: ValidatingAdmissionWebhook
+      sideEffects: None
+      admissionReviewVersions: [v1]
+  relatedImages:
+    - name: manager
+      image: quay.io/example/foo:v1.0.0
+`
+
+const testCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: foos.example.com
+`
+
+const testClusterRole = `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: foo-metrics-reader
+rules:
+  - nonResourceURLs: [/metrics]
+    verbs: [get]
+  - apiGroups: ["*"]
+    resources: ["*"]
+    verbs: ["*"]
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo-metrics
+`
+
+func writeBundleManifests(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{
+		"foo.clusterserviceversion.yaml": testCSV,
+		"foos.example.com.crd.yaml":      testCRD,
+		"foo-metrics.yaml":               testClusterRole,
+	}
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return files
+}
+
+func TestSummarizeBundleManifests(t *testing.T) {
+	dir := t.TempDir()
+	writeBundleManifests(t, dir)
+
+	c, err := summarizeBundleManifests(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "foo.v1.0.0", c.Name)
+	assert.Equal(t, "1.0.0", c.Version)
+	assert.Equal(t, []string{"foos.example.com"}, c.OwnedCRDs)
+	assert.Equal(t, []string{"bars.example.com"}, c.RequiredCRDs)
+	assert.Equal(t, []string{"foo-operator"}, c.Deployments)
+	assert.Equal(t, []string{"quay.io/example/foo:v1.0.0"}, c.RelatedImages)
+	assert.Equal(t, []webhookInfo{{Name: "vfoo.example.com", Type: "ValidatingAdmissionWebhook"}}, c.Webhooks)
+	// the wildcard rule of the ClusterRole grants the use of all the SCCs
+	assert.Equal(t, []string{anySCC, "privileged"}, c.SCCs)
+	assert.Equal(t, []string{"Service foo-metrics"}, c.OtherObjects)
+
+	sources := []string{}
+	for _, rbac := range c.ClusterRBAC {
+		sources = append(sources, rbac.Source)
+	}
+	assert.ElementsMatch(t, []string{"ServiceAccount foo-operator", "ClusterRole foo-metrics-reader"}, sources)
+
+	t.Run("should describe the rules like kubectl", func(t *testing.T) {
+		assert.Equal(t, "get,list nodes", describeRule(rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get", "list"}}))
+		assert.Equal(t, "use securitycontextconstraints.security.openshift.io (privileged)", describeRule(rbacv1.PolicyRule{
+			APIGroups: []string{"security.openshift.io"}, Resources: []string{"securitycontextconstraints"}, ResourceNames: []string{"privileged"}, Verbs: []string{"use"},
+		}))
+		assert.Equal(t, "get /metrics", describeRule(rbacv1.PolicyRule{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}}))
+	})
+}
+
+// pushCachedImage stores an image with a single layer holding files in the local cache of cacheDir
+func pushCachedImage(t *testing.T, cacheDir, repoName, tag string, labels, files map[string]string) {
+	t.Helper()
+	ctx := t.Context()
+	driver, err := factory.Create(ctx, "filesystem", map[string]any{"rootdirectory": cacheDir})
+	require.NoError(t, err)
+	namespace, err := storage.NewRegistry(ctx, driver)
+	require.NoError(t, err)
+	named, err := reference.WithName(repoName)
+	require.NoError(t, err)
+	repo, err := namespace.Repository(ctx, named)
+	require.NoError(t, err)
+
+	var layer bytes.Buffer
+	gz := gzip.NewWriter(&layer)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	config, err := json.Marshal(specv1.Image{Config: specv1.ImageConfig{Labels: labels}})
+	require.NoError(t, err)
+
+	layerDesc, err := repo.Blobs(ctx).Put(ctx, specv1.MediaTypeImageLayerGzip, layer.Bytes())
+	require.NoError(t, err)
+	configDesc, err := repo.Blobs(ctx).Put(ctx, specv1.MediaTypeImageConfig, config)
+	require.NoError(t, err)
+	m, err := ocischema.FromStruct(ocischema.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: specv1.MediaTypeImageManifest,
+		Config:    specv1.Descriptor{MediaType: specv1.MediaTypeImageConfig, Digest: configDesc.Digest, Size: configDesc.Size},
+		Layers:    []specv1.Descriptor{{MediaType: specv1.MediaTypeImageLayerGzip, Digest: layerDesc.Digest, Size: layerDesc.Size}},
+	})
+	require.NoError(t, err)
+	manifests, err := repo.Manifests(ctx)
+	require.NoError(t, err)
+	dgst, err := manifests.Put(ctx, m)
+	require.NoError(t, err)
+	require.NoError(t, repo.Tags(ctx).Tag(ctx, tag, distribution.Descriptor{MediaType: specv1.MediaTypeImageManifest, Digest: dgst}))
+}
+
+func TestRunBundleContents(t *testing.T) {
+	cacheRoot := t.TempDir()
+	cacheDir := filepath.Join(cacheRoot, localCacheDir)
+	bundleFiles := map[string]string{}
+	for name, content := range writeBundleManifests(t, t.TempDir()) {
+		bundleFiles["manifests/"+name] = content
+	}
+	bundleFiles["metadata/annotations.yaml"] = "annotations: {}\n"
+	pushCachedImage(t, cacheDir, "example/foo-bundle", "sha256-0123", map[string]string{bundleMediaTypeLabel: bundleMediaType, bundlePackageLabel: "foo"}, bundleFiles)
+	pushCachedImage(t, cacheDir, "example/bar-bundle", "v0.1.0", map[string]string{bundleMediaTypeLabel: bundleMediaType, bundlePackageLabel: "bar"}, bundleFiles)
+	pushCachedImage(t, cacheDir, "example/foo", "v1.0.0", nil, map[string]string{"usr/bin/foo": "binary"})
+
+	run := func(t *testing.T, opts bundleContentsOptions) []bundleContents {
+		t.Helper()
+		opts.output = outputJSON
+		opts.copyOpts = &mirror.CopyOptions{Global: &mirror.GlobalOptions{CacheDir: cacheRoot}}
+		var out bytes.Buffer
+		require.NoError(t, runBundleContents(t.Context(), clog.New("debug"), &out, &opts))
+		var contents []bundleContents
+		require.NoError(t, json.Unmarshal(out.Bytes(), &contents))
+		return contents
+	}
+
+	t.Run("should list the bundles of the local cache", func(t *testing.T) {
+		contents := run(t, bundleContentsOptions{})
+		require.Len(t, contents, 2)
+		assert.Equal(t, "example/bar-bundle:v0.1.0", contents[0].Image)
+		assert.Equal(t, "bar", contents[0].Package)
+		assert.Equal(t, "example/foo-bundle:sha256-0123", contents[1].Image)
+		assert.Equal(t, []string{"foos.example.com"}, contents[1].OwnedCRDs)
+		assert.Empty(t, contents[1].ManifestsDir)
+	})
+
+	t.Run("should filter the bundles by package and extract their manifests", func(t *testing.T) {
+		extractDir := t.TempDir()
+		contents := run(t, bundleContentsOptions{packages: []string{"foo"}, extractDir: extractDir})
+		require.Len(t, contents, 1)
+		manifestsDir := filepath.Join(extractDir, "example/foo-bundle", "sha256-0123", bundleManifestsDir)
+		assert.Equal(t, manifestsDir, contents[0].ManifestsDir)
+		assert.FileExists(t, filepath.Join(manifestsDir, "foo.clusterserviceversion.yaml"))
+		assert.NoFileExists(t, filepath.Join(extractDir, "example/foo-bundle", "sha256-0123", "metadata", "annotations.yaml"))
+	})
+}
@@ -0,0 +1,418 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/operator-framework/operator-registry/alpha/model"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/image"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+const (
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffChanged = "changed"
+)
+
+type catalogDiffOptions struct {
+	from     string
+	to       string
+	output   string
+	copyOpts *mirror.CopyOptions
+}
+
+// valueChange is a value that differs between the two catalogs
+type valueChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// edgeDiff is a change of the upgrade edges of a channel entry
+type edgeDiff struct {
+	Bundle       string       `json:"bundle"`
+	Replaces     *valueChange `json:"replaces,omitempty"`
+	SkipRange    *valueChange `json:"skipRange,omitempty"`
+	AddedSkips   []string     `json:"addedSkips,omitempty"`
+	RemovedSkips []string     `json:"removedSkips,omitempty"`
+}
+
+// channelDiff lists the changes of a channel
+type channelDiff struct {
+	Name           string     `json:"name"`
+	Status         string     `json:"status"`
+	AddedEntries   []string   `json:"addedEntries,omitempty"`
+	RemovedEntries []string   `json:"removedEntries,omitempty"`
+	Edges          []edgeDiff `json:"edges,omitempty"`
+}
+
+// bundleDiff lists the changes of a bundle of a package
+type bundleDiff struct {
+	Name                 string       `json:"name"`
+	Status               string       `json:"status"`
+	Version              string       `json:"version,omitempty"`
+	Image                *valueChange `json:"image,omitempty"`
+	AddedRelatedImages   []string     `json:"addedRelatedImages,omitempty"`
+	RemovedRelatedImages []string     `json:"removedRelatedImages,omitempty"`
+}
+
+// packageDiff lists the changes of a package
+type packageDiff struct {
+	Name           string        `json:"name"`
+	Status         string        `json:"status"`
+	DefaultChannel *valueChange  `json:"defaultChannel,omitempty"`
+	Channels       []channelDiff `json:"channels,omitempty"`
+	Bundles        []bundleDiff  `json:"bundles,omitempty"`
+}
+
+// catalogDiff is the structured output of `list catalog-diff`
+type catalogDiff struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Packages []packageDiff `json:"packages"`
+}
+
+// NewCatalogDiffCommand returns a `list catalog-diff` command
+func NewCatalogDiffCommand(log clog.PluggableLoggerInterface, copyOpts *mirror.CopyOptions) *cobra.Command {
+	opts := catalogDiffOptions{copyOpts: copyOpts}
+	cmd := &cobra.Command{
+		Use:   "catalog-diff",
+		Short: "List the changes between two versions of an operator catalog",
+		Long: templates.LongDesc(`
+			List the packages, channels and bundles added, removed or changed between two
+			versions of an operator catalog, including default channel, upgrade edge and
+			related image changes.
+
+			When an ImageSetConfiguration is given with -c, the comparison is limited to the
+			packages and channels included for the catalog of the same repository.
+		`),
+		Example: templates.Examples(`
+			# Compare two versions of the redhat operator catalog
+			oc-mirror --v2 list catalog-diff --from registry.redhat.io/redhat/redhat-operator-index:v4.17 --to registry.redhat.io/redhat/redhat-operator-index:v4.18
+
+			# Compare only the packages mirrored by an ImageSetConfiguration, as json
+			oc-mirror --v2 list catalog-diff --from oci:///home/<user>/catalogs/redhat-v4.17 --to registry.redhat.io/redhat/redhat-operator-index:v4.18 -c ./isc.yaml -o json
+		`),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			for _, ref := range []string{opts.from, opts.to} {
+				if _, err := image.ParseRef(ref); err != nil {
+					return fmt.Errorf("%q: %w", ref, err)
+				}
+			}
+			return validateOutputFormat(opts.output)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// NOTE: we don't want help output on errors from here onwards
+			cmd.SilenceUsage = true
+			return runCatalogDiff(cmd.Context(), log, os.Stdout, &opts)
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&opts.from, "from", "", "Catalog image or oci:// layout to compare from.")
+	fs.StringVar(&opts.to, "to", "", "Catalog image or oci:// layout to compare to.")
+	addOutputFlag(cmd, &opts.output)
+
+	cmd.MarkFlagsRequiredTogether("from", "to")
+	cmd.MarkFlagsOneRequired("from", "to")
+
+	return cmd
+}
+
+func runCatalogDiff(ctx context.Context, log clog.PluggableLoggerInterface, w io.Writer, opts *catalogDiffOptions) error {
+	var include *v2alpha1.IncludeConfig
+	if opts.copyOpts.Global.ConfigPath != "" {
+		isc, err := readISC(opts.copyOpts.Global.ConfigPath)
+		if err != nil {
+			return err
+		}
+		if include, err = catalogIncludeConfig(isc.Mirror.Operators, opts.from, opts.to); err != nil {
+			return err
+		}
+	}
+
+	fromCatalog, err := downloadCatalog(ctx, log, opts.from, *opts.copyOpts)
+	if err != nil {
+		return fmt.Errorf("failed to load catalog %s: %w", opts.from, err)
+	}
+	toCatalog, err := downloadCatalog(ctx, log, opts.to, *opts.copyOpts)
+	if err != nil {
+		return fmt.Errorf("failed to load catalog %s: %w", opts.to, err)
+	}
+	if include != nil {
+		fromCatalog = narrowCatalog(fromCatalog, *include)
+		toCatalog = narrowCatalog(toCatalog, *include)
+	}
+
+	diff := catalogDiff{From: opts.from, To: opts.to, Packages: diffCatalogs(fromCatalog, toCatalog)}
+	if isStructured(opts.output) {
+		return printStructured(w, opts.output, diff)
+	}
+	printCatalogDiff(w, diff)
+	return nil
+}
+
+// catalogIncludeConfig returns the include filter of the operator catalog
+// sharing its repository with one of the compared catalogs
+func catalogIncludeConfig(operators []v2alpha1.Operator, refs ...string) (*v2alpha1.IncludeConfig, error) {
+	names := []string{}
+	for _, ref := range refs {
+		imgSpec, err := image.ParseRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, imgSpec.Name)
+	}
+	for _, op := range operators {
+		imgSpec, err := image.ParseRef(op.Catalog)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(names, imgSpec.Name) {
+			return &op.IncludeConfig, nil
+		}
+	}
+	return nil, errors.New("no operator catalog of the configuration matches the compared catalogs")
+}
+
+// narrowCatalog keeps the packages and channels of the include filter.
+// Without packages, the whole catalog is kept.
+func narrowCatalog(catalog model.Model, include v2alpha1.IncludeConfig) model.Model {
+	if len(include.Packages) == 0 {
+		return catalog
+	}
+	narrowed := model.Model{}
+	for _, pkgFilter := range include.Packages {
+		pkg, ok := catalog[pkgFilter.Name]
+		if !ok {
+			continue
+		}
+		if len(pkgFilter.Channels) == 0 {
+			narrowed[pkg.Name] = pkg
+			continue
+		}
+		narrowedPkg := *pkg
+		narrowedPkg.Channels = map[string]*model.Channel{}
+		for _, chFilter := range pkgFilter.Channels {
+			if ch, ok := pkg.Channels[chFilter.Name]; ok {
+				narrowedPkg.Channels[ch.Name] = ch
+			}
+		}
+		narrowed[pkg.Name] = &narrowedPkg
+	}
+	return narrowed
+}
+
+// diffCatalogs returns the changed packages, sorted by name
+func diffCatalogs(from, to model.Model) []packageDiff {
+	diffs := []packageDiff{}
+	for _, name := range sortedUnion(slices.Collect(maps.Keys(from)), slices.Collect(maps.Keys(to))) {
+		fromPkg, inFrom := from[name]
+		toPkg, inTo := to[name]
+		switch {
+		case !inFrom:
+			diffs = append(diffs, packageDiff{Name: name, Status: diffAdded})
+		case !inTo:
+			diffs = append(diffs, packageDiff{Name: name, Status: diffRemoved})
+		default:
+			if diff, changed := diffPackage(fromPkg, toPkg); changed {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+	return diffs
+}
+
+func diffPackage(from, to *model.Package) (packageDiff, bool) {
+	diff := packageDiff{Name: from.Name, Status: diffChanged}
+	if fromDefault, toDefault := defaultChannelName(from), defaultChannelName(to); fromDefault != toDefault {
+		diff.DefaultChannel = &valueChange{From: fromDefault, To: toDefault}
+	}
+
+	for _, name := range sortedUnion(slices.Collect(maps.Keys(from.Channels)), slices.Collect(maps.Keys(to.Channels))) {
+		fromCh, inFrom := from.Channels[name]
+		toCh, inTo := to.Channels[name]
+		switch {
+		case !inFrom:
+			diff.Channels = append(diff.Channels, channelDiff{Name: name, Status: diffAdded})
+		case !inTo:
+			diff.Channels = append(diff.Channels, channelDiff{Name: name, Status: diffRemoved})
+		default:
+			if chDiff, changed := diffChannel(fromCh, toCh); changed {
+				diff.Channels = append(diff.Channels, chDiff)
+			}
+		}
+	}
+
+	fromBundles, toBundles := packageBundles(from), packageBundles(to)
+	for _, name := range sortedUnion(slices.Collect(maps.Keys(fromBundles)), slices.Collect(maps.Keys(toBundles))) {
+		fromBundle, inFrom := fromBundles[name]
+		toBundle, inTo := toBundles[name]
+		switch {
+		case !inFrom:
+			diff.Bundles = append(diff.Bundles, bundleDiff{Name: name, Status: diffAdded, Version: toBundle.Version.String()})
+		case !inTo:
+			diff.Bundles = append(diff.Bundles, bundleDiff{Name: name, Status: diffRemoved, Version: fromBundle.Version.String()})
+		default:
+			if bDiff, changed := diffBundle(fromBundle, toBundle); changed {
+				diff.Bundles = append(diff.Bundles, bDiff)
+			}
+		}
+	}
+
+	changed := diff.DefaultChannel != nil || len(diff.Channels) > 0 || len(diff.Bundles) > 0
+	return diff, changed
+}
+
+func defaultChannelName(pkg *model.Package) string {
+	if pkg.DefaultChannel == nil {
+		return ""
+	}
+	return pkg.DefaultChannel.Name
+}
+
+func diffChannel(from, to *model.Channel) (channelDiff, bool) {
+	diff := channelDiff{Name: from.Name, Status: diffChanged}
+	for _, name := range sortedUnion(slices.Collect(maps.Keys(from.Bundles)), slices.Collect(maps.Keys(to.Bundles))) {
+		fromEntry, inFrom := from.Bundles[name]
+		toEntry, inTo := to.Bundles[name]
+		switch {
+		case !inFrom:
+			diff.AddedEntries = append(diff.AddedEntries, name)
+		case !inTo:
+			diff.RemovedEntries = append(diff.RemovedEntries, name)
+		default:
+			if edge, changed := diffEdges(fromEntry, toEntry); changed {
+				diff.Edges = append(diff.Edges, edge)
+			}
+		}
+	}
+	changed := len(diff.AddedEntries) > 0 || len(diff.RemovedEntries) > 0 || len(diff.Edges) > 0
+	return diff, changed
+}
+
+func diffEdges(from, to *model.Bundle) (edgeDiff, bool) {
+	diff := edgeDiff{Bundle: from.Name}
+	if from.Replaces != to.Replaces {
+		diff.Replaces = &valueChange{From: from.Replaces, To: to.Replaces}
+	}
+	if from.SkipRange != to.SkipRange {
+		diff.SkipRange = &valueChange{From: from.SkipRange, To: to.SkipRange}
+	}
+	diff.AddedSkips, diff.RemovedSkips = diffLists(from.Skips, to.Skips)
+	changed := diff.Replaces != nil || diff.SkipRange != nil || len(diff.AddedSkips) > 0 || len(diff.RemovedSkips) > 0
+	return diff, changed
+}
+
+func diffBundle(from, to *model.Bundle) (bundleDiff, bool) {
+	diff := bundleDiff{Name: from.Name, Status: diffChanged, Version: to.Version.String()}
+	if from.Image != to.Image {
+		diff.Image = &valueChange{From: from.Image, To: to.Image}
+	}
+	diff.AddedRelatedImages, diff.RemovedRelatedImages = diffLists(relatedImageRefs(from), relatedImageRefs(to))
+	changed := diff.Image != nil || len(diff.AddedRelatedImages) > 0 || len(diff.RemovedRelatedImages) > 0
+	return diff, changed
+}
+
+// packageBundles indexes the bundles of all the channels of a package by name
+func packageBundles(pkg *model.Package) map[string]*model.Bundle {
+	bundles := map[string]*model.Bundle{}
+	for _, ch := range pkg.Channels {
+		maps.Copy(bundles, ch.Bundles)
+	}
+	return bundles
+}
+
+func relatedImageRefs(b *model.Bundle) []string {
+	refs := []string{}
+	for _, ri := range b.RelatedImages {
+		refs = append(refs, ri.Image)
+	}
+	return refs
+}
+
+// diffLists returns the (sorted) elements only in to, and only in from
+func diffLists(from, to []string) ([]string, []string) {
+	var added, removed []string
+	for _, s := range sortedUnion(from, to) {
+		switch inFrom, inTo := slices.Contains(from, s), slices.Contains(to, s); {
+		case !inFrom:
+			added = append(added, s)
+		case !inTo:
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+func sortedUnion(a, b []string) []string {
+	return slices.Compact(slices.Sorted(slices.Values(append(slices.Clone(a), b...))))
+}
+
+var diffMarkers = map[string]string{diffAdded: "+", diffRemoved: "-", diffChanged: "~"}
+
+func printCatalogDiff(w io.Writer, diff catalogDiff) {
+	fmt.Fprintf(w, "Changes from %s to %s\n", diff.From, diff.To)
+	if len(diff.Packages) == 0 {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+	for _, pkg := range diff.Packages {
+		fmt.Fprintf(w, "%s package %s\n", diffMarkers[pkg.Status], pkg.Name)
+		if pkg.DefaultChannel != nil {
+			fmt.Fprintf(w, "    default channel: %s -> %s\n", pkg.DefaultChannel.From, pkg.DefaultChannel.To)
+		}
+		for _, ch := range pkg.Channels {
+			fmt.Fprintf(w, "    %s channel %s\n", diffMarkers[ch.Status], ch.Name)
+			for _, entry := range ch.AddedEntries {
+				fmt.Fprintf(w, "        + entry %s\n", entry)
+			}
+			for _, entry := range ch.RemovedEntries {
+				fmt.Fprintf(w, "        - entry %s\n", entry)
+			}
+			for _, edge := range ch.Edges {
+				fmt.Fprintf(w, "        ~ edges of %s: %s\n", edge.Bundle, describeEdgeDiff(edge))
+			}
+		}
+		for _, b := range pkg.Bundles {
+			fmt.Fprintf(w, "    %s bundle %s (%s)\n", diffMarkers[b.Status], b.Name, b.Version)
+			if b.Image != nil {
+				fmt.Fprintf(w, "        image: %s -> %s\n", b.Image.From, b.Image.To)
+			}
+			for _, ri := range b.AddedRelatedImages {
+				fmt.Fprintf(w, "        + related image %s\n", ri)
+			}
+			for _, ri := range b.RemovedRelatedImages {
+				fmt.Fprintf(w, "        - related image %s\n", ri)
+			}
+		}
+	}
+}
+
+func describeEdgeDiff(edge edgeDiff) string {
+	changes := []string{}
+	if edge.Replaces != nil {
+		changes = append(changes, fmt.Sprintf("replaces %q -> %q", edge.Replaces.From, edge.Replaces.To))
+	}
+	if edge.SkipRange != nil {
+		changes = append(changes, fmt.Sprintf("skipRange %q -> %q", edge.SkipRange.From, edge.SkipRange.To))
+	}
+	for _, skip := range edge.AddedSkips {
+		changes = append(changes, "+skips "+skip)
+	}
+	for _, skip := range edge.RemovedSkips {
+		changes = append(changes, "-skips "+skip)
+	}
+	return strings.Join(changes, ", ")
+}
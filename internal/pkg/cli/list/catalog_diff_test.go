@@ -0,0 +1,141 @@
+package list
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/operator-framework/operator-registry/alpha/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+type testEntry struct {
+	name, version, replaces string
+	skips                   []string
+	relatedImages           []string
+}
+
+// newDiffPackage returns a package whose first channel is the default one
+func newDiffPackage(name string, channels map[string][]testEntry, defaultChannel string) *model.Package {
+	pkg := &model.Package{Name: name, Channels: map[string]*model.Channel{}}
+	for chName, entries := range channels {
+		ch := &model.Channel{Package: pkg, Name: chName, Bundles: map[string]*model.Bundle{}}
+		for _, e := range entries {
+			b := &model.Bundle{
+				Package:  pkg,
+				Channel:  ch,
+				Name:     e.name,
+				Image:    "quay.io/example/" + e.name,
+				Replaces: e.replaces,
+				Skips:    e.skips,
+				Version:  semver.MustParse(e.version),
+			}
+			for _, ri := range e.relatedImages {
+				b.RelatedImages = append(b.RelatedImages, model.RelatedImage{Image: ri})
+			}
+			ch.Bundles[e.name] = b
+		}
+		pkg.Channels[chName] = ch
+	}
+	pkg.DefaultChannel = pkg.Channels[defaultChannel]
+	return pkg
+}
+
+func TestDiffCatalogs(t *testing.T) {
+	from := model.Model{
+		"foo": newDiffPackage("foo", map[string][]testEntry{
+			"stable": {
+				{name: "foo.v1.0.0", version: "1.0.0", relatedImages: []string{"quay.io/example/foo:1.0.0"}},
+				{name: "foo.v1.1.0", version: "1.1.0", replaces: "foo.v1.0.0", relatedImages: []string{"quay.io/example/foo:1.1.0"}},
+			},
+			// a bundle has the same content in all the channels it belongs to
+			"fast": {{name: "foo.v1.1.0", version: "1.1.0", relatedImages: []string{"quay.io/example/foo:1.1.0"}}},
+		}, "stable"),
+		"bar":       newDiffPackage("bar", map[string][]testEntry{"stable": {{name: "bar.v1.0.0", version: "1.0.0"}}}, "stable"),
+		"unchanged": newDiffPackage("unchanged", map[string][]testEntry{"stable": {{name: "unchanged.v1.0.0", version: "1.0.0"}}}, "stable"),
+	}
+	to := model.Model{
+		"foo": newDiffPackage("foo", map[string][]testEntry{
+			"stable": {
+				{name: "foo.v1.1.0", version: "1.1.0", relatedImages: []string{"quay.io/example/foo:1.1.0-1"}},
+				{name: "foo.v1.2.0", version: "1.2.0", replaces: "foo.v1.1.0", skips: []string{"foo.v1.0.0"}},
+			},
+			"candidate": {{name: "foo.v1.2.0", version: "1.2.0"}},
+		}, "candidate"),
+		"baz":       newDiffPackage("baz", map[string][]testEntry{"stable": {{name: "baz.v1.0.0", version: "1.0.0"}}}, "stable"),
+		"unchanged": newDiffPackage("unchanged", map[string][]testEntry{"stable": {{name: "unchanged.v1.0.0", version: "1.0.0"}}}, "stable"),
+	}
+
+	t.Run("should report added, removed and changed content", func(t *testing.T) {
+		diffs := diffCatalogs(from, to)
+		assert.Equal(t, []packageDiff{
+			{Name: "bar", Status: diffRemoved},
+			{Name: "baz", Status: diffAdded},
+			{
+				Name:           "foo",
+				Status:         diffChanged,
+				DefaultChannel: &valueChange{From: "stable", To: "candidate"},
+				Channels: []channelDiff{
+					{Name: "candidate", Status: diffAdded},
+					{Name: "fast", Status: diffRemoved},
+					{
+						Name:           "stable",
+						Status:         diffChanged,
+						AddedEntries:   []string{"foo.v1.2.0"},
+						RemovedEntries: []string{"foo.v1.0.0"},
+						Edges:          []edgeDiff{{Bundle: "foo.v1.1.0", Replaces: &valueChange{From: "foo.v1.0.0", To: ""}}},
+					},
+				},
+				Bundles: []bundleDiff{
+					{Name: "foo.v1.0.0", Status: diffRemoved, Version: "1.0.0"},
+					{
+						Name:                 "foo.v1.1.0",
+						Status:               diffChanged,
+						Version:              "1.1.0",
+						AddedRelatedImages:   []string{"quay.io/example/foo:1.1.0-1"},
+						RemovedRelatedImages: []string{"quay.io/example/foo:1.1.0"},
+					},
+					{Name: "foo.v1.2.0", Status: diffAdded, Version: "1.2.0"},
+				},
+			},
+		}, diffs)
+	})
+
+	t.Run("should only compare the included packages and channels", func(t *testing.T) {
+		include := v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+			{Name: "foo", Channels: []v2alpha1.IncludeChannel{{Name: "fast"}}},
+			{Name: "unchanged"},
+		}}
+		diffs := diffCatalogs(narrowCatalog(from, include), narrowCatalog(to, include))
+		assert.Equal(t, []packageDiff{{
+			Name:           "foo",
+			Status:         diffChanged,
+			DefaultChannel: &valueChange{From: "stable", To: "candidate"},
+			Channels:       []channelDiff{{Name: "fast", Status: diffRemoved}},
+			Bundles:        []bundleDiff{{Name: "foo.v1.1.0", Status: diffRemoved, Version: "1.1.0"}},
+		}}, diffs)
+	})
+
+	t.Run("should print the differences as text", func(t *testing.T) {
+		var buf bytes.Buffer
+		printCatalogDiff(&buf, catalogDiff{From: "catalog:v1", To: "catalog:v2", Packages: diffCatalogs(from, to)[:2]})
+		assert.Equal(t, "Changes from catalog:v1 to catalog:v2\n- package bar\n+ package baz\n", buf.String())
+	})
+}
+
+func TestCatalogIncludeConfig(t *testing.T) {
+	operators := []v2alpha1.Operator{
+		{Catalog: "registry.redhat.io/redhat/certified-operator-index:v4.17"},
+		{Catalog: "registry.redhat.io/redhat/redhat-operator-index:v4.17", IncludeConfig: v2alpha1.IncludeConfig{
+			Packages: []v2alpha1.IncludePackage{{Name: "foo"}},
+		}},
+	}
+	include, err := catalogIncludeConfig(operators, "registry.redhat.io/redhat/redhat-operator-index:v4.18")
+	assert.NoError(t, err)
+	assert.Equal(t, &operators[1].IncludeConfig, include)
+
+	_, err = catalogIncludeConfig(operators, "quay.io/example/catalog:latest")
+	assert.EqualError(t, err, "no operator catalog of the configuration matches the compared catalogs")
+}
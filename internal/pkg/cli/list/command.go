@@ -16,6 +16,9 @@ func NewListCommand(log clog.PluggableLoggerInterface, opts *mirror.CopyOptions)
 
 	cmd.AddCommand(NewListOperatorsCommand(log, opts))
 	cmd.AddCommand(NewListReleasesCommand(log, opts))
+	cmd.AddCommand(NewListUpdatesCommand(log, opts))
+	cmd.AddCommand(NewCatalogDiffCommand(log, opts))
+	cmd.AddCommand(NewBundleContentsCommand(log, opts))
 
 	return cmd
 }
@@ -45,9 +45,44 @@ type listOperatorsOptions struct {
 	operator   string
 	channel    string
 	version    string
+	output     string
 	globalOpts *mirror.CopyOptions
 }
 
+// operatorInfo is the structured output of `list operators --catalog`
+type operatorInfo struct {
+	Name           string `json:"name"`
+	DisplayName    string `json:"displayName,omitempty"`
+	DefaultChannel string `json:"defaultChannel,omitempty"`
+}
+
+// channelInfo describes a channel of an operator package
+type channelInfo struct {
+	Package string `json:"package"`
+	Name    string `json:"name"`
+	Head    string `json:"head,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// packageInfo is the structured output of `list operators --catalog --package`
+type packageInfo struct {
+	operatorInfo `json:",inline"`
+	Channels     []channelInfo `json:"channels"`
+}
+
+// bundleInfo is the structured output of `list operators --catalog --package --channel`
+type bundleInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// catalogsInfo is the structured output of `list operators --catalogs`
+type catalogsInfo struct {
+	Version  string   `json:"version"`
+	Catalogs []string `json:"catalogs"`
+	Invalid  []string `json:"invalid,omitempty"`
+}
+
 func NewListOperatorsCommand(log log.PluggableLoggerInterface, globalOpts *mirror.CopyOptions) *cobra.Command {
 	opts := &listOperatorsOptions{globalOpts: globalOpts}
 
@@ -66,6 +101,9 @@ func NewListOperatorsCommand(log log.PluggableLoggerInterface, globalOpts *mirro
 
             # List all available versions for a specified operator in a channel
             oc-mirror --v2 list operators --catalog=catalog-name --package=operator-name --channel=channel-name
+
+            # List all operator packages in a catalog as json
+            oc-mirror --v2 list operators --catalog=catalog-name -o json
     `),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if len(opts.version) > 0 {
@@ -82,7 +120,7 @@ func NewListOperatorsCommand(log log.PluggableLoggerInterface, globalOpts *mirro
 			if len(opts.channel) > 0 && len(opts.operator) == 0 {
 				return errors.New("must specify --catalog and --package with --channel")
 			}
-			return nil
+			return validateOutputFormat(opts.output)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// NOTE: we don't want help output on errors from here onwards
@@ -97,6 +135,7 @@ func NewListOperatorsCommand(log log.PluggableLoggerInterface, globalOpts *mirro
 	fs.StringVar(&opts.catalog, "catalog", "", "List information for a specified catalog.")
 	fs.StringVar(&opts.operator, "package", "", "List information for a specified package. Requires --catalog.")
 	fs.StringVar(&opts.channel, "channel", "", "List information for a specified channel. Requires --catalog and --package.")
+	addOutputFlag(cmd, &opts.output)
 
 	cmd.MarkFlagsRequiredTogether("catalogs", "version")
 	cmd.MarkFlagsMutuallyExclusive("catalog", "catalogs")
@@ -116,6 +155,10 @@ func run(ctx context.Context, log log.PluggableLoggerInterface, opts *listOperat
 		log.Debug("Loaded catalog %s", opts.catalog)
 	}
 
+	if isStructured(opts.output) {
+		return runStructured(ctx, log, os.Stdout, catalog, opts)
+	}
+
 	var err error
 	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
 	switch {
@@ -145,6 +188,38 @@ func run(ctx context.Context, log log.PluggableLoggerInterface, opts *listOperat
 	return err
 }
 
+// runStructured prints the same information as run, for automation
+func runStructured(ctx context.Context, log log.PluggableLoggerInterface, w io.Writer, catalog model.Model, opts *listOperatorsOptions) error {
+	var out any
+	switch {
+	case len(opts.channel) > 0:
+		pkg, ok := catalog[opts.operator]
+		if !ok {
+			return fmt.Errorf("operator %q not found in catalog", opts.operator)
+		}
+		ch, ok := pkg.Channels[opts.channel]
+		if !ok {
+			return fmt.Errorf("channel %q not found for operator %q", opts.channel, opts.operator)
+		}
+		out = channelBundles(ch)
+	case len(opts.operator) > 0:
+		pkg, ok := catalog[opts.operator]
+		if !ok {
+			return fmt.Errorf("operator %q not found in catalog", opts.operator)
+		}
+		out = packageChannels(pkg)
+	case len(opts.catalog) > 0:
+		out = catalogOperators(catalog)
+	case len(opts.version) > 0:
+		catalogs, err := catalogsForVersion(ctx, log, opts.version, *opts.globalOpts)
+		if err != nil {
+			return err
+		}
+		out = catalogs
+	}
+	return printStructured(w, opts.output, out)
+}
+
 func downloadCatalog(ctx context.Context, log log.PluggableLoggerInterface, catalog string, opts mirror.CopyOptions) (model.Model, error) {
 	handler := operator.CatalogHandler{
 		Log:      log,
@@ -189,49 +264,81 @@ func downloadCatalog(ctx context.Context, log log.PluggableLoggerInterface, cata
 
 func listBundles(w io.Writer, channel *model.Channel) error {
 	fmt.Fprintln(w, "VERSIONS")
-	for _, name := range slices.Sorted(maps.Keys(channel.Bundles)) {
-		fmt.Fprintf(w, "%s\n", channel.Bundles[name].Version)
+	for _, b := range channelBundles(channel) {
+		fmt.Fprintf(w, "%s\n", b.Version)
 	}
 
 	return nil
 }
 
+func channelBundles(channel *model.Channel) []bundleInfo {
+	bundles := make([]bundleInfo, 0, len(channel.Bundles))
+	for _, name := range slices.Sorted(maps.Keys(channel.Bundles)) {
+		bundles = append(bundles, bundleInfo{Name: name, Version: channel.Bundles[name].Version.String()})
+	}
+	return bundles
+}
+
 func listChannels(w io.Writer, pkg *model.Package) error {
-	if pkg.DefaultChannel != nil {
+	info := packageChannels(pkg)
+	if info.DefaultChannel != "" {
 		fmt.Fprintln(w, "NAME\tDISPLAY NAME\tDEFAULT CHANNEL")
-		fmt.Fprintf(w, "%s\t%s\t%s\n", pkg.Name, getDisplayName(pkg), pkg.DefaultChannel.Name)
+		fmt.Fprintf(w, "%s\t%s\t%s\n", info.Name, info.DisplayName, info.DefaultChannel)
 		fmt.Fprintln(w, "")
 	}
 
 	fmt.Fprintln(w, "PACKAGE\tCHANNEL\tHEAD")
+	for _, ch := range info.Channels {
+		head := ch.Head
+		if ch.Error != "" {
+			head = fmt.Sprintf("ERROR: %s", ch.Error)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", ch.Package, ch.Name, head)
+	}
+
+	return nil
+}
+
+func packageChannels(pkg *model.Package) packageInfo {
+	info := packageInfo{operatorInfo: packageOperator(pkg), Channels: []channelInfo{}}
 	for _, name := range slices.Sorted(maps.Keys(pkg.Channels)) {
 		ch := pkg.Channels[name]
-		var head string
+		chInfo := channelInfo{Package: pkg.Name, Name: ch.Name}
 		if h, err := ch.Head(); err != nil {
-			head = fmt.Sprintf("ERROR: %s", err)
+			chInfo.Error = err.Error()
 		} else {
-			head = h.Name
+			chInfo.Head = h.Name
 		}
-		fmt.Fprintf(w, "%s\t%s\t%s\n", pkg.Name, ch.Name, head)
+		info.Channels = append(info.Channels, chInfo)
 	}
-
-	return nil
+	return info
 }
 
 func listOperators(w io.Writer, catalogModel model.Model) error {
 	fmt.Fprintln(w, "NAME\tDISPLAY NAME\tDEFAULT CHANNEL")
-	for _, name := range slices.Sorted(maps.Keys(catalogModel)) {
-		op := catalogModel[name]
-		defChannelName := ""
-		if op.DefaultChannel != nil {
-			defChannelName = op.DefaultChannel.Name
-		}
-		fmt.Fprintf(w, "%s\t%s\t%s\n", op.Name, getDisplayName(op), defChannelName)
+	for _, op := range catalogOperators(catalogModel) {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", op.Name, op.DisplayName, op.DefaultChannel)
 	}
 
 	return nil
 }
 
+func catalogOperators(catalogModel model.Model) []operatorInfo {
+	operators := make([]operatorInfo, 0, len(catalogModel))
+	for _, name := range slices.Sorted(maps.Keys(catalogModel)) {
+		operators = append(operators, packageOperator(catalogModel[name]))
+	}
+	return operators
+}
+
+func packageOperator(pkg *model.Package) operatorInfo {
+	info := operatorInfo{Name: pkg.Name, DisplayName: getDisplayName(pkg)}
+	if pkg.DefaultChannel != nil {
+		info.DefaultChannel = pkg.DefaultChannel.Name
+	}
+	return info
+}
+
 func getDisplayName(pkg *model.Package) string {
 	// In the OLMv0 data model, the display name is not a property of the
 	// operator/package but is instead buried within a CSV bundle metadata.
@@ -268,6 +375,39 @@ func listCatalogsForVersion(ctx context.Context, log log.PluggableLoggerInterfac
 	fmt.Fprintln(w, "Available OpenShift OperatorHub catalogs:")
 	fmt.Fprintf(w, "OpenShift %s:\n", version)
 
+	checks, err := checkCatalogsForVersion(ctx, log, version, opts)
+	for _, check := range checks {
+		if check.exists {
+			fmt.Fprintf(w, "%s\n", check.ref)
+		} else {
+			fmt.Fprintf(w, "Invalid catalog reference %q, please check version\n", check.ref)
+		}
+	}
+
+	return err
+}
+
+// catalogsForVersion checks which of the default catalogs exist for an OpenShift version
+func catalogsForVersion(ctx context.Context, log log.PluggableLoggerInterface, version string, opts mirror.CopyOptions) (catalogsInfo, error) {
+	info := catalogsInfo{Version: version, Catalogs: []string{}}
+	checks, err := checkCatalogsForVersion(ctx, log, version, opts)
+	for _, check := range checks {
+		if check.exists {
+			info.Catalogs = append(info.Catalogs, check.ref)
+		} else {
+			info.Invalid = append(info.Invalid, check.ref)
+		}
+	}
+	return info, err
+}
+
+type catalogCheck struct {
+	ref    string
+	exists bool
+}
+
+func checkCatalogsForVersion(ctx context.Context, log log.PluggableLoggerInterface, version string, opts mirror.CopyOptions) ([]catalogCheck, error) {
+	var checks []catalogCheck
 	defaultCatalogs := []string{redhatCatalogRegistry, certifiedCatalogRegistry, communityCatalogRegistry}
 	if sv, err := semver.ParseTolerant(version); err == nil && sv.LT(marketplaceRemoveVersion) {
 		defaultCatalogs = append(defaultCatalogs, marketplaceCatalogRegistry)
@@ -282,14 +422,10 @@ func listCatalogsForVersion(ctx context.Context, log log.PluggableLoggerInterfac
 			errs = append(errs, fmt.Errorf("failed to check catalog %q versions: %w", catalog, err))
 			continue
 		}
-		if exists {
-			fmt.Fprintf(w, "%s\n", ref)
-		} else {
-			fmt.Fprintf(w, "Invalid catalog reference %q, please check version\n", ref)
-		}
+		checks = append(checks, catalogCheck{ref: ref, exists: exists})
 	}
 
-	return errors.Join(errs...)
+	return checks, errors.Join(errs...)
 }
 
 func taggedCatalogExists(ctx context.Context, catalog string, opts mirror.CopyOptions) (bool, error) {
@@ -234,3 +234,38 @@ func TestListBundles(t *testing.T) {
 		})
 	})
 }
+
+func TestRunStructured(t *testing.T) {
+	m := populateValidCatalogModel(t)
+
+	t.Run("should print the channels of a package as json", func(t *testing.T) {
+		w := strings.Builder{}
+		opts := &listOperatorsOptions{catalog: "example.com/catalog:v1", operator: "3scale-operator", output: outputJSON}
+		err := runStructured(t.Context(), log.New("debug"), &w, m, opts)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+  "name": "3scale-operator",
+  "displayName": "Red Hat Integration - 3scale",
+  "defaultChannel": "threescale-2.16",
+  "channels": [
+    {"package": "3scale-operator", "name": "threescale-2.16", "head": "3scale-operator.v0.13.2"},
+    {"package": "3scale-operator", "name": "threescale-mas", "head": "3scale-operator.v0.11.8-mas"}
+  ]
+}`, w.String())
+	})
+
+	t.Run("should print the bundles of a channel as yaml", func(t *testing.T) {
+		w := strings.Builder{}
+		opts := &listOperatorsOptions{catalog: "example.com/catalog:v1", operator: "3scale-operator", channel: "threescale-2.16", output: outputYAML}
+		err := runStructured(t.Context(), log.New("debug"), &w, m, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, "- name: 3scale-operator.v0.13.2\n  version: 0.13.2\n", w.String())
+	})
+
+	t.Run("should fail when the package does not exist", func(t *testing.T) {
+		w := strings.Builder{}
+		opts := &listOperatorsOptions{catalog: "example.com/catalog:v1", operator: "foo", output: outputJSON}
+		err := runStructured(t.Context(), log.New("debug"), &w, m, opts)
+		assert.ErrorContains(t, err, `operator "foo" not found in catalog`)
+	})
+}
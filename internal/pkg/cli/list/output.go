@@ -0,0 +1,55 @@
+package list
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// addOutputFlag registers the `-o, --output` flag shared by all list subcommands
+func addOutputFlag(cmd *cobra.Command, output *string) {
+	cmd.Flags().StringVarP(output, "output", "o", "", "Output format. One of: (json, yaml). Defaults to a human readable table.")
+}
+
+func validateOutputFormat(output string) error {
+	switch output {
+	case "", outputJSON, outputYAML:
+		return nil
+	}
+	return fmt.Errorf("invalid output format %q: must be one of (json, yaml)", output)
+}
+
+// isStructured returns true when the output is meant to be consumed by a program
+func isStructured(output string) bool {
+	return output == outputJSON || output == outputYAML
+}
+
+// printStructured writes v to w in the requested output format
+func printStructured(w io.Writer, output string, v any) error {
+	var (
+		data []byte
+		err  error
+	)
+	switch output {
+	case outputJSON:
+		data, err = json.MarshalIndent(v, "", "  ")
+		data = append(data, '\n')
+	case outputYAML:
+		data, err = yaml.Marshal(v)
+	default:
+		return fmt.Errorf("invalid output format %q", output)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
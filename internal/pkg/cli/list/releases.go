@@ -23,14 +23,41 @@ import (
 	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
 )
 
-const ocpReleaseRepo = "quay.io/openshift-release-dev/ocp-release"
+const (
+	ocpReleaseRepo = "quay.io/openshift-release-dev/ocp-release"
+	okdReleaseRepo = "quay.io/openshift/okd"
+	// okdDefaultChannel is used when listing OKD content for a --version
+	// without --channel: OKD channels are not split per minor version
+	okdDefaultChannel = "stable-4"
+)
 
 type listReleasesOptions struct {
-	channels    bool
-	channel     string
-	version     string
-	filterArchs []string
-	copyOpts    *mirror.CopyOptions
+	channels     bool
+	channel      string
+	version      string
+	filterArchs  []string
+	platformType string
+	output       string
+	copyOpts     *mirror.CopyOptions
+}
+
+// releaseChannelsInfo is the structured output of `list releases --channels`
+type releaseChannelsInfo struct {
+	Version  string   `json:"version"`
+	Channels []string `json:"channels"`
+}
+
+// releaseVersionsInfo is the structured output of `list releases` without channel
+type releaseVersionsInfo struct {
+	Versions []string `json:"versions"`
+}
+
+// channelVersionsInfo is the structured output of `list releases --channel`,
+// one per architecture
+type channelVersionsInfo struct {
+	Channel      string   `json:"channel"`
+	Architecture string   `json:"architecture"`
+	Versions     []string `json:"versions"`
 }
 
 // NewListReleasesCommand returns a `list releases` command
@@ -57,13 +84,23 @@ func NewListReleasesCommand(log clog.PluggableLoggerInterface, copyOpts *mirror.
 
 			# List OpenShift channels for a specific version.
 			oc-mirror --v2 list releases --channels --version=4.13
+
+			# List OKD releases in a specified channel as json
+			oc-mirror --v2 list releases --platform-type=okd --channel=stable-4 -o json
 		`),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if opts.channels && len(opts.version) == 0 {
 				return errors.New("must specify --version with --channels")
 			}
+			platformType, err := v2alpha1.ParsePlatformType(opts.platformType)
+			if err != nil {
+				return err
+			}
 			if len(opts.version) > 0 && len(opts.channel) == 0 {
 				opts.channel = fmt.Sprintf("stable-%s", opts.version)
+				if platformType == v2alpha1.TypeOKD {
+					opts.channel = okdDefaultChannel
+				}
 			}
 			if opts.channel == "stable-" {
 				return errors.New("must specify --version or --channel")
@@ -76,7 +113,7 @@ func NewListReleasesCommand(log clog.PluggableLoggerInterface, copyOpts *mirror.
 				return fmt.Errorf("invalid architecture(s) %v. Known architectures: %v", diff.UnsortedList(), sets.List(validArches))
 			}
 
-			return nil
+			return validateOutputFormat(opts.output)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// NOTE: we don't want help output on errors from here onwards
@@ -90,6 +127,8 @@ func NewListReleasesCommand(log clog.PluggableLoggerInterface, copyOpts *mirror.
 	fs.BoolVar(&opts.channels, "channels", false, "List all channel information. Requires --version.")
 	fs.StringVar(&opts.channel, "channel", "", "List information for a specific channel. Defaults to the stable channel.")
 	fs.StringSliceVar(&opts.filterArchs, "filter-by-archs", []string{v2alpha1.DefaultPlatformArchitecture}, "Architecture list to control the release image picked when multiple variants are available.")
+	fs.StringVar(&opts.platformType, "platform-type", v2alpha1.TypeOCP.String(), "Platform type of the releases. One of: (ocp, okd).")
+	addOutputFlag(cmd, &opts.output)
 
 	return cmd
 }
@@ -97,8 +136,10 @@ func NewListReleasesCommand(log clog.PluggableLoggerInterface, copyOpts *mirror.
 func runListReleases(ctx context.Context, log clog.PluggableLoggerInterface, opts *listReleasesOptions) error {
 	w := os.Stdout
 
-	updateURL := cincinnati.OcpUpdateURL
-	if override := os.Getenv("UPDATE_URL_OVERRIDE"); override != "" {
+	updateURL, releaseRepo := cincinnati.OcpUpdateURL, ocpReleaseRepo
+	if opts.platformType == v2alpha1.TypeOKD.String() {
+		updateURL, releaseRepo = cincinnati.OkdUpdateURL, okdReleaseRepo
+	} else if override := os.Getenv("UPDATE_URL_OVERRIDE"); override != "" {
 		updateURL = override
 	}
 
@@ -107,7 +148,7 @@ func runListReleases(ctx context.Context, log clog.PluggableLoggerInterface, opt
 	}
 
 	if len(opts.channel) == 0 {
-		return listReleaseMajorVersions(ctx, w, ocpReleaseRepo, opts)
+		return listReleaseMajorVersions(ctx, w, releaseRepo, opts)
 	}
 
 	return listVersionsForChannel(ctx, log, w, updateURL, opts)
@@ -119,9 +160,13 @@ func listChannelsForVersion(ctx context.Context, log clog.PluggableLoggerInterfa
 	if err != nil {
 		return fmt.Errorf("channel %q: %w", opts.channel, err)
 	}
-	channels := graph.GetChannels()
+	channels := sets.List(graph.GetChannels())
+	if isStructured(opts.output) {
+		return printStructured(w, opts.output, releaseChannelsInfo{Version: opts.version, Channels: channels})
+	}
+
 	fmt.Fprintf(w, "Listing channels for version %v:\n\n", opts.version)
-	for _, ch := range sets.List(channels) {
+	for _, ch := range channels {
 		fmt.Fprintf(w, "%s\n", ch)
 	}
 
@@ -145,7 +190,19 @@ func listReleaseMajorVersions(ctx context.Context, w io.Writer, releaseImage str
 		},
 	)
 
-	fmt.Fprintf(w, "Available OpenShift Container Platform release versions:\n")
+	if isStructured(opts.output) {
+		info := releaseVersionsInfo{Versions: make([]string, 0, len(sortedVers))}
+		for _, ver := range sortedVers {
+			info.Versions = append(info.Versions, ver.String())
+		}
+		return printStructured(w, opts.output, info)
+	}
+
+	product := "OpenShift Container Platform"
+	if opts.platformType == v2alpha1.TypeOKD.String() {
+		product = "OKD"
+	}
+	fmt.Fprintf(w, "Available %s release versions:\n", product)
 	for _, ver := range sortedVers {
 		fmt.Fprintf(w, "  %s\n", ver)
 	}
@@ -206,13 +263,14 @@ func getXYVersionsFromImage(ctx context.Context, image string, opts *listRelease
 }
 
 func listVersionsForChannel(ctx context.Context, log clog.PluggableLoggerInterface, w io.Writer, remoteURL string, opts *listReleasesOptions) error {
-	if strings.HasPrefix(opts.channel, "stable") {
+	if !isStructured(opts.output) && strings.HasPrefix(opts.channel, "stable") {
 		fmt.Fprintln(w, "Listing stable channels. Use --channel=<name> to filter.")
 		fmt.Fprintf(w, "Use oc-mirror --v2 list releases --channels --version=%s to discover other channels.\n", opts.version)
 		fmt.Fprintln(w, "")
 	}
 
 	var errs []error
+	infos := []channelVersionsInfo{}
 	for _, arch := range opts.filterArchs {
 		graph, err := loadGraphData(ctx, log, remoteURL, arch, opts.channel)
 		if err != nil {
@@ -225,6 +283,15 @@ func listVersionsForChannel(ctx context.Context, log clog.PluggableLoggerInterfa
 			continue
 		}
 
+		if isStructured(opts.output) {
+			info := channelVersionsInfo{Channel: opts.channel, Architecture: arch, Versions: make([]string, 0, len(vers))}
+			for _, ver := range vers {
+				info.Versions = append(info.Versions, ver.String())
+			}
+			infos = append(infos, info)
+			continue
+		}
+
 		fmt.Fprintf(w, "Channel: %s\nArchitecture: %s\n", opts.channel, arch)
 		for _, ver := range vers {
 			fmt.Fprintf(w, "%s\n", ver)
@@ -232,6 +299,12 @@ func listVersionsForChannel(ctx context.Context, log clog.PluggableLoggerInterfa
 
 	}
 
+	if isStructured(opts.output) {
+		if err := printStructured(w, opts.output, infos); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	return errors.Join(errs...)
 }
 
@@ -0,0 +1,449 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/blang/semver/v4"
+	"github.com/operator-framework/operator-registry/alpha/model"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/cincinnati"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/config"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+const (
+	workingDirName  = "working-dir"
+	pinnedISCPrefix = "isc_pinned_"
+	holdReleaseDir  = "hold-release"
+)
+
+// releaseTagArchSuffixes are the architecture suffixes of OCP release tags (e.g. 4.16.0-x86_64)
+var releaseTagArchSuffixes = []string{"-x86_64", "-aarch64", "-ppc64le", "-s390x", "-multi"}
+
+type listUpdatesOptions struct {
+	output   string
+	copyOpts *mirror.CopyOptions
+}
+
+// releaseUpdate lists the releases of a channel newer than the last mirrored one
+type releaseUpdate struct {
+	Channel      string   `json:"channel"`
+	Architecture string   `json:"architecture"`
+	LastVersion  string   `json:"lastVersion,omitempty"`
+	Versions     []string `json:"versions"`
+}
+
+// bundleUpdate is an operator bundle not present in the last mirrored catalog
+type bundleUpdate struct {
+	Catalog  string `json:"catalog"`
+	Package  string `json:"package"`
+	Channel  string `json:"channel"`
+	Bundle   string `json:"bundle"`
+	Version  string `json:"version"`
+	Replaces string `json:"replaces,omitempty"`
+}
+
+// updatesInfo is the structured output of `list updates`
+type updatesInfo struct {
+	PinnedConfig string          `json:"pinnedConfig"`
+	Releases     []releaseUpdate `json:"releases"`
+	Operators    []bundleUpdate  `json:"operators"`
+}
+
+// NewListUpdatesCommand returns a `list updates` command
+func NewListUpdatesCommand(log clog.PluggableLoggerInterface, copyOpts *mirror.CopyOptions) *cobra.Command {
+	opts := listUpdatesOptions{copyOpts: copyOpts}
+	cmd := &cobra.Command{
+		Use:   "updates",
+		Short: "List available updates since the last mirror operation",
+		Long: templates.LongDesc(`
+			List the releases and operator bundles published since the last mirror operation.
+
+			The last mirror operation is read from the most recent pinned ImageSetConfiguration
+			(isc_pinned_*.yaml) of the workspace. When an ImageSetConfiguration is given with -c,
+			its catalog references are used as the current catalogs, otherwise the catalog tags
+			recorded in the pinned configuration are used.
+		`),
+		Example: templates.Examples(`
+			# List updates since the last mirror to disk
+			oc-mirror --v2 list updates --workspace file:///home/<user>/oc-mirror/mirror1
+
+			# List updates against the catalogs of a new ImageSetConfiguration, as yaml
+			oc-mirror --v2 list updates --workspace file:///home/<user>/oc-mirror/mirror1 -c ./isc.yaml -o yaml
+		`),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(opts.copyOpts.Global.WorkingDir) == 0 {
+				return errors.New("must specify --workspace")
+			}
+			if !strings.HasPrefix(opts.copyOpts.Global.WorkingDir, consts.FileProtocol) {
+				return errors.New("when --workspace is used, it must have file:// prefix")
+			}
+			return validateOutputFormat(opts.output)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// NOTE: we don't want help output on errors from here onwards
+			cmd.SilenceUsage = true
+			return runListUpdates(cmd.Context(), log, os.Stdout, &opts)
+		},
+	}
+
+	addOutputFlag(cmd, &opts.output)
+
+	return cmd
+}
+
+func runListUpdates(ctx context.Context, log clog.PluggableLoggerInterface, w io.Writer, opts *listUpdatesOptions) error {
+	workingDir := strings.TrimPrefix(opts.copyOpts.Global.WorkingDir, consts.FileProtocol)
+	if filepath.Base(workingDir) != workingDirName {
+		workingDir = filepath.Join(workingDir, workingDirName)
+	}
+
+	pinnedPath, err := latestPinnedISC(workingDir)
+	if err != nil {
+		return err
+	}
+	log.Debug("Reading last mirrored configuration from %s", pinnedPath)
+	pinned, err := readISC(pinnedPath)
+	if err != nil {
+		return fmt.Errorf("pinned config %s: %w", pinnedPath, err)
+	}
+
+	current := pinned
+	if opts.copyOpts.Global.ConfigPath != "" {
+		if current, err = readISC(opts.copyOpts.Global.ConfigPath); err != nil {
+			return err
+		}
+	}
+
+	info := updatesInfo{PinnedConfig: pinnedPath}
+	var errs []error
+
+	releases, err := releaseUpdates(ctx, log, workingDir, pinned.Mirror.Platform)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	info.Releases = releases
+
+	operators, err := operatorUpdates(ctx, log, pinned.Mirror.Operators, current.Mirror.Operators, *opts.copyOpts)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	info.Operators = operators
+
+	if isStructured(opts.output) {
+		errs = append(errs, printStructured(w, opts.output, info))
+		return errors.Join(errs...)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	printUpdates(tw, info)
+	tw.Flush()
+
+	return errors.Join(errs...)
+}
+
+// latestPinnedISC returns the most recent pinned ImageSetConfiguration of the working-dir.
+// The file names embed an RFC3339 UTC timestamp, so the lexical order is the chronological one.
+func latestPinnedISC(workingDir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(workingDir, pinnedISCPrefix+"*.yaml"))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no %s*.yaml found in %s: run a mirror to disk or mirror to mirror first", pinnedISCPrefix, workingDir)
+	}
+	slices.Sort(matches)
+	return matches[len(matches)-1], nil
+}
+
+func readISC(path string) (v2alpha1.ImageSetConfiguration, error) {
+	cfg, err := config.ReadConfig(path, v2alpha1.ImageSetConfigurationKind)
+	if err != nil {
+		return v2alpha1.ImageSetConfiguration{}, err
+	}
+	isc, ok := cfg.(v2alpha1.ImageSetConfiguration)
+	if !ok {
+		return v2alpha1.ImageSetConfiguration{}, fmt.Errorf("%s is not an %s", path, v2alpha1.ImageSetConfigurationKind)
+	}
+	return isc, nil
+}
+
+// releaseUpdates reports, for each channel and architecture of the platform,
+// the versions of the upgrade graph newer than the last mirrored one
+func releaseUpdates(ctx context.Context, log clog.PluggableLoggerInterface, workingDir string, platform v2alpha1.Platform) ([]releaseUpdate, error) {
+	updates := []releaseUpdate{}
+	if len(platform.Channels) == 0 {
+		return updates, nil
+	}
+
+	//nolint:staticcheck // SA1019: Architectures is deprecated but we maintain backward compatibility
+	archs := platform.Architectures
+	if len(platform.Platforms) > 0 {
+		// platform.platforms mirrors the multi payload
+		archs = []string{"multi"}
+	}
+	if len(archs) == 0 {
+		archs = []string{v2alpha1.DefaultPlatformArchitecture}
+	}
+
+	var errs []error
+	for _, ch := range platform.Channels {
+		updateURL, releaseRepo := cincinnati.OcpUpdateURL, ocpReleaseRepo
+		if ch.Type == v2alpha1.TypeOKD {
+			updateURL, releaseRepo = cincinnati.OkdUpdateURL, okdReleaseRepo
+		} else if override := os.Getenv("UPDATE_URL_OVERRIDE"); override != "" {
+			updateURL = override
+		}
+
+		last, err := lastMirroredVersion(workingDir, releaseRepo, ch)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %q: %w", ch.Name, err))
+			continue
+		}
+
+		for _, arch := range archs {
+			graph, err := loadGraphData(ctx, log, updateURL, arch, ch.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("channel %q: %w", ch.Name, err))
+				continue
+			}
+			update := releaseUpdate{Channel: ch.Name, Architecture: arch, Versions: newerVersions(graph.GetVersions(nil), last)}
+			if last != nil {
+				update.LastVersion = last.String()
+			}
+			updates = append(updates, update)
+		}
+	}
+
+	return updates, errors.Join(errs...)
+}
+
+// lastMirroredVersion returns the maxVersion of the channel when set,
+// otherwise the highest release of the channel extracted in the working-dir.
+// The releases of other channels (e.g. 4.17 for stable-4.16) are left out.
+// A nil version means that every version of the channel is an update.
+func lastMirroredVersion(workingDir, releaseRepo string, ch v2alpha1.ReleaseChannel) (*semver.Version, error) {
+	if ch.MaxVersion != "" {
+		v, err := semver.Parse(ch.MaxVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxVersion %q: %w", ch.MaxVersion, err)
+		}
+		return &v, nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(workingDir, holdReleaseDir, filepath.Base(releaseRepo)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var last *semver.Version
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		v, ok := releaseTagVersion(entry.Name())
+		if !ok || !channelHasVersion(ch.Name, v) {
+			continue
+		}
+		if ch.MinVersion != "" {
+			if minVersion, err := semver.Parse(ch.MinVersion); err == nil && v.LT(minVersion) {
+				continue
+			}
+		}
+		if last == nil || v.GT(*last) {
+			last = &v
+		}
+	}
+	return last, nil
+}
+
+// channelHasVersion tells whether a version belongs to the major.minor a channel is named after
+// (e.g. stable-4.16), or to its major when the channel only names it (e.g. stable-4 or 4-stable
+// of OKD). Channels without a version hold all the versions.
+func channelHasVersion(channel string, v semver.Version) bool {
+	for _, part := range strings.Split(channel, "-") {
+		majorStr, minorStr, hasMinor := strings.Cut(part, ".")
+		major, err := strconv.ParseUint(majorStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		if !hasMinor {
+			return major == v.Major
+		}
+		minor, err := strconv.ParseUint(minorStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		return major == v.Major && minor == v.Minor
+	}
+	return true
+}
+
+// releaseTagVersion parses the version of a release tag (e.g. 4.16.0-x86_64, 4.15.0-0.okd-2024-03-10-010116)
+func releaseTagVersion(tag string) (semver.Version, bool) {
+	for _, suffix := range releaseTagArchSuffixes {
+		if trimmed, ok := strings.CutSuffix(tag, suffix); ok {
+			tag = trimmed
+			break
+		}
+	}
+	v, err := semver.Parse(tag)
+	if err != nil {
+		return semver.Version{}, false
+	}
+	return v, true
+}
+
+// newerVersions returns the (sorted) versions greater than last
+func newerVersions(versions []semver.Version, last *semver.Version) []string {
+	newer := []string{}
+	for _, v := range versions {
+		if last == nil || v.GT(*last) {
+			newer = append(newer, v.String())
+		}
+	}
+	return newer
+}
+
+// operatorUpdates compares each catalog of the pinned configuration with its current version
+func operatorUpdates(ctx context.Context, log clog.PluggableLoggerInterface, pinned, current []v2alpha1.Operator, opts mirror.CopyOptions) ([]bundleUpdate, error) {
+	updates := []bundleUpdate{}
+	var errs []error
+	for i, op := range pinned {
+		currentRef := currentCatalogRef(op, current, i)
+		log.Debug("Comparing catalog %s with %s", op.Catalog, currentRef)
+
+		oldCatalog, err := downloadCatalog(ctx, log, op.Catalog, opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("catalog %s: %w", op.Catalog, err))
+			continue
+		}
+		newCatalog, err := downloadCatalog(ctx, log, currentRef, opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("catalog %s: %w", currentRef, err))
+			continue
+		}
+		updates = append(updates, newBundles(currentRef, op.IncludeConfig, oldCatalog, newCatalog)...)
+	}
+	return updates, errors.Join(errs...)
+}
+
+// currentCatalogRef returns the reference of the catalog to compare with the pinned one:
+// the catalog at the same position in the current configuration when given,
+// the tag recorded when pinning otherwise.
+func currentCatalogRef(pinned v2alpha1.Operator, current []v2alpha1.Operator, idx int) string {
+	if idx < len(current) && current[idx].Catalog != pinned.Catalog {
+		return current[idx].Catalog
+	}
+	name, _, found := strings.Cut(pinned.Catalog, "@")
+	if found && pinned.TargetTag != "" {
+		return name + ":" + pinned.TargetTag
+	}
+	return pinned.Catalog
+}
+
+// newBundles returns the bundles of newCatalog that are not in the same package
+// and channel of oldCatalog, restricted to the packages and channels of the include config.
+func newBundles(catalogRef string, include v2alpha1.IncludeConfig, oldCatalog, newCatalog model.Model) []bundleUpdate {
+	updates := []bundleUpdate{}
+	for _, pkgName := range slices.Sorted(maps.Keys(newCatalog)) {
+		var includedChannels []string
+		if len(include.Packages) > 0 {
+			idx := slices.IndexFunc(include.Packages, func(p v2alpha1.IncludePackage) bool { return p.Name == pkgName })
+			if idx == -1 {
+				continue
+			}
+			for _, ch := range include.Packages[idx].Channels {
+				includedChannels = append(includedChannels, ch.Name)
+			}
+		}
+
+		pkg := newCatalog[pkgName]
+		for _, chName := range slices.Sorted(maps.Keys(pkg.Channels)) {
+			if len(includedChannels) > 0 && !slices.Contains(includedChannels, chName) {
+				continue
+			}
+			var oldChannel *model.Channel
+			if oldPkg, ok := oldCatalog[pkgName]; ok {
+				oldChannel = oldPkg.Channels[chName]
+			}
+			for _, b := range channelBundlesByVersion(pkg.Channels[chName]) {
+				if oldChannel != nil {
+					if _, ok := oldChannel.Bundles[b.Name]; ok {
+						continue
+					}
+				}
+				updates = append(updates, bundleUpdate{
+					Catalog:  catalogRef,
+					Package:  pkgName,
+					Channel:  chName,
+					Bundle:   b.Name,
+					Version:  b.Version.String(),
+					Replaces: b.Replaces,
+				})
+			}
+		}
+	}
+	return updates
+}
+
+func channelBundlesByVersion(ch *model.Channel) []*model.Bundle {
+	bundles := make([]*model.Bundle, 0, len(ch.Bundles))
+	for _, b := range ch.Bundles {
+		bundles = append(bundles, b)
+	}
+	slices.SortFunc(bundles, func(a, b *model.Bundle) int { return a.Version.Compare(b.Version) })
+	return bundles
+}
+
+func printUpdates(w io.Writer, info updatesInfo) {
+	fmt.Fprintf(w, "Listing updates since %s\n\n", filepath.Base(info.PinnedConfig))
+
+	if len(info.Releases) > 0 {
+		fmt.Fprintln(w, "Releases:")
+		fmt.Fprintln(w, "Channel\tArchitecture\tLast mirrored\tAvailable updates")
+		for _, r := range info.Releases {
+			last := r.LastVersion
+			if last == "" {
+				last = "-"
+			}
+			updates := strings.Join(r.Versions, ",")
+			if updates == "" {
+				updates = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Channel, r.Architecture, last, updates)
+		}
+		fmt.Fprintln(w, "")
+	}
+
+	catalog := ""
+	for _, b := range info.Operators {
+		if b.Catalog != catalog {
+			catalog = b.Catalog
+			fmt.Fprintf(w, "Catalog: %s\n", catalog)
+			fmt.Fprintln(w, "Package\tChannel\tBundle\tReplaces")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", b.Package, b.Channel, b.Bundle, b.Replaces)
+	}
+	if len(info.Releases) == 0 && len(info.Operators) == 0 {
+		fmt.Fprintln(w, "No updates found")
+	}
+}
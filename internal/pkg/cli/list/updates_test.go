@@ -0,0 +1,169 @@
+package list
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/operator-framework/operator-registry/alpha/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+func TestLatestPinnedISC(t *testing.T) {
+	workingDir := t.TempDir()
+	_, err := latestPinnedISC(workingDir)
+	assert.ErrorContains(t, err, "no isc_pinned_*.yaml found")
+
+	for _, name := range []string{
+		"isc_pinned_2025-12-31T11:37:17Z.yaml",
+		"isc_pinned_2026-01-02T08:00:00Z.yaml",
+		"isc_pinned_2026-01-01T23:59:59Z.yaml",
+		"disc_pinned_2026-02-01T00:00:00Z.yaml",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(workingDir, name), nil, 0o600))
+	}
+	latest, err := latestPinnedISC(workingDir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(workingDir, "isc_pinned_2026-01-02T08:00:00Z.yaml"), latest)
+}
+
+func TestLastMirroredVersion(t *testing.T) {
+	workingDir := t.TempDir()
+	holdDir := filepath.Join(workingDir, holdReleaseDir, "ocp-release")
+	for _, tag := range []string{"4.16.2-x86_64", "4.16.10-x86_64", "4.17.1-multi", "not-a-release"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(holdDir, tag), 0o755))
+	}
+
+	t.Run("should use the highest extracted release of the channel", func(t *testing.T) {
+		// 4.17.1 was mirrored for another channel
+		v, err := lastMirroredVersion(workingDir, ocpReleaseRepo, v2alpha1.ReleaseChannel{Name: "stable-4.16"})
+		require.NoError(t, err)
+		require.NotNil(t, v)
+		assert.Equal(t, "4.16.10", v.String())
+
+		v, err = lastMirroredVersion(workingDir, ocpReleaseRepo, v2alpha1.ReleaseChannel{Name: "fast-4.17"})
+		require.NoError(t, err)
+		require.NotNil(t, v)
+		assert.Equal(t, "4.17.1", v.String())
+
+		v, err = lastMirroredVersion(workingDir, ocpReleaseRepo, v2alpha1.ReleaseChannel{Name: "eus-4.18"})
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("should prefer maxVersion", func(t *testing.T) {
+		v, err := lastMirroredVersion(workingDir, ocpReleaseRepo, v2alpha1.ReleaseChannel{Name: "stable-4.16", MaxVersion: "4.16.5"})
+		require.NoError(t, err)
+		assert.Equal(t, "4.16.5", v.String())
+	})
+
+	t.Run("should return nil when nothing was mirrored", func(t *testing.T) {
+		v, err := lastMirroredVersion(workingDir, okdReleaseRepo, v2alpha1.ReleaseChannel{Name: "stable-4", Type: v2alpha1.TypeOKD})
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+}
+
+func TestChannelHasVersion(t *testing.T) {
+	tests := []struct {
+		channel string
+		version string
+		want    bool
+	}{
+		{channel: "stable-4.16", version: "4.16.3", want: true},
+		{channel: "stable-4.16", version: "4.17.0", want: false},
+		{channel: "candidate-4.17", version: "4.17.0-rc.1", want: true},
+		{channel: "stable-4", version: "4.15.0-0.okd-2024-03-10-010116", want: true},
+		{channel: "4-stable", version: "5.0.0", want: false},
+		{channel: "stable-scos-4", version: "4.16.0-okd-scos.0", want: true},
+		{channel: "stable", version: "4.16.3", want: true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, channelHasVersion(tt.channel, semver.MustParse(tt.version)), "%s %s", tt.channel, tt.version)
+	}
+}
+
+func TestNewerVersions(t *testing.T) {
+	versions := []semver.Version{semver.MustParse("4.16.1"), semver.MustParse("4.16.2"), semver.MustParse("4.16.3")}
+	last := semver.MustParse("4.16.1")
+	assert.Equal(t, []string{"4.16.2", "4.16.3"}, newerVersions(versions, &last))
+	assert.Equal(t, []string{"4.16.1", "4.16.2", "4.16.3"}, newerVersions(versions, nil))
+}
+
+func TestCurrentCatalogRef(t *testing.T) {
+	pinned := v2alpha1.Operator{Catalog: "registry.redhat.io/redhat/redhat-operator-index@sha256:1234", TargetTag: "v4.16"}
+	assert.Equal(t, "registry.redhat.io/redhat/redhat-operator-index:v4.16", currentCatalogRef(pinned, nil, 0))
+	assert.Equal(t, "registry.redhat.io/redhat/redhat-operator-index:v4.17",
+		currentCatalogRef(pinned, []v2alpha1.Operator{{Catalog: "registry.redhat.io/redhat/redhat-operator-index:v4.17"}}, 0))
+}
+
+func TestNewBundles(t *testing.T) {
+	oldCatalog := channelModel(map[string][]string{"foo": {"foo.v1.0.0"}, "bar": {"bar.v1.0.0"}})
+	newCatalog := channelModel(map[string][]string{"foo": {"foo.v1.0.0", "foo.v1.1.0"}, "bar": {"bar.v1.0.0", "bar.v2.0.0"}, "baz": {"baz.v0.1.0"}})
+
+	t.Run("should report bundles of all packages", func(t *testing.T) {
+		updates := newBundles("catalog:v1", v2alpha1.IncludeConfig{}, oldCatalog, newCatalog)
+		assert.Equal(t, []bundleUpdate{
+			{Catalog: "catalog:v1", Package: "bar", Channel: "stable", Bundle: "bar.v2.0.0", Version: "2.0.0", Replaces: "bar.v1.0.0"},
+			{Catalog: "catalog:v1", Package: "baz", Channel: "stable", Bundle: "baz.v0.1.0", Version: "0.1.0"},
+			{Catalog: "catalog:v1", Package: "foo", Channel: "stable", Bundle: "foo.v1.1.0", Version: "1.1.0", Replaces: "foo.v1.0.0"},
+		}, updates)
+	})
+
+	t.Run("should only report the included packages and channels", func(t *testing.T) {
+		include := v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+			{Name: "foo"},
+			{Name: "bar", Channels: []v2alpha1.IncludeChannel{{Name: "fast"}}},
+		}}
+		updates := newBundles("catalog:v1", include, oldCatalog, newCatalog)
+		assert.Equal(t, []bundleUpdate{
+			{Catalog: "catalog:v1", Package: "foo", Channel: "stable", Bundle: "foo.v1.1.0", Version: "1.1.0", Replaces: "foo.v1.0.0"},
+		}, updates)
+	})
+}
+
+// channelModel returns a catalog model with a stable channel per package, each bundle replacing the previous one.
+func channelModel(bundles map[string][]string) model.Model {
+	m := model.Model{}
+	for pkgName, names := range bundles {
+		pkg := &model.Package{Name: pkgName, Channels: map[string]*model.Channel{}}
+		ch := &model.Channel{Name: "stable", Package: pkg, Bundles: map[string]*model.Bundle{}}
+		for i, name := range names {
+			b := &model.Bundle{Name: name, Package: pkg, Channel: ch, Version: semver.MustParse(strings.Split(name, ".v")[1])}
+			if i > 0 {
+				b.Replaces = names[i-1]
+			}
+			ch.Bundles[name] = b
+		}
+		pkg.Channels["stable"] = ch
+		m[pkgName] = pkg
+	}
+	return m
+}
+
+func TestPrintStructured(t *testing.T) {
+	info := updatesInfo{
+		PinnedConfig: "isc_pinned_2026-01-02T08:00:00Z.yaml",
+		Releases:     []releaseUpdate{{Channel: "stable-4.16", Architecture: "amd64", LastVersion: "4.16.1", Versions: []string{"4.16.2"}}},
+		Operators:    []bundleUpdate{},
+	}
+
+	w := strings.Builder{}
+	require.NoError(t, printStructured(&w, outputYAML, info))
+	assert.Equal(t, `operators: []
+pinnedConfig: isc_pinned_2026-01-02T08:00:00Z.yaml
+releases:
+- architecture: amd64
+  channel: stable-4.16
+  lastVersion: 4.16.1
+  versions:
+  - 4.16.2
+`, w.String())
+
+	assert.ErrorContains(t, validateOutputFormat("table"), `invalid output format "table"`)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/batch"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/emoji"
+)
+
+// remainingImages records in the working-dir the images a time-boxed mirrorToDisk run left
+// out of its archive. It travels with the archive, for diskToMirror to skip these images.
+type remainingImages struct {
+	MaxDuration string   `json:"maxDuration"`
+	Origins     []string `json:"origins"`
+}
+
+// saveRemainingImages writes the images left by a time-boxed run. After a complete run
+// following a time-boxed one, the record is emptied rather than removed: the next archive
+// overwrites the record extracted from the previous one on the diskToMirror side.
+func (o *ExecutorSchema) saveRemainingImages(timeBoxErr *batch.TimeBoxError) error {
+	path := filepath.Join(o.Opts.Global.WorkingDir, remainingImgsFile)
+	record := remainingImages{Origins: []string{}}
+	if timeBoxErr == nil {
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+	} else {
+		record.MaxDuration = timeBoxErr.MaxDuration.String()
+		for _, img := range timeBoxErr.Remaining {
+			record.Origins = append(record.Origins, img.Origin)
+		}
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling the remaining images: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing the remaining images: %w", err)
+	}
+	return nil
+}
+
+// skipRemainingImages removes the images which are not in the archive of a time-boxed
+// mirrorToDisk run.
+func (o *ExecutorSchema) skipRemainingImages(images []v2alpha1.CopyImageSchema) ([]v2alpha1.CopyImageSchema, error) {
+	data, err := os.ReadFile(filepath.Join(o.Opts.Global.WorkingDir, remainingImgsFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return images, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading the remaining images: %w", err)
+	}
+	var record remainingImages
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("parsing the remaining images: %w", err)
+	}
+	if len(record.Origins) == 0 {
+		return images, nil
+	}
+
+	remaining := make(map[string]bool, len(record.Origins))
+	for _, origin := range record.Origins {
+		remaining[origin] = true
+	}
+	kept := make([]v2alpha1.CopyImageSchema, 0, len(images))
+	for _, img := range images {
+		if !remaining[img.Origin] {
+			kept = append(kept, img)
+		}
+	}
+	o.Log.Warn(emoji.Hourglass+" the archive comes from a run stopped by its max duration of %s: %d images are skipped, they are in the next archive", record.MaxDuration, len(images)-len(kept))
+	return kept, nil
+}
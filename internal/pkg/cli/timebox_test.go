@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/batch"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+func TestRemainingImages(t *testing.T) {
+	images := []v2alpha1.CopyImageSchema{
+		{Origin: "docker://quay.io/openshift-release-dev/ocp-release:4.18.1-x86_64", Type: v2alpha1.TypeOCPRelease},
+		{Origin: "docker://quay.io/acme/foo-operator:v1", Type: v2alpha1.TypeOperatorRelatedImage},
+		{Origin: "docker://registry.redhat.io/ubi9/ubi:latest", Type: v2alpha1.TypeGeneric},
+	}
+	workingDir := t.TempDir()
+	ex := &ExecutorSchema{
+		Log:  clog.New("debug"),
+		Opts: &mirror.CopyOptions{Global: &mirror.GlobalOptions{WorkingDir: workingDir}},
+	}
+	record := filepath.Join(workingDir, remainingImgsFile)
+
+	// a complete run doesn't write any record
+	require.NoError(t, ex.saveRemainingImages(nil))
+	assert.NoFileExists(t, record)
+	kept, err := ex.skipRemainingImages(images)
+	require.NoError(t, err)
+	assert.Equal(t, images, kept)
+
+	// a time-boxed run records the remaining images, which are skipped by diskToMirror
+	require.NoError(t, ex.saveRemainingImages(&batch.TimeBoxError{MaxDuration: 6 * time.Hour, Remaining: images[1:]}))
+	data, err := os.ReadFile(record)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"maxDuration": "6h0m0s", "origins": ["docker://quay.io/acme/foo-operator:v1", "docker://registry.redhat.io/ubi9/ubi:latest"]}`, string(data))
+	kept, err = ex.skipRemainingImages(images)
+	require.NoError(t, err)
+	assert.Equal(t, images[:1], kept)
+
+	// the next complete run empties the record
+	require.NoError(t, ex.saveRemainingImages(nil))
+	data, err = os.ReadFile(record)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"maxDuration": "", "origins": []}`, string(data))
+	kept, err = ex.skipRemainingImages(images)
+	require.NoError(t, err)
+	assert.Equal(t, images, kept)
+
+	require.NoError(t, os.WriteFile(record, []byte("{"), 0o644))
+	_, err = ex.skipRemainingImages(images)
+	assert.ErrorContains(t, err, "parsing the remaining images")
+}
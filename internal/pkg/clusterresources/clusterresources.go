@@ -15,6 +15,7 @@ import (
 	"unicode"
 
 	confv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -45,13 +46,39 @@ func New(log clog.PluggableLoggerInterface,
 	return &ClusterResourcesGenerator{Log: log, WorkingDir: workingDir, Config: conf, LocalStorageFQDN: localStorageFQDN}
 }
 
+// NewForDestination returns a generator writing the cluster resources of one of the
+// destinations of a mirrorToMirror run in the destinationDir subdirectory of cluster-resources.
+func NewForDestination(log clog.PluggableLoggerInterface,
+	workingDir string,
+	destinationDir string,
+	conf v2alpha1.ImageSetConfiguration,
+	localStorageFQDN string,
+) GeneratorInterface {
+	return &ClusterResourcesGenerator{
+		Log:              log,
+		WorkingDir:       workingDir,
+		ResourcesDir:     filepath.Join(workingDir, clusterResourcesDir, destinationDir),
+		Config:           conf,
+		LocalStorageFQDN: localStorageFQDN,
+	}
+}
+
 type ClusterResourcesGenerator struct {
-	Log              clog.PluggableLoggerInterface
-	WorkingDir       string
+	Log        clog.PluggableLoggerInterface
+	WorkingDir string
+	// ResourcesDir is where the resources are written, <WorkingDir>/cluster-resources when empty
+	ResourcesDir     string
 	Config           v2alpha1.ImageSetConfiguration
 	LocalStorageFQDN string
 }
 
+func (o *ClusterResourcesGenerator) resourcesDir() string {
+	if o.ResourcesDir != "" {
+		return o.ResourcesDir
+	}
+	return filepath.Join(o.WorkingDir, clusterResourcesDir)
+}
+
 type (
 	imageMirrorsGeneratorMode int
 	mirrorCategory            int
@@ -69,6 +96,7 @@ const (
 	releaseCategory = iota
 	operatorCategory
 	genericCategory
+	samplesCategory
 
 	idmsFileName = "idms-oc-mirror.yaml"
 	itmsFileName = "itms-oc-mirror.yaml"
@@ -99,7 +127,7 @@ func (o *ClusterResourcesGenerator) IDMS_ITMSGenerator(allRelatedImages []v2alph
 			return err
 		}
 
-		err = writeMirrorSet(idmsList, o.WorkingDir, idmsFileName, o.Log)
+		err = writeMirrorSet(idmsList, o.resourcesDir(), idmsFileName, o.Log)
 		if err != nil {
 			return err
 		}
@@ -113,7 +141,7 @@ func (o *ClusterResourcesGenerator) IDMS_ITMSGenerator(allRelatedImages []v2alph
 		if err != nil {
 			return err
 		}
-		err = writeMirrorSet(itmsList, o.WorkingDir, itmsFileName, o.Log)
+		err = writeMirrorSet(itmsList, o.resourcesDir(), itmsFileName, o.Log)
 		if err != nil {
 			return err
 		}
@@ -152,8 +180,8 @@ func (o *ClusterResourcesGenerator) generateITMS(mirrorsByCategory []categorized
 	return itmsList, nil
 }
 
-func writeMirrorSet[T confv1.ImageDigestMirrorSet | confv1.ImageTagMirrorSet](mirrorSetsList []T, workingDir, fileName string, log clog.PluggableLoggerInterface) error {
-	msFilePath := filepath.Join(workingDir, clusterResourcesDir, fileName)
+func writeMirrorSet[T confv1.ImageDigestMirrorSet | confv1.ImageTagMirrorSet](mirrorSetsList []T, resourcesDir, fileName string, log clog.PluggableLoggerInterface) error {
+	msFilePath := filepath.Join(resourcesDir, fileName)
 	msAggregation := []byte{}
 	var err error
 	for _, ms := range mirrorSetsList {
@@ -210,9 +238,8 @@ func (o *ClusterResourcesGenerator) CatalogSourceGenerator(allRelatedImages []v2
 				o.Log.Info(emoji.PageFacingUp + " Generating CatalogSource file...")
 				firstCatalog = false
 			}
-			// check if ImageSetConfig contains a CatalogSourceTemplate for this catalog, and use it
-			template := o.getCSTemplate(copyImage.Origin)
-			err := o.generateCatalogSource(copyImage.Destination, template)
+			// check if ImageSetConfig contains a CatalogSourceTemplate or CatalogSource options for this catalog, and use them
+			err := o.generateCatalogSource(copyImage.Destination, o.catalogOperator(copyImage.Origin))
 			if err != nil {
 				return err
 			}
@@ -239,54 +266,32 @@ func (o *ClusterResourcesGenerator) ClusterCatalogGenerator(allRelatedImages []v
 			o.Log.Info(emoji.PageFacingUp + " Generating ClusterCatalog file...")
 			firstCatalog = false
 		}
-		// TODO: add template support
-		if err := o.generateClusterCatalog(copyImage.Destination); err != nil {
+		if err := o.generateClusterCatalog(copyImage.Destination, o.catalogOperator(copyImage.Origin)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (o *ClusterResourcesGenerator) getCSTemplate(catalogRef string) string {
+// catalogOperator returns the operator entry of the ImageSetConfig for the catalog,
+// or an empty one when not found
+func (o *ClusterResourcesGenerator) catalogOperator(catalogRef string) v2alpha1.Operator {
 	for _, op := range o.Config.ImageSetConfigurationSpec.Mirror.Operators {
 		if strings.Contains(catalogRef, op.Catalog) {
-			return op.TargetCatalogSourceTemplate
+			return op
 		}
 	}
-	return ""
+	return v2alpha1.Operator{}
 }
 
-func (o *ClusterResourcesGenerator) generateCatalogSource(catalogRef string, catalogSourceTemplateFile string) error {
+func (o *ClusterResourcesGenerator) generateCatalogSource(catalogRef string, op v2alpha1.Operator) error {
+	catalogSourceTemplateFile := op.TargetCatalogSourceTemplate
 	catalogSpec, err := image.ParseRef(catalogRef)
 	if err != nil {
 		return err
 	}
 
-	var csSuffix string
-	if catalogSpec.IsImageByDigestOnly() {
-		if len(catalogSpec.Digest) >= hashTruncLen {
-			csSuffix = catalogSpec.Digest[:hashTruncLen]
-		} else {
-			csSuffix = catalogSpec.Digest
-		}
-	} else {
-		tag := catalogSpec.Tag
-		if len(tag) >= hashTruncLen {
-			csSuffix = strings.Map(toRFC1035, tag[:hashTruncLen])
-		} else {
-			csSuffix = strings.Map(toRFC1035, tag)
-		}
-	}
-
-	if csSuffix == "" {
-		csSuffix = "0" // default value
-	}
-
-	pathComponents := strings.Split(catalogSpec.PathComponent, "/")
-	catalogRepository := pathComponents[len(pathComponents)-1]
-	catalogSourceName := "cs-" + catalogRepository + "-" + csSuffix
-	// maybe needs some updating (i.e other unwanted characters !@# etc )
-	catalogSourceName = strings.Replace(catalogSourceName, ".", "-", -1)
+	catalogSourceName := catalogResourceName(catalogSourcePrefix, catalogSpec)
 	errs := validation.IsDNS1035Label(catalogSourceName)
 	if len(errs) != 0 && !isValidRFC1123(catalogSourceName) {
 		return fmt.Errorf("error creating catalog source name: %s", strings.Join(errs, ", "))
@@ -322,6 +327,9 @@ func (o *ClusterResourcesGenerator) generateCatalogSource(catalogRef string, cat
 			},
 		}
 	}
+	if err := applyCatalogSourceOptions(&obj, op.CatalogSource); err != nil {
+		return err
+	}
 
 	// Create an unstructured object for removing creationTimestamp
 	unstructuredObj := unstructured.Unstructured{}
@@ -337,7 +345,7 @@ func (o *ClusterResourcesGenerator) generateCatalogSource(catalogRef string, cat
 		return fmt.Errorf("unable to marshal CatalogSource yaml: %v", err)
 	}
 
-	csFileName := filepath.Join(o.WorkingDir, clusterResourcesDir, catalogSourceName+".yaml")
+	csFileName := filepath.Join(o.resourcesDir(), catalogSourceName+".yaml")
 	// save IDMS struct to file
 	if _, err := os.Stat(csFileName); errors.Is(err, os.ErrNotExist) {
 		o.Log.Debug("%s does not exist, creating it", csFileName)
@@ -360,6 +368,35 @@ func (o *ClusterResourcesGenerator) generateCatalogSource(catalogRef string, cat
 	return err
 }
 
+// catalogResourceName returns the name of the CatalogSource or ClusterCatalog (depending on prefix)
+// generated for the catalog image: its repository name followed by a part of its tag or digest
+func catalogResourceName(prefix string, catalogSpec image.ImageSpec) string {
+	var suffix string
+	if catalogSpec.IsImageByDigestOnly() {
+		if len(catalogSpec.Digest) >= hashTruncLen {
+			suffix = catalogSpec.Digest[:hashTruncLen]
+		} else {
+			suffix = catalogSpec.Digest
+		}
+	} else {
+		tag := catalogSpec.Tag
+		if len(tag) >= hashTruncLen {
+			suffix = strings.Map(toRFC1035, tag[:hashTruncLen])
+		} else {
+			suffix = strings.Map(toRFC1035, tag)
+		}
+	}
+
+	if suffix == "" {
+		suffix = "0" // default value
+	}
+
+	pathComponents := strings.Split(catalogSpec.PathComponent, "/")
+	catalogRepository := pathComponents[len(pathComponents)-1]
+	// maybe needs some updating (i.e other unwanted characters !@# etc )
+	return strings.ReplaceAll(prefix+catalogRepository+"-"+suffix, ".", "-")
+}
+
 func catalogSourceContentFromTemplate(templateFile, catalogSourceName, image string) (ofv1alpha1.CatalogSource, error) {
 	// Initializing catalogSource `obj` from template
 	var obj ofv1alpha1.CatalogSource
@@ -403,38 +440,56 @@ func catalogSourceContentFromTemplate(templateFile, catalogSourceName, image str
 	return obj, nil
 }
 
-// TODO: support generation with template (or equivalent)
-func (o *ClusterResourcesGenerator) generateClusterCatalog(catalogRef string) error {
-	catalogSpec, err := image.ParseRef(catalogRef)
-	if err != nil {
-		return err
+// applyCatalogSourceOptions overrides the fields of the CatalogSource with the options of the ImageSetConfig
+func applyCatalogSourceOptions(obj *ofv1alpha1.CatalogSource, opts *v2alpha1.CatalogSourceOptions) error {
+	if opts == nil {
+		return nil
 	}
-
-	var ccSuffix string
-	if catalogSpec.IsImageByDigestOnly() {
-		if len(catalogSpec.Digest) >= hashTruncLen {
-			ccSuffix = catalogSpec.Digest[:hashTruncLen]
-		} else {
-			ccSuffix = catalogSpec.Digest
-		}
-	} else {
-		tag := catalogSpec.Tag
-		if len(tag) >= hashTruncLen {
-			ccSuffix = strings.Map(toRFC1035, tag[:hashTruncLen])
-		} else {
-			ccSuffix = strings.Map(toRFC1035, tag)
+	if opts.DisplayName != "" {
+		obj.Spec.DisplayName = opts.DisplayName
+	}
+	if opts.Publisher != "" {
+		obj.Spec.Publisher = opts.Publisher
+	}
+	if opts.Priority != 0 {
+		obj.Spec.Priority = opts.Priority
+	}
+	if opts.RegistryPollInterval != "" {
+		obj.Spec.UpdateStrategy = &ofv1alpha1.UpdateStrategy{RegistryPoll: &ofv1alpha1.RegistryPoll{RawInterval: opts.RegistryPollInterval}}
+	}
+	if opts.GrpcPodConfig == nil {
+		return nil
+	}
+	if obj.Spec.GrpcPodConfig == nil {
+		obj.Spec.GrpcPodConfig = &ofv1alpha1.GrpcPodConfig{}
+	}
+	podConfig := obj.Spec.GrpcPodConfig
+	if len(opts.GrpcPodConfig.NodeSelector) > 0 {
+		podConfig.NodeSelector = opts.GrpcPodConfig.NodeSelector
+	}
+	if len(opts.GrpcPodConfig.Tolerations) > 0 {
+		podConfig.Tolerations = opts.GrpcPodConfig.Tolerations
+	}
+	if opts.GrpcPodConfig.SecurityContextConfig != "" {
+		podConfig.SecurityContextConfig = ofv1alpha1.SecurityConfig(opts.GrpcPodConfig.SecurityContextConfig)
+	}
+	if opts.GrpcPodConfig.MemoryTarget != "" {
+		memoryTarget, err := resource.ParseQuantity(opts.GrpcPodConfig.MemoryTarget)
+		if err != nil {
+			return fmt.Errorf("error during CatalogSource generation: invalid memoryTarget %q: %w", opts.GrpcPodConfig.MemoryTarget, err)
 		}
+		podConfig.MemoryTarget = &memoryTarget
 	}
+	return nil
+}
 
-	if ccSuffix == "" {
-		ccSuffix = "0" // default value
+func (o *ClusterResourcesGenerator) generateClusterCatalog(catalogRef string, op v2alpha1.Operator) error {
+	catalogSpec, err := image.ParseRef(catalogRef)
+	if err != nil {
+		return err
 	}
 
-	pathComponents := strings.Split(catalogSpec.PathComponent, "/")
-	catalogRepository := pathComponents[len(pathComponents)-1]
-	clusterCatalogName := "cc-" + catalogRepository + "-" + ccSuffix
-	// maybe needs some updating (i.e other unwanted characters !@# etc )
-	clusterCatalogName = strings.ReplaceAll(clusterCatalogName, ".", "-")
+	clusterCatalogName := catalogResourceName(clusterCatalogPrefix, catalogSpec)
 	errs := validation.IsDNS1035Label(clusterCatalogName)
 	if len(errs) != 0 && !isValidRFC1123(clusterCatalogName) {
 		return fmt.Errorf("error creating cluster catalog name: %s", strings.Join(errs, ", "))
@@ -458,6 +513,10 @@ func (o *ClusterResourcesGenerator) generateClusterCatalog(catalogRef string) er
 			},
 		},
 	}
+	if op.ClusterCatalog != nil {
+		obj.Labels = op.ClusterCatalog.Labels
+		obj.Spec.Priority = op.ClusterCatalog.Priority
+	}
 
 	// Create an unstructured object for removing creationTimestamp
 	unstructuredObj := unstructured.Unstructured{}
@@ -473,7 +532,7 @@ func (o *ClusterResourcesGenerator) generateClusterCatalog(catalogRef string) er
 		return fmt.Errorf("unable to marshal ClusterCatalog yaml: %v", err)
 	}
 
-	ccFileName := filepath.Join(o.WorkingDir, clusterResourcesDir, clusterCatalogName+".yaml")
+	ccFileName := filepath.Join(o.resourcesDir(), clusterCatalogName+".yaml")
 	// save ClusterCatalog struct to file
 	if _, err := os.Stat(ccFileName); errors.Is(err, os.ErrNotExist) {
 		o.Log.Debug("%s does not exist, creating it", ccFileName)
@@ -648,7 +707,7 @@ func (o *ClusterResourcesGenerator) UpdateServiceGenerator(graphImageRef, releas
 	osusBytes = bytes.ReplaceAll(osusBytes, []byte("status: {}\n"), []byte(""))
 
 	// save UpdateService struct to file
-	osusPath := filepath.Join(o.WorkingDir, clusterResourcesDir, updateServiceFilename)
+	osusPath := filepath.Join(o.resourcesDir(), updateServiceFilename)
 
 	if _, err := os.Stat(osusPath); errors.Is(err, os.ErrNotExist) {
 		o.Log.Debug("%s does not exist, creating it", osusPath)
@@ -700,6 +759,8 @@ func (m mirrorCategory) toString() string {
 		return "operator"
 	case genericCategory:
 		return "generic"
+	case samplesCategory:
+		return "samples"
 	default:
 		return "generic"
 	}
@@ -709,7 +770,7 @@ func imageTypeToCategory(imageType v2alpha1.ImageType) mirrorCategory {
 	switch imageType {
 	case v2alpha1.TypeCincinnatiGraph:
 		return releaseCategory
-	case v2alpha1.TypeGeneric:
+	case v2alpha1.TypeGeneric, v2alpha1.TypeArtifact:
 		return genericCategory
 	case v2alpha1.TypeOCPRelease:
 		return releaseCategory
@@ -721,6 +782,8 @@ func imageTypeToCategory(imageType v2alpha1.ImageType) mirrorCategory {
 		return operatorCategory
 	case v2alpha1.TypeOperatorRelatedImage:
 		return operatorCategory
+	case v2alpha1.TypeSampleImage:
+		return samplesCategory
 	case v2alpha1.TypeInvalid:
 		return genericCategory
 	default:
@@ -826,7 +889,7 @@ func (o *ClusterResourcesGenerator) GenerateSignatureConfigMap(allRelatedImages
 
 	// pointless creating configmap if there were no BinaryData found
 	if len(cm.BinaryData) > 0 {
-		crPath := filepath.Join(o.WorkingDir, clusterResourcesDir)
+		crPath := o.resourcesDir()
 		o.Log.Info(emoji.PageFacingUp + " Generating Signature Configmap...")
 		jsonData, err := json.Marshal(cm)
 		if err != nil {
@@ -11,6 +11,9 @@ import (
 	confv1 "github.com/openshift/api/config/v1"
 	"github.com/otiai10/copy"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
@@ -369,6 +372,17 @@ func TestIDMS_ITMS_NoStatusField(t *testing.T) {
 	}
 }
 
+func TestNewForDestination(t *testing.T) {
+	workingDir := filepath.Join(t.TempDir(), "working-dir")
+
+	cr := NewForDestination(clog.New("trace"), workingDir, "mirror.us.acme.com", v2alpha1.ImageSetConfiguration{}, "localhost:55000")
+	err := cr.IDMS_ITMSGenerator(imageListDigestsOnly, false)
+	assert.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(workingDir, clusterResourcesDir, "mirror.us.acme.com", "idms-oc-mirror.yaml"))
+	assert.NoFileExists(t, filepath.Join(workingDir, clusterResourcesDir, "idms-oc-mirror.yaml"))
+}
+
 func TestGenerateIDMS(t *testing.T) {
 	log := clog.New("trace")
 
@@ -1026,6 +1040,54 @@ func TestCatalogSourceGenerator(t *testing.T) {
 
 		assert.Equal(t, expectedCS, actualCS, "contents of catalogSource file incorrect")
 	})
+
+	t.Run("Testing GenerateCatalogSource with options over template: should pass", func(t *testing.T) {
+		workingDir := filepath.Join(t.TempDir(), "working-dir")
+		cr := &ClusterResourcesGenerator{
+			Log:              log,
+			WorkingDir:       workingDir,
+			LocalStorageFQDN: "localhost:55000",
+			Config: v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{
+								Catalog:                     "registry.redhat.io/redhat/redhat-operator-index:v4.15",
+								TargetCatalogSourceTemplate: filepath.Join(consts.TestFolder, "catalog-source_template.yaml"),
+								CatalogSource: &v2alpha1.CatalogSourceOptions{
+									DisplayName:          "Mirrored Red Hat operators",
+									Publisher:            "IT",
+									Priority:             -100,
+									RegistryPollInterval: "1h",
+									GrpcPodConfig: &v2alpha1.CatalogSourceGrpcPodConfig{
+										NodeSelector:          map[string]string{"node-role.kubernetes.io/infra": ""},
+										Tolerations:           []corev1.Toleration{{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists}},
+										SecurityContextConfig: "restricted",
+										MemoryTarget:          "100Mi",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		require.NoError(t, cr.CatalogSourceGenerator(imageList))
+
+		actualCS, err := parser.ParseYamlFile[ofv1alpha1.CatalogSource](filepath.Join(workingDir, clusterResourcesDir, "cs-redhat-operator-index-v4-15.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, "Mirrored Red Hat operators", actualCS.Spec.DisplayName)
+		assert.Equal(t, "IT", actualCS.Spec.Publisher)
+		assert.Equal(t, -100, actualCS.Spec.Priority)
+		assert.Equal(t, "1h", actualCS.Spec.UpdateStrategy.RawInterval)
+		memoryTarget := resource.MustParse("100Mi")
+		assert.Equal(t, &ofv1alpha1.GrpcPodConfig{
+			NodeSelector:          map[string]string{"node-role.kubernetes.io/infra": ""},
+			Tolerations:           []corev1.Toleration{{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists}},
+			SecurityContextConfig: ofv1alpha1.Restricted,
+			MemoryTarget:          &memoryTarget,
+		}, actualCS.Spec.GrpcPodConfig)
+	})
 }
 
 func TestClusterCatalogGenerator(t *testing.T) {
@@ -1238,6 +1300,33 @@ func TestClusterCatalogGenerator(t *testing.T) {
 		assert.Equal(t, expectedCC.ObjectMeta.Annotations["createdBy"], actualCC.ObjectMeta.Annotations["createdBy"], "contents of catalogSource file incorrect (Annotations.createdBy)")
 		assert.Equal(t, expectedCC.ObjectMeta.Annotations["oc-mirror_version"], actualCC.ObjectMeta.Annotations["oc-mirror_version"], "contents of catalogSource file incorrect (Annotations.oc-mirror_version)")
 	})
+
+	t.Run("Testing GenerateClusterCatalog with options: should pass", func(t *testing.T) {
+		workingDir := filepath.Join(t.TempDir(), "working-dir")
+		cr := &ClusterResourcesGenerator{
+			Log:              log,
+			WorkingDir:       workingDir,
+			LocalStorageFQDN: "localhost:55000",
+			Config: v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{
+								Catalog:        "registry.redhat.io/redhat/redhat-operator-index:v4.15",
+								ClusterCatalog: &v2alpha1.ClusterCatalogOptions{Priority: -100, Labels: map[string]string{"example.com/mirrored": "true"}},
+							},
+						},
+					},
+				},
+			},
+		}
+		require.NoError(t, cr.ClusterCatalogGenerator(imageList))
+
+		actualCC, err := parser.ParseYamlFile[ofv1.ClusterCatalog](filepath.Join(workingDir, clusterResourcesDir, "cc-redhat-operator-index-v4-15.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"example.com/mirrored": "true"}, actualCC.Labels)
+		assert.Equal(t, int32(-100), actualCC.Spec.Priority)
+	})
 }
 
 func TestGenerateImageMirrors(t *testing.T) {
@@ -14,4 +14,12 @@ const (
 	signatureLabel                        = "release.openshift.io/verification-signatures"
 	signatureConfigMapMsg                 = "[GenerateSignatureConfigMap] %v"
 	signatureDir                          = "signatures"
+	catalogSourcePrefix                   = "cs-"
+	clusterCatalogPrefix                  = "cc-"
+	catalogSourceNamespace                = "openshift-marketplace"
+	installManifestsDir                   = "operator-install"
+	olmV0InstallDir                       = "olm-v0"
+	olmV1InstallDir                       = "olm-v1"
+	samplesConfigFilename                 = "samplesConfig.yaml"
+	samplesConfigName                     = "cluster"
 )
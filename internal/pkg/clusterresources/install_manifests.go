@@ -0,0 +1,298 @@
+package clusterresources
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	opv1 "github.com/openshift/oc-mirror/v2/internal/pkg/api/operator-framework/operators/v1"
+	ofv1 "github.com/openshift/oc-mirror/v2/internal/pkg/api/operator-framework/v1"
+	ofv1alpha1 "github.com/openshift/oc-mirror/v2/internal/pkg/api/operator-framework/v1alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/emoji"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/image"
+)
+
+const (
+	suggestedNamespaceAnnotation = "operatorframework.io/suggested-namespace"
+	// globalOperatorsNamespace has the global-operators OperatorGroup on OpenShift
+	globalOperatorsNamespace = "openshift-operators"
+)
+
+// installablePackage is a mirrored package, installed from the head of its default channel
+type installablePackage struct {
+	name         string
+	channel      string
+	bundle       string
+	version      string
+	namespace    string
+	installModes []v1alpha1.InstallMode
+	approval     ofv1alpha1.Approval
+}
+
+// installableCatalog is a catalog configured with generateInstallManifests, and its packages
+type installableCatalog struct {
+	catalogSourceName  string
+	clusterCatalogName string
+	packages           []installablePackage
+}
+
+// InstallManifestsGenerator generates the manifests installing the packages mirrored from the catalogs
+// configured with generateInstallManifests. They are written in their own directory of the cluster
+// resources, one for each version of OLM, so that they are not applied with the other cluster resources.
+// catalogToFBC is the CollectorSchema.CatalogToFBCMap of the operator collection.
+func (o *ClusterResourcesGenerator) InstallManifestsGenerator(allRelatedImages []v2alpha1.CopyImageSchema, catalogToFBC map[string]v2alpha1.CatalogFilterResult) error {
+	if !slices.ContainsFunc(o.Config.Mirror.Operators, func(op v2alpha1.Operator) bool { return op.GenerateInstallManifests }) {
+		return nil
+	}
+
+	o.Log.Info(emoji.PageFacingUp + " Generating operator install manifests...")
+	catalogs := []installableCatalog{}
+	for _, copyImage := range allRelatedImages {
+		// see CatalogSourceGenerator for the catalogs copied to the local cache
+		if copyImage.Type != v2alpha1.TypeOperatorCatalog || strings.Contains(copyImage.Destination, o.LocalStorageFQDN) {
+			continue
+		}
+		originSpec, err := image.ParseRef(copyImage.Origin)
+		if err != nil {
+			return err
+		}
+		result, ok := catalogToFBC[originSpec.ReferenceWithTransport]
+		if !ok || !result.OperatorFilter.GenerateInstallManifests || result.DeclConfig == nil {
+			continue
+		}
+		catalogSpec, err := image.ParseRef(copyImage.Destination)
+		if err != nil {
+			return err
+		}
+		packages, err := installablePackages(result.DeclConfig)
+		if err != nil {
+			return fmt.Errorf("error generating install manifests of catalog %s: %w", copyImage.Origin, err)
+		}
+		setInstallPlanApprovals(packages, result.OperatorFilter)
+		catalogs = append(catalogs, installableCatalog{
+			catalogSourceName:  catalogResourceName(catalogSourcePrefix, catalogSpec),
+			clusterCatalogName: catalogResourceName(clusterCatalogPrefix, catalogSpec),
+			packages:           packages,
+		})
+	}
+
+	operatorGroups := o.operatorGroupManifests(catalogs)
+	for _, ctlg := range catalogs {
+		for _, pkg := range ctlg.packages {
+			olmV0Objs := []any{pkg.namespaceManifest()}
+			if og, ok := operatorGroups[pkg.namespace]; ok {
+				olmV0Objs = append(olmV0Objs, og)
+			}
+			olmV0, err := marshalManifests(append(olmV0Objs, pkg.subscriptionManifest(ctlg.catalogSourceName))...)
+			if err != nil {
+				return err
+			}
+			if err := o.writeInstallManifests(filepath.Join(olmV0InstallDir, ctlg.catalogSourceName, pkg.name+".yaml"), olmV0); err != nil {
+				return err
+			}
+			olmV1, err := marshalManifests(pkg.namespaceManifest(), pkg.clusterExtensionManifest(ctlg.clusterCatalogName))
+			if err != nil {
+				return err
+			}
+			if err := o.writeInstallManifests(filepath.Join(olmV1InstallDir, ctlg.clusterCatalogName, pkg.name+".yaml"), olmV1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// operatorGroupManifests returns the OperatorGroup of each namespace the packages are installed in.
+// OLM only accepts one OperatorGroup per namespace, so the packages sharing a namespace, across
+// catalogs, share its OperatorGroup, which is written in each of their manifests. It targets all
+// namespaces only when all of them support it. The openshift-operators namespace already has the
+// global-operators group, so none is generated for it.
+func (o *ClusterResourcesGenerator) operatorGroupManifests(catalogs []installableCatalog) map[string]*opv1.OperatorGroup {
+	byNamespace := map[string][]installablePackage{}
+	for _, ctlg := range catalogs {
+		for _, pkg := range ctlg.packages {
+			byNamespace[pkg.namespace] = append(byNamespace[pkg.namespace], pkg)
+		}
+	}
+
+	operatorGroups := map[string]*opv1.OperatorGroup{}
+	for namespace, packages := range byNamespace {
+		if namespace == globalOperatorsNamespace {
+			continue
+		}
+		var targetNamespaces []string
+		for _, pkg := range packages {
+			if targets := pkg.targetNamespaces(); len(targets) > 0 {
+				targetNamespaces = targets
+			}
+		}
+		if targetNamespaces != nil {
+			for _, pkg := range packages {
+				if len(pkg.installModes) > 0 && !slices.Contains(pkg.installModes, v1alpha1.InstallMode{Type: v1alpha1.InstallModeTypeOwnNamespace, Supported: true}) {
+					o.Log.Warn("package %s does not support the OwnNamespace install mode of the OperatorGroup of namespace %s", pkg.name, namespace)
+				}
+			}
+		}
+		operatorGroups[namespace] = &opv1.OperatorGroup{
+			TypeMeta:   metav1.TypeMeta{APIVersion: opv1.OperatorGroupCRDAPIVersion, Kind: opv1.OperatorGroupKind},
+			ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace, Annotations: generateOcMirrorAnnotations()},
+			Spec:       opv1.OperatorGroupSpec{TargetNamespaces: targetNamespaces},
+		}
+	}
+	return operatorGroups
+}
+
+// installablePackages returns the packages of the declarative config, sorted by name
+func installablePackages(dc *declcfg.DeclarativeConfig) ([]installablePackage, error) {
+	m, err := declcfg.ConvertToModel(*dc)
+	if err != nil {
+		return nil, err
+	}
+	packages := []installablePackage{}
+	for _, pkg := range m {
+		if pkg.DefaultChannel == nil {
+			continue
+		}
+		head, err := pkg.DefaultChannel.Head()
+		if err != nil {
+			return nil, fmt.Errorf("package %s: %w", pkg.Name, err)
+		}
+		installable := installablePackage{
+			name:      pkg.Name,
+			channel:   pkg.DefaultChannel.Name,
+			bundle:    head.Name,
+			version:   head.Version.String(),
+			namespace: pkg.Name,
+		}
+		// the CSV metadata is only available in the catalogs rendered by recent versions of opm
+		if props, err := property.Parse(head.Properties); err == nil && len(props.CSVMetadatas) > 0 {
+			csv := props.CSVMetadatas[0]
+			installable.installModes = csv.InstallModes
+			if ns := csv.Annotations[suggestedNamespaceAnnotation]; len(validation.IsDNS1123Label(ns)) == 0 {
+				installable.namespace = ns
+			}
+		}
+		packages = append(packages, installable)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].name < packages[j].name })
+	return packages, nil
+}
+
+// setInstallPlanApprovals sets the install plan approval of the packages configured in the operator
+// filter. The other packages, dependencies included, are approved manually: on a disconnected cluster,
+// an update must not be installed before it is reviewed.
+func setInstallPlanApprovals(packages []installablePackage, op v2alpha1.Operator) {
+	for i := range packages {
+		packages[i].approval = ofv1alpha1.ApprovalManual
+		j := slices.IndexFunc(op.Packages, func(pkg v2alpha1.IncludePackage) bool { return pkg.Name == packages[i].name })
+		if j >= 0 && op.Packages[j].InstallPlanApproval != "" {
+			packages[i].approval = ofv1alpha1.Approval(op.Packages[j].InstallPlanApproval)
+		}
+	}
+}
+
+// targetNamespaces returns the namespaces the package needs its OperatorGroup to target:
+// all namespaces when supported by the bundle, or else its own namespace
+func (p installablePackage) targetNamespaces() []string {
+	if len(p.installModes) == 0 || slices.Contains(p.installModes, v1alpha1.InstallMode{Type: v1alpha1.InstallModeTypeAllNamespaces, Supported: true}) {
+		return nil
+	}
+	return []string{p.namespace}
+}
+
+func (p installablePackage) namespaceManifest() *corev1.Namespace {
+	return &corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: p.namespace, Annotations: generateOcMirrorAnnotations()},
+	}
+}
+
+func (p installablePackage) subscriptionManifest(catalogSourceName string) *ofv1alpha1.Subscription {
+	return &ofv1alpha1.Subscription{
+		TypeMeta:   metav1.TypeMeta{APIVersion: ofv1alpha1.SubscriptionCRDAPIVersion, Kind: ofv1alpha1.SubscriptionKind},
+		ObjectMeta: metav1.ObjectMeta{Name: p.name, Namespace: p.namespace, Annotations: generateOcMirrorAnnotations()},
+		Spec: &ofv1alpha1.SubscriptionSpec{
+			CatalogSource:          catalogSourceName,
+			CatalogSourceNamespace: catalogSourceNamespace,
+			Package:                p.name,
+			Channel:                p.channel,
+			StartingCSV:            p.bundle,
+			InstallPlanApproval:    p.approval,
+		},
+	}
+}
+
+func (p installablePackage) clusterExtensionManifest(clusterCatalogName string) *ofv1.ClusterExtension {
+	return &ofv1.ClusterExtension{
+		TypeMeta:   metav1.TypeMeta{APIVersion: ofv1.ClusterExtensionCRDAPIVersion, Kind: ofv1.ClusterExtensionKind},
+		ObjectMeta: metav1.ObjectMeta{Name: p.name, Annotations: generateOcMirrorAnnotations()},
+		Spec: ofv1.ClusterExtensionSpec{
+			Namespace: p.namespace,
+			// the service account and its permissions are not generated
+			ServiceAccount: ofv1.ServiceAccountReference{Name: p.name + "-installer"},
+			Source: ofv1.SourceConfig{
+				SourceType: ofv1.SourceTypeCatalog,
+				Catalog: &ofv1.CatalogFilter{
+					PackageName: p.name,
+					Version:     p.version,
+					Channels:    []string{p.channel},
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{ofv1.MetadataNameLabel: clusterCatalogName},
+					},
+				},
+			},
+		},
+	}
+}
+
+// marshalManifests marshals the objects, which must be pointers, in a multi-document yaml
+func marshalManifests(objs ...any) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, obj := range objs {
+		// Create an unstructured object for removing creationTimestamp
+		unstructuredObj := unstructured.Unstructured{}
+		var err error
+		unstructuredObj.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("error while sanitizing the install manifests prior to marshalling: %v", err)
+		}
+		delete(unstructuredObj.Object["metadata"].(map[string]interface{}), "creationTimestamp")
+		delete(unstructuredObj.Object, "status")
+		out, err := yaml.Marshal(unstructuredObj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal install manifests yaml: %v", err)
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(out)
+	}
+	return buf.Bytes(), nil
+}
+
+func (o *ClusterResourcesGenerator) writeInstallManifests(relativePath string, content []byte) error {
+	fileName := filepath.Join(o.resourcesDir(), installManifestsDir, relativePath)
+	if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(fileName, content, 0o644); err != nil { //nolint:gosec // cluster resources are not sensitive
+		return err
+	}
+	o.Log.Debug("%s file created", fileName)
+	return nil
+}
@@ -0,0 +1,258 @@
+package clusterresources
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	opv1 "github.com/openshift/oc-mirror/v2/internal/pkg/api/operator-framework/operators/v1"
+	ofv1 "github.com/openshift/oc-mirror/v2/internal/pkg/api/operator-framework/v1"
+	ofv1alpha1 "github.com/openshift/oc-mirror/v2/internal/pkg/api/operator-framework/v1alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+)
+
+// newInstallCatalog returns a catalog with package foo, whose CSV metadata is available,
+// and package bar, whose bundles were rendered without it
+func newInstallCatalog() *declcfg.DeclarativeConfig {
+	csvMetadata := property.MustBuildCSVMetadata(v1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{suggestedNamespaceAnnotation: "foo-system"}},
+		Spec: v1alpha1.ClusterServiceVersionSpec{InstallModes: []v1alpha1.InstallMode{
+			{Type: v1alpha1.InstallModeTypeOwnNamespace, Supported: true},
+			{Type: v1alpha1.InstallModeTypeAllNamespaces, Supported: false},
+		}},
+	})
+	return &declcfg.DeclarativeConfig{
+		Packages: []declcfg.Package{
+			{Schema: declcfg.SchemaPackage, Name: "foo", DefaultChannel: "stable"},
+			{Schema: declcfg.SchemaPackage, Name: "bar", DefaultChannel: "fast"},
+		},
+		Channels: []declcfg.Channel{
+			{Schema: declcfg.SchemaChannel, Package: "foo", Name: "stable", Entries: []declcfg.ChannelEntry{
+				{Name: "foo.v1.0.0"},
+				{Name: "foo.v1.1.0", Replaces: "foo.v1.0.0"},
+			}},
+			{Schema: declcfg.SchemaChannel, Package: "bar", Name: "fast", Entries: []declcfg.ChannelEntry{{Name: "bar.v0.1.0"}}},
+		},
+		Bundles: []declcfg.Bundle{
+			{Schema: declcfg.SchemaBundle, Package: "foo", Name: "foo.v1.0.0", Image: "quay.io/example/foo-bundle:v1.0.0", Properties: []property.Property{
+				property.MustBuildPackage("foo", "1.0.0"), csvMetadata,
+			}},
+			{Schema: declcfg.SchemaBundle, Package: "foo", Name: "foo.v1.1.0", Image: "quay.io/example/foo-bundle:v1.1.0", Properties: []property.Property{
+				property.MustBuildPackage("foo", "1.1.0"), csvMetadata,
+			}},
+			{Schema: declcfg.SchemaBundle, Package: "bar", Name: "bar.v0.1.0", Image: "quay.io/example/bar-bundle:v0.1.0", Properties: []property.Property{
+				property.MustBuildPackage("bar", "0.1.0"),
+			}},
+		},
+	}
+}
+
+func TestInstallManifestsGenerator(t *testing.T) {
+	workingDir := filepath.Join(t.TempDir(), "working-dir")
+	catalogs := []v2alpha1.Operator{
+		{
+			Catalog:                  "registry.redhat.io/redhat/redhat-operator-index:v4.18",
+			GenerateInstallManifests: true,
+			IncludeConfig: v2alpha1.IncludeConfig{
+				Packages: []v2alpha1.IncludePackage{{Name: "foo", InstallPlanApproval: "Automatic"}, {Name: "bar"}},
+			},
+		},
+		{Catalog: "registry.redhat.io/redhat/certified-operator-index:v4.18"},
+	}
+	cr := &ClusterResourcesGenerator{
+		Log:              clog.New("debug"),
+		WorkingDir:       workingDir,
+		LocalStorageFQDN: "localhost:55000",
+		Config: v2alpha1.ImageSetConfiguration{ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+			Mirror: v2alpha1.Mirror{Operators: catalogs},
+		}},
+	}
+	images := []v2alpha1.CopyImageSchema{
+		{
+			Source:      "docker://localhost:55000/redhat/redhat-operator-index:v4.18",
+			Destination: "docker://myregistry/mynamespace/redhat/redhat-operator-index:v4.18",
+			Origin:      "docker://registry.redhat.io/redhat/redhat-operator-index:v4.18",
+			Type:        v2alpha1.TypeOperatorCatalog,
+		},
+		{
+			Source:      "docker://localhost:55000/redhat/certified-operator-index:v4.18",
+			Destination: "docker://myregistry/mynamespace/redhat/certified-operator-index:v4.18",
+			Origin:      "docker://registry.redhat.io/redhat/certified-operator-index:v4.18",
+			Type:        v2alpha1.TypeOperatorCatalog,
+		},
+	}
+	catalogToFBC := map[string]v2alpha1.CatalogFilterResult{
+		"docker://registry.redhat.io/redhat/redhat-operator-index:v4.18":    {OperatorFilter: catalogs[0], DeclConfig: newInstallCatalog()},
+		"docker://registry.redhat.io/redhat/certified-operator-index:v4.18": {OperatorFilter: catalogs[1], DeclConfig: newInstallCatalog()},
+	}
+
+	require.NoError(t, cr.InstallManifestsGenerator(images, catalogToFBC))
+
+	installDir := filepath.Join(workingDir, clusterResourcesDir, installManifestsDir)
+	readDocs := func(t *testing.T, path string) []string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(installDir, path))
+		require.NoError(t, err)
+		return strings.Split(string(content), "---\n")
+	}
+
+	t.Run("should generate the OLM v0 manifests from the CSV metadata", func(t *testing.T) {
+		docs := readDocs(t, "olm-v0/cs-redhat-operator-index-v4-18/foo.yaml")
+		require.Len(t, docs, 3)
+		var og opv1.OperatorGroup
+		require.NoError(t, yaml.Unmarshal([]byte(docs[1]), &og))
+		assert.Equal(t, "foo-system", og.Name)
+		assert.Equal(t, "foo-system", og.Namespace)
+		assert.Equal(t, []string{"foo-system"}, og.Spec.TargetNamespaces)
+		var sub ofv1alpha1.Subscription
+		require.NoError(t, yaml.Unmarshal([]byte(docs[2]), &sub))
+		assert.Equal(t, "foo-system", sub.Namespace)
+		assert.Equal(t, &ofv1alpha1.SubscriptionSpec{
+			CatalogSource:          "cs-redhat-operator-index-v4-18",
+			CatalogSourceNamespace: "openshift-marketplace",
+			Package:                "foo",
+			Channel:                "stable",
+			StartingCSV:            "foo.v1.1.0",
+			InstallPlanApproval:    ofv1alpha1.ApprovalAutomatic,
+		}, sub.Spec)
+	})
+
+	t.Run("should default to the package namespace and all namespaces without CSV metadata", func(t *testing.T) {
+		docs := readDocs(t, "olm-v0/cs-redhat-operator-index-v4-18/bar.yaml")
+		require.Len(t, docs, 3)
+		var og opv1.OperatorGroup
+		require.NoError(t, yaml.Unmarshal([]byte(docs[1]), &og))
+		assert.Equal(t, "bar", og.Namespace)
+		assert.Empty(t, og.Spec.TargetNamespaces)
+	})
+
+	t.Run("should approve the install plans manually by default", func(t *testing.T) {
+		docs := readDocs(t, "olm-v0/cs-redhat-operator-index-v4-18/bar.yaml")
+		require.Len(t, docs, 3)
+		var sub ofv1alpha1.Subscription
+		require.NoError(t, yaml.Unmarshal([]byte(docs[2]), &sub))
+		assert.Equal(t, ofv1alpha1.ApprovalManual, sub.Spec.InstallPlanApproval)
+	})
+
+	t.Run("should generate the OLM v1 manifests", func(t *testing.T) {
+		docs := readDocs(t, "olm-v1/cc-redhat-operator-index-v4-18/foo.yaml")
+		require.Len(t, docs, 2)
+		var ce ofv1.ClusterExtension
+		require.NoError(t, yaml.Unmarshal([]byte(docs[1]), &ce))
+		assert.Equal(t, "foo-system", ce.Spec.Namespace)
+		assert.Equal(t, "foo", ce.Spec.Source.Catalog.PackageName)
+		assert.Equal(t, "1.1.0", ce.Spec.Source.Catalog.Version)
+		assert.Equal(t, []string{"stable"}, ce.Spec.Source.Catalog.Channels)
+		assert.Equal(t, map[string]string{ofv1.MetadataNameLabel: "cc-redhat-operator-index-v4-18"}, ce.Spec.Source.Catalog.Selector.MatchLabels)
+	})
+
+	t.Run("should skip the catalogs without generateInstallManifests", func(t *testing.T) {
+		_, err := os.Stat(filepath.Join(installDir, "olm-v0", "cs-certified-operator-index-v4-18"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+// newNamespacedCatalog returns a catalog with one package per entry of namespaces, whose bundle
+// suggests this namespace and supports the install modes given
+func newNamespacedCatalog(namespaces map[string]string, installModes ...v1alpha1.InstallModeType) *declcfg.DeclarativeConfig {
+	modes := []v1alpha1.InstallMode{}
+	for _, mode := range installModes {
+		modes = append(modes, v1alpha1.InstallMode{Type: mode, Supported: true})
+	}
+	dc := &declcfg.DeclarativeConfig{}
+	for name, namespace := range namespaces {
+		csvMetadata := property.MustBuildCSVMetadata(v1alpha1.ClusterServiceVersion{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{suggestedNamespaceAnnotation: namespace}},
+			Spec:       v1alpha1.ClusterServiceVersionSpec{InstallModes: modes},
+		})
+		dc.Packages = append(dc.Packages, declcfg.Package{Schema: declcfg.SchemaPackage, Name: name, DefaultChannel: "stable"})
+		dc.Channels = append(dc.Channels, declcfg.Channel{Schema: declcfg.SchemaChannel, Package: name, Name: "stable", Entries: []declcfg.ChannelEntry{{Name: name + ".v1.0.0"}}})
+		dc.Bundles = append(dc.Bundles, declcfg.Bundle{Schema: declcfg.SchemaBundle, Package: name, Name: name + ".v1.0.0", Image: "quay.io/example/" + name + "-bundle:v1.0.0", Properties: []property.Property{
+			property.MustBuildPackage(name, "1.0.0"), csvMetadata,
+		}})
+	}
+	return dc
+}
+
+func TestInstallManifestsOperatorGroups(t *testing.T) {
+	workingDir := filepath.Join(t.TempDir(), "working-dir")
+	catalog := v2alpha1.Operator{Catalog: "registry.redhat.io/redhat/redhat-operator-index:v4.18", GenerateInstallManifests: true}
+	cr := &ClusterResourcesGenerator{
+		Log:              clog.New("debug"),
+		WorkingDir:       workingDir,
+		LocalStorageFQDN: "localhost:55000",
+		Config: v2alpha1.ImageSetConfiguration{ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+			Mirror: v2alpha1.Mirror{Operators: []v2alpha1.Operator{catalog}},
+		}},
+	}
+	images := []v2alpha1.CopyImageSchema{{
+		Source:      "docker://localhost:55000/redhat/redhat-operator-index:v4.18",
+		Destination: "docker://myregistry/mynamespace/redhat/redhat-operator-index:v4.18",
+		Origin:      "docker://registry.redhat.io/redhat/redhat-operator-index:v4.18",
+		Type:        v2alpha1.TypeOperatorCatalog,
+	}}
+	dc := newNamespacedCatalog(map[string]string{
+		"elasticsearch-operator": "openshift-operators-redhat",
+		"loki-operator":          "openshift-operators-redhat",
+		"foo":                    "openshift-operators",
+	}, v1alpha1.InstallModeTypeOwnNamespace, v1alpha1.InstallModeTypeAllNamespaces)
+	catalogToFBC := map[string]v2alpha1.CatalogFilterResult{
+		"docker://registry.redhat.io/redhat/redhat-operator-index:v4.18": {OperatorFilter: catalog, DeclConfig: dc},
+	}
+
+	require.NoError(t, cr.InstallManifestsGenerator(images, catalogToFBC))
+
+	readDocs := func(t *testing.T, pkg string) []string {
+		t.Helper()
+		content, err := os.ReadFile(filepath.Join(workingDir, clusterResourcesDir, installManifestsDir, "olm-v0", "cs-redhat-operator-index-v4-18", pkg+".yaml"))
+		require.NoError(t, err)
+		return strings.Split(string(content), "---\n")
+	}
+
+	t.Run("should share the OperatorGroup of a namespace", func(t *testing.T) {
+		groups := []opv1.OperatorGroup{}
+		for _, pkg := range []string{"elasticsearch-operator", "loki-operator"} {
+			docs := readDocs(t, pkg)
+			require.Len(t, docs, 3)
+			var og opv1.OperatorGroup
+			require.NoError(t, yaml.Unmarshal([]byte(docs[1]), &og))
+			groups = append(groups, og)
+		}
+		assert.Equal(t, "openshift-operators-redhat", groups[0].Name)
+		assert.Equal(t, "openshift-operators-redhat", groups[0].Namespace)
+		assert.Empty(t, groups[0].Spec.TargetNamespaces)
+		assert.Equal(t, groups[0], groups[1], "the packages of a namespace must apply the same OperatorGroup")
+	})
+
+	t.Run("should not generate an OperatorGroup in openshift-operators", func(t *testing.T) {
+		docs := readDocs(t, "foo")
+		require.Len(t, docs, 2)
+		var sub ofv1alpha1.Subscription
+		require.NoError(t, yaml.Unmarshal([]byte(docs[1]), &sub))
+		assert.Equal(t, ofv1alpha1.SubscriptionKind, sub.Kind)
+		assert.Equal(t, "openshift-operators", sub.Namespace)
+	})
+}
+
+func TestOperatorGroupManifests(t *testing.T) {
+	cr := &ClusterResourcesGenerator{Log: clog.New("debug")}
+	ownNamespace := []v1alpha1.InstallMode{{Type: v1alpha1.InstallModeTypeOwnNamespace, Supported: true}}
+	allNamespaces := []v1alpha1.InstallMode{{Type: v1alpha1.InstallModeTypeOwnNamespace, Supported: true}, {Type: v1alpha1.InstallModeTypeAllNamespaces, Supported: true}}
+
+	groups := cr.operatorGroupManifests([]installableCatalog{
+		{packages: []installablePackage{{name: "foo", namespace: "shared", installModes: allNamespaces}}},
+		{packages: []installablePackage{{name: "bar", namespace: "shared", installModes: ownNamespace}}},
+	})
+	require.Len(t, groups, 1)
+	assert.Equal(t, []string{"shared"}, groups["shared"].Spec.TargetNamespaces, "the packages of other catalogs must share the OperatorGroup")
+}
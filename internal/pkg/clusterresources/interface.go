@@ -10,4 +10,6 @@ type GeneratorInterface interface {
 	CatalogSourceGenerator(allRelatedImages []v2alpha1.CopyImageSchema) error
 	GenerateSignatureConfigMap(allRelatedImages []v2alpha1.CopyImageSchema) error
 	ClusterCatalogGenerator(allRelatedImages []v2alpha1.CopyImageSchema) error
+	InstallManifestsGenerator(allRelatedImages []v2alpha1.CopyImageSchema, catalogToFBC map[string]v2alpha1.CatalogFilterResult) error
+	SamplesConfigGenerator(allRelatedImages []v2alpha1.CopyImageSchema, skippedImageStreams []string) error
 }
@@ -0,0 +1,109 @@
+package clusterresources
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	samplesv1 "github.com/openshift/api/samples/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/emoji"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/image"
+)
+
+// SamplesConfigGenerator generates the Config of the cluster-samples-operator, so that the
+// imagestreams of the samples are imported from the mirror registry.
+// skippedImageStreams are the imagestreams of the release payload that were not mirrored:
+// the operator is told to skip them, as they could not be imported in a disconnected cluster.
+func (o *ClusterResourcesGenerator) SamplesConfigGenerator(allRelatedImages []v2alpha1.CopyImageSchema, skippedImageStreams []string) error {
+	if len(o.Config.Mirror.Samples) == 0 {
+		return nil
+	}
+
+	samplesRegistry := ""
+	for _, copyImage := range allRelatedImages {
+		// sample images copied to the local cache during mirrorToDisk are not used by the cluster
+		if copyImage.Type != v2alpha1.TypeSampleImage || strings.Contains(copyImage.Destination, o.LocalStorageFQDN) {
+			continue
+		}
+		registry, err := samplesRegistryOf(copyImage)
+		if err != nil {
+			return err
+		}
+		samplesRegistry = registry
+		break
+	}
+	if samplesRegistry == "" {
+		o.Log.Info(emoji.PageFacingUp + " No sample images mirrored. Skipping samples Config file generation.")
+		return nil
+	}
+
+	o.Log.Info(emoji.PageFacingUp + " Generating samples Config file...")
+	skipped := slices.Clone(skippedImageStreams)
+	slices.Sort(skipped)
+	config := &samplesv1.Config{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: samplesv1.GroupVersion.String(),
+			Kind:       "Config",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        samplesConfigName,
+			Annotations: generateOcMirrorAnnotations(),
+		},
+		Spec: samplesv1.ConfigSpec{
+			ManagementState:     operatorv1.Managed,
+			SamplesRegistry:     samplesRegistry,
+			SkippedImagestreams: slices.Compact(skipped),
+		},
+	}
+
+	obj := unstructured.Unstructured{}
+	var err error
+	obj.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(config)
+	if err != nil {
+		return fmt.Errorf("error while sanitizing the samples Config object prior to marshalling: %w", err)
+	}
+	delete(obj.Object["metadata"].(map[string]any), "creationTimestamp")
+	delete(obj.Object, "status")
+	configBytes, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return fmt.Errorf("unable to marshal samples Config: %w", err)
+	}
+
+	configPath := filepath.Join(o.resourcesDir(), samplesConfigFilename)
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(configPath, configBytes, 0o644); err != nil { //nolint:gosec // G306: no sensitive data
+		return err
+	}
+	o.Log.Info("%s file created", configPath)
+	return nil
+}
+
+// samplesRegistryOf returns the registry, along with its optional namespace, under which
+// the repository of a sample image is mirrored. The cluster-samples-operator replaces the
+// registry of the imagestreams by it.
+func samplesRegistryOf(copyImage v2alpha1.CopyImageSchema) (string, error) {
+	srcSpec, err := image.ParseRef(copyImage.Origin)
+	if err != nil {
+		return "", fmt.Errorf("unable to generate samples Config: %w", err)
+	}
+	dstSpec, err := image.ParseRef(copyImage.Destination)
+	if err != nil {
+		return "", fmt.Errorf("unable to generate samples Config: %w", err)
+	}
+	registry, found := strings.CutSuffix(dstSpec.Name, "/"+srcSpec.PathComponent)
+	if !found {
+		return "", fmt.Errorf("unable to generate samples Config: %s is not mirrored under the same repository path in %s", srcSpec.Name, dstSpec.Name)
+	}
+	return registry, nil
+}
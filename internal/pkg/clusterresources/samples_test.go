@@ -0,0 +1,93 @@
+package clusterresources
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	confv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	samplesv1 "github.com/openshift/api/samples/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+)
+
+func TestSamplesConfigGenerator(t *testing.T) {
+	rubyImage := v2alpha1.CopyImageSchema{
+		Source:      "docker://localhost:55000/ubi9/ruby-33:latest",
+		Destination: "docker://myregistry:5000/mynamespace/ubi9/ruby-33:latest",
+		Origin:      "docker://registry.redhat.io/ubi9/ruby-33:latest",
+		Type:        v2alpha1.TypeSampleImage,
+	}
+
+	t.Run("should generate the samples Config with the mirror registry and the skipped imagestreams", func(t *testing.T) {
+		cr := newSamplesGenerator(t, []v2alpha1.SampleImage{{Name: "ruby"}})
+		require.NoError(t, cr.SamplesConfigGenerator([]v2alpha1.CopyImageSchema{rubyImage}, []string{"python", "nodejs", "python"}))
+
+		content, err := os.ReadFile(filepath.Join(cr.WorkingDir, clusterResourcesDir, samplesConfigFilename))
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "creationTimestamp")
+		assert.NotContains(t, string(content), "status")
+		var config samplesv1.Config
+		require.NoError(t, yaml.Unmarshal(content, &config))
+		assert.Equal(t, "samples.operator.openshift.io/v1", config.APIVersion)
+		assert.Equal(t, "Config", config.Kind)
+		assert.Equal(t, "cluster", config.Name)
+		assert.Equal(t, samplesv1.ConfigSpec{
+			ManagementState:     operatorv1.Managed,
+			SamplesRegistry:     "myregistry:5000/mynamespace",
+			SkippedImagestreams: []string{"nodejs", "python"},
+		}, config.Spec)
+	})
+
+	t.Run("should not generate the samples Config when the sample images are only copied to the cache", func(t *testing.T) {
+		cr := newSamplesGenerator(t, []v2alpha1.SampleImage{{Name: "ruby"}})
+		cached := rubyImage
+		cached.Destination = "docker://localhost:55000/ubi9/ruby-33:latest"
+		require.NoError(t, cr.SamplesConfigGenerator([]v2alpha1.CopyImageSchema{cached}, nil))
+		assert.NoFileExists(t, filepath.Join(cr.WorkingDir, clusterResourcesDir, samplesConfigFilename))
+	})
+
+	t.Run("should not generate the samples Config without samples", func(t *testing.T) {
+		cr := newSamplesGenerator(t, nil)
+		require.NoError(t, cr.SamplesConfigGenerator([]v2alpha1.CopyImageSchema{rubyImage}, nil))
+		assert.NoFileExists(t, filepath.Join(cr.WorkingDir, clusterResourcesDir, samplesConfigFilename))
+	})
+
+	t.Run("should add the sample images to their own ITMS", func(t *testing.T) {
+		cr := newSamplesGenerator(t, []v2alpha1.SampleImage{{Name: "ruby"}})
+		require.NoError(t, cr.IDMS_ITMSGenerator([]v2alpha1.CopyImageSchema{rubyImage}, false))
+
+		content, err := os.ReadFile(filepath.Join(cr.WorkingDir, clusterResourcesDir, itmsFileName))
+		require.NoError(t, err)
+		var itms confv1.ImageTagMirrorSet
+		require.NoError(t, yaml.Unmarshal(content, &itms))
+		assert.Equal(t, "itms-samples-0", itms.Name)
+		assert.Equal(t, []confv1.ImageTagMirrors{
+			{Source: "registry.redhat.io/ubi9", Mirrors: []confv1.ImageMirror{"myregistry:5000/mynamespace/ubi9"}},
+		}, itms.Spec.ImageTagMirrors)
+	})
+
+	t.Run("should fail when the repository path of the sample images is not kept", func(t *testing.T) {
+		cr := newSamplesGenerator(t, []v2alpha1.SampleImage{{Name: "ruby"}})
+		renamed := rubyImage
+		renamed.Destination = "docker://myregistry:5000/ruby:latest"
+		assert.ErrorContains(t, cr.SamplesConfigGenerator([]v2alpha1.CopyImageSchema{renamed}, nil), "is not mirrored under the same repository path")
+	})
+}
+
+func newSamplesGenerator(t *testing.T, samples []v2alpha1.SampleImage) *ClusterResourcesGenerator {
+	t.Helper()
+	return &ClusterResourcesGenerator{
+		Log:              clog.New("debug"),
+		WorkingDir:       filepath.Join(t.TempDir(), "working-dir"),
+		LocalStorageFQDN: "localhost:55000",
+		Config: v2alpha1.ImageSetConfiguration{ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+			Mirror: v2alpha1.Mirror{Samples: samples},
+		}},
+	}
+}
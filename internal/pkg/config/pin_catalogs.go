@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -79,6 +80,7 @@ func PinCatalogDigests(
 	// Iterate through operator catalogs by index to modify in place
 	for i := range pinnedCfg.Mirror.Operators {
 		op := &pinnedCfg.Mirror.Operators[i]
+		original := op.Catalog
 
 		if err := pinSingleCatalogDigest(ctx, op, manifestAPI, opts, log); err != nil {
 			// Best-effort: log warning and continue instead of failing
@@ -86,11 +88,57 @@ func PinCatalogDigests(
 			log.Warn("failed to pin catalog at index %d (%s): %v - will be resolved during collection", i, op.Catalog, err)
 			continue
 		}
+
+		// Keep the merged catalog members pointing to the pinned references
+		if merge := pinnedCfg.Mirror.MergedCatalog; merge != nil {
+			for j := range merge.Catalogs {
+				if merge.Catalogs[j] == original {
+					merge.Catalogs[j] = op.Catalog
+				}
+			}
+		}
 	}
 
 	return pinnedCfg
 }
 
+// PinOperatorSelections returns a copy of the ImageSetConfiguration where the package
+// selections resolved against each catalog during collection (e.g. `latest: N`) replace
+// the ones of the configuration, so that the pinned ISC selects the same bundles.
+// catalogToFBC is the CollectorSchema.CatalogToFBCMap of the operator collection.
+func PinOperatorSelections(cfg v2alpha1.ImageSetConfiguration, catalogToFBC map[string]v2alpha1.CatalogFilterResult) v2alpha1.ImageSetConfiguration {
+	pinnedCfg := copyISC(cfg)
+	for i := range pinnedCfg.Mirror.Operators {
+		op := &pinnedCfg.Mirror.Operators[i]
+		for _, result := range catalogToFBC {
+			samePackages := slices.EqualFunc(result.OperatorFilter.Packages, op.Packages, func(a, b v2alpha1.IncludePackage) bool { return a.Name == b.Name })
+			if result.OperatorFilter.Catalog == op.Catalog && samePackages {
+				op.IncludeConfig = result.OperatorFilter.IncludeConfig
+				break
+			}
+		}
+	}
+	return pinnedCfg
+}
+
+// PinAdditionalImages returns a copy of the ImageSetConfiguration where each additional image
+// with a tag selector, or naming a namespace, is replaced by the images resolved for it during
+// collection, so that the pinned ISC mirrors the same tags.
+// resolved is the CollectorSchema.ResolvedAdditionalImages of the additional images collection.
+func PinAdditionalImages(cfg v2alpha1.ImageSetConfiguration, resolved map[string][]v2alpha1.AdditionalImage) v2alpha1.ImageSetConfiguration {
+	pinnedCfg := copyISC(cfg)
+	pinnedCfg.Mirror.AdditionalImages = nil
+	for _, img := range cfg.Mirror.AdditionalImages {
+		images, ok := resolved[img.Name]
+		if !ok || (img.Tags == nil && !img.IsNamespace()) {
+			pinnedCfg.Mirror.AdditionalImages = append(pinnedCfg.Mirror.AdditionalImages, img)
+			continue
+		}
+		pinnedCfg.Mirror.AdditionalImages = append(pinnedCfg.Mirror.AdditionalImages, images...)
+	}
+	return pinnedCfg
+}
+
 // pinSingleCatalogDigest pins a single catalog reference to its SHA256 digest.
 //
 // The function modifies the op.Catalog field in-place and handles the following cases:
@@ -192,17 +240,27 @@ func writeConfigToFile(obj interface{}, configType, workingDir string) (string,
 // The file is written to: {workingDir}/isc_pinned_{timestamp}.yaml
 // e.g., isc_pinned_2025-12-31T11:37:17Z.yaml
 //
+// The operators built from a template keep the template only: their catalog
+// is built again from it when the pinned ISC is used.
+//
 // Returns the absolute path to the written file.
 func writePinnedISC(
 	cfg v2alpha1.ImageSetConfiguration,
 	workingDir string,
 ) (string, error) {
+	cfg = copyISC(cfg)
+	for i := range cfg.Mirror.Operators {
+		if cfg.Mirror.Operators[i].Template != "" {
+			cfg.Mirror.Operators[i].Catalog = ""
+		}
+	}
 	cfg.SetGroupVersionKind(v2alpha1.GroupVersion.WithKind(v2alpha1.ImageSetConfigurationKind))
 	return writeConfigToFile(cfg, "isc", workingDir)
 }
 
 // createDISCFromISC creates a DeleteImageSetConfiguration from an already-pinned ImageSetConfiguration.
-// It simply converts the Mirror section to a Delete section.
+// It simply converts the Mirror section to a Delete section. The catalogs built from a
+// template are deleted like any catalog on disk, from their image in the working directory.
 //
 // The file is written to: {workingDir}/disc_pinned_{timestamp}.yaml
 // e.g., disc_pinned_2025-12-31T11:37:17Z.yaml
@@ -212,14 +270,20 @@ func createDISCFromISC(
 	pinnedISC v2alpha1.ImageSetConfiguration,
 	workingDir string,
 ) (string, error) {
+	operators := slices.Clone(pinnedISC.Mirror.Operators)
+	for i := range operators {
+		operators[i].Template = ""
+	}
 	disc := v2alpha1.DeleteImageSetConfiguration{
 		DeleteImageSetConfigurationSpec: v2alpha1.DeleteImageSetConfigurationSpec{
 			Delete: v2alpha1.Delete{
 				Platform:         pinnedISC.Mirror.Platform,
-				Operators:        pinnedISC.Mirror.Operators,
+				Operators:        operators,
 				AdditionalImages: pinnedISC.Mirror.AdditionalImages,
+				Artifacts:        pinnedISC.Mirror.Artifacts,
 				Helm:             pinnedISC.Mirror.Helm,
 			},
+			DestinationRewrites: pinnedISC.DestinationRewrites,
 		},
 	}
 
@@ -229,7 +293,8 @@ func createDISCFromISC(
 	return writeConfigToFile(disc, "disc", workingDir)
 }
 
-// copyISC creates a shallow copy of ImageSetConfiguration with a deep copy of the Operators slice.
+// copyISC creates a shallow copy of ImageSetConfiguration with a deep copy of the Operators slice
+// and of the merged catalog.
 // This ensures we don't mutate the original configuration when pinning catalog digests.
 func copyISC(cfg v2alpha1.ImageSetConfiguration) v2alpha1.ImageSetConfiguration {
 	copied := cfg
@@ -240,5 +305,12 @@ func copyISC(cfg v2alpha1.ImageSetConfiguration) v2alpha1.ImageSetConfiguration
 		copy(copied.Mirror.Operators, cfg.Mirror.Operators)
 	}
 
+	// Merged catalog members follow the pinned catalog references
+	if cfg.Mirror.MergedCatalog != nil {
+		merge := *cfg.Mirror.MergedCatalog
+		merge.Catalogs = slices.Clone(merge.Catalogs)
+		copied.Mirror.MergedCatalog = &merge
+	}
+
 	return copied
 }
@@ -231,6 +231,41 @@ func TestPinCatalogDigests_MultipleOperators(t *testing.T) {
 	)
 }
 
+func TestPinCatalogDigests_MergedCatalog(t *testing.T) {
+	logger := clog.New("trace")
+	opts := createM2DTestOpts(t)
+
+	cfg := v2alpha1.ImageSetConfiguration{
+		ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+			Mirror: v2alpha1.Mirror{
+				Operators: []v2alpha1.Operator{
+					{Catalog: redhatIndexTag},
+					{Catalog: certifiedIndexTag},
+				},
+				MergedCatalog: &v2alpha1.MergedCatalog{
+					Catalogs:      []string{certifiedIndexTag, redhatIndexTag},
+					TargetCatalog: "merged-catalog",
+				},
+			},
+		},
+	}
+
+	manifest := &MockManifest{
+		digestMap: map[string]string{
+			redhatIndexTagDocker: testDigestShort2,
+		},
+	}
+
+	pinnedCfg := PinCatalogDigests(t.Context(), cfg, manifest, opts, logger)
+
+	assert.Equal(t,
+		[]string{certifiedIndexTag, image.WithDigest(redhatIndexBase, testDigestShort2)},
+		pinnedCfg.Mirror.MergedCatalog.Catalogs,
+	)
+	// the original configuration is left untouched
+	assert.Equal(t, []string{certifiedIndexTag, redhatIndexTag}, cfg.Mirror.MergedCatalog.Catalogs)
+}
+
 func TestWritePinnedISC_Success(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -335,6 +370,46 @@ func TestPinAndWriteConfigs(t *testing.T) {
 	)
 }
 
+func TestPinAndWriteConfigs_TemplateCatalog(t *testing.T) {
+	logger := clog.New("trace")
+	opts := createM2DTestOpts(t)
+
+	// the catalog built from the template, as set by operator.BuildTemplateCatalogs
+	builtCatalog := "oci://" + filepath.Join(opts.Global.WorkingDir, "operator-catalogs", "templates", "catalog-image", "myorg-catalog")
+	cfg := v2alpha1.ImageSetConfiguration{
+		ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+			Mirror: v2alpha1.Mirror{
+				Operators: []v2alpha1.Operator{
+					{
+						Catalog:                builtCatalog,
+						Template:               "/tmp/catalog-template.yaml",
+						TargetCatalog:          "myorg/catalog",
+						TargetCatalogBaseImage: "quay.io/operator-framework/opm:latest",
+					},
+				},
+			},
+		},
+	}
+
+	iscPath, discPath, err := WriteISCAndDSC(cfg, opts, logger)
+	require.NoError(t, err)
+	assert.Equal(t, builtCatalog, cfg.Mirror.Operators[0].Catalog, "the configuration of the run must not be mutated")
+
+	// the pinned ISC can be read back, and builds the catalog from the template again
+	isc, err := ReadConfig(iscPath, v2alpha1.ImageSetConfigurationKind)
+	require.NoError(t, err)
+	op := isc.(v2alpha1.ImageSetConfiguration).Mirror.Operators[0]
+	assert.Empty(t, op.Catalog)
+	assert.Equal(t, "/tmp/catalog-template.yaml", op.Template)
+
+	// the pinned DISC deletes the catalog built
+	disc, err := ReadConfig(discPath, v2alpha1.DeleteImageSetConfigurationKind)
+	require.NoError(t, err)
+	op = disc.(v2alpha1.DeleteImageSetConfiguration).Delete.Operators[0]
+	assert.Equal(t, builtCatalog, op.Catalog)
+	assert.Empty(t, op.Template)
+}
+
 func TestCopyISC(t *testing.T) {
 	original := v2alpha1.ImageSetConfiguration{
 		ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
@@ -729,3 +804,74 @@ func TestPinSingleCatalog_MultipleCatalogs(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, image.WithDigest("registry.redhat.io/redhat/certified-operator-index", testDigestShort2), op2.Catalog)
 }
+
+func TestPinOperatorSelections(t *testing.T) {
+	catalog := "registry.redhat.io/redhat/redhat-operator-index@sha256:abc123"
+	cfg := v2alpha1.ImageSetConfiguration{
+		ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+			Mirror: v2alpha1.Mirror{
+				Operators: []v2alpha1.Operator{
+					{
+						Catalog:   catalog,
+						TargetTag: "v4.16",
+						IncludeConfig: v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+							{Name: "foo", IncludeBundle: v2alpha1.IncludeBundle{Latest: 1}},
+						}},
+					},
+					{Catalog: "oci:///tmp/catalog"},
+				},
+			},
+		},
+	}
+	resolved := v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+		{Name: "foo", Channels: []v2alpha1.IncludeChannel{{Name: "stable", IncludeBundle: v2alpha1.IncludeBundle{VersionRange: ">=1.2.0 <=1.2.0"}}}},
+	}}
+	catalogToFBC := map[string]v2alpha1.CatalogFilterResult{
+		"docker://" + catalog: {OperatorFilter: v2alpha1.Operator{Catalog: catalog, IncludeConfig: resolved}},
+	}
+
+	pinned := PinOperatorSelections(cfg, catalogToFBC)
+	assert.Equal(t, resolved, pinned.Mirror.Operators[0].IncludeConfig)
+	assert.Equal(t, "v4.16", pinned.Mirror.Operators[0].TargetTag)
+	assert.Equal(t, cfg.Mirror.Operators[1], pinned.Mirror.Operators[1])
+	// the original configuration is not mutated
+	assert.Equal(t, 1, cfg.Mirror.Operators[0].Packages[0].Latest)
+}
+
+func TestPinAdditionalImages(t *testing.T) {
+	platforms := []v2alpha1.InstancePlatformFilter{{OS: "linux", Architecture: "amd64"}}
+	cfg := v2alpha1.ImageSetConfiguration{
+		ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+			Mirror: v2alpha1.Mirror{
+				AdditionalImages: []v2alpha1.AdditionalImage{
+					{Name: "registry.redhat.io/ubi9/ubi:latest"},
+					{Name: "quay.io/example/base", TargetRepo: "mirrored/base", Platforms: platforms, Tags: &v2alpha1.TagSelector{Latest: 2}},
+					{Name: "quay.io/example/app", Tags: &v2alpha1.TagSelector{All: true}},
+					{Name: "quay.io/ourorg/*", TargetRepo: "mirrored/ourorg"},
+				},
+			},
+		},
+	}
+	resolved := map[string][]v2alpha1.AdditionalImage{
+		"quay.io/example/base": {
+			{Name: "quay.io/example/base:1.21.0", TargetRepo: "mirrored/base", Platforms: platforms},
+			{Name: "quay.io/example/base:1.22.1", TargetRepo: "mirrored/base", Platforms: platforms},
+		},
+		"quay.io/ourorg/*": {
+			{Name: "quay.io/ourorg/team/app:v1", TargetRepo: "mirrored/ourorg/team/app"},
+		},
+	}
+
+	pinned := PinAdditionalImages(cfg, resolved)
+	assert.Equal(t, []v2alpha1.AdditionalImage{
+		{Name: "registry.redhat.io/ubi9/ubi:latest"},
+		{Name: "quay.io/example/base:1.21.0", TargetRepo: "mirrored/base", Platforms: platforms},
+		{Name: "quay.io/example/base:1.22.1", TargetRepo: "mirrored/base", Platforms: platforms},
+		// tags not resolved during the collection are kept as selectors
+		{Name: "quay.io/example/app", Tags: &v2alpha1.TagSelector{All: true}},
+		{Name: "quay.io/ourorg/team/app:v1", TargetRepo: "mirrored/ourorg/team/app"},
+	}, pinned.Mirror.AdditionalImages)
+	// the original configuration is not mutated
+	assert.Len(t, cfg.Mirror.AdditionalImages, 4)
+	assert.NotNil(t, cfg.Mirror.AdditionalImages[1].Tags)
+}
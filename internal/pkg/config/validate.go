@@ -1,14 +1,24 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/image"
 )
 
 type (
@@ -17,8 +27,11 @@ type (
 )
 
 var (
-	validationChecks       = []validationFunc{validateOperatorOptions, validateReleaseChannels, validateBlockedImages, validateReleasePlatformFields}
-	validationDeleteChecks = []validationDeleteFunc{validateOperatorOptionsDelete, validateReleaseChannelsDelete}
+	validationChecks       = []validationFunc{validateOperatorOptions, validateReleaseChannels, validateBlockedImages, validateReleasePlatformFields, validateBootArtifacts, validateGraphData, validateMergedCatalog, validateSamples, validateAdditionalImages, validateArtifacts, validateDestinationRewrites}
+	validationDeleteChecks = []validationDeleteFunc{validateOperatorOptionsDelete, validateReleaseChannelsDelete, validateAdditionalImagesDelete, validateArtifactsDelete, validateDestinationRewritesDelete}
+
+	// graphRevisionRegex matches git commits and tags of cincinnati-graph-data
+	graphRevisionRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
 )
 
 // Validate will check an ImagesetConfiguration for input errors.
@@ -36,9 +49,17 @@ func validateOperatorOptions(cfg *v2alpha1.ImageSetConfiguration) []error {
 	catalogs := sets.New[string]()
 	errs := []error{}
 	for _, ctlg := range cfg.Mirror.Operators {
-		ctlgName, err := ctlg.GetUniqueName()
-		if err != nil {
-			errs = append(errs, err)
+		var ctlgName string
+		if ctlg.Template != "" {
+			// catalogs built from templates are identified by their target catalog
+			ctlgName = ctlg.TargetCatalog
+			errs = append(errs, validateTemplateOperator(ctlg)...)
+		} else {
+			var err error
+			ctlgName, err = ctlg.GetUniqueName()
+			if err != nil {
+				errs = append(errs, err)
+			}
 		}
 		if catalogs.Has(ctlgName) {
 			errs = append(errs, fmt.Errorf(
@@ -60,6 +81,22 @@ func validateOperatorOptions(cfg *v2alpha1.ImageSetConfiguration) []error {
 
 func validateOperator(ctlg v2alpha1.Operator) []error {
 	errs := []error{}
+	if !ctlg.Deprecations.IsValid() {
+		errs = append(errs, fmt.Errorf(
+			"catalog %q: deprecations %q must be one of (exclude, warn, fail)", ctlg.Catalog, ctlg.Deprecations,
+		))
+	}
+	if ctlg.CrossCatalogDependencies && ctlg.SkipDependencies {
+		errs = append(errs, fmt.Errorf("catalog %q: crossCatalogDependencies cannot be combined with skipDependencies", ctlg.Catalog))
+	}
+	if ctlg.TargetCatalogBaseImage != "" {
+		// the base image is pulled from a registry when rebuilding the catalog
+		if ref, err := image.ParseRef(ctlg.TargetCatalogBaseImage); err != nil || ref.Transport != consts.DockerProtocol {
+			errs = append(errs, fmt.Errorf("catalog %q: targetCatalogBaseImage %q must be a registry image reference", ctlg.Catalog, ctlg.TargetCatalogBaseImage))
+		}
+	}
+	errs = append(errs, validateCatalogSourceOptions(ctlg)...)
+	errs = append(errs, validateClusterCatalogOptions(ctlg)...)
 	packages := sets.New[string]()
 	for _, pkg := range ctlg.Packages {
 		if packages.Has(pkg.Name) {
@@ -84,6 +121,20 @@ func validateOperator(ctlg v2alpha1.Operator) []error {
 			}
 		}
 
+		errs = append(errs, validateVersionSelection(fmt.Sprintf("catalog %q: operator %q", ctlg.Catalog, pkg.Name), pkg.IncludeBundle)...)
+		for _, excluded := range pkg.ExcludeRelatedImages {
+			if excluded.Name == "" && excluded.Pattern == "" {
+				errs = append(errs, fmt.Errorf("catalog %q: operator %q: excludeRelatedImages entries must set a name or a pattern", ctlg.Catalog, pkg.Name))
+			}
+			if _, err := regexp.Compile(excluded.Pattern); err != nil {
+				errs = append(errs, fmt.Errorf("catalog %q: operator %q: invalid excludeRelatedImages pattern %q: %w", ctlg.Catalog, pkg.Name, excluded.Pattern, err))
+			}
+		}
+		switch pkg.InstallPlanApproval {
+		case "", "Manual", "Automatic":
+		default:
+			errs = append(errs, fmt.Errorf("catalog %q: operator %q: installPlanApproval %q must be one of (Manual, Automatic)", ctlg.Catalog, pkg.Name, pkg.InstallPlanApproval))
+		}
 		errs = append(errs, validatePackageChannels(ctlg.Catalog, &pkg)...)
 	}
 
@@ -93,6 +144,125 @@ func validateOperator(ctlg v2alpha1.Operator) []error {
 	return nil
 }
 
+func validateCatalogSourceOptions(ctlg v2alpha1.Operator) []error {
+	errs := []error{}
+	opts := ctlg.CatalogSource
+	if opts == nil {
+		return errs
+	}
+	if opts.RegistryPollInterval != "" {
+		if interval, err := time.ParseDuration(opts.RegistryPollInterval); err != nil || interval <= 0 {
+			errs = append(errs, fmt.Errorf("catalog %q: catalogSource registryPollInterval %q must be a positive duration", ctlg.Catalog, opts.RegistryPollInterval))
+		}
+	}
+	if opts.GrpcPodConfig == nil {
+		return errs
+	}
+	switch opts.GrpcPodConfig.SecurityContextConfig {
+	case "", "legacy", "restricted":
+	default:
+		errs = append(errs, fmt.Errorf("catalog %q: catalogSource securityContextConfig %q must be one of (legacy, restricted)", ctlg.Catalog, opts.GrpcPodConfig.SecurityContextConfig))
+	}
+	if opts.GrpcPodConfig.MemoryTarget != "" {
+		if _, err := resource.ParseQuantity(opts.GrpcPodConfig.MemoryTarget); err != nil {
+			errs = append(errs, fmt.Errorf("catalog %q: catalogSource memoryTarget %q: %w", ctlg.Catalog, opts.GrpcPodConfig.MemoryTarget, err))
+		}
+	}
+	if err := validateLabels("catalogSource nodeSelector", opts.GrpcPodConfig.NodeSelector); err != nil {
+		errs = append(errs, fmt.Errorf("catalog %q: %w", ctlg.Catalog, err))
+	}
+	for _, toleration := range opts.GrpcPodConfig.Tolerations {
+		switch {
+		case toleration.Operator != "" && toleration.Operator != corev1.TolerationOpEqual && toleration.Operator != corev1.TolerationOpExists:
+			errs = append(errs, fmt.Errorf("catalog %q: catalogSource toleration operator %q must be one of (Equal, Exists)", ctlg.Catalog, toleration.Operator))
+		case toleration.Operator == corev1.TolerationOpExists && toleration.Value != "":
+			errs = append(errs, fmt.Errorf("catalog %q: catalogSource toleration %q: value must be empty when operator is Exists", ctlg.Catalog, toleration.Key))
+		}
+		switch toleration.Effect {
+		case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			errs = append(errs, fmt.Errorf("catalog %q: catalogSource toleration effect %q must be one of (NoSchedule, PreferNoSchedule, NoExecute)", ctlg.Catalog, toleration.Effect))
+		}
+	}
+	return errs
+}
+
+func validateClusterCatalogOptions(ctlg v2alpha1.Operator) []error {
+	if ctlg.ClusterCatalog == nil {
+		return nil
+	}
+	if err := validateLabels("clusterCatalog labels", ctlg.ClusterCatalog.Labels); err != nil {
+		return []error{fmt.Errorf("catalog %q: %w", ctlg.Catalog, err)}
+	}
+	return nil
+}
+
+// validateLabels checks that labels are valid kubernetes label keys and values
+func validateLabels(field string, labels map[string]string) error {
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("%s: invalid key %q: %s", field, key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(labels[key]); len(errs) > 0 {
+			return fmt.Errorf("%s: invalid value %q of %q: %s", field, labels[key], key, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+func validateTemplateOperator(ctlg v2alpha1.Operator) []error {
+	errs := []error{}
+	if ctlg.Catalog != "" {
+		errs = append(errs, fmt.Errorf("template %q: catalog and template are mutually exclusive", ctlg.Template))
+	}
+	switch {
+	case ctlg.TargetCatalog == "":
+		errs = append(errs, fmt.Errorf("template %q: targetCatalog is required", ctlg.Template))
+	case !v2alpha1.IsValidPathComponent(ctlg.TargetCatalog):
+		errs = append(errs, fmt.Errorf("template %q: invalid targetCatalog %q", ctlg.Template, ctlg.TargetCatalog))
+	}
+	// the rendered catalog has no original image to be rebuilt on
+	if ctlg.TargetCatalogBaseImage == "" {
+		errs = append(errs, fmt.Errorf("template %q: targetCatalogBaseImage is required", ctlg.Template))
+	}
+	return errs
+}
+
+func validateMergedCatalog(cfg *v2alpha1.ImageSetConfiguration) []error {
+	merge := cfg.Mirror.MergedCatalog
+	if merge == nil {
+		return nil
+	}
+	errs := []error{}
+	switch {
+	case merge.TargetCatalog == "":
+		errs = append(errs, errors.New("mergedCatalog: targetCatalog is required"))
+	case !v2alpha1.IsValidPathComponent(merge.TargetCatalog):
+		errs = append(errs, fmt.Errorf("mergedCatalog: invalid targetCatalog %q", merge.TargetCatalog))
+	}
+	if len(merge.Catalogs) < 2 {
+		errs = append(errs, errors.New("mergedCatalog: at least two catalogs must be merged"))
+	}
+	catalogs := sets.New[string]()
+	for _, op := range cfg.Mirror.Operators {
+		catalogs.Insert(op.Catalog)
+	}
+	merged := sets.New[string]()
+	for _, ctlg := range merge.Catalogs {
+		if merged.Has(ctlg) {
+			errs = append(errs, fmt.Errorf("mergedCatalog: catalog %q: duplicate found in configuration", ctlg))
+		}
+		merged.Insert(ctlg)
+		if !catalogs.Has(ctlg) {
+			errs = append(errs, fmt.Errorf("mergedCatalog: catalog %q is not one of mirror.operators", ctlg))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
 func validatePackageChannels(ctlgName string, pkg *v2alpha1.IncludePackage) []error {
 	errs := []error{}
 	channels := sets.New[string]()
@@ -129,11 +299,18 @@ func validatePackageChannel(ctlgName string, pkg *v2alpha1.IncludePackage, ch *v
 			))
 		}
 	}
-	if (ch.MinVersion != "" || ch.MaxVersion != "") && (pkg.MinVersion != "" || pkg.MaxVersion != "") {
+	errs = append(errs, validateVersionSelection(fmt.Sprintf("catalog %q: operator %q: channel %q", ctlgName, pkg.Name, ch.Name), ch.IncludeBundle)...)
+	switch {
+	case (ch.MinVersion != "" || ch.MaxVersion != "") && (pkg.MinVersion != "" || pkg.MaxVersion != ""):
 		errs = append(errs, fmt.Errorf(
 			"catalog %q: operator %q: mixing both filtering by minVersion/maxVersion and filtering by channel minVersion/maxVersion is not allowed",
 			ctlgName, pkg.Name,
 		))
+	case hasVersionSelection(ch.IncludeBundle) && hasVersionSelection(pkg.IncludeBundle):
+		errs = append(errs, fmt.Errorf(
+			"catalog %q: operator %q: mixing both package and channel %q version selection (minVersion/maxVersion, versionRange, latest) is not allowed",
+			ctlgName, pkg.Name, ch.Name,
+		))
 	}
 
 	if len(errs) > 0 {
@@ -142,6 +319,28 @@ func validatePackageChannel(ctlgName string, pkg *v2alpha1.IncludePackage, ch *v
 	return nil
 }
 
+// validateVersionSelection checks the versionRange and latest fields of a package or channel.
+// prefix identifies the package or channel in error messages.
+func validateVersionSelection(prefix string, b v2alpha1.IncludeBundle) []error {
+	errs := []error{}
+	if b.VersionRange != "" {
+		if _, err := semver.NewConstraint(b.VersionRange); err != nil {
+			errs = append(errs, fmt.Errorf("%s: versionRange %q must be a valid semver constraint", prefix, b.VersionRange))
+		}
+		if b.MinVersion != "" || b.MaxVersion != "" {
+			errs = append(errs, fmt.Errorf("%s: versionRange cannot be combined with minVersion/maxVersion", prefix))
+		}
+	}
+	if b.Latest < 0 {
+		errs = append(errs, fmt.Errorf("%s: latest must be a positive number, got %d", prefix, b.Latest))
+	}
+	return errs
+}
+
+func hasVersionSelection(b v2alpha1.IncludeBundle) bool {
+	return b.MinVersion != "" || b.MaxVersion != "" || b.VersionRange != "" || b.Latest != 0
+}
+
 // validateReleasePlatformFields ensures that platform.platforms and platform.architectures
 // are not used together — they are mutually exclusive.
 func validateReleasePlatformFields(cfg *v2alpha1.ImageSetConfiguration) []error {
@@ -155,6 +354,239 @@ func validateReleasePlatformFields(cfg *v2alpha1.ImageSetConfiguration) []error
 	return nil
 }
 
+// validateBootArtifacts ensures that only known boot artifact formats are requested,
+// and that each of them is requested only once.
+func validateBootArtifacts(cfg *v2alpha1.ImageSetConfiguration) []error {
+	var errs []error
+	formats := sets.New[v2alpha1.BootArtifactFormat]()
+	for _, format := range cfg.Mirror.Platform.BootArtifacts {
+		if !format.IsValid() {
+			errs = append(errs, fmt.Errorf(
+				"boot artifact %q: unknown format, supported formats are %q, %q, %q and %q",
+				format, v2alpha1.BootArtifactISO, v2alpha1.BootArtifactPXE, v2alpha1.BootArtifactQCOW2, v2alpha1.BootArtifactOVA,
+			))
+		}
+		if formats.Has(format) {
+			errs = append(errs, fmt.Errorf("boot artifact %q: duplicate found in configuration", format))
+		}
+		formats.Insert(format)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateGraphData checks the graph data revision and overlays
+// used to build the graph image.
+func validateGraphData(cfg *v2alpha1.ImageSetConfiguration) []error {
+	gd := cfg.Mirror.Platform.GraphData
+	var errs []error
+	if !cfg.Mirror.Platform.Graph && (gd.Revision != "" || gd.OverlayDir != "" || len(gd.BlockedEdges) > 0 || len(gd.Channels) > 0) {
+		errs = append(errs, fmt.Errorf("platform.graphData: requires platform.graph to be set to true"))
+	}
+	if gd.Revision != "" && !graphRevisionRegex.MatchString(gd.Revision) {
+		errs = append(errs, fmt.Errorf("platform.graphData: revision %q: must be a git commit or tag", gd.Revision))
+	}
+	for _, edge := range gd.BlockedEdges {
+		if _, err := semver.StrictNewVersion(edge.To); err != nil {
+			errs = append(errs, fmt.Errorf("platform.graphData: blocked edge to %q must respect semantic versioning notation", edge.To))
+		}
+		if edge.From == "" {
+			errs = append(errs, fmt.Errorf("platform.graphData: blocked edge to %q: from is mandatory", edge.To))
+		} else if _, err := regexp.Compile(edge.From); err != nil {
+			errs = append(errs, fmt.Errorf("platform.graphData: blocked edge to %q: invalid regular expression: %w", edge.To, err))
+		}
+	}
+	channels := sets.New[string]()
+	for _, ch := range gd.Channels {
+		if channels.Has(ch.Name) {
+			errs = append(errs, fmt.Errorf("platform.graphData: channel %q: duplicate found in configuration", ch.Name))
+		}
+		channels.Insert(ch.Name)
+		if len(ch.Versions) == 0 {
+			errs = append(errs, fmt.Errorf("platform.graphData: channel %q: at least one version is required", ch.Name))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateSamples checks that the imagestreams of the samples discovered from the
+// release payload are named once, and that releases are mirrored for them.
+func validateSamples(cfg *v2alpha1.ImageSetConfiguration) []error {
+	var errs []error
+	names := sets.New[string]()
+	hasRelease := len(cfg.Mirror.Platform.Channels) > 0 || cfg.Mirror.Platform.Release != ""
+	for _, sample := range cfg.Mirror.Samples {
+		if sample.Path != "" {
+			continue
+		}
+		if sample.Name == "" {
+			errs = append(errs, errors.New("samples: name or path is mandatory"))
+			continue
+		}
+		if names.Has(sample.Name) {
+			errs = append(errs, fmt.Errorf("sample %q: duplicate found in configuration", sample.Name))
+		}
+		names.Insert(sample.Name)
+		if !hasRelease {
+			errs = append(errs, fmt.Errorf("sample %q: requires platform.channels or platform.release, or a path to local imagestreams", sample.Name))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateAdditionalImages checks the namespaces and the tag selectors of the additional images.
+func validateAdditionalImages(cfg *v2alpha1.ImageSetConfiguration) []error {
+	var errs []error
+	for _, img := range cfg.Mirror.AdditionalImages {
+		errs = append(errs, validateAdditionalImage(img)...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateAdditionalImage(img v2alpha1.AdditionalImage) []error {
+	errs := []error{}
+	prefix := fmt.Sprintf("additional image %q", img.Name)
+	isRegistry := !strings.Contains(img.Name, "://") || strings.HasPrefix(img.Name, consts.DockerProtocol)
+	switch {
+	case img.IsNamespace():
+		if !isRegistry {
+			errs = append(errs, fmt.Errorf("%s: namespaces can only be mirrored from registries", prefix))
+		}
+		if img.TargetTag != "" {
+			errs = append(errs, fmt.Errorf("%s: targetTag cannot be combined with a namespace", prefix))
+		}
+	case img.RepositoriesFile != "":
+		errs = append(errs, fmt.Errorf("%s: repositoriesFile requires a namespace (registry/namespace/*)", prefix))
+	case img.Tags != nil:
+		if !isRegistry {
+			errs = append(errs, fmt.Errorf("%s: tags can only be selected on registries", prefix))
+		} else if _, err := image.ParseRef(img.Name); err == nil {
+			errs = append(errs, fmt.Errorf("%s: name must be a repository without tag or digest when tags are selected", prefix))
+		}
+		if img.TargetTag != "" {
+			errs = append(errs, fmt.Errorf("%s: targetTag cannot be combined with tags", prefix))
+		}
+	}
+	if img.Tags != nil {
+		errs = append(errs, validateTagSelector(prefix, *img.Tags)...)
+	}
+	return errs
+}
+
+// validateArtifacts checks the references and the target repositories of the OCI artifacts.
+func validateArtifacts(cfg *v2alpha1.ImageSetConfiguration) []error {
+	if errs := validateArtifactList(cfg.Mirror.Artifacts); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateArtifactsDelete(cfg *v2alpha1.DeleteImageSetConfiguration) error {
+	return utilerrors.NewAggregate(validateArtifactList(cfg.Delete.Artifacts))
+}
+
+func validateArtifactList(artifacts []v2alpha1.Artifact) []error {
+	errs := []error{}
+	names := sets.New[string]()
+	for _, artifact := range artifacts {
+		prefix := fmt.Sprintf("artifact %q", artifact.Name)
+		if names.Has(artifact.Name) {
+			errs = append(errs, fmt.Errorf("%s: duplicate found in configuration", prefix))
+			continue
+		}
+		names.Insert(artifact.Name)
+		imgSpec, err := image.ParseRef(artifact.Name)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+		case imgSpec.Transport != consts.DockerProtocol && imgSpec.Transport != consts.OciProtocol:
+			errs = append(errs, fmt.Errorf("%s: artifacts can only be mirrored from registries or OCI layouts", prefix))
+		}
+		if artifact.TargetRepo != "" && !v2alpha1.IsValidPathComponent(artifact.TargetRepo) {
+			errs = append(errs, fmt.Errorf("%s: invalid targetRepo %s", prefix, artifact.TargetRepo))
+		}
+	}
+	return errs
+}
+
+// validateDestinationRewrites checks the targets, the regular expressions
+// and the content types of the destination rewrite rules.
+func validateDestinationRewrites(cfg *v2alpha1.ImageSetConfiguration) []error {
+	if errs := validateRewriteRules(cfg.DestinationRewrites); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateDestinationRewritesDelete(cfg *v2alpha1.DeleteImageSetConfiguration) error {
+	return utilerrors.NewAggregate(validateRewriteRules(cfg.DestinationRewrites))
+}
+
+func validateRewriteRules(rules []v2alpha1.DestinationRewrite) []error {
+	errs := []error{}
+	contentTypes := []v2alpha1.RewriteContentType{v2alpha1.RewriteOperator, v2alpha1.RewriteAdditionalImage, v2alpha1.RewriteArtifact, v2alpha1.RewriteHelm}
+	for i, rule := range rules {
+		prefix := fmt.Sprintf("destination rewrite %d", i)
+		if rule.Target == "" {
+			errs = append(errs, fmt.Errorf("%s: target must be set", prefix))
+		}
+		if rule.Match != "" {
+			if _, err := regexp.Compile(rule.Match); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid match %q: %w", prefix, rule.Match, err))
+			}
+		}
+		for _, contentType := range rule.ContentTypes {
+			if !slices.Contains(contentTypes, contentType) {
+				errs = append(errs, fmt.Errorf("%s: content type %q must be one of %v", prefix, contentType, contentTypes))
+			}
+		}
+	}
+	return errs
+}
+
+func validateTagSelector(prefix string, sel v2alpha1.TagSelector) []error {
+	errs := []error{}
+	if !sel.All && len(sel.Include) == 0 && len(sel.Exclude) == 0 && sel.VersionRange == "" && sel.Latest == 0 {
+		errs = append(errs, fmt.Errorf("%s: tags must select all tags, or set include, exclude, versionRange or latest", prefix))
+	}
+	if sel.All && (len(sel.Include) > 0 || sel.VersionRange != "" || sel.Latest != 0) {
+		errs = append(errs, fmt.Errorf("%s: tags.all can only be combined with tags.exclude", prefix))
+	}
+	for _, expr := range slices.Concat(sel.Include, sel.Exclude) {
+		if _, err := regexp.Compile(expr); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid tag regular expression %q: %w", prefix, expr, err))
+		}
+	}
+	if sel.VersionRange != "" {
+		if _, err := semver.NewConstraint(sel.VersionRange); err != nil {
+			errs = append(errs, fmt.Errorf("%s: versionRange %q must be a valid semver constraint", prefix, sel.VersionRange))
+		}
+	}
+	if sel.Latest < 0 {
+		errs = append(errs, fmt.Errorf("%s: latest must be a positive number, got %d", prefix, sel.Latest))
+	}
+	switch sel.SortBy {
+	case "", v2alpha1.TagSortingSemver, v2alpha1.TagSortingCreated:
+		if sel.SortBy != "" && sel.Latest == 0 {
+			errs = append(errs, fmt.Errorf("%s: sortBy requires latest", prefix))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("%s: sortBy %q must be %q or %q", prefix, sel.SortBy, v2alpha1.TagSortingSemver, v2alpha1.TagSortingCreated))
+	}
+	return errs
+}
+
 func validateReleaseChannels(cfg *v2alpha1.ImageSetConfiguration) []error {
 	channels := sets.New[string]()
 	for _, channel := range cfg.Mirror.Platform.Channels {
@@ -223,3 +655,11 @@ func validateReleaseChannelsDelete(cfg *v2alpha1.DeleteImageSetConfiguration) er
 	}
 	return nil
 }
+
+func validateAdditionalImagesDelete(cfg *v2alpha1.DeleteImageSetConfiguration) error {
+	var errs []error
+	for _, img := range cfg.Delete.AdditionalImages {
+		errs = append(errs, validateAdditionalImage(img)...)
+	}
+	return utilerrors.NewAggregate(errs)
+}
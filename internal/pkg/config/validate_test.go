@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
 )
@@ -330,6 +331,572 @@ func TestValidate(t *testing.T) {
 			},
 			expError: `invalid configuration: blocked image "[invalid": invalid regular expression: error parsing regexp: missing closing ]: ` + "`[invalid`",
 		},
+		{
+			name: "Valid/BootArtifacts",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Platform: v2alpha1.Platform{
+							BootArtifacts: []v2alpha1.BootArtifactFormat{v2alpha1.BootArtifactISO, v2alpha1.BootArtifactPXE},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/BootArtifactUnknownFormat",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Platform: v2alpha1.Platform{
+							BootArtifacts: []v2alpha1.BootArtifactFormat{"vhd"},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: boot artifact "vhd": unknown format, supported formats are "iso", "pxe", "qcow2" and "ova"`,
+		},
+		{
+			name: "Invalid/DuplicateBootArtifacts",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Platform: v2alpha1.Platform{
+							BootArtifacts: []v2alpha1.BootArtifactFormat{v2alpha1.BootArtifactISO, v2alpha1.BootArtifactISO},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: boot artifact "iso": duplicate found in configuration`,
+		},
+		{
+			name: "Valid/GraphData",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Platform: v2alpha1.Platform{
+							Graph: true,
+							GraphData: v2alpha1.GraphData{
+								Revision:     "6f1a3c2",
+								BlockedEdges: []v2alpha1.GraphBlockedEdge{{To: "4.14.2", From: "4\\.13\\..*"}},
+								Channels:     []v2alpha1.GraphChannel{{Name: "stable-4.14", Versions: []string{"4.14.1"}}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/GraphDataWithoutGraph",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Platform: v2alpha1.Platform{
+							GraphData: v2alpha1.GraphData{Revision: "6f1a3c2"},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: platform.graphData: requires platform.graph to be set to true`,
+		},
+		{
+			name: "Invalid/GraphDataBlockedEdge",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Platform: v2alpha1.Platform{
+							Graph: true,
+							GraphData: v2alpha1.GraphData{
+								Revision:     "../main",
+								BlockedEdges: []v2alpha1.GraphBlockedEdge{{To: "4.14", From: "["}},
+								Channels:     []v2alpha1.GraphChannel{{Name: "stable-4.14"}},
+							},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: [platform.graphData: revision "../main": must be a git commit or tag, ` +
+				`platform.graphData: blocked edge to "4.14" must respect semantic versioning notation, ` +
+				`platform.graphData: blocked edge to "4.14": invalid regular expression: error parsing regexp: missing closing ]: ` + "`[`" + `, ` +
+				`platform.graphData: channel "stable-4.14": at least one version is required]`,
+		},
+		{
+			name: "Valid/CatalogFilteringByVersionRangeAndLatest",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{
+								Catalog: "test-catalog1:latest",
+								IncludeConfig: v2alpha1.IncludeConfig{
+									Packages: []v2alpha1.IncludePackage{
+										{
+											Name:          "operator1",
+											IncludeBundle: v2alpha1.IncludeBundle{VersionRange: ">=4.14 <4.17, !=4.15.3", Latest: 3},
+										},
+										{
+											Name: "operator2",
+											Channels: []v2alpha1.IncludeChannel{
+												{Name: "stable", IncludeBundle: v2alpha1.IncludeBundle{MinVersion: "1.0.0", Latest: 2}},
+												{Name: "fast", IncludeBundle: v2alpha1.IncludeBundle{VersionRange: "^2.0"}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/CatalogFilteringByVersionRangeAndLatest",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{
+								Catalog: "test-catalog1:latest",
+								IncludeConfig: v2alpha1.IncludeConfig{
+									Packages: []v2alpha1.IncludePackage{
+										{
+											Name:          "operator1",
+											IncludeBundle: v2alpha1.IncludeBundle{VersionRange: ">=1.0.0", MinVersion: "1.0.0", Latest: -1},
+										},
+										{
+											Name:          "operator2",
+											IncludeBundle: v2alpha1.IncludeBundle{Latest: 2},
+											Channels: []v2alpha1.IncludeChannel{
+												{Name: "stable", IncludeBundle: v2alpha1.IncludeBundle{VersionRange: "not-a-range"}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: [catalog "test-catalog1:latest": operator "operator1": versionRange cannot be combined with minVersion/maxVersion, ` +
+				`catalog "test-catalog1:latest": operator "operator1": latest must be a positive number, got -1, ` +
+				`catalog "test-catalog1:latest": operator "operator2": channel "stable": versionRange "not-a-range" must be a valid semver constraint, ` +
+				`catalog "test-catalog1:latest": operator "operator2": mixing both package and channel "stable" version selection (minVersion/maxVersion, versionRange, latest) is not allowed]`,
+		},
+		{
+			name: "Valid/CatalogDeprecationPolicy",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest", Deprecations: v2alpha1.DeprecationsExclude},
+							{Catalog: "test-catalog2:latest", Deprecations: v2alpha1.DeprecationsWarn},
+							{Catalog: "test-catalog3:latest", Deprecations: v2alpha1.DeprecationsFail},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/CatalogDeprecationPolicy",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest", Deprecations: "ignore"},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: catalog "test-catalog1:latest": deprecations "ignore" must be one of (exclude, warn, fail)`,
+		},
+		{
+			name: "Invalid/CrossCatalogDependenciesWithSkipDependencies",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest", CrossCatalogDependencies: true, SkipDependencies: true},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: catalog "test-catalog1:latest": crossCatalogDependencies cannot be combined with skipDependencies`,
+		},
+		{
+			name: "Valid/CatalogRebuildOptions",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{
+								Catalog:                "test-catalog1:latest",
+								TargetCatalogBaseImage: "quay.io/operator-framework/opm:v1.50.0",
+								GenerateServeCache:     true,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/CatalogBaseImageOnDisk",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest", TargetCatalogBaseImage: "oci:///tmp/opm"},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: catalog "test-catalog1:latest": targetCatalogBaseImage "oci:///tmp/opm" must be a registry image reference`,
+		},
+		{
+			name: "Valid/CatalogTemplate",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest"},
+							{
+								Template:               "/tmp/templates/semver.yaml",
+								TargetCatalog:          "internal/internal-catalog",
+								TargetCatalogBaseImage: "quay.io/operator-framework/opm:latest",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/CatalogTemplate",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest", Template: "/tmp/templates"},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: [template "/tmp/templates": catalog and template are mutually exclusive, ` +
+				`template "/tmp/templates": targetCatalog is required, template "/tmp/templates": targetCatalogBaseImage is required]`,
+		},
+		{
+			name: "Valid/ExcludeRelatedImages",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest", IncludeConfig: v2alpha1.IncludeConfig{
+								Packages: []v2alpha1.IncludePackage{{
+									Name: "foo",
+									ExcludeRelatedImages: []v2alpha1.ExcludedRelatedImage{
+										{Name: "must-gather"},
+										{Pattern: `.*/foo-gpu@sha256:.*`, Bundles: []string{"foo.v1.0.0"}},
+									},
+								}},
+							}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/ExcludeRelatedImages",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest", IncludeConfig: v2alpha1.IncludeConfig{
+								Packages: []v2alpha1.IncludePackage{{
+									Name:                 "foo",
+									ExcludeRelatedImages: []v2alpha1.ExcludedRelatedImage{{Bundles: []string{"foo.v1.0.0"}}, {Pattern: "foo-("}},
+								}},
+							}},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: [catalog "test-catalog1:latest": operator "foo": excludeRelatedImages entries must set a name or a pattern, ` +
+				`catalog "test-catalog1:latest": operator "foo": invalid excludeRelatedImages pattern "foo-(": error parsing regexp: missing closing ): ` + "`foo-(`]",
+		},
+		{
+			name: "Invalid/InstallPlanApproval",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest", GenerateInstallManifests: true, IncludeConfig: v2alpha1.IncludeConfig{
+								Packages: []v2alpha1.IncludePackage{{Name: "foo", InstallPlanApproval: "Manual"}, {Name: "bar", InstallPlanApproval: "automatic"}},
+							}},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: catalog "test-catalog1:latest": operator "bar": installPlanApproval "automatic" must be one of (Manual, Automatic)`,
+		},
+		{
+			name: "Valid/CatalogResourceOptions",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{
+								Catalog: "test-catalog1:latest",
+								CatalogSource: &v2alpha1.CatalogSourceOptions{
+									DisplayName:          "Mirrored operators",
+									Priority:             -100,
+									RegistryPollInterval: "30m",
+									GrpcPodConfig: &v2alpha1.CatalogSourceGrpcPodConfig{
+										NodeSelector:          map[string]string{"node-role.kubernetes.io/infra": ""},
+										Tolerations:           []corev1.Toleration{{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+										SecurityContextConfig: "restricted",
+										MemoryTarget:          "100Mi",
+									},
+								},
+								ClusterCatalog: &v2alpha1.ClusterCatalogOptions{Priority: 10, Labels: map[string]string{"example.com/mirrored": "true"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/CatalogResourceOptions",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{
+								Catalog: "test-catalog1:latest",
+								CatalogSource: &v2alpha1.CatalogSourceOptions{
+									RegistryPollInterval: "10",
+									GrpcPodConfig: &v2alpha1.CatalogSourceGrpcPodConfig{
+										Tolerations:           []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists, Value: "true"}},
+										SecurityContextConfig: "privileged",
+										MemoryTarget:          "lots",
+									},
+								},
+								ClusterCatalog: &v2alpha1.ClusterCatalogOptions{Labels: map[string]string{"mirrored": "not valid"}},
+							},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: [catalog "test-catalog1:latest": catalogSource registryPollInterval "10" must be a positive duration, ` +
+				`catalog "test-catalog1:latest": catalogSource securityContextConfig "privileged" must be one of (legacy, restricted), ` +
+				`catalog "test-catalog1:latest": catalogSource memoryTarget "lots": quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$', ` +
+				`catalog "test-catalog1:latest": catalogSource toleration "infra": value must be empty when operator is Exists, ` +
+				`catalog "test-catalog1:latest": clusterCatalog labels: invalid value "not valid" of "mirrored": a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')]`,
+		},
+		{
+			name: "Valid/Samples",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Platform: v2alpha1.Platform{Release: "quay.io/openshift-release-dev/ocp-release:4.16.0-x86_64"},
+						Samples: []v2alpha1.SampleImage{
+							{Name: "ruby"},
+							{Name: "python", Tags: []string{"3.12-ubi9"}},
+							{Path: "/samples/imagestreams"},
+							{Name: "ruby", Path: "/samples/ruby.json"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/Samples",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Samples: []v2alpha1.SampleImage{
+							{Name: "ruby"},
+							{Name: "ruby"},
+							{Tags: []string{"latest"}},
+							{Path: "/samples/imagestreams"},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: [sample "ruby": requires platform.channels or platform.release, or a path to local imagestreams, ` +
+				`sample "ruby": duplicate found in configuration, ` +
+				`samples: name or path is mandatory]`,
+		},
+		{
+			name: "Valid/AdditionalImageTags",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						AdditionalImages: []v2alpha1.AdditionalImage{
+							{Name: "registry.redhat.io/ubi9/ubi:latest"},
+							{Name: "quay.io/example/base", Tags: &v2alpha1.TagSelector{Include: []string{`1\.2[0-9]\..*`}, Exclude: []string{".*-rc.*"}}},
+							{Name: "quay.io/example/app", Tags: &v2alpha1.TagSelector{VersionRange: ">=1.20 <1.30", Latest: 3, SortBy: v2alpha1.TagSortingCreated}},
+							{Name: "docker://quay.io/example/tools", TargetRepo: "mirrored/tools", Tags: &v2alpha1.TagSelector{All: true, Exclude: []string{"latest"}}},
+							{Name: "quay.io/ourorg/*", TargetRepo: "mirrored/ourorg"},
+							{Name: "registry.example.com/*", RepositoriesFile: "/config/repositories.txt", Tags: &v2alpha1.TagSelector{Latest: 1}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/AdditionalImageTags",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						AdditionalImages: []v2alpha1.AdditionalImage{
+							{Name: "quay.io/example/base:1.2", TargetTag: "mirrored", Tags: &v2alpha1.TagSelector{}},
+							{Name: "oci:///images/base", Tags: &v2alpha1.TagSelector{All: true, Latest: 2}},
+							{Name: "quay.io/example/app", Tags: &v2alpha1.TagSelector{Include: []string{"1.(2"}, VersionRange: "1.x.y", Latest: -1, SortBy: "date"}},
+							{Name: "quay.io/example/tools", Tags: &v2alpha1.TagSelector{Include: []string{"v.*"}, SortBy: v2alpha1.TagSortingSemver}},
+							{Name: "oci:///images/*", TargetTag: "latest"},
+							{Name: "quay.io/example/single:1.0", RepositoriesFile: "/config/repositories.txt"},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: [additional image "quay.io/example/base:1.2": name must be a repository without tag or digest when tags are selected, ` +
+				`additional image "quay.io/example/base:1.2": targetTag cannot be combined with tags, ` +
+				`additional image "quay.io/example/base:1.2": tags must select all tags, or set include, exclude, versionRange or latest, ` +
+				`additional image "oci:///images/base": tags can only be selected on registries, ` +
+				`additional image "oci:///images/base": tags.all can only be combined with tags.exclude, ` +
+				"additional image \"quay.io/example/app\": invalid tag regular expression \"1.(2\": error parsing regexp: missing closing ): `1.(2`, " +
+				`additional image "quay.io/example/app": versionRange "1.x.y" must be a valid semver constraint, ` +
+				`additional image "quay.io/example/app": latest must be a positive number, got -1, ` +
+				`additional image "quay.io/example/app": sortBy "date" must be "semver" or "created", ` +
+				`additional image "quay.io/example/tools": sortBy requires latest, ` +
+				`additional image "oci:///images/*": namespaces can only be mirrored from registries, ` +
+				`additional image "oci:///images/*": targetTag cannot be combined with a namespace, ` +
+				`additional image "quay.io/example/single:1.0": repositoriesFile requires a namespace (registry/namespace/*)]`,
+		},
+		{
+			name: "Valid/Artifacts",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Artifacts: []v2alpha1.Artifact{
+							{Name: "quay.io/ourorg/policies/opa-bundle:v1.4"},
+							{Name: "ghcr.io/ourorg/filters/ratelimit@sha256:6f0f4f1b0c9f1b3c8b2e7f6a5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c", TargetRepo: "wasm/ratelimit", TargetTag: "1.0"},
+							{Name: "oci:///artifacts/models/classifier", TargetRepo: "models/classifier"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/Artifacts",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Artifacts: []v2alpha1.Artifact{
+							{Name: "quay.io/ourorg/policies/opa-bundle"},
+							{Name: "dir:///artifacts/model"},
+							{Name: "quay.io/ourorg/filters/ratelimit:1.0", TargetRepo: "Filters//RateLimit"},
+							{Name: "quay.io/ourorg/filters/ratelimit:1.0"},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: [artifact "quay.io/ourorg/policies/opa-bundle": quay.io/ourorg/policies/opa-bundle unable to parse image correctly : tag and digest are empty, ` +
+				`artifact "dir:///artifacts/model": artifacts can only be mirrored from registries or OCI layouts, ` +
+				`artifact "quay.io/ourorg/filters/ratelimit:1.0": invalid targetRepo Filters//RateLimit, ` +
+				`artifact "quay.io/ourorg/filters/ratelimit:1.0": duplicate found in configuration]`,
+		},
+		{
+			name: "Valid/DestinationRewrites",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					DestinationRewrites: []v2alpha1.DestinationRewrite{
+						{Registry: "registry.redhat.io", ContentTypes: []v2alpha1.RewriteContentType{v2alpha1.RewriteOperator, v2alpha1.RewriteHelm}, Target: "redhat/{namespace}-{name}"},
+						{Match: `quay\.io/(.+)`, Target: "virtual-quay/$1"},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/DestinationRewrites",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					DestinationRewrites: []v2alpha1.DestinationRewrite{
+						{Registry: "registry.redhat.io"},
+						{Match: "quay.io/(.+", Target: "virtual-quay/$1", ContentTypes: []v2alpha1.RewriteContentType{"release"}},
+					},
+				},
+			},
+			expError: `invalid configuration: [destination rewrite 0: target must be set, ` +
+				"destination rewrite 1: invalid match \"quay.io/(.+\": error parsing regexp: missing closing ): `quay.io/(.+`, " +
+				`destination rewrite 1: content type "release" must be one of [operator additionalImage artifact helm]]`,
+		},
+		{
+			name: "Valid/MergedCatalog",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest"},
+							{Catalog: "test-catalog2:latest"},
+						},
+						MergedCatalog: &v2alpha1.MergedCatalog{
+							Catalogs:      []string{"test-catalog2:latest", "test-catalog1:latest"},
+							TargetCatalog: "example/merged-catalog",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid/MergedCatalogUnknownCatalog",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest"},
+						},
+						MergedCatalog: &v2alpha1.MergedCatalog{
+							Catalogs:      []string{"test-catalog1:latest", "test-catalog2:latest"},
+							TargetCatalog: "merged-catalog",
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: mergedCatalog: catalog "test-catalog2:latest" is not one of mirror.operators`,
+		},
+		{
+			name: "Invalid/MergedCatalogSingleCatalog",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest"},
+						},
+						MergedCatalog: &v2alpha1.MergedCatalog{
+							Catalogs:      []string{"test-catalog1:latest"},
+							TargetCatalog: "merged-catalog",
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: mergedCatalog: at least two catalogs must be merged`,
+		},
+		{
+			name: "Invalid/MergedCatalogMissingTarget",
+			config: &v2alpha1.ImageSetConfiguration{
+				ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+					Mirror: v2alpha1.Mirror{
+						Operators: []v2alpha1.Operator{
+							{Catalog: "test-catalog1:latest"},
+							{Catalog: "test-catalog2:latest"},
+						},
+						MergedCatalog: &v2alpha1.MergedCatalog{
+							Catalogs: []string{"test-catalog1:latest", "test-catalog2:latest"},
+						},
+					},
+				},
+			},
+			expError: `invalid configuration: mergedCatalog: targetCatalog is required`,
+		},
 		{
 			name: "Invalid/DuplicateOperatorPackageChannels",
 			config: &v2alpha1.ImageSetConfiguration{
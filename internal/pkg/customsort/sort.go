@@ -16,9 +16,11 @@ func (a ByTypePriority) Less(i, j int) bool {
 		v2alpha1.TypeCincinnatiGraph.String():      4,
 		v2alpha1.TypeOperatorRelatedImage.String(): 5,
 		v2alpha1.TypeGeneric.String():              6,
+		v2alpha1.TypeArtifact.String():             6,
 		v2alpha1.TypeHelmImage.String():            7,
-		v2alpha1.TypeOperatorBundle.String():       8,
-		v2alpha1.TypeOperatorCatalog.String():      9,
+		v2alpha1.TypeSampleImage.String():          8,
+		v2alpha1.TypeOperatorBundle.String():       9,
+		v2alpha1.TypeOperatorCatalog.String():      10,
 	}
 
 	defaultPriority := 0
@@ -92,8 +92,10 @@ func (o DeleteImages) WriteDeleteMetaData(ctx context.Context, images []v2alpha1
 				Platform:         o.Config.Mirror.Platform,
 				Operators:        o.Config.Mirror.Operators,
 				AdditionalImages: o.Config.Mirror.AdditionalImages,
+				Artifacts:        o.Config.Mirror.Artifacts,
 				Helm:             o.Config.Mirror.Helm,
 			},
+			DestinationRewrites: o.Config.DestinationRewrites,
 		},
 	}
 	discYamlData, err := yaml.Marshal(disc)
@@ -120,6 +122,7 @@ func (o DeleteImages) processDeleteItems(ctx context.Context, images []v2alpha1.
 		item := v2alpha1.DeleteItem{
 			ImageName:      img.Origin,
 			ImageReference: img.Destination,
+			CacheReference: o.cacheReference(img, img.Destination),
 			Type:           img.Type,
 		}
 		items = append(items, item)
@@ -201,9 +204,13 @@ func (o DeleteImages) DeleteRegistryImages(deleteImageList v2alpha1.DeleteImageL
 		collectorSchema.AllImages = append(collectorSchema.AllImages, cis)
 
 		if o.Opts.Global.ForceCacheDelete {
+			cacheRef := img.CacheReference
+			if cacheRef == "" {
+				cacheRef = strings.ReplaceAll(img.ImageReference, o.Opts.Global.DeleteDestination, consts.DockerProtocol+o.LocalStorageFQDN)
+			}
 			cis := v2alpha1.CopyImageSchema{
 				Origin:      img.ImageName,
-				Destination: strings.ReplaceAll(img.ImageReference, o.Opts.Global.DeleteDestination, consts.DockerProtocol+o.LocalStorageFQDN),
+				Destination: cacheRef,
 				Type:        img.Type,
 			}
 			o.Log.Debug("deleting images local cache %v", cis.Destination)
@@ -211,7 +218,7 @@ func (o DeleteImages) DeleteRegistryImages(deleteImageList v2alpha1.DeleteImageL
 		}
 
 		switch {
-		case img.Type.IsRelease():
+		case img.Type.IsRelease() || img.Type.IsSampleImage():
 			collectorSchema.TotalReleaseImages += increment
 		case img.Type.IsOperator():
 			collectorSchema.TotalOperatorImages += increment
@@ -320,6 +327,26 @@ func (o DeleteImages) sigDeleteItem(img v2alpha1.CopyImageSchema, sig string) *v
 	return &v2alpha1.DeleteItem{
 		ImageName:      originSigRef,
 		ImageReference: destSigRef,
+		CacheReference: o.cacheReference(img, destSigRef),
 		Type:           img.Type,
 	}
 }
+
+// cacheReference returns the reference in the local cache of ref, a reference of the destination
+// repository of img, when the destination was rewritten to another repository than the one
+// of the cache. It is empty when both repositories have the same path.
+func (o DeleteImages) cacheReference(img v2alpha1.CopyImageSchema, ref string) string {
+	srcSpec, err := image.ParseRef(img.Source)
+	if err != nil || srcSpec.Transport != consts.DockerProtocol || srcSpec.Domain != o.LocalStorageFQDN {
+		return ""
+	}
+	destSpec, err := image.ParseRef(img.Destination)
+	if err != nil {
+		return ""
+	}
+	cachedRepo := strings.Replace(destSpec.Transport+destSpec.Name, o.Opts.Global.DeleteDestination, consts.DockerProtocol+o.LocalStorageFQDN, 1)
+	if cachedRepo == srcSpec.Transport+srcSpec.Name {
+		return ""
+	}
+	return srcSpec.Transport + srcSpec.Name + strings.TrimPrefix(ref, destSpec.Transport+destSpec.Name)
+}
@@ -569,3 +569,58 @@ func (o mockManifest) GetManifestListDigests(ctx context.Context, sourceCtx *typ
 func (o mockManifest) GetOCIImageFromIndex(dir string) (gcrv1.Image, error) { //nolint:ireturn // as expected by go-containerregistry
 	return nil, nil
 }
+
+func TestCacheReference(t *testing.T) {
+	d := DeleteImages{
+		Opts:             mirror.CopyOptions{Global: &mirror.GlobalOptions{DeleteDestination: "docker://mirror.example.com/ocp"}},
+		LocalStorageFQDN: "localhost:55000",
+	}
+	tests := []struct {
+		name     string
+		img      v2alpha1.CopyImageSchema
+		ref      string
+		expected string
+	}{
+		{
+			name: "destination with the repository of the cache",
+			img: v2alpha1.CopyImageSchema{
+				Source:      "docker://localhost:55000/ubi9/ruby-33:latest",
+				Destination: "docker://mirror.example.com/ocp/ubi9/ruby-33:latest",
+			},
+			ref:      "docker://mirror.example.com/ocp/ubi9/ruby-33:latest",
+			expected: "",
+		},
+		{
+			name: "rewritten destination",
+			img: v2alpha1.CopyImageSchema{
+				Source:      "docker://localhost:55000/ubi9/ruby-33:latest",
+				Destination: "docker://mirror.example.com/ocp/redhat/ubi9-ruby-33:latest",
+			},
+			ref:      "docker://mirror.example.com/ocp/redhat/ubi9-ruby-33:latest",
+			expected: "docker://localhost:55000/ubi9/ruby-33:latest",
+		},
+		{
+			name: "signature of a rewritten destination",
+			img: v2alpha1.CopyImageSchema{
+				Source:      "docker://localhost:55000/ubi9/ruby-33:latest",
+				Destination: "docker://mirror.example.com/ocp/redhat/ubi9-ruby-33:latest",
+			},
+			ref:      "docker://mirror.example.com/ocp/redhat/ubi9-ruby-33:sha256-abc123.sig",
+			expected: "docker://localhost:55000/ubi9/ruby-33:sha256-abc123.sig",
+		},
+		{
+			name: "source outside of the cache",
+			img: v2alpha1.CopyImageSchema{
+				Source:      "docker://registry.redhat.io/ubi9/ruby-33:latest",
+				Destination: "docker://mirror.example.com/ocp/redhat/ubi9-ruby-33:latest",
+			},
+			ref:      "docker://mirror.example.com/ocp/redhat/ubi9-ruby-33:latest",
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, d.cacheReference(tt.img, tt.ref))
+		})
+	}
+}
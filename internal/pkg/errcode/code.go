@@ -6,4 +6,5 @@ const (
 	OperatorErr      = 1 << 2
 	AdditionalImgErr = 1 << 3
 	HelmErr          = 1 << 4
+	TimeBoxErr       = 1 << 5
 )
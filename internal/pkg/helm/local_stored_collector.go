@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
-	"maps"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -81,35 +80,34 @@ func WithV1Tags(o CollectorInterface) CollectorInterface {
 
 func (o *LocalStorageCollector) HelmImageCollector(ctx context.Context) (v2alpha1.CollectorSchema, error) {
 	var allImages []v2alpha1.CopyImageSchema
-	var platformFilters map[string][]v2alpha1.InstancePlatformFilter
+	collected := newChartImages()
 	var errs []error
 
 	switch {
 	case lsc.Opts.IsMirrorToDisk() || lsc.Opts.IsMirrorToMirror():
 		defer lsc.cleanup()
-		allImages, platformFilters, errs = lsc.collectHelmImagesM2D()
+		allImages, errs = lsc.collectHelmImagesM2D(collected)
 	case lsc.Opts.IsDiskToMirror():
-		allImages, platformFilters, errs = lsc.collectHelmImagesD2M(o.generateV1DestTags)
+		allImages, errs = lsc.collectHelmImagesD2M(collected, o.generateV1DestTags)
 	}
 
-	cs := v2alpha1.CollectorSchema{AllImages: allImages}
-	if len(platformFilters) > 0 {
-		cs.PlatformFilters = platformFilters
+	cs := v2alpha1.CollectorSchema{
+		AllImages:          allImages,
+		CopyImageSchemaMap: v2alpha1.CopyImageSchemaMap{ChartsByImage: collected.chartsByImage},
+	}
+	if len(collected.platformFilters) > 0 {
+		cs.PlatformFilters = collected.platformFilters
 	}
 	return cs, errors.Join(errs...)
 }
 
-func (lsc *LocalStorageCollector) collectHelmImagesM2D() ([]v2alpha1.CopyImageSchema, map[string][]v2alpha1.InstancePlatformFilter, []error) { //nolint:cyclop
+func (lsc *LocalStorageCollector) collectHelmImagesM2D(collected chartImages) ([]v2alpha1.CopyImageSchema, []error) { //nolint:cyclop
 	var allHelmImages []v2alpha1.RelatedImage
 	var errs []error
-	platformFilters := make(map[string][]v2alpha1.InstancePlatformFilter)
 
-	imgs, localPlatforms, errors := getHelmImagesFromLocalChart()
+	imgs, errors := getHelmImagesFromLocalChart(collected)
 	errs = append(errs, errors...)
-	if len(imgs) > 0 {
-		allHelmImages = append(allHelmImages, imgs...)
-		maps.Copy(platformFilters, localPlatforms)
-	}
+	allHelmImages = append(allHelmImages, imgs...)
 
 	for _, repo := range lsc.Config.Mirror.Helm.Repositories {
 		charts := repo.Charts
@@ -148,7 +146,7 @@ func (lsc *LocalStorageCollector) collectHelmImagesM2D() ([]v2alpha1.CopyImageSc
 				errs = append(errs, err)
 			}
 			allHelmImages = append(allHelmImages, chartImgs...)
-			addChartPlatformFilters(platformFilters, chartImgs, chart.Platforms)
+			collected.add(ref, chartImgs, chart.Platforms)
 		}
 	}
 
@@ -157,18 +155,16 @@ func (lsc *LocalStorageCollector) collectHelmImagesM2D() ([]v2alpha1.CopyImageSc
 		lsc.Log.Error(errMsg, err.Error())
 		errs = append(errs, err)
 	}
-	return allImages, platformFilters, errs
+	return allImages, errs
 }
 
-func (lsc *LocalStorageCollector) collectHelmImagesD2M(generateV1Tags bool) ([]v2alpha1.CopyImageSchema, map[string][]v2alpha1.InstancePlatformFilter, []error) {
+func (lsc *LocalStorageCollector) collectHelmImagesD2M(collected chartImages, generateV1Tags bool) ([]v2alpha1.CopyImageSchema, []error) {
 	var allHelmImages []v2alpha1.RelatedImage
 	var errs []error
-	platformFilters := make(map[string][]v2alpha1.InstancePlatformFilter)
 
-	imgs, localPlatforms, errors := getHelmImagesFromLocalChart()
+	imgs, errors := getHelmImagesFromLocalChart(collected)
 	errs = append(errs, errors...)
 	allHelmImages = append(allHelmImages, imgs...)
-	maps.Copy(platformFilters, localPlatforms)
 
 	for _, repo := range lsc.Config.Mirror.Helm.Repositories {
 		charts, err := resolveChartsForRepo(repo)
@@ -194,7 +190,7 @@ func (lsc *LocalStorageCollector) collectHelmImagesD2M(generateV1Tags bool) ([]v
 				errs = append(errs, err)
 			}
 			allHelmImages = append(allHelmImages, chartImgs...)
-			addChartPlatformFilters(platformFilters, chartImgs, chart.Platforms)
+			collected.add(repo.Name+"/"+chart.Name, chartImgs, chart.Platforms)
 		}
 	}
 
@@ -203,20 +199,36 @@ func (lsc *LocalStorageCollector) collectHelmImagesD2M(generateV1Tags bool) ([]v
 		lsc.Log.Error(errMsg, err.Error())
 		errs = append(errs, err)
 	}
-	return allImages, platformFilters, errs
+	return allImages, errs
+}
+
+// chartImages holds the platforms and the charts of the helm images, by image origin.
+type chartImages struct {
+	platformFilters map[string][]v2alpha1.InstancePlatformFilter
+	chartsByImage   map[string]map[string]struct{}
 }
 
-func addChartPlatformFilters(filters map[string][]v2alpha1.InstancePlatformFilter, imgs []v2alpha1.RelatedImage, platforms []v2alpha1.InstancePlatformFilter) {
-	if len(platforms) == 0 {
-		return
+func newChartImages() chartImages {
+	return chartImages{
+		platformFilters: make(map[string][]v2alpha1.InstancePlatformFilter),
+		chartsByImage:   make(map[string]map[string]struct{}),
 	}
+}
+
+func (c chartImages) add(chart string, imgs []v2alpha1.RelatedImage, platforms []v2alpha1.InstancePlatformFilter) {
 	for _, img := range imgs {
 		ref, err := image.ParseRef(img.Image)
 		if err != nil {
 			continue
 		}
 		origin := ref.ReferenceWithTransport
-		filters[origin] = append(filters[origin], platforms...)
+		if len(platforms) > 0 {
+			c.platformFilters[origin] = append(c.platformFilters[origin], platforms...)
+		}
+		if c.chartsByImage[origin] == nil {
+			c.chartsByImage[origin] = make(map[string]struct{})
+		}
+		c.chartsByImage[origin][chart] = struct{}{}
 	}
 }
 
@@ -256,10 +268,9 @@ func GetDefaultChartDownloader() chartDownloader {
 	}
 }
 
-func getHelmImagesFromLocalChart() ([]v2alpha1.RelatedImage, map[string][]v2alpha1.InstancePlatformFilter, []error) {
+func getHelmImagesFromLocalChart(collected chartImages) ([]v2alpha1.RelatedImage, []error) {
 	var allHelmImages []v2alpha1.RelatedImage
 	var errs []error
-	platformFilters := make(map[string][]v2alpha1.InstancePlatformFilter)
 
 	for _, chart := range lsc.Config.Mirror.Helm.Local {
 		if err := v2alpha1.ValidatePlatforms(chart.Platforms); err != nil {
@@ -276,10 +287,10 @@ func getHelmImagesFromLocalChart() ([]v2alpha1.RelatedImage, map[string][]v2alph
 			continue
 		}
 		allHelmImages = append(allHelmImages, imgs...)
-		addChartPlatformFilters(platformFilters, imgs, chart.Platforms)
+		collected.add(chart.Name, imgs, chart.Platforms)
 	}
 
-	return allHelmImages, platformFilters, errs
+	return allHelmImages, errs
 }
 
 func repoAdd(chartRepo v2alpha1.Repository) error {
@@ -711,6 +711,9 @@ func TestHelmImageCollector(t *testing.T) {
 			if len(testCase.expectedResult) > 0 {
 				assert.NotEmpty(t, imgs.AllImages)
 				assert.ElementsMatch(t, testCase.expectedResult, imgs.AllImages)
+				for _, img := range imgs.AllImages {
+					assert.NotEmpty(t, imgs.CopyImageSchemaMap.ChartsByImage[img.Origin], "the charts of %s", img.Origin)
+				}
 			}
 		})
 	}
@@ -14,7 +14,12 @@ import (
 	"github.com/google/go-containerregistry/pkg/crane"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/match"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/operator-framework/operator-registry/pkg/cache"
+	"github.com/operator-framework/operator-registry/pkg/containertools"
 	"github.com/otiai10/copy"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
@@ -35,6 +40,10 @@ const (
 	operatorCatalogFilteredImageDir = "filtered-catalog-image"
 	operatorCatalogImageDir         = "catalog-image"
 	operatorCatalogConfigDir        = "catalog-config"
+	operatorCatalogServeCacheDir    = "serve-cache"
+
+	// serveCacheDir is where opm looks for the serve cache in catalog images
+	serveCacheDir = "/tmp/cache"
 )
 
 type GCRCatalogBuilder struct {
@@ -53,7 +62,7 @@ func NewGCRCatalogBuilder(logger log.PluggableLoggerInterface, opts mirror.CopyO
 	}
 }
 
-func (c GCRCatalogBuilder) RebuildCatalog(ctx context.Context, catalogCopyRef v2alpha1.CopyImageSchema, configPath string) error {
+func (c GCRCatalogBuilder) RebuildCatalog(ctx context.Context, catalogCopyRef v2alpha1.CopyImageSchema, configPath string, rebuildOpts CatalogRebuildOptions) error {
 	layersToAdd := []v1.Layer{}
 	layersToDelete := []v1.Layer{}
 
@@ -62,20 +71,30 @@ func (c GCRCatalogBuilder) RebuildCatalog(ctx context.Context, catalogCopyRef v2
 		return fmt.Errorf("error reading filtered config for catalog %s from %s: %v", catalogCopyRef.Origin, configPath, err)
 	}
 
-	originCatalogLayoutDir, err := catalogImageOnDisk(configPath)
-	if err != nil {
-		return fmt.Errorf("error initializing a container image for catalog %s from %s: %v", catalogCopyRef.Origin, originCatalogLayoutDir, err)
-	}
-
 	configLayerToAdd, err := LayerFromPathWithUidGid("configs", configPath, 0, 0)
 	if err != nil {
 		return fmt.Errorf("error creating add layer: %v", err)
 	}
 	layersToAdd = append(layersToAdd, configLayerToAdd)
 
+	configCMD := []string{"serve", "/configs"}
+	if rebuildOpts.ServeCache {
+		cacheDir := filepath.Join(filepath.Dir(configPath), operatorCatalogServeCacheDir)
+		if err := generateServeCache(ctx, configPath, cacheDir); err != nil {
+			return fmt.Errorf("error generating the serve cache of catalog %s: %w", catalogCopyRef.Origin, err)
+		}
+		cacheLayerToAdd, err := LayerFromPathWithUidGid(strings.TrimPrefix(serveCacheDir, "/"), cacheDir, 0, 0)
+		if err != nil {
+			return fmt.Errorf("error creating serve cache layer: %v", err)
+		}
+		layersToAdd = append(layersToAdd, cacheLayerToAdd)
+		configCMD = append(configCMD, "--cache-dir="+serveCacheDir)
+	}
+
 	// Since we are defining the FBC as index.json,
-	// remove anything that may currently exist
-	deletedConfigLayer, err := deleteLayer(".wh.configs")
+	// remove anything that may currently exist, including the serve cache
+	// of the original catalog which no longer matches the FBC
+	deletedConfigLayer, err := deleteLayer(".wh.configs", "tmp/.wh.cache")
 	if err != nil {
 		return fmt.Errorf("error preparing to delete old configs/ from catalog %s : %w", catalogCopyRef.Origin, err)
 	}
@@ -90,17 +109,11 @@ func (c GCRCatalogBuilder) RebuildCatalog(ctx context.Context, catalogCopyRef v2
 
 	layoutDir := strings.Replace(configPath, operatorCatalogConfigDir, operatorCatalogFilteredImageDir, -1)
 
-	err = copy.Copy(originCatalogLayoutDir, layoutDir)
+	layoutPath, err := c.prepareBaseLayout(ctx, configPath, layoutDir, rebuildOpts.BaseImage)
 	if err != nil {
-		return fmt.Errorf("error creating OCI layout: %v", err)
-	}
-	layoutPath, err := layout.FromPath(layoutDir)
-	if err != nil {
-		return fmt.Errorf("error creating OCI layout: %v", err)
+		return fmt.Errorf("error initializing a container image for catalog %s: %w", catalogCopyRef.Origin, err)
 	}
 
-	configCMD := []string{"serve", "/configs"}
-
 	var srcCache string
 	filteredDir := filepath.Dir(configPath)
 	destRef, err := image.ParseRef(catalogCopyRef.Destination)
@@ -134,6 +147,158 @@ func (c GCRCatalogBuilder) RebuildCatalog(ctx context.Context, catalogCopyRef v2
 	return nil
 }
 
+// BuildCatalogLayout creates in layoutDir the OCI layout of a catalog image serving
+// the declarative config found in configPath, built on top of the opm baseImage.
+// The image is only written to disk: it is mirrored like any oci:// catalog.
+func (c GCRCatalogBuilder) BuildCatalogLayout(ctx context.Context, baseImage, configPath, layoutDir string) error {
+	configLayer, err := LayerFromPathWithUidGid("configs", configPath, 0, 0)
+	if err != nil {
+		return fmt.Errorf("error creating add layer: %v", err)
+	}
+	layoutPath, err := c.prepareBaseLayout(ctx, configPath, layoutDir, baseImage)
+	if err != nil {
+		return err
+	}
+	idx, err := layoutPath.ImageIndex()
+	if err != nil {
+		return err
+	}
+	idxManifest, err := idx.IndexManifest()
+	if err != nil {
+		return err
+	}
+	originalHashes := []v1.Hash{}
+	for _, desc := range idxManifest.Manifests {
+		originalHashes = append(originalHashes, desc.Digest)
+	}
+
+	var v2format bool
+	resultIdx, err := c.imgBuilder.ProcessImageIndex(ctx, idx, &v2format, []string{"serve", "/configs"}, baseImage, configLayer)
+	if err != nil {
+		return fmt.Errorf("error building catalog from %s: %w", configPath, err)
+	}
+	if v2format {
+		resultIdx = mutate.IndexMediaType(resultIdx, types.DockerManifestList)
+	}
+	return layoutPath.ReplaceIndex(resultIdx, match.Digests(originalHashes...))
+}
+
+// prepareBaseLayout creates in layoutDir the OCI layout of the image the catalog is rebuilt on:
+// the original catalog image, or baseImage when set.
+func (c GCRCatalogBuilder) prepareBaseLayout(ctx context.Context, configPath, layoutDir, baseImage string) (layout.Path, error) {
+	if baseImage != "" {
+		if err := os.RemoveAll(layoutDir); err != nil {
+			return "", err
+		}
+		layoutPath, err := c.imgBuilder.SaveImageLayoutToDir(ctx, baseImage, layoutDir)
+		if err != nil {
+			return "", fmt.Errorf("error pulling base image %s: %w", baseImage, err)
+		}
+		// opm base images do not carry the label locating the declarative config
+		if err := labelCatalogLayout(layoutPath); err != nil {
+			return "", fmt.Errorf("error labelling base image %s: %w", baseImage, err)
+		}
+		return layoutPath, nil
+	}
+
+	originCatalogLayoutDir, err := catalogImageOnDisk(configPath)
+	if err != nil {
+		return "", err
+	}
+	if err := copy.Copy(originCatalogLayoutDir, layoutDir); err != nil {
+		return "", fmt.Errorf("error creating OCI layout: %v", err)
+	}
+	layoutPath, err := layout.FromPath(layoutDir)
+	if err != nil {
+		return "", fmt.Errorf("error creating OCI layout: %v", err)
+	}
+	return layoutPath, nil
+}
+
+// labelCatalogLayout sets the configs location label on all the images of the OCI layout
+func labelCatalogLayout(layoutPath layout.Path) error {
+	idx, err := layoutPath.ImageIndex()
+	if err != nil {
+		return err
+	}
+	idxManifest, err := idx.IndexManifest()
+	if err != nil {
+		return err
+	}
+	originalHashes := []v1.Hash{}
+	for _, desc := range idxManifest.Manifests {
+		originalHashes = append(originalHashes, desc.Digest)
+	}
+	labeled, err := labelCatalogIndex(idx)
+	if err != nil {
+		return err
+	}
+	return layoutPath.ReplaceIndex(labeled, match.Digests(originalHashes...))
+}
+
+func labelCatalogIndex(idx v1.ImageIndex) (v1.ImageIndex, error) { //nolint:ireturn // as expected by go-containerregistry
+	idxManifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	result := idx
+	for _, desc := range idxManifest.Manifests {
+		var add mutate.Appendable
+		switch {
+		case desc.MediaType.IsIndex():
+			innerIdx, err := idx.ImageIndex(desc.Digest)
+			if err != nil {
+				return nil, err
+			}
+			if add, err = labelCatalogIndex(innerIdx); err != nil {
+				return nil, err
+			}
+		case desc.MediaType.IsImage():
+			img, err := idx.Image(desc.Digest)
+			if err != nil {
+				return nil, err
+			}
+			cfg, err := img.ConfigFile()
+			if err != nil {
+				return nil, err
+			}
+			config := cfg.Config
+			if config.Labels == nil {
+				config.Labels = map[string]string{}
+			}
+			config.Labels[containertools.ConfigsLocationLabel] = "/configs"
+			if add, err = mutate.Config(img, config); err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+		result = mutate.AppendManifests(
+			mutate.RemoveManifests(result, match.Digests(desc.Digest)),
+			mutate.IndexAddendum{Add: add, Descriptor: v1.Descriptor{MediaType: desc.MediaType, Platform: desc.Platform}},
+		)
+	}
+	return result, nil
+}
+
+// generateServeCache builds in cacheDir the opm serve cache of the declarative config in configPath
+func generateServeCache(ctx context.Context, configPath, cacheDir string) error {
+	// a stale cache from a previous rebuild is not reused
+	if err := os.RemoveAll(cacheDir); err != nil {
+		return err
+	}
+	// the JSON format is understood by more opm versions than the default one
+	store, err := cache.New(cacheDir, cache.WithFormat(cache.FormatJSON))
+	if err != nil {
+		return err
+	}
+	if err := store.Build(ctx, os.DirFS(configPath)); err != nil {
+		store.Close()
+		return err
+	}
+	return store.Close()
+}
+
 // LayerFromPath will write the contents of the path(s) the target
 // directory specifying the target UID/GID and build a v1.Layer.
 // Use gid = -1 , uid = -1 if you don't want to override.
@@ -259,9 +424,11 @@ func LayerFromPathWithUidGid(targetPath, path string, uid int, gid int) (v1.Laye
 	return tarball.LayerFromOpener(opener)
 }
 
-func deleteLayer(old string) (v1.Layer, error) {
+func deleteLayer(old ...string) (v1.Layer, error) {
 	deleteMap := map[string][]byte{}
-	deleteMap[old] = []byte{}
+	for _, whiteout := range old {
+		deleteMap[whiteout] = []byte{}
+	}
 	return crane.Layer(deleteMap)
 }
 
@@ -0,0 +1,78 @@
+package imagebuilder
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/operator-framework/operator-registry/pkg/cache"
+	"github.com/operator-framework/operator-registry/pkg/containertools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateServeCache(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), operatorCatalogConfigDir)
+	require.NoError(t, os.MkdirAll(configPath, 0o755))
+	dc := declcfg.DeclarativeConfig{
+		Packages: []declcfg.Package{{Schema: declcfg.SchemaPackage, Name: "foo", DefaultChannel: "stable"}},
+		Channels: []declcfg.Channel{{Schema: declcfg.SchemaChannel, Package: "foo", Name: "stable", Entries: []declcfg.ChannelEntry{{Name: "foo.v1.0.0"}}}},
+		Bundles: []declcfg.Bundle{{
+			Schema:     declcfg.SchemaBundle,
+			Package:    "foo",
+			Name:       "foo.v1.0.0",
+			Image:      "quay.io/example/foo-bundle:v1.0.0",
+			Properties: []property.Property{property.MustBuildPackage("foo", "1.0.0")},
+		}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, declcfg.WriteJSON(dc, &buf))
+	require.NoError(t, os.WriteFile(filepath.Join(configPath, "index.json"), buf.Bytes(), 0o600))
+
+	cacheDir := filepath.Join(filepath.Dir(configPath), operatorCatalogServeCacheDir)
+	// a stale cache is replaced
+	require.NoError(t, os.MkdirAll(filepath.Join(cacheDir, "stale"), 0o755))
+	require.NoError(t, generateServeCache(t.Context(), configPath, cacheDir))
+
+	store, err := cache.New(cacheDir)
+	require.NoError(t, err)
+	defer store.Close()
+	require.NoError(t, store.CheckIntegrity(t.Context(), os.DirFS(configPath)))
+	require.NoError(t, store.Load(t.Context()))
+	packages, err := store.ListPackages(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo"}, packages)
+}
+
+func TestLabelCatalogLayout(t *testing.T) {
+	idx, err := random.Index(64, 1, 2)
+	require.NoError(t, err)
+	layoutPath, err := layout.Write(t.TempDir(), idx)
+	require.NoError(t, err)
+
+	require.NoError(t, labelCatalogLayout(layoutPath))
+
+	top, err := layoutPath.ImageIndex()
+	require.NoError(t, err)
+	topManifest, err := top.IndexManifest()
+	require.NoError(t, err)
+	// layout.Write nests the multi-arch index in the index of the layout
+	require.Len(t, topManifest.Manifests, 1)
+	labeled, err := top.ImageIndex(topManifest.Manifests[0].Digest)
+	require.NoError(t, err)
+	manifest, err := labeled.IndexManifest()
+	require.NoError(t, err)
+	require.Len(t, manifest.Manifests, 2)
+	for _, desc := range manifest.Manifests {
+		img, err := labeled.Image(desc.Digest)
+		require.NoError(t, err)
+		cfg, err := img.ConfigFile()
+		require.NoError(t, err)
+		assert.Equal(t, "/configs", cfg.Config.Labels[containertools.ConfigsLocationLabel])
+	}
+}
@@ -16,5 +16,14 @@ type ImageBuilderInterface interface {
 }
 
 type CatalogBuilderInterface interface {
-	RebuildCatalog(ctx context.Context, catalogCopyRefs v2alpha1.CopyImageSchema, configPath string) error
+	RebuildCatalog(ctx context.Context, catalogCopyRefs v2alpha1.CopyImageSchema, configPath string, rebuildOpts CatalogRebuildOptions) error
+	BuildCatalogLayout(ctx context.Context, baseImage, configPath, layoutDir string) error
+}
+
+// CatalogRebuildOptions customizes the image of a rebuilt catalog
+type CatalogRebuildOptions struct {
+	// BaseImage is the opm image the catalog is rebuilt on, instead of the original catalog image
+	BaseImage string
+	// ServeCache pre-generates the opm serve cache in the rebuilt image
+	ServeCache bool
 }
@@ -0,0 +1,292 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"go.podman.io/image/v5/types"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/image"
+)
+
+const (
+	namespace = "oc_mirror"
+	// registryNamespace prefixes the statistics of the local cache registry
+	registryNamespace = "registry"
+	// localCacheLabel is the registry label of the local cache, whose port changes between runs
+	localCacheLabel = "cache"
+)
+
+// The results of the images processed by the batch worker.
+const (
+	ResultSucceeded = "succeeded"
+	ResultFailed    = "failed"
+	ResultSkipped   = "skipped"
+)
+
+// Options are the outputs of the metrics of a run, set on the command line.
+type Options struct {
+	// Address is the address the metrics are served on at /metrics during the run (e.g. :9100)
+	Address string
+	// Textfile is the node-exporter textfile the metrics are written to at the end of the run
+	Textfile string
+}
+
+// Enabled tells whether the metrics of the run are exposed.
+func (o Options) Enabled() bool {
+	return o.Address != "" || o.Textfile != ""
+}
+
+// Validate checks the outputs set on the command line.
+func (o Options) Validate() error {
+	if o.Address != "" {
+		if _, _, err := net.SplitHostPort(o.Address); err != nil {
+			return fmt.Errorf("invalid --metrics-address %q: %w", o.Address, err)
+		}
+	}
+	if o.Textfile != "" && filepath.Ext(o.Textfile) != ".prom" {
+		return fmt.Errorf("invalid --metrics-textfile %q: the node-exporter textfile collector only reads .prom files", o.Textfile)
+	}
+	return nil
+}
+
+// Recorder records the metrics of a run: the images processed by the batch worker, the bytes
+// transferred, the retries, the archive chunks and the images collected. The statistics of the
+// local cache registry are exposed alongside. A nil Recorder records nothing.
+type Recorder struct {
+	opts             Options
+	localStorageFQDN string
+	registry         *prometheus.Registry
+	gatherer         prometheus.Gatherer
+
+	images             *prometheus.GaugeVec
+	processed          *prometheus.CounterVec
+	copyDuration       *prometheus.HistogramVec
+	transferredBytes   *prometheus.CounterVec
+	retries            *prometheus.CounterVec
+	archiveChunkSize   prometheus.Histogram
+	collectedImages    *prometheus.GaugeVec
+	collectionDuration *prometheus.GaugeVec
+
+	listener net.Listener
+	server   *http.Server
+}
+
+// New returns the recorder of the metrics of a run, nil when the metrics are not exposed.
+func New(opts Options, localStorageFQDN string) *Recorder {
+	if !opts.Enabled() {
+		return nil
+	}
+	r := &Recorder{
+		opts:             opts,
+		localStorageFQDN: localStorageFQDN,
+		registry:         prometheus.NewRegistry(),
+		images: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "images",
+			Help:      "Images to mirror or delete in the run, by type.",
+		}, []string{"type"}),
+		processed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "images_processed_total",
+			Help:      "Images processed by the batch workers, by type, destination registry and result (succeeded, failed or skipped).",
+		}, []string{"type", "destination", "result"}),
+		copyDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "copy_duration_seconds",
+			Help:      "Time to copy or delete an image, retries included, by type and destination registry.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		}, []string{"type", "destination"}),
+		transferredBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transferred_bytes_total",
+			Help:      "Bytes of the blobs copied, by source and destination registry.",
+		}, []string{"source", "destination"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "copy_retries_total",
+			Help:      "Retries of the image copies, by source and destination registry.",
+		}, []string{"source", "destination"}),
+		archiveChunkSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "archive_chunk_size_bytes",
+			Help:      "Size of the archive chunks written by mirrorToDisk.",
+			Buckets:   prometheus.ExponentialBuckets(1<<30, 2, 10),
+		}),
+		collectedImages: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "collected_images",
+			Help:      "Images found by the collectors, by collector.",
+		}, []string{"collector"}),
+		collectionDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "collection_duration_seconds",
+			Help:      "Time taken by the collectors to find the images, by collector.",
+		}, []string{"collector"}),
+	}
+	r.registry.MustRegister(r.images, r.processed, r.copyDuration, r.transferredBytes, r.retries, r.archiveChunkSize, r.collectedImages, r.collectionDuration)
+	// the local cache registry registers its statistics with the default registerer
+	r.gatherer = prometheus.Gatherers{r.registry, prometheus.DefaultGatherer}
+	return r
+}
+
+// SetImages sets the images of the run, once they are collected. The batch workers may
+// process them several times, e.g. once to pull them to the cache, then once per destination.
+func (r *Recorder) SetImages(images []v2alpha1.CopyImageSchema) {
+	if r == nil {
+		return
+	}
+	r.images.Reset()
+	for _, img := range images {
+		r.images.WithLabelValues(img.Type.String()).Inc()
+	}
+}
+
+// ObserveImage records the result of an image processed by a batch worker, labelled with the
+// registry of its destination, and the duration of its copy when it wasn't skipped.
+func (r *Recorder) ObserveImage(img v2alpha1.CopyImageSchema, result string, duration time.Duration) {
+	if r == nil {
+		return
+	}
+	dest := r.registryLabel(img.Destination)
+	r.processed.WithLabelValues(img.Type.String(), dest, result).Inc()
+	if result != ResultSkipped {
+		r.copyDuration.WithLabelValues(img.Type.String(), dest).Observe(duration.Seconds())
+	}
+}
+
+// CountBytes returns the progress channel counting the bytes transferred by a copy from src to
+// dest, to set with a ProgressInterval in its copy options. It returns nil when the metrics are
+// not recorded. The progress events are then sent to next, when set, so that the count can be
+// chained with the throttling of the copy.
+// stop must be called once the copy is done, and before next is closed.
+func (r *Recorder) CountBytes(src, dest string, next chan<- types.ProgressProperties) (progress chan types.ProgressProperties, stop func()) {
+	if r == nil {
+		return nil, func() {}
+	}
+	transferred := r.transferredBytes.WithLabelValues(r.registryLabel(src), r.registryLabel(dest))
+	progress = make(chan types.ProgressProperties)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for p := range progress {
+			// OffsetUpdate is the number of bytes read since the previous event of the blob
+			if p.Event == types.ProgressEventRead || p.Event == types.ProgressEventDone {
+				transferred.Add(float64(p.OffsetUpdate))
+			}
+			if next != nil {
+				next <- p
+			}
+		}
+	}()
+	return progress, func() {
+		close(progress)
+		<-done
+	}
+}
+
+// ObserveRetry records a retry of a copy from src to dest.
+func (r *Recorder) ObserveRetry(src, dest string) {
+	if r == nil {
+		return
+	}
+	r.retries.WithLabelValues(r.registryLabel(src), r.registryLabel(dest)).Inc()
+}
+
+// ObserveArchiveChunk records the size of an archive chunk, once it is written.
+func (r *Recorder) ObserveArchiveChunk(size int64) {
+	if r == nil {
+		return
+	}
+	r.archiveChunkSize.Observe(float64(size))
+}
+
+// ObserveCollection records the images found by a collector, and the time it took.
+func (r *Recorder) ObserveCollection(collector string, images int, duration time.Duration) {
+	if r == nil {
+		return
+	}
+	r.collectedImages.WithLabelValues(collector).Set(float64(images))
+	r.collectionDuration.WithLabelValues(collector).Set(duration.Seconds())
+}
+
+// registryLabel returns the registry of an image reference, or its transport when the image
+// isn't in a registry.
+func (r *Recorder) registryLabel(ref string) string {
+	if !strings.HasPrefix(ref, consts.DockerProtocol) {
+		transport, _, _ := strings.Cut(ref, ":")
+		return transport
+	}
+	spec, err := image.ParseRef(ref)
+	if err != nil || spec.Domain == "" {
+		return "unknown"
+	}
+	if spec.Domain == r.localStorageFQDN {
+		return localCacheLabel
+	}
+	return spec.Domain
+}
+
+// Start serves the metrics at /metrics on Options.Address, when set, until Stop is called.
+func (r *Recorder) Start() error {
+	if r == nil || r.opts.Address == "" {
+		return nil
+	}
+	listener, err := net.Listen("tcp", r.opts.Address)
+	if err != nil {
+		return fmt.Errorf("unable to serve the metrics: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(r.gatherer, promhttp.HandlerOpts{}))
+	r.listener = listener
+	r.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go r.server.Serve(listener) //nolint:errcheck // ErrServerClosed once stopped
+	return nil
+}
+
+// Stop stops serving the metrics, and writes them to Options.Textfile when set.
+func (r *Recorder) Stop(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+	var errs []error
+	if r.server != nil {
+		if err := r.server.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stopping the metrics server: %w", err))
+		}
+	}
+	if r.opts.Textfile != "" {
+		if err := prometheus.WriteToTextfile(r.opts.Textfile, textfileGatherer{r.gatherer}); err != nil {
+			errs = append(errs, fmt.Errorf("writing the metrics to %s: %w", r.opts.Textfile, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// textfileGatherer keeps the metrics of the run and of the local cache registry. The metrics
+// of the process are left out: node-exporter exposes its own, under the same names.
+type textfileGatherer struct {
+	prometheus.Gatherer
+}
+
+func (g textfileGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.Gatherer.Gather()
+	kept := families[:0]
+	for _, family := range families {
+		if strings.HasPrefix(family.GetName(), namespace+"_") || strings.HasPrefix(family.GetName(), registryNamespace+"_") {
+			kept = append(kept, family)
+		}
+	}
+	return kept, err
+}
@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/types"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+const cache = "localhost:55000"
+
+func TestOptions(t *testing.T) {
+	assert.False(t, Options{}.Enabled())
+	assert.Nil(t, New(Options{}, cache))
+	assert.True(t, Options{Address: ":9100"}.Enabled())
+	assert.True(t, Options{Textfile: "/var/lib/node_exporter/oc-mirror.prom"}.Enabled())
+
+	require.NoError(t, Options{}.Validate())
+	require.NoError(t, Options{Address: "127.0.0.1:9100", Textfile: "oc-mirror.prom"}.Validate())
+	require.ErrorContains(t, Options{Address: "9100"}.Validate(), `invalid --metrics-address "9100"`)
+	require.EqualError(t, Options{Textfile: "oc-mirror.txt"}.Validate(), `invalid --metrics-textfile "oc-mirror.txt": the node-exporter textfile collector only reads .prom files`)
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.SetImages([]v2alpha1.CopyImageSchema{{Type: v2alpha1.TypeGeneric}})
+	r.ObserveImage(v2alpha1.CopyImageSchema{Type: v2alpha1.TypeGeneric}, ResultSucceeded, time.Second)
+	r.ObserveRetry("docker://quay.io/acme/foo:v1", "docker://"+cache+"/acme/foo:v1")
+	r.ObserveArchiveChunk(1 << 30)
+	r.ObserveCollection("release", 1, time.Second)
+	progress, stop := r.CountBytes("docker://quay.io/acme/foo:v1", "docker://"+cache+"/acme/foo:v1", nil)
+	assert.Nil(t, progress)
+	stop()
+	require.NoError(t, r.Start())
+	require.NoError(t, r.Stop(t.Context()))
+}
+
+func TestRecorder(t *testing.T) {
+	r := New(Options{Textfile: "oc-mirror.prom"}, cache)
+	r.SetImages([]v2alpha1.CopyImageSchema{
+		{Type: v2alpha1.TypeOCPRelease},
+		{Type: v2alpha1.TypeOperatorBundle},
+		{Type: v2alpha1.TypeOperatorBundle},
+	})
+	assert.InDelta(t, 2, testutil.ToFloat64(r.images.WithLabelValues("operatorBundle")), 0)
+	// the images are set once collected, whatever the number of batch workers
+	r.SetImages([]v2alpha1.CopyImageSchema{{Type: v2alpha1.TypeOperatorBundle}})
+	assert.InDelta(t, 1, testutil.ToFloat64(r.images.WithLabelValues("operatorBundle")), 0)
+	assert.Equal(t, 1, testutil.CollectAndCount(r.images))
+
+	bundle := v2alpha1.CopyImageSchema{Destination: "docker://" + cache + "/acme/foo-bundle:v1", Type: v2alpha1.TypeOperatorBundle}
+	release := v2alpha1.CopyImageSchema{Destination: "docker://mirror.acme.com/openshift/release-images:4.18.1-x86_64", Type: v2alpha1.TypeOCPRelease}
+	r.ObserveImage(bundle, ResultSucceeded, 3*time.Second)
+	r.ObserveImage(bundle, ResultSkipped, 0)
+	r.ObserveImage(release, ResultFailed, time.Minute)
+	assert.InDelta(t, 1, testutil.ToFloat64(r.processed.WithLabelValues("operatorBundle", "cache", ResultSucceeded)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(r.processed.WithLabelValues("operatorBundle", "cache", ResultSkipped)), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(r.processed.WithLabelValues("ocpRelease", "mirror.acme.com", ResultFailed)), 0)
+	// the skipped images have no copy duration
+	assert.Equal(t, 2, testutil.CollectAndCount(r.copyDuration))
+
+	r.ObserveRetry("docker://quay.io/acme/foo:v1", "docker://"+cache+"/acme/foo:v1")
+	r.ObserveRetry("docker://"+cache+"/acme/foo:v1", "docker://mirror.acme.com/acme/foo:v1")
+	r.ObserveRetry("docker://"+cache+"/acme/foo:v1", "docker://mirror.acme.com/acme/foo:v1")
+	assert.InDelta(t, 1, testutil.ToFloat64(r.retries.WithLabelValues("quay.io", "cache")), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(r.retries.WithLabelValues("cache", "mirror.acme.com")), 0)
+
+	r.ObserveArchiveChunk(3 << 30)
+	r.ObserveArchiveChunk(1 << 20)
+	assert.Equal(t, 1, testutil.CollectAndCount(r.archiveChunkSize))
+
+	r.ObserveCollection("operator", 12, 2*time.Second)
+	assert.InDelta(t, 12, testutil.ToFloat64(r.collectedImages.WithLabelValues("operator")), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(r.collectionDuration.WithLabelValues("operator")), 0)
+}
+
+func TestRegistryLabel(t *testing.T) {
+	r := New(Options{Address: ":0"}, cache)
+	assert.Equal(t, "quay.io", r.registryLabel("docker://quay.io/acme/foo:v1"))
+	assert.Equal(t, "cache", r.registryLabel("docker://"+cache+"/acme/foo:v1"))
+	assert.Equal(t, "oci", r.registryLabel("oci:///tmp/acme/foo"))
+}
+
+func TestCountBytes(t *testing.T) {
+	src, dest := "docker://quay.io/acme/foo:v1", "docker://"+cache+"/acme/foo:v1"
+	events := []types.ProgressProperties{
+		{Event: types.ProgressEventNewArtifact},
+		{Event: types.ProgressEventRead, OffsetUpdate: 1024},
+		{Event: types.ProgressEventRead, OffsetUpdate: 2048},
+		{Event: types.ProgressEventDone, OffsetUpdate: 512},
+		// blobs already in the destination are not transferred
+		{Event: types.ProgressEventSkipped, OffsetUpdate: 1 << 20},
+	}
+
+	t.Run("counted", func(t *testing.T) {
+		r := New(Options{Address: ":0"}, cache)
+		progress, stop := r.CountBytes(src, dest, nil)
+		require.NotNil(t, progress)
+		for _, e := range events {
+			progress <- e
+		}
+		stop()
+		assert.InDelta(t, 3584, testutil.ToFloat64(r.transferredBytes.WithLabelValues("quay.io", "cache")), 0)
+	})
+
+	t.Run("sent on to the next channel", func(t *testing.T) {
+		r := New(Options{Address: ":0"}, cache)
+		next := make(chan types.ProgressProperties)
+		var received []types.ProgressProperties
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for p := range next {
+				received = append(received, p)
+			}
+		}()
+		progress, stop := r.CountBytes(src, dest, next)
+		for _, e := range events {
+			progress <- e
+		}
+		stop()
+		close(next)
+		<-done
+		assert.Equal(t, events, received)
+	})
+}
+
+func TestStartStop(t *testing.T) {
+	textfile := filepath.Join(t.TempDir(), "oc-mirror.prom")
+	r := New(Options{Address: "127.0.0.1:0", Textfile: textfile}, cache)
+	require.NoError(t, r.Start())
+	r.SetImages([]v2alpha1.CopyImageSchema{{Type: v2alpha1.TypeGeneric}})
+
+	resp, err := http.Get("http://" + r.listener.Addr().String() + "/metrics")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `oc_mirror_images{type="generic"} 1`)
+	assert.Contains(t, string(body), "go_goroutines")
+
+	require.NoError(t, r.Stop(t.Context()))
+	_, err = http.Get("http://" + r.listener.Addr().String() + "/metrics")
+	require.Error(t, err)
+
+	data, err := os.ReadFile(textfile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `oc_mirror_images{type="generic"} 1`)
+	// node-exporter exposes the metrics of its own process
+	assert.NotContains(t, string(data), "go_goroutines")
+
+	t.Run("address in use", func(t *testing.T) {
+		first := New(Options{Address: "127.0.0.1:0"}, cache)
+		require.NoError(t, first.Start())
+		defer first.Stop(t.Context()) //nolint:errcheck
+
+		second := New(Options{Address: first.listener.Addr().String()}, cache)
+		require.ErrorContains(t, second.Start(), "unable to serve the metrics")
+	})
+}
@@ -5,11 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"math/rand/v2"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/distribution/reference"
+	"github.com/docker/distribution/registry/api/errcode"
 	"go.podman.io/common/pkg/retry"
 	"go.podman.io/image/v5/copy"
 	"go.podman.io/image/v5/docker"
@@ -20,6 +25,7 @@ import (
 	"go.podman.io/image/v5/types"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/ratelimit"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/registriesd"
 )
 
@@ -192,8 +198,21 @@ func (o *Mirror) copy(ctx context.Context, src, dest string, opts *CopyOptions)
 		co.ReportWriter = opts.Stdout
 	}
 
+	progress, stopThrottling := opts.Limiter.Throttle(ctx, src, dest)
+	defer stopThrottling()
+	// the bytes are counted first, the progress events are then sent on to the throttling
+	counted, stopCounting := opts.Metrics.CountBytes(src, dest, progress)
+	defer stopCounting()
+	if counted != nil {
+		progress = counted
+	}
+	if progress != nil {
+		co.Progress = progress
+		co.ProgressInterval = ratelimit.ProgressInterval
+	}
+
 	//nolint:wrapcheck // context will be added by the calling function
-	return retry.IfNecessary(ctx, func() error {
+	return retryCopy(ctx, func() error {
 		manifestBytes, err := o.mc.CopyImage(ctx, policyContext, destRef, srcRef, co)
 		if err != nil {
 			return err
@@ -208,7 +227,85 @@ func (o *Mirror) copy(ctx context.Context, src, dest string, opts *CopyOptions)
 			}
 		}
 		return nil
-	}, retryOptionsFrom(opts))
+	}, src, dest, opts)
+}
+
+// retryCopy retries a copy from src to dest like retry.IfNecessary. When the registries answer
+// that they are overloaded, they are also backed off by opts.Limiter, so that the other copies
+// wait for the delay instead of adding to the load. The delay is the one requested by the
+// Retry-After header of the registry when the error carries it.
+func retryCopy(ctx context.Context, operation func() error, src, dest string, opts *CopyOptions) error {
+	ro := retryOptionsFrom(opts)
+	err := operation()
+	for attempt := 0; err != nil && ro.IsErrorRetryable(err) && attempt < ro.MaxRetry; attempt++ {
+		delay := time.Duration(math.Pow(2, float64(attempt))) * time.Second
+		if ro.Delay != 0 {
+			delay = ro.Delay
+		}
+		delay += rand.N(delay / 10)
+		if isThrottlingError(err) {
+			if after, ok := retryAfter(err); ok {
+				delay = after
+			}
+			opts.Limiter.Backoff(src, dest, delay)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+		opts.Metrics.ObserveRetry(src, dest)
+		err = operation()
+	}
+	return err
+}
+
+// headerError is implemented by the registry errors which keep the headers of the answer.
+type headerError interface {
+	error
+	Header() http.Header
+}
+
+// retryAfter returns the delay requested by the Retry-After header of the registry answer in
+// err, either a number of seconds or an HTTP date. The delay is not known when the error
+// does not carry the headers of the answer, as the UnexpectedHTTPStatusError of the client.
+func retryAfter(err error) (time.Duration, bool) {
+	var hErr headerError
+	if !errors.As(err, &hErr) {
+		return 0, false
+	}
+	after := hErr.Header().Get("Retry-After")
+	if after == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(after, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(after); err == nil {
+		if delay := time.Until(t); delay > 0 {
+			return delay, true
+		}
+	}
+	return 0, false
+}
+
+// isThrottlingError tells whether a registry answered that it is overloaded:
+// 429 Too Many Requests or 503 Service Unavailable.
+func isThrottlingError(err error) bool {
+	var httpError docker.UnexpectedHTTPStatusError
+	var ecError errcode.Error
+	switch {
+	case errors.Is(err, docker.ErrTooManyRequests):
+		return true
+	case errors.As(err, &httpError):
+		return httpError.StatusCode == http.StatusTooManyRequests || httpError.StatusCode == http.StatusServiceUnavailable
+	case errors.As(err, &ecError):
+		return ecError.Code == errcode.ErrorCodeTooManyRequests || ecError.Code == errcode.ErrorCodeUnavailable
+	}
+	return false
 }
 
 // retryOptionsFrom returns a copy of the caller's retry options with oc-mirror's
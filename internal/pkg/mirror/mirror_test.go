@@ -7,6 +7,8 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -22,6 +24,7 @@ import (
 	"go.podman.io/image/v5/types"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/ratelimit"
 )
 
 func TestMirrorCopy(t *testing.T) {
@@ -442,6 +445,193 @@ func TestRetryOptionsFrom(t *testing.T) {
 	})
 }
 
+func TestIsThrottlingError(t *testing.T) {
+	assert.True(t, isThrottlingError(fmt.Errorf("reading manifest: %w", docker.ErrTooManyRequests)))
+	assert.True(t, isThrottlingError(docker.UnexpectedHTTPStatusError{StatusCode: http.StatusTooManyRequests}))
+	assert.True(t, isThrottlingError(docker.UnexpectedHTTPStatusError{StatusCode: http.StatusServiceUnavailable}))
+	assert.True(t, isThrottlingError(errcode.Error{Code: errcode.ErrorCodeTooManyRequests}))
+	assert.True(t, isThrottlingError(errcode.Error{Code: errcode.ErrorCodeUnavailable}))
+	assert.False(t, isThrottlingError(docker.UnexpectedHTTPStatusError{StatusCode: http.StatusBadGateway}))
+	assert.False(t, isThrottlingError(errcode.Error{Code: errcode.ErrorCodeUnauthorized}))
+	assert.False(t, isThrottlingError(context.DeadlineExceeded))
+}
+
+type retryAfterError struct {
+	error
+	header http.Header
+}
+
+func (e retryAfterError) Header() http.Header {
+	return e.header
+}
+
+func (e retryAfterError) Unwrap() error {
+	return e.error
+}
+
+func TestRetryAfter(t *testing.T) {
+	withHeader := func(after string) error {
+		return retryAfterError{error: docker.ErrTooManyRequests, header: http.Header{"Retry-After": []string{after}}}
+	}
+
+	delay, ok := retryAfter(fmt.Errorf("reading blob: %w", withHeader("120")))
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, delay)
+
+	delay, ok = retryAfter(withHeader(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Hour, delay, float64(time.Minute))
+
+	for _, err := range []error{
+		withHeader(""),
+		withHeader("-1"),
+		withHeader("soon"),
+		withHeader(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)),
+		docker.ErrTooManyRequests,
+		docker.UnexpectedHTTPStatusError{StatusCode: http.StatusServiceUnavailable},
+	} {
+		_, ok = retryAfter(err)
+		assert.False(t, ok, err)
+	}
+}
+
+// TestMirrorThrottling copies an image to a registry stand-in which limits the rate of the manifest uploads.
+func TestMirrorThrottling(t *testing.T) {
+	var throttle atomic.Bool
+	var manifestPuts atomic.Int32
+	throttled := make(chan struct{}, 1)
+	reg := registry.New()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/manifests/") {
+			manifestPuts.Add(1)
+			if throttle.CompareAndSwap(true, false) {
+				w.Header().Set("Retry-After", "1")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"errors":[{"code":"TOOMANYREQUESTS","message":"slow down"}]}`))
+				throttled <- struct{}{}
+				return
+			}
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+	u, err := url.Parse(s.URL)
+	require.NoError(t, err)
+
+	global := &GlobalOptions{SecurePolicy: false}
+	_, sharedOpts := SharedImageFlags()
+	_, deprecatedTLSVerifyOpt := DeprecatedTLSVerifyFlags()
+	_, srcOpts := ImageSrcFlags(global, sharedOpts, deprecatedTLSVerifyOpt, "src-", "screds")
+	dstFlags, destOpts := ImageDestFlags(global, sharedOpts, deprecatedTLSVerifyOpt, "dest-", "dcreds")
+	require.NoError(t, dstFlags.Set("dest-tls-verify", "false"))
+	opts := CopyOptions{
+		Global:              global,
+		DeprecatedTLSVerify: deprecatedTLSVerifyOpt,
+		SrcImage:            srcOpts,
+		DestImage:           destOpts,
+		RetryOpts:           &retry.Options{MaxRetry: 2, Delay: 500 * time.Millisecond},
+		Mode:                MirrorToMirror,
+		MultiArch:           "all",
+	}
+
+	imageAbsolutePath, err := filepath.Abs(consts.TestFolder + "albo-bundle-image")
+	require.NoError(t, err)
+	src := consts.DirProtocol + imageAbsolutePath
+	m := New(NewMirrorCopy(), NewMirrorDelete())
+
+	t.Run("bandwidth is capped", func(t *testing.T) {
+		opts.Limiter, err = ratelimit.New(ratelimit.Options{RegistryMaxBandwidth: map[string]string{u.Host: "4Ki"}}, "localhost:55000")
+		require.NoError(t, err)
+		start := time.Now()
+		require.NoError(t, m.Run(t.Context(), src, consts.DockerProtocol+u.Host+"/albo-capped:latest", "copy", &opts))
+		// the 6.2kB of blobs exceed the 4KiB burst of the registry by half a second
+		assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	})
+
+	t.Run("registry is backed off when throttling", func(t *testing.T) {
+		opts.Limiter, err = ratelimit.New(ratelimit.Options{RegistryMaxParallelImages: map[string]int{u.Host: 2}}, "localhost:55000")
+		require.NoError(t, err)
+		manifestPuts.Store(0)
+		throttle.Store(true)
+
+		errs := make(chan error, 1)
+		go func() {
+			errs <- m.Run(t.Context(), src, consts.DockerProtocol+u.Host+"/albo-throttled:latest", "copy", &opts)
+		}()
+
+		<-throttled
+		time.Sleep(50 * time.Millisecond)
+		// another image for the same registry waits for the retry of the throttled one
+		start := time.Now()
+		release, err := opts.Limiter.Acquire(t.Context(), src, consts.DockerProtocol+u.Host+"/other:latest")
+		require.NoError(t, err)
+		release()
+		assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+		require.NoError(t, <-errs)
+		// the manifest of the image, then the throttled and the retried uploads of the index
+		assert.Equal(t, int32(3), manifestPuts.Load())
+	})
+}
+
+func TestRetryCopy(t *testing.T) {
+	t.Run("not retryable", func(t *testing.T) {
+		var attempts int
+		err := retryCopy(t.Context(), func() error {
+			attempts++
+			return docker.UnexpectedHTTPStatusError{StatusCode: http.StatusUnauthorized}
+		}, "", "", &CopyOptions{RetryOpts: &retry.Options{MaxRetry: 3, Delay: time.Millisecond}})
+		require.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		var attempts int
+		err := retryCopy(t.Context(), func() error {
+			attempts++
+			return docker.ErrTooManyRequests
+		}, "", "", &CopyOptions{RetryOpts: &retry.Options{MaxRetry: 3, Delay: time.Millisecond}})
+		require.ErrorIs(t, err, docker.ErrTooManyRequests)
+		assert.Equal(t, 4, attempts)
+	})
+
+	t.Run("waits for the Retry-After delay", func(t *testing.T) {
+		limiter, err := ratelimit.New(ratelimit.Options{}, "localhost:55000")
+		require.NoError(t, err)
+		var attempts int
+		start := time.Now()
+		err = retryCopy(t.Context(), func() error {
+			attempts++
+			if attempts == 1 {
+				return fmt.Errorf("writing manifest: %w", retryAfterError{
+					error:  docker.ErrTooManyRequests,
+					header: http.Header{"Retry-After": []string{"1"}},
+				})
+			}
+			return nil
+		}, "docker://quay.io/foo:latest", "docker://localhost:5000/foo:latest", &CopyOptions{
+			Limiter:   limiter,
+			RetryOpts: &retry.Options{MaxRetry: 3, Delay: time.Millisecond},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		var attempts int
+		err := retryCopy(ctx, func() error {
+			attempts++
+			return docker.ErrTooManyRequests
+		}, "", "", &CopyOptions{RetryOpts: &retry.Options{MaxRetry: 3, Delay: time.Hour}})
+		require.ErrorIs(t, err, docker.ErrTooManyRequests)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
 type (
 	mockMirrorCopy   struct{}
 	mockMirrorDelete struct{}
@@ -19,6 +19,9 @@ import (
 	"go.podman.io/image/v5/manifest"
 	"go.podman.io/image/v5/signature"
 	"go.podman.io/image/v5/types"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/metrics"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/ratelimit"
 )
 
 const defaultUserAgent string = "oc-mirror"
@@ -29,38 +32,40 @@ type ErrorShouldDisplayUsage struct {
 }
 
 type GlobalOptions struct {
-	LogLevel               string        // one of info, debug, trace
-	PolicyPath             string        // Path to a signature verification policy file
-	SecurePolicy           bool          // Use an "allow everything" signature verification policy
-	RegistriesDirPath      string        // Path to a "registries.d" registry configuration directory
-	OverrideArch           string        // Architecture to use for choosing images, instead of the runtime one
-	OverrideOS             string        // OS to use for choosing images, instead of the runtime one
-	OverrideVariant        string        // Architecture variant to use for choosing images, instead of the runtime one
-	CommandTimeout         time.Duration // Timeout for the command execution
-	RegistriesConfPath     string        // Path to the "registries.conf" file
-	TmpDir                 string        // Path to use for big temporary files
-	WorkingDir             string        // working directory
-	From                   string        // local storage for diskToMirror workflow
-	Port                   uint16        // HTTP port used by oc-mirror's local storage instance
-	ConfigPath             string        // Path to use for imagesetconfig
-	Quiet                  bool          // Suppress output information when copying images
-	Force                  bool          // Force the copy/mirror even if there is nothing to update
-	V2                     bool          // Redirect the flow to oc-mirror v2 - PLEASE DO NOT USE that. V2 is still under development and it is not ready to be used.
-	CpuProf                bool          // Enable CPU profiling
-	MemProf                bool          // Enable Memory profiling
-	MaxNestedPaths         int           // Sets the maximum allowed path-components on the destination registry
-	StrictArchiving        bool          // If set, generates archives that are strictly less than `archiveSize`, failing for files that exceed that limit.
-	SinceString            string        // Sets the date since which all content mirrored after is included in the archive
-	Since                  time.Time     // Sets the date since which all content mirrored after is included in the archive
-	DeleteGenerate         bool          // Used to generate the delete-images.yaml file , mandatory fist step in the delete workflow
-	DeleteSignatures       bool          // Used to delete the container image signatures, for multi arch images, it deletes only the manifest list signature
-	DeleteDestination      string        // Used primarily for delete - denotes the remote registry to delete from
-	ForceCacheDelete       bool          // Used to force delete the local cache
-	DeleteID               string        // This flag is used to append to the artifacts created by the delete functionality
-	DeleteYaml             string        // This flag will use the contents of the indicated yaml as basis to delete the local cache and remote registry
-	CacheDir               string        // Path to the cache directory
-	IsTerminal             bool          // Whether we're running in a terminal console or not
-	IgnoreReleaseSignature bool          // Ignore release signatures, used primarily for qe testing unpublished signatures
+	LogLevel               string            // one of info, debug, trace
+	PolicyPath             string            // Path to a signature verification policy file
+	SecurePolicy           bool              // Use an "allow everything" signature verification policy
+	RegistriesDirPath      string            // Path to a "registries.d" registry configuration directory
+	OverrideArch           string            // Architecture to use for choosing images, instead of the runtime one
+	OverrideOS             string            // OS to use for choosing images, instead of the runtime one
+	OverrideVariant        string            // Architecture variant to use for choosing images, instead of the runtime one
+	CommandTimeout         time.Duration     // Timeout for the command execution
+	RegistriesConfPath     string            // Path to the "registries.conf" file
+	TmpDir                 string            // Path to use for big temporary files
+	WorkingDir             string            // working directory
+	From                   string            // local storage for diskToMirror workflow
+	Port                   uint16            // HTTP port used by oc-mirror's local storage instance
+	ConfigPath             string            // Path to use for imagesetconfig
+	Quiet                  bool              // Suppress output information when copying images
+	Force                  bool              // Force the copy/mirror even if there is nothing to update
+	V2                     bool              // Redirect the flow to oc-mirror v2 - PLEASE DO NOT USE that. V2 is still under development and it is not ready to be used.
+	CpuProf                bool              // Enable CPU profiling
+	MemProf                bool              // Enable Memory profiling
+	MaxNestedPaths         int               // Sets the maximum allowed path-components on the destination registry
+	StrictArchiving        bool              // If set, generates archives that are strictly less than `archiveSize`, failing for files that exceed that limit.
+	SinceString            string            // Sets the date since which all content mirrored after is included in the archive
+	Since                  time.Time         // Sets the date since which all content mirrored after is included in the archive
+	DeleteGenerate         bool              // Used to generate the delete-images.yaml file , mandatory fist step in the delete workflow
+	DeleteSignatures       bool              // Used to delete the container image signatures, for multi arch images, it deletes only the manifest list signature
+	DeleteDestination      string            // Used primarily for delete - denotes the remote registry to delete from
+	ForceCacheDelete       bool              // Used to force delete the local cache
+	DeleteID               string            // This flag is used to append to the artifacts created by the delete functionality
+	DeleteYaml             string            // This flag will use the contents of the indicated yaml as basis to delete the local cache and remote registry
+	CacheDir               string            // Path to the cache directory
+	IsTerminal             bool              // Whether we're running in a terminal console or not
+	IgnoreReleaseSignature bool              // Ignore release signatures, used primarily for qe testing unpublished signatures
+	Limits                 ratelimit.Options // Bandwidth caps and per-registry concurrency limits of the copies
+	Metrics                metrics.Options   // Outputs of the Prometheus metrics of the run
 }
 
 type CopyOptions struct {
@@ -92,11 +97,21 @@ type CopyOptions struct {
 	UUID                     uuid.UUID // set uuid
 	ImageType                string    // release, catalog-operator, additionalImage
 	Stdout                   io.Writer
-	ParallelLayerImages      uint   // number of image layers to copy/delete concurrently
-	ParallelImages           uint   // number of images to copy/delete concurrently
-	Function                 string // copy or delete (default is copy)
+	ParallelLayerImages      uint          // number of image layers to copy/delete concurrently
+	ParallelImages           uint          // number of images to copy/delete concurrently
+	AdaptiveParallelImages   bool          // adjust the number of images copied concurrently at runtime, starting from ParallelImages
+	MinParallelImages        uint          // lower bound of the adaptive number of images copied concurrently
+	MaxParallelImages        uint          // upper bound of the adaptive number of images copied concurrently
+	ScheduleUnits            bool          // copy the images unit by unit (release, operator package, helm chart, additional image)
+	UnitPriority             []string      // order of the units, as <kind> or <kind>:<name> entries
+	MaxDuration              time.Duration // time box of the run: no copy is started once it is over
+	MaxDurationGrace         time.Duration // time given to the copies running at the end of MaxDuration to finish
+	Deadline                 time.Time     // end of MaxDuration, set when the run starts
+	Function                 string        // copy or delete (default is copy)
 	LocalStorageFQDN         string
-	RootlessStoragePath      string // used to override the container rootlesss storage path (usually set in /etc/containers/storage.conf)
+	RootlessStoragePath      string             // used to override the container rootlesss storage path (usually set in /etc/containers/storage.conf)
+	Limiter                  *ratelimit.Limiter // enforces Global.Limits, nil when no limit is set
+	Metrics                  *metrics.Recorder  // records the metrics of the run, nil when Global.Metrics are not exposed
 }
 
 // deprecatedTLSVerifyOption represents a deprecated --tls-verify option,
@@ -0,0 +1,172 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+// bundleVersionRange returns the semver constraint expression of a package or channel selection
+func bundleVersionRange(b v2alpha1.IncludeBundle) string {
+	if b.VersionRange != "" {
+		return b.VersionRange
+	}
+	var versionRange string
+	if b.MinVersion != "" {
+		versionRange = ">=" + b.MinVersion
+	}
+	if b.MaxVersion != "" {
+		versionRange += " <=" + b.MaxVersion
+	}
+	return versionRange
+}
+
+// resolveLatestBundles returns a copy of the operator filter where every `latest: N`
+// is replaced by the versionRange selecting the N highest versions of the channel in dc.
+// A package level `latest` applies to each channel of the package, so the channels are
+// listed explicitly in the result.
+// The result only depends on the catalog content, so that it can be recorded in the
+// pinned ImageSetConfiguration and give the same bundles when filtering again.
+func resolveLatestBundles(op v2alpha1.Operator, dc declcfg.DeclarativeConfig) (v2alpha1.Operator, error) {
+	if !slices.ContainsFunc(op.Packages, hasLatest) {
+		return op, nil
+	}
+
+	versions, err := bundleVersions(dc)
+	if err != nil {
+		return op, err
+	}
+	channels := map[string]map[string]declcfg.Channel{}
+	for _, ch := range dc.Channels {
+		if _, ok := channels[ch.Package]; !ok {
+			channels[ch.Package] = map[string]declcfg.Channel{}
+		}
+		channels[ch.Package][ch.Name] = ch
+	}
+
+	resolved := op
+	resolved.Packages = make([]v2alpha1.IncludePackage, len(op.Packages))
+	for i, pkg := range op.Packages {
+		resolved.Packages[i] = pkg
+		if !hasLatest(pkg) {
+			continue
+		}
+		pkgChannels, ok := channels[pkg.Name]
+		if !ok {
+			// the filter reports packages missing from the catalog
+			continue
+		}
+
+		includeChannels := slices.Clone(pkg.Channels)
+		if len(includeChannels) == 0 {
+			for _, name := range slices.Sorted(maps.Keys(pkgChannels)) {
+				includeChannels = append(includeChannels, v2alpha1.IncludeChannel{Name: name})
+			}
+		}
+
+		for j, ch := range includeChannels {
+			selection := ch.IncludeBundle
+			if pkg.Latest > 0 {
+				selection = pkg.IncludeBundle
+			}
+			if selection.Latest == 0 {
+				continue
+			}
+			catalogChannel, ok := pkgChannels[ch.Name]
+			if !ok {
+				continue
+			}
+			versionRange, err := latestVersionRange(catalogChannel, versions[pkg.Name], selection)
+			if err != nil {
+				return op, fmt.Errorf("package %q channel %q: %w", pkg.Name, ch.Name, err)
+			}
+			includeChannels[j].IncludeBundle = v2alpha1.IncludeBundle{VersionRange: versionRange}
+		}
+
+		resolved.Packages[i].Channels = includeChannels
+		if pkg.Latest > 0 {
+			resolved.Packages[i].IncludeBundle = v2alpha1.IncludeBundle{}
+		}
+	}
+	return resolved, nil
+}
+
+func hasLatest(pkg v2alpha1.IncludePackage) bool {
+	return pkg.Latest > 0 || slices.ContainsFunc(pkg.Channels, func(ch v2alpha1.IncludeChannel) bool { return ch.Latest > 0 })
+}
+
+// latestVersionRange returns the versionRange keeping the `selection.Latest` highest versions
+// of the channel that match the versionRange (or minVersion/maxVersion) of the selection
+func latestVersionRange(ch declcfg.Channel, versions map[string]*semver.Version, selection v2alpha1.IncludeBundle) (string, error) {
+	baseRange := bundleVersionRange(selection)
+	var constraint *semver.Constraints
+	if baseRange != "" {
+		var err error
+		if constraint, err = semver.NewConstraint(baseRange); err != nil {
+			return "", fmt.Errorf("invalid version range %q: %w", baseRange, err)
+		}
+	}
+
+	candidates := []*semver.Version{}
+	for _, entry := range ch.Entries {
+		v, ok := versions[entry.Name]
+		if !ok || (constraint != nil && !constraint.Check(v)) {
+			continue
+		}
+		candidates = append(candidates, v)
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no bundle matches version range %q", baseRange)
+	}
+	slices.SortFunc(candidates, func(a, b *semver.Version) int { return b.Compare(a) })
+	candidates = slices.CompactFunc(candidates, func(a, b *semver.Version) bool { return a.Equal(b) })
+
+	highest := candidates[0]
+	lowest := candidates[min(selection.Latest, len(candidates))-1]
+	latestRange := fmt.Sprintf(">=%s <=%s", lowest, highest)
+	if selection.VersionRange == "" {
+		// minVersion/maxVersion select a contiguous range that already contains the latest range
+		return latestRange, nil
+	}
+
+	// constraints are ANDed with spaces and ORed with ||: add the latest range to each alternative
+	alternatives := strings.Split(baseRange, "||")
+	for i, alt := range alternatives {
+		alternatives[i] = strings.TrimSpace(alt) + " " + latestRange
+	}
+	return strings.Join(alternatives, " || "), nil
+}
+
+// bundleVersions indexes the olm.package version of the bundles by package and bundle name
+func bundleVersions(dc declcfg.DeclarativeConfig) (map[string]map[string]*semver.Version, error) {
+	versions := map[string]map[string]*semver.Version{}
+	for _, b := range dc.Bundles {
+		for _, p := range b.Properties {
+			if p.Type != property.TypePackage {
+				continue
+			}
+			var pkg property.Package
+			if err := json.Unmarshal(p.Value, &pkg); err != nil {
+				return nil, fmt.Errorf("bundle %q: %w", b.Name, err)
+			}
+			v, err := semver.NewVersion(pkg.Version)
+			if err != nil {
+				return nil, fmt.Errorf("bundle %q: invalid version %q: %w", b.Name, pkg.Version, err)
+			}
+			if _, ok := versions[b.Package]; !ok {
+				versions[b.Package] = map[string]*semver.Version{}
+			}
+			versions[b.Package][b.Name] = v
+			break
+		}
+	}
+	return versions, nil
+}
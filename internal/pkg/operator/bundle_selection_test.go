@@ -0,0 +1,128 @@
+package operator
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+)
+
+// newSelectionCatalog returns a catalog with one package, and a linear channel per entry of channels
+func newSelectionCatalog(t *testing.T, pkgName string, channels map[string][]string) declcfg.DeclarativeConfig {
+	t.Helper()
+	dc := declcfg.DeclarativeConfig{Packages: []declcfg.Package{{Schema: declcfg.SchemaPackage, Name: pkgName, DefaultChannel: "stable"}}}
+	bundles := map[string]bool{}
+	for chName, versions := range channels {
+		ch := declcfg.Channel{Schema: declcfg.SchemaChannel, Name: chName, Package: pkgName}
+		for i, v := range versions {
+			entry := declcfg.ChannelEntry{Name: pkgName + ".v" + v}
+			if i > 0 {
+				entry.Replaces = pkgName + ".v" + versions[i-1]
+			}
+			ch.Entries = append(ch.Entries, entry)
+			if bundles[entry.Name] {
+				continue
+			}
+			bundles[entry.Name] = true
+			value, err := json.Marshal(property.Package{PackageName: pkgName, Version: v})
+			require.NoError(t, err)
+			dc.Bundles = append(dc.Bundles, declcfg.Bundle{
+				Schema:     declcfg.SchemaBundle,
+				Name:       entry.Name,
+				Package:    pkgName,
+				Properties: []property.Property{{Type: property.TypePackage, Value: value}},
+			})
+		}
+		dc.Channels = append(dc.Channels, ch)
+	}
+	return dc
+}
+
+func TestResolveLatestBundles(t *testing.T) {
+	dc := newSelectionCatalog(t, "foo", map[string][]string{
+		"stable": {"4.14.0", "4.14.1", "4.15.0", "4.15.3", "4.16.0", "4.16.1", "4.17.0"},
+		"fast":   {"4.16.0", "4.16.1", "4.17.0", "4.17.1"},
+	})
+
+	t.Run("should keep the latest N versions of each channel of the package", func(t *testing.T) {
+		op := v2alpha1.Operator{IncludeConfig: v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+			{Name: "foo", DefaultChannel: "stable", IncludeBundle: v2alpha1.IncludeBundle{Latest: 2}},
+		}}}
+		resolved, err := resolveLatestBundles(op, dc)
+		require.NoError(t, err)
+		assert.Equal(t, []v2alpha1.IncludePackage{{
+			Name:           "foo",
+			DefaultChannel: "stable",
+			Channels: []v2alpha1.IncludeChannel{
+				{Name: "fast", IncludeBundle: v2alpha1.IncludeBundle{VersionRange: ">=4.17.0 <=4.17.1"}},
+				{Name: "stable", IncludeBundle: v2alpha1.IncludeBundle{VersionRange: ">=4.16.1 <=4.17.0"}},
+			},
+		}}, resolved.Packages)
+		// the original filter is left untouched
+		assert.Equal(t, 2, op.Packages[0].Latest)
+	})
+
+	t.Run("should apply latest within the version range of the channel", func(t *testing.T) {
+		op := v2alpha1.Operator{IncludeConfig: v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+			{Name: "foo", Channels: []v2alpha1.IncludeChannel{
+				{Name: "stable", IncludeBundle: v2alpha1.IncludeBundle{VersionRange: ">=4.14 <4.17, !=4.16.1 || >=4.17.0", Latest: 3}},
+				{Name: "fast", IncludeBundle: v2alpha1.IncludeBundle{MaxVersion: "4.16.1", Latest: 1}},
+			}},
+		}}}
+		resolved, err := resolveLatestBundles(op, dc)
+		require.NoError(t, err)
+		assert.Equal(t, []v2alpha1.IncludeChannel{
+			{Name: "stable", IncludeBundle: v2alpha1.IncludeBundle{VersionRange: ">=4.14 <4.17, !=4.16.1 >=4.15.3 <=4.17.0 || >=4.17.0 >=4.15.3 <=4.17.0"}},
+			{Name: "fast", IncludeBundle: v2alpha1.IncludeBundle{VersionRange: ">=4.16.1 <=4.16.1"}},
+		}, resolved.Packages[0].Channels)
+	})
+
+	t.Run("should fail when no bundle matches the range", func(t *testing.T) {
+		op := v2alpha1.Operator{IncludeConfig: v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+			{Name: "foo", Channels: []v2alpha1.IncludeChannel{
+				{Name: "fast", IncludeBundle: v2alpha1.IncludeBundle{VersionRange: ">=5.0.0", Latest: 1}},
+			}},
+		}}}
+		_, err := resolveLatestBundles(op, dc)
+		assert.EqualError(t, err, `package "foo" channel "fast": no bundle matches version range ">=5.0.0"`)
+	})
+
+	t.Run("should refuse to filter an unresolved latest", func(t *testing.T) {
+		op := v2alpha1.Operator{IncludeConfig: v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+			{Name: "foo", IncludeBundle: v2alpha1.IncludeBundle{Latest: 1}},
+		}}}
+		_, err := filterFromImageSetConfig(op)
+		assert.EqualError(t, err, `package "foo": latest was not resolved against the catalog`)
+	})
+}
+
+func TestFilterCatalogLatest(t *testing.T) {
+	handler := &CatalogHandler{Log: clog.New("debug")}
+	dc, err := handler.GetDeclarativeConfig(t.Context(), filepath.Join(consts.TestFolder, "configs"))
+	require.NoError(t, err)
+
+	op := v2alpha1.Operator{
+		Catalog: "registry.redhat.io/redhat/redhat-operator-index:v4.16",
+		IncludeConfig: v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+			{Name: "jaeger-product", Channels: []v2alpha1.IncludeChannel{
+				{Name: "stable", IncludeBundle: v2alpha1.IncludeBundle{Latest: 2}},
+			}},
+		}},
+	}
+	res, err := filterCatalog(t.Context(), *dc, op)
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, b := range res.Bundles {
+		names = append(names, b.Name)
+	}
+	assert.ElementsMatch(t, []string{"jaeger-operator.v1.47.1-5", "jaeger-operator.v1.51.0-1"}, names)
+}
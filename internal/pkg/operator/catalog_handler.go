@@ -48,12 +48,7 @@ func filterPackage(op v2alpha1.IncludePackage) filter.Package {
 	p := filter.Package{
 		Name:           op.Name,
 		DefaultChannel: op.DefaultChannel,
-	}
-	if op.MinVersion != "" {
-		p.VersionRange = ">=" + op.MinVersion
-	}
-	if op.MaxVersion != "" {
-		p.VersionRange += " <=" + op.MaxVersion
+		VersionRange:   bundleVersionRange(op.IncludeBundle),
 	}
 
 	if len(op.Channels) == 0 {
@@ -63,13 +58,8 @@ func filterPackage(op v2alpha1.IncludePackage) filter.Package {
 	p.Channels = make([]filter.Channel, 0, len(op.Channels))
 	for _, ch := range op.Channels {
 		filterChan := filter.Channel{
-			Name: ch.Name,
-		}
-		if ch.MinVersion != "" {
-			filterChan.VersionRange = ">=" + ch.MinVersion
-		}
-		if ch.MaxVersion != "" {
-			filterChan.VersionRange += " <=" + ch.MaxVersion
+			Name:         ch.Name,
+			VersionRange: bundleVersionRange(ch.IncludeBundle),
 		}
 		p.Channels = append(p.Channels, filterChan)
 	}
@@ -77,6 +67,8 @@ func filterPackage(op v2alpha1.IncludePackage) filter.Package {
 	return p
 }
 
+// filterFromImageSetConfig converts the ImageSetConfiguration operator filter
+// to a catalog filter. `latest` must be resolved by resolveLatestBundles first.
 func filterFromImageSetConfig(iscCatalogFilter v2alpha1.Operator) (filter.FilterConfiguration, error) {
 	catFilter := filter.FilterConfiguration{
 		TypeMeta: v1.TypeMeta{
@@ -87,6 +79,9 @@ func filterFromImageSetConfig(iscCatalogFilter v2alpha1.Operator) (filter.Filter
 
 	catFilter.Packages = make([]filter.Package, 0, len(iscCatalogFilter.Packages))
 	for _, op := range iscCatalogFilter.Packages {
+		if hasLatest(op) {
+			return catFilter, fmt.Errorf("package %q: latest was not resolved against the catalog", op.Name)
+		}
 		catFilter.Packages = append(catFilter.Packages, filterPackage(op))
 	}
 
@@ -97,6 +92,18 @@ func filterFromImageSetConfig(iscCatalogFilter v2alpha1.Operator) (filter.Filter
 }
 
 func filterCatalog(ctx context.Context, operatorCatalog declcfg.DeclarativeConfig, iscCatalogFilter v2alpha1.Operator) (*declcfg.DeclarativeConfig, error) {
+	iscCatalogFilter, err := resolveLatestBundles(iscCatalogFilter, operatorCatalog)
+	if err != nil {
+		return nil, err
+	}
+	switch iscCatalogFilter.Deprecations {
+	case v2alpha1.DeprecationsFail:
+		if err := checkRequestedDeprecations(operatorCatalog, iscCatalogFilter); err != nil {
+			return nil, fmt.Errorf("catalog %q: deprecated content requested: %w", iscCatalogFilter.Catalog, err)
+		}
+	case v2alpha1.DeprecationsExclude:
+		operatorCatalog = excludeDeprecated(operatorCatalog, iscCatalogFilter)
+	}
 	config, err := filterFromImageSetConfig(iscCatalogFilter)
 	if err != nil {
 		return nil, err
@@ -106,6 +113,8 @@ func filterCatalog(ctx context.Context, operatorCatalog declcfg.DeclarativeConfi
 	if err != nil {
 		return nil, fmt.Errorf("failed to filter catalog: %w", err)
 	}
+	// OLM shows the deprecation warnings of whatever remains in the filtered catalog
+	pruneDeprecations(dc)
 	return dc, nil
 }
 
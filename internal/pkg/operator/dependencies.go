@@ -0,0 +1,384 @@
+package operator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// dependencyScope is a configured catalog taking part in the cross-catalog dependency resolution
+type dependencyScope struct {
+	catalog string
+	// filtered is the content mirrored for the catalog, where the bundles providing dependencies are added
+	filtered *declcfg.DeclarativeConfig
+	// loadOriginal returns the unfiltered catalog. It is only called when a dependency
+	// is not satisfied by the filtered catalogs.
+	loadOriginal func() (*declcfg.DeclarativeConfig, error)
+	// resolve is true when the dependencies of the bundles of this catalog must be resolved
+	resolve bool
+	// full is true when the whole catalog is mirrored: nothing can be added to it
+	full bool
+
+	original *declcfg.DeclarativeConfig
+	modified bool
+}
+
+func (s *dependencyScope) originalDeclConfig() (*declcfg.DeclarativeConfig, error) {
+	if s.original == nil {
+		dc, err := s.loadOriginal()
+		if err != nil {
+			return nil, fmt.Errorf("catalog %q: %w", s.catalog, err)
+		}
+		s.original = dc
+	}
+	return s.original, nil
+}
+
+// bundleDependencies holds the dependency related properties of a bundle
+type bundleDependencies struct {
+	pkg      string
+	name     string
+	version  *semver.Version
+	gvks     []property.GVK
+	packages []property.PackageRequired
+	apis     []property.GVKRequired
+}
+
+func parseBundleDependencies(b declcfg.Bundle) (bundleDependencies, error) {
+	deps := bundleDependencies{pkg: b.Package, name: b.Name}
+	for _, p := range b.Properties {
+		var err error
+		switch p.Type {
+		case property.TypePackage:
+			var pkg property.Package
+			if err = json.Unmarshal(p.Value, &pkg); err == nil {
+				deps.version, err = semver.NewVersion(pkg.Version)
+			}
+		case property.TypeGVK:
+			var gvk property.GVK
+			err = json.Unmarshal(p.Value, &gvk)
+			deps.gvks = append(deps.gvks, gvk)
+		case property.TypePackageRequired:
+			var required property.PackageRequired
+			err = json.Unmarshal(p.Value, &required)
+			deps.packages = append(deps.packages, required)
+		case property.TypeGVKRequired:
+			var required property.GVKRequired
+			err = json.Unmarshal(p.Value, &required)
+			deps.apis = append(deps.apis, required)
+		}
+		if err != nil {
+			return deps, fmt.Errorf("bundle %q: invalid %s property: %w", b.Name, p.Type, err)
+		}
+	}
+	return deps, nil
+}
+
+// requirement is an olm.package.required or olm.gvk.required dependency of a bundle
+type requirement struct {
+	description string
+	satisfiedBy func(bundleDependencies) bool
+}
+
+func (d bundleDependencies) requirements() ([]requirement, error) {
+	reqs := []requirement{}
+	for _, required := range d.packages {
+		constraint, err := semver.NewConstraint(required.VersionRange)
+		if err != nil {
+			return nil, fmt.Errorf("bundle %q: invalid version range %q for required package %q: %w", d.name, required.VersionRange, required.PackageName, err)
+		}
+		reqs = append(reqs, requirement{
+			description: fmt.Sprintf("package %q in version range %q", required.PackageName, required.VersionRange),
+			satisfiedBy: func(b bundleDependencies) bool {
+				return b.pkg == required.PackageName && b.version != nil && constraint.Check(b.version)
+			},
+		})
+	}
+	for _, required := range d.apis {
+		reqs = append(reqs, requirement{
+			description: fmt.Sprintf("API %s/%s %s", required.Group, required.Version, required.Kind),
+			satisfiedBy: func(b bundleDependencies) bool {
+				return slices.Contains(b.gvks, property.GVK(required))
+			},
+		})
+	}
+	return reqs, nil
+}
+
+type pendingBundle struct {
+	scope  *dependencyScope
+	bundle bundleDependencies
+}
+
+// resolveDependencies computes the transitive closure of the dependencies of the bundles of the
+// scopes to resolve. A dependency satisfied by a bundle of any filtered catalog is left as is.
+// Otherwise, the best bundle providing it is looked up in the original catalogs, the catalog of
+// the dependent bundle first, and added to the filtered catalog it belongs to.
+// All the dependencies that no catalog provides are reported in the returned error.
+func resolveDependencies(scopes []*dependencyScope) ([]string, error) {
+	provided := []bundleDependencies{}
+	pending := []pendingBundle{}
+	for _, scope := range scopes {
+		for _, b := range scope.filtered.Bundles {
+			deps, err := parseBundleDependencies(b)
+			if err != nil {
+				return nil, fmt.Errorf("catalog %q: %w", scope.catalog, err)
+			}
+			provided = append(provided, deps)
+			if scope.resolve {
+				pending = append(pending, pendingBundle{scope: scope, bundle: deps})
+			}
+		}
+	}
+
+	added := []string{}
+	errs := []error{}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+
+		reqs, err := current.bundle.requirements()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("catalog %q: %w", current.scope.catalog, err))
+			continue
+		}
+		for _, req := range reqs {
+			if slices.ContainsFunc(provided, req.satisfiedBy) {
+				continue
+			}
+			scope, provider, err := findProvider(scopes, current.scope, req)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if scope == nil {
+				errs = append(errs, fmt.Errorf("catalog %q: bundle %q requires %s, which is not provided by any configured catalog",
+					current.scope.catalog, current.bundle.name, req.description))
+				continue
+			}
+			newBundles, err := addBundle(scope, provider)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			for _, b := range newBundles {
+				deps, err := parseBundleDependencies(b)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("catalog %q: %w", scope.catalog, err))
+					continue
+				}
+				provided = append(provided, deps)
+				pending = append(pending, pendingBundle{scope: scope, bundle: deps})
+				added = append(added, fmt.Sprintf("bundle %s of package %s to catalog %s (required by %s)", b.Name, b.Package, scope.catalog, current.bundle.name))
+			}
+		}
+	}
+	return added, errors.Join(errs...)
+}
+
+// findProvider returns the bundle providing req in the original catalogs, searching the preferred
+// catalog first. Bundles of the default channel of their package are preferred, then higher versions.
+func findProvider(scopes []*dependencyScope, preferred *dependencyScope, req requirement) (*dependencyScope, bundleDependencies, error) {
+	ordered := append([]*dependencyScope{preferred}, slices.DeleteFunc(slices.Clone(scopes), func(s *dependencyScope) bool { return s == preferred })...)
+	for _, scope := range ordered {
+		if scope.full {
+			// the filtered catalog is the original one
+			continue
+		}
+		original, err := scope.originalDeclConfig()
+		if err != nil {
+			return nil, bundleDependencies{}, err
+		}
+
+		defaultChannelBundles := sets.New[string]()
+		defaultChannels := map[string]string{}
+		for _, pkg := range original.Packages {
+			defaultChannels[pkg.Name] = pkg.DefaultChannel
+		}
+		for _, ch := range original.Channels {
+			if defaultChannels[ch.Package] != ch.Name {
+				continue
+			}
+			for _, entry := range ch.Entries {
+				defaultChannelBundles.Insert(ch.Package + "/" + entry.Name)
+			}
+		}
+
+		var best *bundleDependencies
+		bestInDefault := false
+		for _, b := range original.Bundles {
+			deps, err := parseBundleDependencies(b)
+			if err != nil {
+				return nil, bundleDependencies{}, fmt.Errorf("catalog %q: %w", scope.catalog, err)
+			}
+			if !req.satisfiedBy(deps) {
+				continue
+			}
+			inDefault := defaultChannelBundles.Has(b.Package + "/" + b.Name)
+			switch {
+			case best == nil,
+				inDefault && !bestInDefault,
+				inDefault == bestInDefault && deps.version != nil && (best.version == nil || deps.version.GreaterThan(best.version)):
+				best = &deps
+				bestInDefault = inDefault
+			}
+		}
+		if best != nil {
+			return scope, *best, nil
+		}
+	}
+	return nil, bundleDependencies{}, nil
+}
+
+// addBundle adds the provider bundle of the original catalog to the filtered catalog of the scope,
+// with its package and a channel containing it. When the channel is already filtered, the entries
+// linking the new bundle to the existing ones are added too, so that the channel keeps a single head.
+// It returns the bundles added to the filtered catalog.
+func addBundle(scope *dependencyScope, provider bundleDependencies) ([]declcfg.Bundle, error) {
+	original := scope.original
+	filtered := scope.filtered
+
+	candidates := []declcfg.Channel{}
+	for _, ch := range original.Channels {
+		if ch.Package == provider.pkg && slices.ContainsFunc(ch.Entries, func(e declcfg.ChannelEntry) bool { return e.Name == provider.name }) {
+			candidates = append(candidates, ch)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("catalog %q: bundle %q does not belong to any channel", scope.catalog, provider.name)
+	}
+
+	pkgIndex := slices.IndexFunc(filtered.Packages, func(p declcfg.Package) bool { return p.Name == provider.pkg })
+	chIndex := -1
+	channel := candidates[0]
+	for _, candidate := range candidates {
+		if i := slices.IndexFunc(filtered.Channels, func(c declcfg.Channel) bool { return c.Package == candidate.Package && c.Name == candidate.Name }); i >= 0 {
+			chIndex = i
+			channel = candidate
+			break
+		}
+		if pkgIndex < 0 && isDefaultChannel(original, candidate) {
+			channel = candidate
+		}
+	}
+
+	if pkgIndex < 0 {
+		origIndex := slices.IndexFunc(original.Packages, func(p declcfg.Package) bool { return p.Name == provider.pkg })
+		if origIndex < 0 {
+			return nil, fmt.Errorf("catalog %q: package %q not found", scope.catalog, provider.pkg)
+		}
+		pkg := original.Packages[origIndex]
+		pkg.DefaultChannel = channel.Name
+		filtered.Packages = append(filtered.Packages, pkg)
+	}
+
+	newEntries := []declcfg.ChannelEntry{}
+	if chIndex < 0 {
+		for _, entry := range channel.Entries {
+			if entry.Name == provider.name {
+				newEntries = append(newEntries, entry)
+			}
+		}
+		ch := channel
+		ch.Entries = newEntries
+		filtered.Channels = append(filtered.Channels, ch)
+	} else {
+		existing := sets.New[string]()
+		for _, entry := range filtered.Channels[chIndex].Entries {
+			existing.Insert(entry.Name)
+		}
+		keep := connectEntries(channel, existing.Clone().Insert(provider.name))
+		for _, entry := range channel.Entries {
+			if keep.Has(entry.Name) && !existing.Has(entry.Name) {
+				newEntries = append(newEntries, entry)
+			}
+		}
+		filtered.Channels[chIndex].Entries = append(slices.Clone(filtered.Channels[chIndex].Entries), newEntries...)
+	}
+
+	filteredBundles := sets.New[string]()
+	for _, b := range filtered.Bundles {
+		if b.Package == provider.pkg {
+			filteredBundles.Insert(b.Name)
+		}
+	}
+	added := []declcfg.Bundle{}
+	for _, entry := range newEntries {
+		if filteredBundles.Has(entry.Name) {
+			continue
+		}
+		i := slices.IndexFunc(original.Bundles, func(b declcfg.Bundle) bool { return b.Package == provider.pkg && b.Name == entry.Name })
+		if i < 0 {
+			continue
+		}
+		added = append(added, original.Bundles[i])
+	}
+	filtered.Bundles = append(filtered.Bundles, added...)
+	scope.modified = true
+	return added, nil
+}
+
+func isDefaultChannel(dc *declcfg.DeclarativeConfig, ch declcfg.Channel) bool {
+	return slices.ContainsFunc(dc.Packages, func(p declcfg.Package) bool { return p.Name == ch.Package && p.DefaultChannel == ch.Name })
+}
+
+// connectEntries returns the names of the entries of ch to keep so that the upgrade graph
+// goes through all the entries of keep: the replaces chain between the newest and
+// the oldest of them. Entries only reachable by skips are placed at the entry skipping them.
+func connectEntries(ch declcfg.Channel, keep sets.Set[string]) sets.Set[string] {
+	entries := map[string]declcfg.ChannelEntry{}
+	replacedOrSkipped := sets.New[string]()
+	for _, entry := range ch.Entries {
+		entries[entry.Name] = entry
+		replacedOrSkipped.Insert(entry.Replaces)
+		replacedOrSkipped.Insert(entry.Skips...)
+	}
+	heads := []string{}
+	for _, entry := range ch.Entries {
+		if !replacedOrSkipped.Has(entry.Name) {
+			heads = append(heads, entry.Name)
+		}
+	}
+	if len(heads) != 1 {
+		// not a valid upgrade graph: nothing to connect
+		return keep
+	}
+
+	chain := []string{}
+	positions := map[string]int{}
+	for name := heads[0]; name != ""; name = entries[name].Replaces {
+		if _, seen := positions[name]; seen {
+			break
+		}
+		if _, ok := entries[name]; !ok {
+			break
+		}
+		positions[name] = len(chain)
+		chain = append(chain, name)
+	}
+
+	first, last := len(chain), -1
+	for name := range keep {
+		pos, ok := positions[name]
+		if !ok {
+			pos = slices.IndexFunc(chain, func(c string) bool { return slices.Contains(entries[c].Skips, name) })
+			if pos < 0 {
+				continue
+			}
+		}
+		first = min(first, pos)
+		last = max(last, pos)
+	}
+
+	result := keep.Clone()
+	for i := first; i <= last; i++ {
+		result.Insert(chain[i])
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addProperties adds properties to a bundle of dc
+func addProperties(dc *declcfg.DeclarativeConfig, bundle string, props ...property.Property) {
+	for i := range dc.Bundles {
+		if dc.Bundles[i].Name == bundle {
+			dc.Bundles[i].Properties = append(dc.Bundles[i].Properties, props...)
+		}
+	}
+}
+
+// filteredCopy returns the part of dc limited to the bundles of keep
+func filteredCopy(dc declcfg.DeclarativeConfig, keep ...string) *declcfg.DeclarativeConfig {
+	filtered := &declcfg.DeclarativeConfig{}
+	kept := map[string]bool{}
+	for _, name := range keep {
+		kept[name] = true
+	}
+	packages := map[string]bool{}
+	for _, ch := range dc.Channels {
+		entries := []declcfg.ChannelEntry{}
+		for _, e := range ch.Entries {
+			if kept[e.Name] {
+				entries = append(entries, e)
+			}
+		}
+		if len(entries) > 0 {
+			ch.Entries = entries
+			filtered.Channels = append(filtered.Channels, ch)
+			packages[ch.Package] = true
+		}
+	}
+	for _, pkg := range dc.Packages {
+		if packages[pkg.Name] {
+			filtered.Packages = append(filtered.Packages, pkg)
+		}
+	}
+	for _, b := range dc.Bundles {
+		if kept[b.Name] {
+			filtered.Bundles = append(filtered.Bundles, b)
+		}
+	}
+	return filtered
+}
+
+func newDependencyScope(catalog string, original declcfg.DeclarativeConfig, filtered *declcfg.DeclarativeConfig, resolve bool) *dependencyScope {
+	return &dependencyScope{
+		catalog:      catalog,
+		filtered:     filtered,
+		loadOriginal: func() (*declcfg.DeclarativeConfig, error) { return &original, nil },
+		resolve:      resolve,
+	}
+}
+
+func TestResolveDependencies(t *testing.T) {
+	t.Run("should add the provider of a required API from another catalog", func(t *testing.T) {
+		certified, redhat := dependencyCatalogs(t)
+		scopes := []*dependencyScope{
+			newDependencyScope("certified", certified, filteredCopy(certified, "foo.v1.1.0"), true),
+			newDependencyScope("redhat", redhat, &declcfg.DeclarativeConfig{}, false),
+		}
+		added, err := resolveDependencies(scopes)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"bundle bar.v2.2.0 of package bar to catalog redhat (required by foo.v1.1.0)"}, added)
+		assert.False(t, scopes[0].modified)
+		assert.True(t, scopes[1].modified)
+		assert.Equal(t, []string{"bar.v2.2.0"}, bundleNames(scopes[1].filtered))
+		require.Len(t, scopes[1].filtered.Packages, 1)
+		assert.Equal(t, "stable", scopes[1].filtered.Packages[0].DefaultChannel)
+		require.Len(t, scopes[1].filtered.Channels, 1)
+		assert.Equal(t, []declcfg.ChannelEntry{{Name: "bar.v2.2.0", Replaces: "bar.v2.1.0"}}, scopes[1].filtered.Channels[0].Entries)
+	})
+
+	t.Run("should keep dependencies already satisfied by a filtered catalog", func(t *testing.T) {
+		certified, redhat := dependencyCatalogs(t)
+		scopes := []*dependencyScope{
+			newDependencyScope("certified", certified, filteredCopy(certified, "foo.v1.1.0"), true),
+			{
+				catalog:      "redhat",
+				filtered:     filteredCopy(redhat, "bar.v2.0.0"),
+				loadOriginal: func() (*declcfg.DeclarativeConfig, error) { return nil, assert.AnError },
+			},
+		}
+		added, err := resolveDependencies(scopes)
+		require.NoError(t, err)
+		assert.Empty(t, added)
+		assert.False(t, scopes[1].modified)
+	})
+
+	t.Run("should connect the added bundle to the filtered channel and resolve transitively", func(t *testing.T) {
+		certified, redhat := dependencyCatalogs(t)
+		addProperties(&certified, "foo.v1.1.0", property.MustBuildPackageRequired("bar", "<2.1.0"))
+		addProperties(&redhat, "bar.v2.0.0", property.MustBuildPackageRequired("baz", ">=1.0.0"))
+		scopes := []*dependencyScope{
+			newDependencyScope("certified", certified, filteredCopy(certified, "foo.v1.1.0"), true),
+			newDependencyScope("redhat", redhat, filteredCopy(redhat, "bar.v2.2.0"), false),
+		}
+		_, err := resolveDependencies(scopes)
+		assert.EqualError(t, err, `catalog "redhat": bundle "bar.v2.0.0" requires package "baz" in version range ">=1.0.0", which is not provided by any configured catalog`)
+
+		assert.ElementsMatch(t, []string{"bar.v2.0.0", "bar.v2.1.0", "bar.v2.2.0"}, bundleNames(scopes[1].filtered))
+		assert.Len(t, scopes[1].filtered.Channels[0].Entries, 3)
+	})
+}
+
+// dependencyCatalogs returns a certified catalog whose foo.v1.1.0 requires an API provided by the bar bundles of a redhat catalog.
+func dependencyCatalogs(t *testing.T) (declcfg.DeclarativeConfig, declcfg.DeclarativeConfig) {
+	t.Helper()
+	certified := newSelectionCatalog(t, "foo", map[string][]string{"stable": {"1.0.0", "1.1.0"}})
+	addProperties(&certified, "foo.v1.1.0", property.MustBuildGVKRequired("example.com", "v1", "Bar"))
+
+	redhat := newSelectionCatalog(t, "bar", map[string][]string{"stable": {"2.0.0", "2.1.0", "2.2.0"}})
+	for _, b := range []string{"bar.v2.0.0", "bar.v2.1.0", "bar.v2.2.0"} {
+		addProperties(&redhat, b, property.MustBuildGVK("example.com", "v1", "Bar"))
+	}
+	return certified, redhat
+}
@@ -0,0 +1,282 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+// packageDeprecations holds the olm.deprecations messages of a package,
+// indexed by the name of the deprecated channels and bundles
+type packageDeprecations struct {
+	message  string
+	channels map[string]string
+	bundles  map[string]string
+}
+
+func indexDeprecations(dc declcfg.DeclarativeConfig) map[string]packageDeprecations {
+	deprecated := map[string]packageDeprecations{}
+	for _, d := range dc.Deprecations {
+		pkg, ok := deprecated[d.Package]
+		if !ok {
+			pkg = packageDeprecations{channels: map[string]string{}, bundles: map[string]string{}}
+		}
+		for _, entry := range d.Entries {
+			message := strings.TrimSpace(entry.Message)
+			switch entry.Reference.Schema {
+			case declcfg.SchemaPackage:
+				pkg.message = message
+			case declcfg.SchemaChannel:
+				pkg.channels[entry.Reference.Name] = message
+			case declcfg.SchemaBundle:
+				pkg.bundles[entry.Reference.Name] = message
+			}
+		}
+		deprecated[d.Package] = pkg
+	}
+	return deprecated
+}
+
+// deprecationMessage describes a deprecated package, channel or bundle
+func deprecationMessage(pkg string, ref declcfg.PackageScopedReference, message string) string {
+	switch ref.Schema {
+	case declcfg.SchemaChannel:
+		return fmt.Sprintf("package %q channel %q is deprecated: %s", pkg, ref.Name, message)
+	case declcfg.SchemaBundle:
+		return fmt.Sprintf("package %q bundle %q is deprecated: %s", pkg, ref.Name, message)
+	default:
+		return fmt.Sprintf("package %q is deprecated: %s", pkg, message)
+	}
+}
+
+// checkRequestedDeprecations returns an error for each package, channel or bundle explicitly
+// requested by the operator filter that is deprecated in dc.
+// A bundle is explicitly requested when minVersion and maxVersion select a single version.
+func checkRequestedDeprecations(dc declcfg.DeclarativeConfig, op v2alpha1.Operator) error {
+	deprecated := indexDeprecations(dc)
+	if len(deprecated) == 0 {
+		return nil
+	}
+
+	errs := []error{}
+	pinned := map[string]sets.Set[string]{}
+	hasPins := false
+	for _, pkg := range op.Packages {
+		d, ok := deprecated[pkg.Name]
+		if !ok {
+			continue
+		}
+		if d.message != "" {
+			errs = append(errs, errors.New(deprecationMessage(pkg.Name, declcfg.PackageScopedReference{Schema: declcfg.SchemaPackage}, d.message)))
+		}
+		pinned[pkg.Name] = sets.New[string]()
+		if v := pinnedVersion(pkg.IncludeBundle); v != "" {
+			pinned[pkg.Name].Insert(v)
+			hasPins = true
+		}
+		for _, ch := range pkg.Channels {
+			if message, ok := d.channels[ch.Name]; ok {
+				errs = append(errs, errors.New(deprecationMessage(pkg.Name, declcfg.PackageScopedReference{Schema: declcfg.SchemaChannel, Name: ch.Name}, message)))
+			}
+			if v := pinnedVersion(ch.IncludeBundle); v != "" {
+				pinned[pkg.Name].Insert(v)
+				hasPins = true
+			}
+		}
+	}
+
+	if hasPins {
+		versions, err := bundleVersions(dc)
+		if err != nil {
+			return err
+		}
+		for _, b := range dc.Bundles {
+			message, ok := deprecated[b.Package].bundles[b.Name]
+			if !ok {
+				continue
+			}
+			v, ok := versions[b.Package][b.Name]
+			if ok && pinned[b.Package].Has(v.String()) {
+				errs = append(errs, errors.New(deprecationMessage(b.Package, declcfg.PackageScopedReference{Schema: declcfg.SchemaBundle, Name: b.Name}, message)))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pinnedVersion returns the version of a selection limited to a single bundle, in normalized form
+func pinnedVersion(b v2alpha1.IncludeBundle) string {
+	if b.MinVersion == "" || b.MinVersion != b.MaxVersion {
+		return ""
+	}
+	v, err := semver.NewVersion(b.MinVersion)
+	if err != nil {
+		// rejected by the ImageSetConfiguration validation
+		return ""
+	}
+	return v.String()
+}
+
+// excludeDeprecated returns a copy of dc without its deprecated channels and bundles.
+// The entries replacing a removed bundle are rewired to the bundle it replaced, and skip it,
+// so that the channels remain valid upgrade graphs.
+// The default channel of a package is kept, as the catalog would be invalid without it,
+// unless the operator filter configures another default channel.
+func excludeDeprecated(dc declcfg.DeclarativeConfig, op v2alpha1.Operator) declcfg.DeclarativeConfig {
+	deprecated := indexDeprecations(dc)
+	if len(deprecated) == 0 {
+		return dc
+	}
+
+	defaultChannels := map[string]string{}
+	for _, pkg := range dc.Packages {
+		defaultChannels[pkg.Name] = pkg.DefaultChannel
+	}
+	for _, pkg := range op.Packages {
+		if pkg.DefaultChannel != "" {
+			defaultChannels[pkg.Name] = pkg.DefaultChannel
+		}
+	}
+
+	result := dc
+	result.Channels = make([]declcfg.Channel, 0, len(dc.Channels))
+	keptBundles := map[string]sets.Set[string]{}
+	for _, ch := range dc.Channels {
+		d := deprecated[ch.Package]
+		isDefault := defaultChannels[ch.Package] == ch.Name
+		if _, ok := d.channels[ch.Name]; ok && !isDefault {
+			continue
+		}
+		entries := withoutDeprecatedEntries(ch.Entries, d.bundles)
+		if len(entries) == 0 {
+			if !isDefault {
+				continue
+			}
+			entries = ch.Entries
+		}
+		ch.Entries = entries
+		result.Channels = append(result.Channels, ch)
+
+		if _, ok := keptBundles[ch.Package]; !ok {
+			keptBundles[ch.Package] = sets.New[string]()
+		}
+		for _, entry := range entries {
+			keptBundles[ch.Package].Insert(entry.Name)
+		}
+	}
+
+	result.Bundles = make([]declcfg.Bundle, 0, len(dc.Bundles))
+	for _, b := range dc.Bundles {
+		if keptBundles[b.Package].Has(b.Name) {
+			result.Bundles = append(result.Bundles, b)
+		}
+	}
+	return result
+}
+
+func withoutDeprecatedEntries(entries []declcfg.ChannelEntry, deprecatedBundles map[string]string) []declcfg.ChannelEntry {
+	removed := map[string]declcfg.ChannelEntry{}
+	kept := make([]declcfg.ChannelEntry, 0, len(entries))
+	for _, entry := range entries {
+		if _, ok := deprecatedBundles[entry.Name]; ok {
+			removed[entry.Name] = entry
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	if len(removed) == 0 {
+		return entries
+	}
+
+	for i := range kept {
+		entry := &kept[i]
+		if _, ok := removed[entry.Replaces]; !ok {
+			continue
+		}
+		skips := sets.New(entry.Skips...)
+		entry.Skips = slices.Clone(entry.Skips)
+		for {
+			replaced, ok := removed[entry.Replaces]
+			if !ok || skips.Has(replaced.Name) {
+				break
+			}
+			for _, name := range append([]string{replaced.Name}, replaced.Skips...) {
+				if !skips.Has(name) {
+					skips.Insert(name)
+					entry.Skips = append(entry.Skips, name)
+				}
+			}
+			entry.Replaces = replaced.Replaces
+		}
+	}
+	return kept
+}
+
+// pruneDeprecations keeps only the olm.deprecations entries referencing
+// a package, channel or bundle of dc
+func pruneDeprecations(dc *declcfg.DeclarativeConfig) {
+	packages := sets.New[string]()
+	for _, pkg := range dc.Packages {
+		packages.Insert(pkg.Name)
+	}
+	channels := map[string]sets.Set[string]{}
+	for _, ch := range dc.Channels {
+		if _, ok := channels[ch.Package]; !ok {
+			channels[ch.Package] = sets.New[string]()
+		}
+		channels[ch.Package].Insert(ch.Name)
+	}
+	bundles := map[string]sets.Set[string]{}
+	for _, b := range dc.Bundles {
+		if _, ok := bundles[b.Package]; !ok {
+			bundles[b.Package] = sets.New[string]()
+		}
+		bundles[b.Package].Insert(b.Name)
+	}
+
+	// nil when none is kept, as in a catalog loaded from disk
+	var deprecations []declcfg.Deprecation
+	for _, d := range dc.Deprecations {
+		if !packages.Has(d.Package) {
+			continue
+		}
+		entries := []declcfg.DeprecationEntry{}
+		for _, entry := range d.Entries {
+			switch entry.Reference.Schema {
+			case declcfg.SchemaChannel:
+				if !channels[d.Package].Has(entry.Reference.Name) {
+					continue
+				}
+			case declcfg.SchemaBundle:
+				if !bundles[d.Package].Has(entry.Reference.Name) {
+					continue
+				}
+			}
+			entries = append(entries, entry)
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		d.Entries = entries
+		deprecations = append(deprecations, d)
+	}
+	dc.Deprecations = deprecations
+}
+
+// deprecationWarnings describes the deprecated content of dc
+func deprecationWarnings(dc declcfg.DeclarativeConfig) []string {
+	warnings := []string{}
+	for _, d := range dc.Deprecations {
+		for _, entry := range d.Entries {
+			warnings = append(warnings, deprecationMessage(d.Package, entry.Reference, strings.TrimSpace(entry.Message)))
+		}
+	}
+	return warnings
+}
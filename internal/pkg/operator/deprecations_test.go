@@ -0,0 +1,131 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+// newDeprecatedCatalog returns a catalog where channel fast and bundle foo.v1.1.0 are deprecated
+func newDeprecatedCatalog(t *testing.T) declcfg.DeclarativeConfig {
+	t.Helper()
+	dc := newSelectionCatalog(t, "foo", map[string][]string{
+		"stable": {"1.0.0", "1.1.0", "1.2.0"},
+		"fast":   {"1.2.0", "1.3.0"},
+	})
+	dc.Deprecations = []declcfg.Deprecation{{
+		Schema:  declcfg.SchemaDeprecation,
+		Package: "foo",
+		Entries: []declcfg.DeprecationEntry{
+			{Reference: declcfg.PackageScopedReference{Schema: declcfg.SchemaChannel, Name: "fast"}, Message: "fast is no longer maintained\n"},
+			{Reference: declcfg.PackageScopedReference{Schema: declcfg.SchemaBundle, Name: "foo.v1.1.0"}, Message: "1.1.0 has a known issue"},
+		},
+	}}
+	return dc
+}
+
+func bundleNames(dc *declcfg.DeclarativeConfig) []string {
+	names := []string{}
+	for _, b := range dc.Bundles {
+		names = append(names, b.Name)
+	}
+	return names
+}
+
+func TestExcludeDeprecated(t *testing.T) {
+	t.Run("should remove deprecated channels and bundles and rewire the upgrade graph", func(t *testing.T) {
+		dc := newDeprecatedCatalog(t)
+		res := excludeDeprecated(dc, v2alpha1.Operator{})
+
+		require.Len(t, res.Channels, 1)
+		assert.Equal(t, "stable", res.Channels[0].Name)
+		assert.Equal(t, []declcfg.ChannelEntry{
+			{Name: "foo.v1.0.0"},
+			{Name: "foo.v1.2.0", Replaces: "foo.v1.0.0", Skips: []string{"foo.v1.1.0"}},
+		}, res.Channels[0].Entries)
+		assert.ElementsMatch(t, []string{"foo.v1.0.0", "foo.v1.2.0"}, bundleNames(&res))
+		// the original catalog is left untouched
+		assert.Len(t, dc.Channels, 2)
+		assert.Len(t, dc.Bundles, 4)
+	})
+
+	t.Run("should keep the deprecated default channel unless another one is configured", func(t *testing.T) {
+		dc := newDeprecatedCatalog(t)
+		dc.Packages[0].DefaultChannel = "fast"
+		res := excludeDeprecated(dc, v2alpha1.Operator{})
+		assert.Len(t, res.Channels, 2)
+
+		op := v2alpha1.Operator{IncludeConfig: v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+			{Name: "foo", DefaultChannel: "stable"},
+		}}}
+		res = excludeDeprecated(dc, op)
+		require.Len(t, res.Channels, 1)
+		assert.Equal(t, "stable", res.Channels[0].Name)
+	})
+}
+
+func TestCheckRequestedDeprecations(t *testing.T) {
+	dc := newDeprecatedCatalog(t)
+
+	t.Run("should fail on deprecated channels and pinned bundles", func(t *testing.T) {
+		op := v2alpha1.Operator{IncludeConfig: v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+			{Name: "foo", Channels: []v2alpha1.IncludeChannel{
+				{Name: "fast"},
+				{Name: "stable", IncludeBundle: v2alpha1.IncludeBundle{MinVersion: "1.1.0", MaxVersion: "1.1.0"}},
+			}},
+		}}}
+		err := checkRequestedDeprecations(dc, op)
+		assert.EqualError(t, err, `package "foo" channel "fast" is deprecated: fast is no longer maintained`+"\n"+
+			`package "foo" bundle "foo.v1.1.0" is deprecated: 1.1.0 has a known issue`)
+	})
+
+	t.Run("should accept deprecated content that is not explicitly requested", func(t *testing.T) {
+		op := v2alpha1.Operator{IncludeConfig: v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+			{Name: "foo", Channels: []v2alpha1.IncludeChannel{
+				{Name: "stable", IncludeBundle: v2alpha1.IncludeBundle{MinVersion: "1.0.0"}},
+			}},
+		}}}
+		assert.NoError(t, checkRequestedDeprecations(dc, op))
+	})
+}
+
+func TestFilterCatalogDeprecations(t *testing.T) {
+	fooStable := v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{
+		{Name: "foo", Channels: []v2alpha1.IncludeChannel{
+			{Name: "stable", IncludeBundle: v2alpha1.IncludeBundle{MinVersion: "1.0.0"}},
+		}},
+	}}
+
+	t.Run("should keep the deprecation entries of the remaining content", func(t *testing.T) {
+		op := v2alpha1.Operator{Deprecations: v2alpha1.DeprecationsWarn, IncludeConfig: fooStable}
+		res, err := filterCatalog(t.Context(), newDeprecatedCatalog(t), op)
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"foo.v1.0.0", "foo.v1.1.0", "foo.v1.2.0"}, bundleNames(res))
+		assert.Equal(t, []string{`package "foo" bundle "foo.v1.1.0" is deprecated: 1.1.0 has a known issue`}, deprecationWarnings(*res))
+	})
+
+	t.Run("should exclude deprecated content", func(t *testing.T) {
+		op := v2alpha1.Operator{Deprecations: v2alpha1.DeprecationsExclude, IncludeConfig: fooStable}
+		res, err := filterCatalog(t.Context(), newDeprecatedCatalog(t), op)
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"foo.v1.0.0", "foo.v1.2.0"}, bundleNames(res))
+		// encoded as a catalog loaded from disk, so that the digests of the filtered catalog match
+		assert.Nil(t, res.Deprecations)
+	})
+
+	t.Run("should fail when a requested channel is deprecated", func(t *testing.T) {
+		op := v2alpha1.Operator{
+			Catalog:       "registry.example.com/catalog:v1",
+			Deprecations:  v2alpha1.DeprecationsFail,
+			IncludeConfig: v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{{Name: "foo", Channels: []v2alpha1.IncludeChannel{{Name: "fast"}}}}},
+		}
+		_, err := filterCatalog(t.Context(), newDeprecatedCatalog(t), op)
+		assert.EqualError(t, err, `catalog "registry.example.com/catalog:v1": deprecated content requested: package "foo" channel "fast" is deprecated: fast is no longer maintained`)
+	})
+}
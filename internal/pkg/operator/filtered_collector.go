@@ -11,10 +11,13 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
 	"github.com/vbauerster/mpb/v8"
 	"go.podman.io/image/v5/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
 	"github.com/openshift/oc-mirror/v2/internal/pkg/folder"
@@ -52,6 +55,7 @@ func (o *FilterCollector) OperatorImageCollector(ctx context.Context) (v2alpha1.
 	allErrs := []error{}
 
 	p := mpb.New(mpb.PopCompletedMode(), mpb.ContainerOptional(mpb.WithOutput(io.Discard), !o.Opts.Global.IsTerminal))
+	collected := make([]filteredOperator, 0, len(o.Config.Mirror.Operators))
 	for _, op := range o.Config.Mirror.Operators {
 		// download the operator index image
 		o.Log.Debug(collectorPrefix+"copying operator image %s", op.Catalog)
@@ -71,11 +75,54 @@ func (o *FilterCollector) OperatorImageCollector(ctx context.Context) (v2alpha1.
 			continue
 		}
 
+		result, err := o.filterOperatorCatalog(ctx, op, imgSpec)
+		if err != nil {
+			spinner.Abort(true)
+			spinner.Wait()
+			allErrs = append(allErrs, fmt.Errorf("collect catalog %q: %w", op.Catalog, err))
+			continue
+		}
+		collected = append(collected, filteredOperator{op: op, imgSpec: imgSpec, result: result, spinner: spinner})
+	}
+
+	if slices.ContainsFunc(collected, func(f filteredOperator) bool { return f.op.CrossCatalogDependencies }) {
+		// unsatisfiable dependencies are reported before any image is collected
+		if err := o.resolveCrossCatalogDependencies(ctx, collected); err != nil {
+			for _, f := range collected {
+				f.spinner.Abort(true)
+				f.spinner.Wait()
+			}
+			p.Wait()
+			return collectorSchema, errors.Join(append(allErrs, err)...)
+		}
+	}
+
+	var merged *mergedCatalog
+	if merge := o.Config.Mirror.MergedCatalog; merge != nil {
+		m, err := o.mergeCatalogs(ctx, *merge, collected)
+		if err != nil {
+			allErrs = append(allErrs, fmt.Errorf("merge catalogs into %q: %w", merge.TargetCatalog, err))
+		} else {
+			merged = &m
+		}
+	}
+
+	for _, f := range collected {
+		op, imgSpec, result, spinner := f.op, f.imgSpec, f.result, f.spinner
+
 		// Use a fresh local map per catalog so we can read exactly which images this
 		// catalog contributed — needed to populate platform filters correctly when the
 		// same bundle appears in multiple catalogs with different platform requirements.
 		localRelatedImages := make(map[string][]v2alpha1.RelatedImage)
-		result, err := o.collectOperator(ctx, op, localRelatedImages, copyImageSchemaMap)
+		err := o.collectOperator(op, imgSpec, result, localRelatedImages, copyImageSchemaMap)
+		if err == nil && !f.merged {
+			// the catalogs merged are only mirrored through the merged catalog image
+			var rebuiltTag string
+			rebuiltTag, err = o.filteredTag(f)
+			if err == nil {
+				err = o.collectCatalogImage(op, imgSpec, result.Digest, rebuiltTag, localRelatedImages)
+			}
+		}
 		if err != nil {
 			spinner.Abort(true)
 			spinner.Wait()
@@ -104,17 +151,7 @@ func (o *FilterCollector) OperatorImageCollector(ctx context.Context) (v2alpha1.
 		// Merge this catalog's images into the global map.
 		maps.Copy(relatedImages, localRelatedImages)
 
-		// OCPBUGS-81712: In M2D/M2M modes, op.Catalog is already pinned to digest by executor.go
-		// CLID-513: For OCI paths with digest, use a consistent key format (without digest)
-		// This matches how catalogImage is constructed in collectOperator
-		mapKey := imgSpec.ReferenceWithTransport
-		if imgSpec.Transport == consts.OciProtocol && imgSpec.IsImageByDigestOnly() {
-			sourceOCIDir, absErr := filepath.Abs(imgSpec.Name)
-			if absErr == nil {
-				mapKey = consts.OciProtocol + sourceOCIDir
-			}
-		}
-		collectorSchema.CatalogToFBCMap[mapKey] = result
+		collectorSchema.CatalogToFBCMap[catalogMapKey(imgSpec)] = result
 
 		spinner.Increment()
 		if !o.Opts.Global.IsTerminal {
@@ -123,6 +160,14 @@ func (o *FilterCollector) OperatorImageCollector(ctx context.Context) (v2alpha1.
 	}
 	p.Wait()
 
+	if merged != nil {
+		if err := o.collectCatalogImage(merged.op, merged.imgSpec, merged.result.Digest, merged.digest, relatedImages); err != nil {
+			allErrs = append(allErrs, fmt.Errorf("collect merged catalog %q: %w", merged.op.TargetCatalog, err))
+		}
+		// the rebuild of the merged catalog is driven by its first catalog
+		collectorSchema.CatalogToFBCMap[catalogMapKey(merged.imgSpec)] = merged.result
+	}
+
 	o.Log.Debug(collectorPrefix+"related images length %d ", len(relatedImages))
 	count := 0
 	for _, v := range relatedImages {
@@ -151,8 +196,24 @@ func (o *FilterCollector) OperatorImageCollector(ctx context.Context) (v2alpha1.
 	return collectorSchema, errors.Join(allErrs...)
 }
 
+// catalogMapKey returns the key of the catalog in CollectorSchema.CatalogToFBCMap
+func catalogMapKey(imgSpec image.ImageSpec) string {
+	// OCPBUGS-81712: In M2D/M2M modes, op.Catalog is already pinned to digest by executor.go
+	// CLID-513: For OCI paths with digest, use a consistent key format (without digest)
+	// This matches how catalogImage is constructed in collectCatalogImage
+	mapKey := imgSpec.ReferenceWithTransport
+	if imgSpec.Transport == consts.OciProtocol && imgSpec.IsImageByDigestOnly() {
+		sourceOCIDir, err := filepath.Abs(imgSpec.Name)
+		if err == nil {
+			mapKey = consts.OciProtocol + sourceOCIDir
+		}
+	}
+	return mapKey
+}
+
 func isFullCatalog(catalog v2alpha1.Operator) bool {
-	return len(catalog.IncludeConfig.Packages) == 0 && catalog.Full
+	// excluding deprecated content requires rebuilding the catalog
+	return len(catalog.IncludeConfig.Packages) == 0 && catalog.Full && catalog.Deprecations != v2alpha1.DeprecationsExclude
 }
 
 // digestOfFilter computes a hash of the operator filter configuration.
@@ -166,6 +227,16 @@ func digestOfFilter(catalog v2alpha1.Operator, catalogDigest string) (string, er
 	c.TargetCatalog = ""
 	c.TargetTag = ""
 	c.TargetCatalogSourceTemplate = ""
+	// the cluster resources generated for the catalog don't change its content
+	c.CatalogSource = nil
+	c.ClusterCatalog = nil
+	c.GenerateInstallManifests = false
+	if slices.ContainsFunc(c.Packages, func(pkg v2alpha1.IncludePackage) bool { return pkg.InstallPlanApproval != "" }) {
+		c.Packages = slices.Clone(c.Packages)
+		for i := range c.Packages {
+			c.Packages[i].InstallPlanApproval = ""
+		}
+	}
 	if c.Catalog != "" && catalogDigest != "" {
 		imgSpec, err := image.ParseRef(c.Catalog)
 		if err == nil {
@@ -248,37 +319,54 @@ func addOriginFromOperatorCatalogOnDisk(relatedImages *map[string][]v2alpha1.Rel
 	}
 }
 
-func (o FilterCollector) collectOperator( //nolint:cyclop // TODO: this needs further refactoring
-	ctx context.Context,
-	op v2alpha1.Operator,
-	relatedImages map[string][]v2alpha1.RelatedImage,
-	copyImageSchemaMap *v2alpha1.CopyImageSchemaMap,
-) (v2alpha1.CatalogFilterResult, error) {
+// filteredOperator is an operator catalog of the configuration, once filtered
+type filteredOperator struct {
+	op      v2alpha1.Operator
+	imgSpec image.ImageSpec
+	result  v2alpha1.CatalogFilterResult
+	spinner *mpb.Bar
+	// merged is set when the catalog is part of the merged catalog
+	merged bool
+	// rebuiltTag is the tag of the filtered catalog when bundles of its dependencies were added to it
+	rebuiltTag string
+}
+
+// filterOperatorCatalog returns the filtered declarative config of the operator catalog
+func (o FilterCollector) filterOperatorCatalog(ctx context.Context, op v2alpha1.Operator, imgSpec image.ImageSpec) (v2alpha1.CatalogFilterResult, error) {
 	// CLID-47 double check that targetCatalog is valid
 	if op.TargetCatalog != "" && !v2alpha1.IsValidPathComponent(op.TargetCatalog) {
 		return v2alpha1.CatalogFilterResult{}, fmt.Errorf("invalid targetCatalog %s", op.TargetCatalog)
 	}
 
-	// CLID-27 ensure we pick up oci:// (on disk) catalogs
-	imgSpec, err := image.ParseRef(op.Catalog)
-	if err != nil {
-		return v2alpha1.CatalogFilterResult{}, err
-	}
-
 	catalogDigest, err := o.getCatalogDigest(ctx, op)
 	if err != nil {
 		// OCPBUGS-36548 (manifest unknown)
 		return v2alpha1.CatalogFilterResult{}, err
 	}
 
-	result, err := o.filterOperator(ctx, op, imgSpec, catalogDigest)
+	return o.filterOperator(ctx, op, imgSpec, catalogDigest)
+}
+
+func (o FilterCollector) collectOperator(
+	op v2alpha1.Operator,
+	imgSpec image.ImageSpec,
+	result v2alpha1.CatalogFilterResult,
+	relatedImages map[string][]v2alpha1.RelatedImage,
+	copyImageSchemaMap *v2alpha1.CopyImageSchemaMap,
+) error {
+	exclusions, err := relatedImageExclusions(op)
 	if err != nil {
-		return v2alpha1.CatalogFilterResult{}, err
+		return err
 	}
-
-	ri, err := o.ctlgHandler.getRelatedImagesFromCatalog(result.DeclConfig, copyImageSchemaMap)
+	// excluded images are neither mirrored nor tracked as images of their bundles,
+	// so that the bundles are not skipped for missing them
+	dc, excluded := withoutExcludedRelatedImages(result.DeclConfig, exclusions)
+	if len(excluded) > 0 {
+		o.Log.Debug("excluding %d related images of catalog %q: %v", len(excluded), op.Catalog, excluded)
+	}
+	ri, err := o.ctlgHandler.getRelatedImagesFromCatalog(dc, copyImageSchemaMap)
 	if err != nil {
-		return v2alpha1.CatalogFilterResult{}, err
+		return err
 	}
 	o.Log.Debug("Found %d related images for catalog %q", len(ri), op.Catalog)
 
@@ -289,7 +377,34 @@ func (o FilterCollector) collectOperator( //nolint:cyclop // TODO: this needs fu
 	}
 
 	maps.Copy(relatedImages, ri)
+	return nil
+}
+
+// rebuiltTag returns the tag of the filtered catalog, empty when the catalog is mirrored as is
+func (o FilterCollector) rebuiltTag(op v2alpha1.Operator, imgSpec image.ImageSpec, catalogDigest string) (string, error) {
+	if isFullCatalog(op) {
+		return "", nil
+	}
+	imageIndexDir := filepath.Join(o.Opts.Global.WorkingDir, operatorCatalogsDir, imgSpec.ComponentName(), catalogDigest)
+	filteredCatalogsDir := filepath.Join(imageIndexDir, operatorCatalogFilteredDir)
+	return findFilterDigest(op, catalogDigest, filteredCatalogsDir)
+}
+
+// filteredTag returns the tag of a collected filtered catalog, including the bundles of its dependencies
+func (o FilterCollector) filteredTag(f filteredOperator) (string, error) {
+	if f.rebuiltTag != "" {
+		return f.rebuiltTag, nil
+	}
+	return o.rebuiltTag(f.op, f.imgSpec, f.result.Digest)
+}
 
+// collectCatalogImage adds the catalog image itself to relatedImages
+func (o FilterCollector) collectCatalogImage(
+	op v2alpha1.Operator,
+	imgSpec image.ImageSpec,
+	catalogDigest, rebuiltTag string,
+	relatedImages map[string][]v2alpha1.RelatedImage,
+) error {
 	targetTag := op.TargetTag
 	if len(targetTag) == 0 && imgSpec.Transport == consts.OciProtocol {
 		// for this case only, img.ParseRef(in its current state)
@@ -312,24 +427,12 @@ func (o FilterCollector) collectOperator( //nolint:cyclop // TODO: this needs fu
 		// ensure correct oci format and directory lookup
 		sourceOCIDir, err := filepath.Abs(imgSpec.Name)
 		if err != nil {
-			return v2alpha1.CatalogFilterResult{}, fmt.Errorf("failed to get OCI image path: %w", err)
+			return fmt.Errorf("failed to get OCI image path: %w", err)
 		}
 		catalogImage = consts.OciProtocol + sourceOCIDir
 	}
 
-	rebuiltTag := ""
-	if !isFullCatalog(op) {
-		imageIndexDir := filepath.Join(o.Opts.Global.WorkingDir, operatorCatalogsDir, imgSpec.ComponentName(), catalogDigest)
-		filteredCatalogsDir := filepath.Join(imageIndexDir, operatorCatalogFilteredDir)
-
-		tag, err := findFilterDigest(op, catalogDigest, filteredCatalogsDir)
-		if err != nil {
-			return v2alpha1.CatalogFilterResult{}, err
-		}
-		rebuiltTag = tag
-	}
-
-	componentName := imgSpec.ComponentName() + "." + result.Digest
+	componentName := imgSpec.ComponentName() + "." + catalogDigest
 	relatedImages[componentName] = []v2alpha1.RelatedImage{
 		{
 			Name:          catalogName,
@@ -341,7 +444,7 @@ func (o FilterCollector) collectOperator( //nolint:cyclop // TODO: this needs fu
 			FullCatalog:   isFullCatalog(op),
 		},
 	}
-	return result, nil
+	return nil
 }
 
 func (o FilterCollector) getCatalogDigest(ctx context.Context, op v2alpha1.Operator) (string, error) {
@@ -396,8 +499,14 @@ func (o FilterCollector) filterOperator(ctx context.Context, op v2alpha1.Operato
 		if err != nil {
 			return v2alpha1.CatalogFilterResult{}, fmt.Errorf("retrieve filtered catalog config from %s: %w", filterConfigDir, err)
 		}
+		// the highest versions of the original catalog are all kept in the filtered one
+		resolvedOp, err := resolveLatestBundles(op, *filteredDC)
+		if err != nil {
+			return v2alpha1.CatalogFilterResult{}, err
+		}
+		o.warnDeprecations(op, filteredDC)
 		return v2alpha1.CatalogFilterResult{
-			OperatorFilter:     op,
+			OperatorFilter:     resolvedOp,
 			FilteredConfigPath: filterConfigDir,
 			ToRebuild:          false,
 			DeclConfig:         filteredDC,
@@ -406,17 +515,7 @@ func (o FilterCollector) filterOperator(ctx context.Context, op v2alpha1.Operato
 	}
 	o.Log.Debug("Catalog has not been filtered previously")
 
-	if err := o.ctlgHandler.EnsureCatalogInOCIFormat(ctx, imgSpec, op.Catalog, imageIndexDir, o.Opts); err != nil {
-		return v2alpha1.CatalogFilterResult{}, err
-	}
-
-	// It's now in oci format so we can go directly to the index.json file
-	dcPath, err := o.ctlgHandler.ExtractOCIConfigLayers(imgSpec, imageIndexDir)
-	if err != nil {
-		return v2alpha1.CatalogFilterResult{}, err
-	}
-
-	originalDC, err := o.ctlgHandler.GetDeclarativeConfig(ctx, dcPath)
+	originalDC, err := o.originalDeclConfig(ctx, op, imgSpec, catalogDigest)
 	if err != nil {
 		return v2alpha1.CatalogFilterResult{}, err
 	}
@@ -432,7 +531,12 @@ func (o FilterCollector) filterOperator(ctx context.Context, op v2alpha1.Operato
 		}, nil
 	}
 
-	filteredDC, err := filterCatalog(ctx, *originalDC, op)
+	resolvedOp, err := resolveLatestBundles(op, *originalDC)
+	if err != nil {
+		return v2alpha1.CatalogFilterResult{}, err
+	}
+
+	filteredDC, err := filterCatalog(ctx, *originalDC, resolvedOp)
 	if err != nil {
 		return v2alpha1.CatalogFilterResult{}, err
 	}
@@ -446,9 +550,10 @@ func (o FilterCollector) filterOperator(ctx context.Context, op v2alpha1.Operato
 	if err := saveDeclarativeConfig(*filteredDC, filteredDigestPath); err != nil {
 		return v2alpha1.CatalogFilterResult{}, err
 	}
+	o.warnDeprecations(op, filteredDC)
 
 	return v2alpha1.CatalogFilterResult{
-		OperatorFilter:     op,
+		OperatorFilter:     resolvedOp,
 		FilteredConfigPath: filteredDigestPath,
 		ToRebuild:          true,
 		DeclConfig:         filteredDC,
@@ -456,6 +561,209 @@ func (o FilterCollector) filterOperator(ctx context.Context, op v2alpha1.Operato
 	}, nil
 }
 
+// originalDeclConfig returns the declarative config of the unfiltered catalog
+func (o FilterCollector) originalDeclConfig(ctx context.Context, op v2alpha1.Operator, imgSpec image.ImageSpec, catalogDigest string) (*declcfg.DeclarativeConfig, error) {
+	imageIndexDir := filepath.Join(o.Opts.Global.WorkingDir, operatorCatalogsDir, imgSpec.ComponentName(), catalogDigest)
+	if err := o.ctlgHandler.EnsureCatalogInOCIFormat(ctx, imgSpec, op.Catalog, imageIndexDir, o.Opts); err != nil {
+		return nil, err
+	}
+
+	// It's now in oci format so we can go directly to the index.json file
+	dcPath, err := o.ctlgHandler.ExtractOCIConfigLayers(imgSpec, imageIndexDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.ctlgHandler.GetDeclarativeConfig(ctx, dcPath)
+}
+
+// resolveCrossCatalogDependencies adds to the filtered catalogs the bundles providing the dependencies
+// of the catalogs with crossCatalogDependencies. The filtered catalogs that changed are saved apart
+// from the cached filtered catalogs, which only depend on the filter, so that they are rebuilt.
+func (o FilterCollector) resolveCrossCatalogDependencies(ctx context.Context, collected []filteredOperator) error {
+	o.Log.Debug(collectorPrefix + "resolving dependencies across operator catalogs")
+	scopes := make([]*dependencyScope, len(collected))
+	filteredBundles := make([]sets.Set[string], len(collected))
+	for i, f := range collected {
+		filteredBundles[i] = bundleRefs(f.result.DeclConfig)
+		scopes[i] = &dependencyScope{
+			catalog:  f.op.Catalog,
+			filtered: f.result.DeclConfig,
+			loadOriginal: func() (*declcfg.DeclarativeConfig, error) {
+				return o.originalDeclConfig(ctx, f.op, f.imgSpec, f.result.Digest)
+			},
+			resolve: f.op.CrossCatalogDependencies,
+			full:    isFullCatalog(f.op),
+		}
+	}
+
+	added, err := resolveDependencies(scopes)
+	if err != nil {
+		return fmt.Errorf("unsatisfiable operator dependencies: %w", err)
+	}
+	for _, msg := range added {
+		o.Log.Info("Adding %s", msg)
+	}
+
+	for i, scope := range scopes {
+		if !scope.modified {
+			continue
+		}
+		added := bundleRefs(collected[i].result.DeclConfig).Difference(filteredBundles[i])
+		if err := o.saveWithDependencies(ctx, &collected[i], sets.List(added)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveWithDependencies saves the filtered catalog with the bundles of its dependencies under the
+// filtered catalogs of its catalog, and marks it to rebuild unless it was already built. It is
+// tagged with the digest of its filter and of the bundles added, which are sorted, so that the
+// same tag is found whether the filtered catalog was just filtered or loaded from the working-dir.
+func (o FilterCollector) saveWithDependencies(ctx context.Context, f *filteredOperator, added []string) error {
+	filterDigest, err := o.rebuiltTag(f.op, f.imgSpec, f.result.Digest)
+	if err != nil {
+		return err
+	}
+	depsDigest := fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(append([]string{filterDigest}, added...), "\n"))))[0:32]
+
+	filteredDir := filepath.Join(o.Opts.Global.WorkingDir, operatorCatalogsDir, f.imgSpec.ComponentName(), f.result.Digest, operatorCatalogFilteredDir, depsDigest)
+	configDir := filepath.Join(filteredDir, operatorCatalogConfigDir)
+
+	toRebuild := true
+	if filteredImageDigest, err := os.ReadFile(filepath.Join(filteredDir, "digest")); err == nil {
+		src, err := o.cachedCatalog(f.op, depsDigest)
+		if err != nil {
+			return err
+		}
+		toRebuild = !o.isAlreadyFiltered(ctx, src, string(filteredImageDigest))
+	}
+	if toRebuild {
+		if err := folder.CreateFolders(configDir); err != nil {
+			return err
+		}
+		if err := saveDeclarativeConfig(*f.result.DeclConfig, configDir); err != nil {
+			return err
+		}
+	}
+
+	f.result.FilteredConfigPath = configDir
+	f.result.ToRebuild = toRebuild
+	f.rebuiltTag = depsDigest
+	return nil
+}
+
+// bundleRefs returns the package and name of the bundles of dc
+func bundleRefs(dc *declcfg.DeclarativeConfig) sets.Set[string] {
+	refs := sets.New[string]()
+	for _, b := range dc.Bundles {
+		refs.Insert(b.Package + "/" + b.Name)
+	}
+	return refs
+}
+
+// mergedCatalog is the catalog image combining the filtered catalogs of mirror.mergedCatalog
+type mergedCatalog struct {
+	// op describes the merged catalog image, based on the image of the first catalog merged
+	op      v2alpha1.Operator
+	imgSpec image.ImageSpec
+	result  v2alpha1.CatalogFilterResult
+	// digest identifies the merged declarative config, and tags the rebuilt image
+	digest string
+}
+
+// mergeCatalogs combines the filtered catalogs of the merge into a single declarative config,
+// saved under the filtered catalogs of the first one so that its image is the base of the rebuild.
+// The packages of a catalog that are taken from a catalog with a higher precedence are removed
+// from its filtered catalog, and the catalogs merged are no longer rebuilt individually.
+func (o FilterCollector) mergeCatalogs(ctx context.Context, merge v2alpha1.MergedCatalog, collected []filteredOperator) (mergedCatalog, error) {
+	members := make([]int, 0, len(merge.Catalogs))
+	dcs := make([]*declcfg.DeclarativeConfig, 0, len(merge.Catalogs))
+	for _, ctlg := range merge.Catalogs {
+		idx := slices.IndexFunc(collected, func(f filteredOperator) bool { return f.op.Catalog == ctlg })
+		if idx < 0 {
+			return mergedCatalog{}, fmt.Errorf("catalog %q was not collected", ctlg)
+		}
+		members = append(members, idx)
+		dcs = append(dcs, collected[idx].result.DeclConfig)
+	}
+
+	mergedDC, conflicts := mergeDeclConfigs(dcs)
+	for _, conflict := range conflicts {
+		o.Log.Warn("merged catalog %s: %s", merge.TargetCatalog, conflict.describe(merge.Catalogs))
+		for _, i := range conflict.dropped {
+			removePackages(collected[members[i]].result.DeclConfig, conflict.pkg)
+		}
+	}
+	for _, i := range members {
+		collected[i].merged = true
+		collected[i].result.ToRebuild = false
+	}
+
+	// the merged content only depends on the filtered catalogs and their precedence
+	memberTags := make([]string, 0, len(members))
+	for _, i := range members {
+		tag, err := o.filteredTag(collected[i])
+		if err != nil {
+			return mergedCatalog{}, err
+		}
+		memberTags = append(memberTags, collected[i].op.Catalog+"@"+collected[i].result.Digest+":"+tag)
+	}
+	mergeDigest := fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(memberTags, "\n"))))[0:32]
+
+	targetTag := merge.TargetTag
+	if targetTag == "" {
+		targetTag = "latest"
+	}
+	first := collected[members[0]]
+	op := v2alpha1.Operator{Catalog: first.op.Catalog, TargetCatalog: merge.TargetCatalog, TargetTag: targetTag}
+
+	filteredDir := filepath.Join(o.Opts.Global.WorkingDir, operatorCatalogsDir, first.imgSpec.ComponentName(), first.result.Digest, operatorCatalogFilteredDir, mergeDigest)
+	configDir := filepath.Join(filteredDir, operatorCatalogConfigDir)
+
+	toRebuild := true
+	if filteredImageDigest, err := os.ReadFile(filepath.Join(filteredDir, "digest")); err == nil {
+		src, err := o.cachedCatalog(op, mergeDigest)
+		if err != nil {
+			return mergedCatalog{}, err
+		}
+		toRebuild = !o.isAlreadyFiltered(ctx, src, string(filteredImageDigest))
+	}
+	if toRebuild {
+		if err := folder.CreateFolders(configDir); err != nil {
+			return mergedCatalog{}, err
+		}
+		if err := saveDeclarativeConfig(*mergedDC, configDir); err != nil {
+			return mergedCatalog{}, err
+		}
+	}
+
+	return mergedCatalog{
+		op:      op,
+		imgSpec: first.imgSpec,
+		result: v2alpha1.CatalogFilterResult{
+			OperatorFilter:     first.result.OperatorFilter,
+			FilteredConfigPath: configDir,
+			ToRebuild:          toRebuild,
+			DeclConfig:         mergedDC,
+			Digest:             first.result.Digest,
+		},
+		digest: mergeDigest,
+	}, nil
+}
+
+// warnDeprecations logs the deprecated content kept in a filtered catalog,
+// when the operator filter sets a deprecation policy
+func (o FilterCollector) warnDeprecations(op v2alpha1.Operator, dc *declcfg.DeclarativeConfig) {
+	if op.Deprecations == "" {
+		return
+	}
+	for _, warning := range deprecationWarnings(*dc) {
+		o.Log.Warn("catalog %s: %s", op.Catalog, warning)
+	}
+}
+
 func TagRebuiltCatalogByDigestOnly(collectorSchema *v2alpha1.CollectorSchema, localStorageFQDN, workingDir string) {
 	for k, img := range collectorSchema.AllImages {
 		if img.RebuiltTag == "" || !img.Type.IsOperatorCatalog() || strings.Contains(img.Destination, localStorageFQDN) {
@@ -1203,3 +1203,189 @@ func TestFindFilterDigest(t *testing.T) {
 		assert.Equal(t, normalizedDigest, result)
 	})
 }
+
+func TestDigestOfFilter(t *testing.T) {
+	op := v2alpha1.Operator{
+		Catalog: "registry.redhat.io/redhat/redhat-operator-index:v4.14",
+		IncludeConfig: v2alpha1.IncludeConfig{
+			Packages: []v2alpha1.IncludePackage{{Name: "op1"}},
+		},
+	}
+	filterDigest, err := digestOfFilter(op, "abc123")
+	assert.NoError(t, err)
+
+	type testCase struct {
+		caseName string
+		update   func(op *v2alpha1.Operator)
+	}
+	testCases := []testCase{
+		{
+			caseName: "target catalog source template",
+			update:   func(op *v2alpha1.Operator) { op.TargetCatalogSourceTemplate = "/tmp/template.yaml" },
+		},
+		{
+			caseName: "catalog source",
+			update: func(op *v2alpha1.Operator) {
+				op.CatalogSource = &v2alpha1.CatalogSourceOptions{DisplayName: "Red Hat Operators", Priority: 10}
+			},
+		},
+		{
+			caseName: "cluster catalog",
+			update: func(op *v2alpha1.Operator) {
+				op.ClusterCatalog = &v2alpha1.ClusterCatalogOptions{Priority: 10, Labels: map[string]string{"env": "prod"}}
+			},
+		},
+		{
+			caseName: "install manifests",
+			update: func(op *v2alpha1.Operator) {
+				op.GenerateInstallManifests = true
+				op.Packages = []v2alpha1.IncludePackage{{Name: "op1", InstallPlanApproval: "Automatic"}}
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.caseName+" does not change the filter", func(t *testing.T) {
+			updated := op
+			tc.update(&updated)
+			digest, err := digestOfFilter(updated, "abc123")
+			assert.NoError(t, err)
+			assert.Equal(t, filterDigest, digest)
+		})
+	}
+}
+
+func TestSaveWithDependencies(t *testing.T) {
+	log := clog.New("debug")
+	const catalogDigest = "abc123"
+	op := v2alpha1.Operator{Catalog: "registry.redhat.io/redhat/redhat-operator-index:v4.14"}
+	imgSpec, err := image.ParseRef(op.Catalog)
+	assert.NoError(t, err)
+
+	withDependency := func(dir string) filteredOperator {
+		return filteredOperator{
+			op:      op,
+			imgSpec: imgSpec,
+			result: v2alpha1.CatalogFilterResult{
+				FilteredConfigPath: dir,
+				DeclConfig: &declcfg.DeclarativeConfig{
+					Packages:     []declcfg.Package{{Schema: declcfg.SchemaPackage, Name: "foo"}, {Schema: declcfg.SchemaPackage, Name: "bar"}},
+					Bundles:      []declcfg.Bundle{{Schema: declcfg.SchemaBundle, Package: "bar", Name: "bar.v1.0.0", Image: "quay.io/example/bar-bundle:v1.0.0"}},
+					Deprecations: []declcfg.Deprecation{},
+				},
+				Digest: catalogDigest,
+			},
+		}
+	}
+	added := []string{"bar/bar.v1.0.0"}
+
+	t.Run("keeps the cached filtered catalog", func(t *testing.T) {
+		tempDir := t.TempDir()
+		ex := setupFilterCollector_MirrorToDisk(tempDir, log, &MockManifest{})
+		cachedDir := filepath.Join(tempDir, "working-dir", operatorCatalogsDir, imgSpec.ComponentName(), catalogDigest, operatorCatalogFilteredDir, "filterdigest", operatorCatalogConfigDir)
+		assert.NoError(t, os.MkdirAll(cachedDir, 0o755))
+		assert.NoError(t, saveDeclarativeConfig(declcfg.DeclarativeConfig{Packages: []declcfg.Package{{Schema: declcfg.SchemaPackage, Name: "foo"}}}, cachedDir))
+
+		f := withDependency(cachedDir)
+		assert.NoError(t, ex.saveWithDependencies(t.Context(), &f, added))
+		assert.True(t, f.result.ToRebuild)
+		assert.NotEmpty(t, f.rebuiltTag)
+		assert.Equal(t, filepath.Join(filepath.Dir(filepath.Dir(cachedDir)), f.rebuiltTag, operatorCatalogConfigDir), f.result.FilteredConfigPath)
+
+		cached, err := declcfg.LoadFS(t.Context(), os.DirFS(cachedDir))
+		assert.NoError(t, err)
+		assert.Len(t, cached.Packages, 1, "the cached filtered catalog must not get the dependencies")
+		saved, err := declcfg.LoadFS(t.Context(), os.DirFS(f.result.FilteredConfigPath))
+		assert.NoError(t, err)
+		assert.Len(t, saved.Packages, 2)
+	})
+
+	t.Run("tagged by the dependencies added", func(t *testing.T) {
+		ex := setupFilterCollector_MirrorToDisk(t.TempDir(), log, &MockManifest{})
+		f1 := withDependency("")
+		assert.NoError(t, ex.saveWithDependencies(t.Context(), &f1, added))
+		f2 := withDependency("")
+		assert.NoError(t, ex.saveWithDependencies(t.Context(), &f2, append(added, "baz/baz.v0.1.0")))
+		assert.NotEqual(t, f1.rebuiltTag, f2.rebuiltTag)
+	})
+
+	t.Run("same tag when the filtered catalog is reloaded", func(t *testing.T) {
+		ex := setupFilterCollector_MirrorToDisk(t.TempDir(), log, &MockManifest{})
+		inMemory := withDependency("")
+		assert.NoError(t, ex.saveWithDependencies(t.Context(), &inMemory, added))
+
+		// as in diskToMirror, where the declarative config is loaded from the working-dir
+		reloadedDC, err := declcfg.LoadFS(t.Context(), os.DirFS(inMemory.result.FilteredConfigPath))
+		assert.NoError(t, err)
+		reloaded := withDependency("")
+		reloaded.result.DeclConfig = reloadedDC
+		assert.NoError(t, ex.saveWithDependencies(t.Context(), &reloaded, added))
+		assert.Equal(t, inMemory.rebuiltTag, reloaded.rebuiltTag)
+	})
+
+	t.Run("not rebuilt when already built", func(t *testing.T) {
+		tempDir := t.TempDir()
+		ex := setupFilterCollector_MirrorToDisk(tempDir, log, &MockManifest{})
+		f := withDependency("")
+		assert.NoError(t, ex.saveWithDependencies(t.Context(), &f, added))
+		// the digest of the rebuilt image, as returned by the cache
+		assert.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(f.result.FilteredConfigPath), "digest"), []byte("f30638f60452062aba36a26ee6c036feead2f03b28f2c47f2b0a991e41baebea"), 0o644)) // #nosec G306
+
+		again := withDependency("")
+		assert.NoError(t, ex.saveWithDependencies(t.Context(), &again, added))
+		assert.False(t, again.result.ToRebuild)
+		assert.Equal(t, f.rebuiltTag, again.rebuiltTag)
+		assert.Equal(t, f.result.FilteredConfigPath, again.result.FilteredConfigPath)
+	})
+}
+
+func TestMergeCatalogsDigest(t *testing.T) {
+	log := clog.New("debug")
+	catalogs := []string{"registry.redhat.io/redhat/certified-operator-index:v4.14", "registry.redhat.io/redhat/redhat-operator-index:v4.14"}
+	merge := v2alpha1.MergedCatalog{TargetCatalog: "my/merged-index", Catalogs: catalogs}
+
+	collect := func(t *testing.T, dcs ...*declcfg.DeclarativeConfig) []filteredOperator {
+		t.Helper()
+		collected := make([]filteredOperator, 0, len(catalogs))
+		for i, ctlg := range catalogs {
+			imgSpec, err := image.ParseRef(ctlg)
+			assert.NoError(t, err)
+			collected = append(collected, filteredOperator{
+				op:         v2alpha1.Operator{Catalog: ctlg},
+				imgSpec:    imgSpec,
+				rebuiltTag: fmt.Sprintf("filterdigest%d", i),
+				result:     v2alpha1.CatalogFilterResult{DeclConfig: dcs[i], Digest: fmt.Sprintf("catalogdigest%d", i)},
+			})
+		}
+		return collected
+	}
+
+	foo := newSelectionCatalog(t, "foo", map[string][]string{"stable": {"1.0.0"}})
+	foo.Deprecations = []declcfg.Deprecation{}
+	bar := newSelectionCatalog(t, "bar", map[string][]string{"stable": {"2.0.0"}})
+	bar.Deprecations = []declcfg.Deprecation{}
+
+	ex := setupFilterCollector_MirrorToDisk(t.TempDir(), log, &MockManifest{})
+	inMemory, err := ex.mergeCatalogs(t.Context(), merge, collect(t, &foo, &bar))
+	assert.NoError(t, err)
+
+	// as in diskToMirror, where the filtered catalogs are loaded from the working-dir
+	reloaded := make([]*declcfg.DeclarativeConfig, 0, 2)
+	for _, dc := range []declcfg.DeclarativeConfig{foo, bar} {
+		dir := t.TempDir()
+		assert.NoError(t, saveDeclarativeConfig(dc, dir))
+		loaded, err := declcfg.LoadFS(t.Context(), os.DirFS(dir))
+		assert.NoError(t, err)
+		reloaded = append(reloaded, loaded)
+	}
+	fromDisk, err := ex.mergeCatalogs(t.Context(), merge, collect(t, reloaded...))
+	assert.NoError(t, err)
+	assert.Equal(t, inMemory.digest, fromDisk.digest)
+	assert.Equal(t, inMemory.result.FilteredConfigPath, fromDisk.result.FilteredConfigPath)
+
+	t.Run("changes with the precedence", func(t *testing.T) {
+		reversed := v2alpha1.MergedCatalog{TargetCatalog: merge.TargetCatalog, Catalogs: []string{catalogs[1], catalogs[0]}}
+		m, err := ex.mergeCatalogs(t.Context(), reversed, collect(t, &foo, &bar))
+		assert.NoError(t, err)
+		assert.NotEqual(t, inMemory.digest, m.digest)
+	})
+}
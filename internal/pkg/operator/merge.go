@@ -0,0 +1,90 @@
+package operator
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+)
+
+// packageConflict is a package found in several merged catalogs
+type packageConflict struct {
+	pkg string
+	// kept is the index of the catalog the package is taken from
+	kept int
+	// dropped holds the indexes of the catalogs the package is removed from
+	dropped []int
+}
+
+// mergeDeclConfigs combines declarative configs given in precedence order into a single one:
+// a package found in several of them is taken, with all its channels, bundles and
+// deprecations, from the first config that has it.
+func mergeDeclConfigs(dcs []*declcfg.DeclarativeConfig) (*declcfg.DeclarativeConfig, []packageConflict) {
+	merged := &declcfg.DeclarativeConfig{}
+	owners := map[string]int{}
+	conflicts := []packageConflict{}
+	for i, dc := range dcs {
+		for _, pkg := range dc.Packages {
+			owner, ok := owners[pkg.Name]
+			if !ok {
+				owners[pkg.Name] = i
+				continue
+			}
+			idx := slices.IndexFunc(conflicts, func(c packageConflict) bool { return c.pkg == pkg.Name })
+			if idx < 0 {
+				conflicts = append(conflicts, packageConflict{pkg: pkg.Name, kept: owner})
+				idx = len(conflicts) - 1
+			}
+			conflicts[idx].dropped = append(conflicts[idx].dropped, i)
+		}
+	}
+
+	for i, dc := range dcs {
+		owned := func(pkg string) bool { return owners[pkg] == i }
+		for _, pkg := range dc.Packages {
+			if owned(pkg.Name) {
+				merged.Packages = append(merged.Packages, pkg)
+			}
+		}
+		for _, ch := range dc.Channels {
+			if owned(ch.Package) {
+				merged.Channels = append(merged.Channels, ch)
+			}
+		}
+		for _, b := range dc.Bundles {
+			if owned(b.Package) {
+				merged.Bundles = append(merged.Bundles, b)
+			}
+		}
+		for _, d := range dc.Deprecations {
+			if owned(d.Package) {
+				merged.Deprecations = append(merged.Deprecations, d)
+			}
+		}
+		for _, meta := range dc.Others {
+			// blobs that are not scoped to a package are taken from the first catalog
+			if (meta.Package == "" && i == 0) || (meta.Package != "" && owned(meta.Package)) {
+				merged.Others = append(merged.Others, meta)
+			}
+		}
+	}
+	return merged, conflicts
+}
+
+// removePackages removes the packages, with all their content, from dc
+func removePackages(dc *declcfg.DeclarativeConfig, pkgs ...string) {
+	dc.Packages = slices.DeleteFunc(dc.Packages, func(p declcfg.Package) bool { return slices.Contains(pkgs, p.Name) })
+	dc.Channels = slices.DeleteFunc(dc.Channels, func(c declcfg.Channel) bool { return slices.Contains(pkgs, c.Package) })
+	dc.Bundles = slices.DeleteFunc(dc.Bundles, func(b declcfg.Bundle) bool { return slices.Contains(pkgs, b.Package) })
+	dc.Deprecations = slices.DeleteFunc(dc.Deprecations, func(d declcfg.Deprecation) bool { return slices.Contains(pkgs, d.Package) })
+	dc.Others = slices.DeleteFunc(dc.Others, func(m declcfg.Meta) bool { return m.Package != "" && slices.Contains(pkgs, m.Package) })
+}
+
+// describe describes the conflict using the catalog references of the merge
+func (c packageConflict) describe(catalogs []string) string {
+	dropped := make([]string, 0, len(c.dropped))
+	for _, i := range c.dropped {
+		dropped = append(dropped, catalogs[i])
+	}
+	return fmt.Sprintf("package %s is taken from catalog %s, ignoring catalogs %v", c.pkg, catalogs[c.kept], dropped)
+}
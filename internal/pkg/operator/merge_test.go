@@ -0,0 +1,59 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeDeclConfigs(t *testing.T) {
+	t.Run("should take conflicting packages from the catalog with the highest precedence", func(t *testing.T) {
+		dcs := catalogsToMerge(t)
+		merged, conflicts := mergeDeclConfigs(dcs)
+
+		assert.Equal(t, []packageConflict{{pkg: "foo", kept: 0, dropped: []int{1}}}, conflicts)
+		assert.Equal(t, []string{"foo.v2.0.0", "foo.v2.1.0", "bar.v1.0.0", "baz.v0.1.0"}, bundleNames(merged))
+		require.Len(t, merged.Packages, 3)
+		require.Len(t, merged.Channels, 3)
+		assert.Equal(t, "foo", merged.Channels[0].Package)
+		assert.Len(t, merged.Channels[0].Entries, 2)
+		assert.Len(t, merged.Deprecations, 1)
+		assert.Len(t, merged.Others, 2)
+	})
+
+	t.Run("should keep the catalog merged untouched", func(t *testing.T) {
+		dcs := catalogsToMerge(t)
+		merged, conflicts := mergeDeclConfigs(dcs)
+		for _, c := range conflicts {
+			for _, i := range c.dropped {
+				removePackages(dcs[i], c.pkg)
+			}
+		}
+
+		assert.Equal(t, []string{"bar.v1.0.0"}, bundleNames(dcs[1]))
+		assert.Len(t, merged.Bundles, 4)
+		assert.Equal(t,
+			"package foo is taken from catalog redhat, ignoring catalogs [certified]",
+			conflicts[0].describe([]string{"redhat", "certified", "community"}),
+		)
+	})
+}
+
+// catalogsToMerge returns a redhat, a certified and a community catalog, the foo package being in the first two.
+func catalogsToMerge(t *testing.T) []*declcfg.DeclarativeConfig {
+	t.Helper()
+	certifiedFoo := newSelectionCatalog(t, "foo", map[string][]string{"stable": {"1.0.0"}})
+	certifiedBar := newSelectionCatalog(t, "bar", map[string][]string{"stable": {"1.0.0"}})
+	certified := declcfg.DeclarativeConfig{
+		Packages: append(certifiedFoo.Packages, certifiedBar.Packages...),
+		Channels: append(certifiedFoo.Channels, certifiedBar.Channels...),
+		Bundles:  append(certifiedFoo.Bundles, certifiedBar.Bundles...),
+	}
+	redhat := newSelectionCatalog(t, "foo", map[string][]string{"stable": {"2.0.0", "2.1.0"}})
+	redhat.Deprecations = []declcfg.Deprecation{{Schema: declcfg.SchemaDeprecation, Package: "foo"}}
+	redhat.Others = []declcfg.Meta{{Schema: "olm.example", Package: "foo"}, {Schema: "olm.example"}}
+	community := newSelectionCatalog(t, "baz", map[string][]string{"stable": {"0.1.0"}})
+	return []*declcfg.DeclarativeConfig{&redhat, &certified, &community}
+}
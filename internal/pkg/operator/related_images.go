@@ -0,0 +1,76 @@
+package operator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+// relatedImageExclusion is an excluded related image of the configuration, with its compiled pattern
+type relatedImageExclusion struct {
+	v2alpha1.ExcludedRelatedImage
+	pattern *regexp.Regexp
+}
+
+// relatedImageExclusions returns the related image exclusions of the operator, by package
+func relatedImageExclusions(op v2alpha1.Operator) (map[string][]relatedImageExclusion, error) {
+	exclusions := map[string][]relatedImageExclusion{}
+	for _, pkg := range op.Packages {
+		for _, excluded := range pkg.ExcludeRelatedImages {
+			exclusion := relatedImageExclusion{ExcludedRelatedImage: excluded}
+			if excluded.Pattern != "" {
+				pattern, err := regexp.Compile(excluded.Pattern)
+				if err != nil {
+					return nil, fmt.Errorf("operator %q: invalid excludeRelatedImages pattern %q: %w", pkg.Name, excluded.Pattern, err)
+				}
+				exclusion.pattern = pattern
+			}
+			exclusions[pkg.Name] = append(exclusions[pkg.Name], exclusion)
+		}
+	}
+	return exclusions, nil
+}
+
+// excludes reports whether the related image ri of bundle is excluded
+func (e relatedImageExclusion) excludes(bundle declcfg.Bundle, ri declcfg.RelatedImage) bool {
+	if len(e.Bundles) > 0 && !slices.Contains(e.Bundles, bundle.Name) {
+		return false
+	}
+	if e.Name != "" && e.Name != ri.Name {
+		return false
+	}
+	if e.pattern != nil && !e.pattern.MatchString(ri.Image) {
+		return false
+	}
+	return true
+}
+
+// withoutExcludedRelatedImages returns a copy of dc where the excluded related images are removed
+// from the bundles, along with the references of the images removed.
+// The bundle images themselves are never excluded.
+func withoutExcludedRelatedImages(dc *declcfg.DeclarativeConfig, exclusions map[string][]relatedImageExclusion) (*declcfg.DeclarativeConfig, []string) {
+	if len(exclusions) == 0 {
+		return dc, nil
+	}
+	excludedImages := []string{}
+	filtered := *dc
+	filtered.Bundles = make([]declcfg.Bundle, 0, len(dc.Bundles))
+	for _, bundle := range dc.Bundles {
+		pkgExclusions := exclusions[bundle.Package]
+		if len(pkgExclusions) > 0 {
+			bundle.RelatedImages = slices.DeleteFunc(slices.Clone(bundle.RelatedImages), func(ri declcfg.RelatedImage) bool {
+				excluded := ri.Image != bundle.Image && slices.ContainsFunc(pkgExclusions, func(e relatedImageExclusion) bool { return e.excludes(bundle, ri) })
+				if excluded {
+					excludedImages = append(excludedImages, ri.Image)
+				}
+				return excluded
+			})
+		}
+		filtered.Bundles = append(filtered.Bundles, bundle)
+	}
+	return &filtered, excludedImages
+}
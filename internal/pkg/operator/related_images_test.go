@@ -0,0 +1,76 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+)
+
+func TestWithoutExcludedRelatedImages(t *testing.T) {
+	dc := &declcfg.DeclarativeConfig{Bundles: []declcfg.Bundle{relatedImagesBundle("v1"), relatedImagesBundle("v2")}}
+	op := v2alpha1.Operator{IncludeConfig: v2alpha1.IncludeConfig{Packages: []v2alpha1.IncludePackage{{
+		Name: "foo",
+		ExcludeRelatedImages: []v2alpha1.ExcludedRelatedImage{
+			{Name: "must-gather"},
+			{Pattern: `/foo-gpu:`, Bundles: []string{"v2"}},
+			// the bundle image is always mirrored
+			{Pattern: `foo-bundle`},
+		},
+	}}}}
+
+	exclusions, err := relatedImageExclusions(op)
+	require.NoError(t, err)
+	filtered, excluded := withoutExcludedRelatedImages(dc, exclusions)
+
+	assert.Equal(t, []string{
+		"quay.io/example/foo-must-gather:v1",
+		"quay.io/example/foo-must-gather:v2",
+		"quay.io/example/foo-gpu:v2",
+	}, excluded)
+	relatedImageNames := func(b declcfg.Bundle) []string {
+		names := []string{}
+		for _, ri := range b.RelatedImages {
+			names = append(names, ri.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"", "operator", "gpu"}, relatedImageNames(filtered.Bundles[0]))
+	assert.Equal(t, []string{"", "operator"}, relatedImageNames(filtered.Bundles[1]))
+	// the declarative config of the catalog is left untouched
+	assert.Len(t, dc.Bundles[1].RelatedImages, 4)
+
+	t.Run("excluded images should not be tracked as images of the bundles", func(t *testing.T) {
+		handler := &CatalogHandler{Log: clog.New("debug")}
+		copyImageSchemaMap := &v2alpha1.CopyImageSchemaMap{OperatorsByImage: map[string]map[string]struct{}{}, BundlesByImage: map[string]map[string]string{}}
+		_, err := handler.getRelatedImagesFromCatalog(filtered, copyImageSchemaMap)
+		require.NoError(t, err)
+		assert.Contains(t, copyImageSchemaMap.BundlesByImage, "docker://quay.io/example/foo-gpu:v1")
+		assert.NotContains(t, copyImageSchemaMap.BundlesByImage, "docker://quay.io/example/foo-gpu:v2")
+		assert.NotContains(t, copyImageSchemaMap.BundlesByImage, "docker://quay.io/example/foo-must-gather:v1")
+	})
+
+	t.Run("should fail on an invalid pattern", func(t *testing.T) {
+		op.Packages[0].ExcludeRelatedImages = []v2alpha1.ExcludedRelatedImage{{Pattern: "foo-("}}
+		_, err := relatedImageExclusions(op)
+		assert.ErrorContains(t, err, `operator "foo": invalid excludeRelatedImages pattern "foo-("`)
+	})
+}
+
+func relatedImagesBundle(name string) declcfg.Bundle {
+	return declcfg.Bundle{
+		Name:    name,
+		Package: "foo",
+		Image:   "quay.io/example/foo-bundle:" + name,
+		RelatedImages: []declcfg.RelatedImage{
+			{Name: "", Image: "quay.io/example/foo-bundle:" + name},
+			{Name: "operator", Image: "quay.io/example/foo:" + name},
+			{Name: "must-gather", Image: "quay.io/example/foo-must-gather:" + name},
+			{Name: "gpu", Image: "quay.io/example/foo-gpu:" + name},
+		},
+	}
+}
@@ -0,0 +1,280 @@
+package operator
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/operator-framework/operator-registry/alpha/template"
+	orimage "github.com/operator-framework/operator-registry/pkg/image"
+	"github.com/operator-framework/operator-registry/pkg/image/containersimageregistry"
+	orregistry "github.com/operator-framework/operator-registry/pkg/registry"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/imagebuilder"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+const (
+	operatorCatalogTemplatesDir string = "templates"
+	// bundlePackageLabel is the label identifying the bundle images
+	bundlePackageLabel = "operators.operatorframework.io.bundle.package.v1"
+)
+
+// BuildTemplateCatalogs renders the FBC templates of the operators configured with a template,
+// and builds their catalog images in OCI layouts of the working directory.
+// It returns a copy of cfg where the Catalog of those operators is the oci:// reference of
+// the built image, so that they are filtered and mirrored like any catalog on disk.
+// In the disk to mirror workflow, the catalogs were built by the mirror to disk workflow
+// and only the references are set. A dry run of the other workflows builds nothing, so it
+// fails rather than leave the images of those catalogs out of the mapping.
+func BuildTemplateCatalogs(ctx context.Context, log clog.PluggableLoggerInterface, cfg v2alpha1.ImageSetConfiguration, opts mirror.CopyOptions, builder imagebuilder.CatalogBuilderInterface) (v2alpha1.ImageSetConfiguration, error) {
+	if !slices.ContainsFunc(cfg.Mirror.Operators, func(op v2alpha1.Operator) bool { return op.Template != "" }) {
+		return cfg, nil
+	}
+	if opts.IsDryRun && !opts.IsDiskToMirror() {
+		var templates []string
+		for _, op := range cfg.Mirror.Operators {
+			if op.Template != "" {
+				templates = append(templates, fmt.Sprintf("%s (template %s)", op.TargetCatalog, op.Template))
+			}
+		}
+		return cfg, fmt.Errorf("a dry run does not build the catalogs from templates, and cannot list their images: %s", strings.Join(templates, ", "))
+	}
+	// avoid mutating the operators of the original configuration
+	cfg.Mirror.Operators = slices.Clone(cfg.Mirror.Operators)
+
+	var renderBundle template.BundleRenderer
+	if !opts.IsDiskToMirror() {
+		sysCtx, err := opts.SrcImage.NewSystemContext()
+		if err != nil {
+			return cfg, err
+		}
+		reg, err := containersimageregistry.New(sysCtx, containersimageregistry.WithTemporaryImageCache())
+		if err != nil {
+			return cfg, fmt.Errorf("error creating the registry rendering bundles: %w", err)
+		}
+		defer func() {
+			if err := reg.Destroy(); err != nil {
+				log.Warn("error removing the bundle rendering cache: %v", err)
+			}
+		}()
+		renderBundle = func(ctx context.Context, ref string) (*declcfg.DeclarativeConfig, error) {
+			return renderBundleImage(ctx, reg, ref)
+		}
+	}
+
+	for i := range cfg.Mirror.Operators {
+		op := &cfg.Mirror.Operators[i]
+		if op.Template == "" {
+			continue
+		}
+		configDir, layoutDir := templateCatalogDirs(opts.Global.WorkingDir, op.TargetCatalog)
+		if renderBundle != nil {
+			log.Info("building catalog %s from template %s", op.TargetCatalog, op.Template)
+			dc, err := renderCatalogTemplate(ctx, op.Template, renderBundle)
+			if err != nil {
+				return cfg, fmt.Errorf("error rendering template %s: %w", op.Template, err)
+			}
+			if err := os.RemoveAll(configDir); err != nil {
+				return cfg, err
+			}
+			if err := saveDeclarativeConfig(*dc, configDir); err != nil {
+				return cfg, err
+			}
+			if err := builder.BuildCatalogLayout(ctx, op.TargetCatalogBaseImage, configDir, layoutDir); err != nil {
+				return cfg, fmt.Errorf("error building catalog %s from template %s: %w", op.TargetCatalog, op.Template, err)
+			}
+		}
+		op.Catalog = consts.OciProtocol + layoutDir
+	}
+	return cfg, nil
+}
+
+// templateCatalogDirs returns the directories of the declarative config rendered from
+// the template of targetCatalog and of the OCI layout of its catalog image
+func templateCatalogDirs(workingDir, targetCatalog string) (string, string) {
+	name := strings.ReplaceAll(targetCatalog, "/", "-")
+	templatesDir := filepath.Join(workingDir, operatorCatalogsDir, operatorCatalogTemplatesDir)
+	return filepath.Join(templatesDir, operatorCatalogConfigDir, name), filepath.Join(templatesDir, operatorCatalogImageDir, name)
+}
+
+// renderCatalogTemplate renders the template file, or all the yaml and json templates of
+// the directory, into a single declarative config
+func renderCatalogTemplate(ctx context.Context, templatePath string, renderBundle template.BundleRenderer) (*declcfg.DeclarativeConfig, error) {
+	stat, err := os.Stat(templatePath)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{templatePath}
+	if stat.IsDir() {
+		entries, err := os.ReadDir(templatePath)
+		if err != nil {
+			return nil, err
+		}
+		files = []string{}
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml", ".json":
+				if !entry.IsDir() {
+					files = append(files, filepath.Join(templatePath, entry.Name()))
+				}
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no template found in directory %s", templatePath)
+		}
+	}
+
+	registry := template.NewRegistry()
+	rendered := &declcfg.DeclarativeConfig{}
+	for _, file := range files {
+		dc, err := renderTemplateFile(ctx, registry, file, renderBundle)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+		rendered.Merge(dc)
+	}
+	// fail on inconsistent content before building an image opm would not serve
+	if _, err := declcfg.ConvertToModel(*rendered); err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+func renderTemplateFile(ctx context.Context, registry template.Registry, file string, renderBundle template.BundleRenderer) (*declcfg.DeclarativeConfig, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tmpl, reader, err := registry.CreateTemplateBySchema(f, renderBundle)
+	if err != nil {
+		return nil, err
+	}
+	return tmpl.Render(ctx, reader)
+}
+
+// renderBundleImage renders the bundle image ref into a declarative config holding its bundle,
+// as opm render does. The render action of opm is not used: it pulls the dependencies of the
+// sqlite catalogs and of the bundle validation into the binary, which a bundle doesn't need.
+func renderBundleImage(ctx context.Context, reg orimage.Registry, ref string) (*declcfg.DeclarativeConfig, error) {
+	imgRef := orimage.SimpleReference(ref)
+	if err := reg.Pull(ctx, imgRef); err != nil {
+		return nil, fmt.Errorf("failed to pull image %q: %w", ref, err)
+	}
+	labels, err := reg.Labels(ctx, imgRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get labels for image %q: %w", ref, err)
+	}
+	if _, ok := labels[bundlePackageLabel]; !ok {
+		return nil, fmt.Errorf("render %q: not a bundle image", ref)
+	}
+	tmpDir, err := os.MkdirTemp("", "render-unpack-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := reg.Unpack(ctx, imgRef, tmpDir); err != nil {
+		return nil, fmt.Errorf("failed to unpack image %q: %w", ref, err)
+	}
+	img, err := orregistry.NewImageInput(imgRef, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := bundleToDeclcfg(img.Bundle)
+	if err != nil {
+		return nil, err
+	}
+	return &declcfg.DeclarativeConfig{Bundles: []declcfg.Bundle{*bundle}}, nil
+}
+
+// bundleToDeclcfg converts a bundle to its declarative config, with its objects at the end
+// of its properties and its related images sorted, like opm render
+func bundleToDeclcfg(bundle *orregistry.Bundle) (*declcfg.Bundle, error) {
+	objs, props, err := orregistry.ObjectsAndPropertiesFromBundle(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("get properties for bundle %q: %w", bundle.Name, err)
+	}
+	slices.SortStableFunc(props, func(a, b property.Property) int {
+		return cmp.Compare(isBundleObject(a), isBundleObject(b))
+	})
+	relatedImages, err := bundleRelatedImages(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("get related images for bundle %q: %w", bundle.Name, err)
+	}
+
+	var csvJSON []byte
+	for _, obj := range bundle.Objects {
+		if obj.GetKind() == "ClusterServiceVersion" {
+			csvJSON, err = json.Marshal(obj)
+			if err != nil {
+				return nil, fmt.Errorf("marshal CSV JSON for bundle %q: %w", bundle.Name, err)
+			}
+		}
+	}
+
+	return &declcfg.Bundle{
+		Schema:        declcfg.SchemaBundle,
+		Name:          bundle.Name,
+		Package:       bundle.Package,
+		Image:         bundle.BundleImage,
+		Properties:    props,
+		RelatedImages: relatedImages,
+		Objects:       objs,
+		CsvJSON:       string(csvJSON),
+	}, nil
+}
+
+func isBundleObject(p property.Property) int {
+	if p.Type == property.TypeBundleObject || p.Type == property.TypeCSVMetadata {
+		return 1
+	}
+	return 0
+}
+
+// bundleRelatedImages returns the related images of the CSV of the bundle, along with the
+// bundle image and the images of the operator deployments
+func bundleRelatedImages(bundle *orregistry.Bundle) ([]declcfg.RelatedImage, error) {
+	csv, err := bundle.ClusterServiceVersion()
+	if err != nil {
+		return nil, err
+	}
+	var spec struct {
+		RelatedImages []declcfg.RelatedImage `json:"relatedImages"`
+	}
+	if err := json.Unmarshal(csv.Spec, &spec); err != nil {
+		return nil, err
+	}
+	relatedImages := spec.RelatedImages
+	images := sets.New[string]()
+	for _, ri := range relatedImages {
+		images.Insert(ri.Image)
+	}
+	if bundle.BundleImage != "" && !images.Has(bundle.BundleImage) {
+		relatedImages = append(relatedImages, declcfg.RelatedImage{Image: bundle.BundleImage})
+		images.Insert(bundle.BundleImage)
+	}
+	operatorImages, err := csv.GetOperatorImages()
+	if err != nil {
+		return nil, err
+	}
+	for img := range operatorImages {
+		if !images.Has(img) {
+			relatedImages = append(relatedImages, declcfg.RelatedImage{Image: img})
+			images.Insert(img)
+		}
+	}
+	slices.SortFunc(relatedImages, func(a, b declcfg.RelatedImage) int { return cmp.Compare(a.Image, b.Image) })
+	return relatedImages, nil
+}
@@ -0,0 +1,179 @@
+package operator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/operator-framework/operator-registry/alpha/declcfg"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	orregistry "github.com/operator-framework/operator-registry/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/imagebuilder"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+const basicTemplate = `schema: olm.template.basic
+entries:
+  - schema: olm.package
+    name: foo
+    defaultChannel: stable
+  - schema: olm.channel
+    package: foo
+    name: stable
+    entries:
+      - name: foo.v1.0.0
+  - schema: olm.bundle
+    image: quay.io/example/foo-bundle:v1.0.0
+`
+
+const semverTemplate = `schema: olm.semver
+generateMajorChannels: true
+stable:
+  bundles:
+    - image: quay.io/example/bar-bundle:v1.0.0
+    - image: quay.io/example/bar-bundle:v1.1.0
+`
+
+// fakeRenderBundle renders quay.io/example/<pkg>-bundle:v<version> without pulling it
+func fakeRenderBundle(_ context.Context, ref string) (*declcfg.DeclarativeConfig, error) {
+	repo, tag, _ := strings.Cut(filepath.Base(ref), ":")
+	pkg := strings.TrimSuffix(repo, "-bundle")
+	return &declcfg.DeclarativeConfig{Bundles: []declcfg.Bundle{{
+		Schema:     declcfg.SchemaBundle,
+		Package:    pkg,
+		Name:       pkg + "." + tag,
+		Image:      ref,
+		Properties: []property.Property{property.MustBuildPackage(pkg, strings.TrimPrefix(tag, "v"))},
+	}}}, nil
+}
+
+type fakeCatalogBuilder struct {
+	imagebuilder.CatalogBuilderInterface
+	built map[string]string
+}
+
+func (f *fakeCatalogBuilder) BuildCatalogLayout(_ context.Context, baseImage, configPath, layoutDir string) error {
+	if _, err := os.Stat(configPath); err != nil {
+		return err
+	}
+	f.built[layoutDir] = baseImage
+	return nil
+}
+
+func TestRenderCatalogTemplate(t *testing.T) {
+	templatesDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(templatesDir, "foo.yaml"), []byte(basicTemplate), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(templatesDir, "bar.yaml"), []byte(semverTemplate), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(templatesDir, "README.md"), []byte("# templates"), 0o600))
+
+	t.Run("should render a template file", func(t *testing.T) {
+		dc, err := renderCatalogTemplate(t.Context(), filepath.Join(templatesDir, "foo.yaml"), fakeRenderBundle)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foo.v1.0.0"}, bundleNames(dc))
+	})
+
+	t.Run("should render all the templates of a directory", func(t *testing.T) {
+		dc, err := renderCatalogTemplate(t.Context(), templatesDir, fakeRenderBundle)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"foo.v1.0.0", "bar.v1.0.0", "bar.v1.1.0"}, bundleNames(dc))
+		assert.Len(t, dc.Packages, 2)
+	})
+
+	t.Run("should fail on a directory without templates", func(t *testing.T) {
+		_, err := renderCatalogTemplate(t.Context(), t.TempDir(), fakeRenderBundle)
+		assert.ErrorContains(t, err, "no template found in directory")
+	})
+
+	t.Run("should fail on an unknown template schema", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "unknown.yaml")
+		require.NoError(t, os.WriteFile(file, []byte("schema: olm.unknown\n"), 0o600))
+		_, err := renderCatalogTemplate(t.Context(), file, fakeRenderBundle)
+		assert.ErrorContains(t, err, "unknown.yaml")
+	})
+}
+
+func TestBuildTemplateCatalogs(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "foo.yaml")
+	require.NoError(t, os.WriteFile(templateFile, []byte(basicTemplate), 0o600))
+	cfg := v2alpha1.ImageSetConfiguration{}
+	cfg.Mirror.Operators = []v2alpha1.Operator{
+		{Catalog: "quay.io/example/catalog:latest"},
+		{Template: templateFile, TargetCatalog: "internal/foo-catalog", TargetCatalogBaseImage: "quay.io/operator-framework/opm:latest"},
+	}
+	workingDir := t.TempDir()
+	_, layoutDir := templateCatalogDirs(workingDir, "internal/foo-catalog")
+
+	t.Run("should only set the catalog reference in the disk to mirror workflow", func(t *testing.T) {
+		opts := mirror.CopyOptions{Mode: mirror.DiskToMirror, Global: &mirror.GlobalOptions{WorkingDir: workingDir}}
+		builder := &fakeCatalogBuilder{built: map[string]string{}}
+		built, err := BuildTemplateCatalogs(t.Context(), clog.New("debug"), cfg, opts, builder)
+		require.NoError(t, err)
+		assert.Equal(t, "quay.io/example/catalog:latest", built.Mirror.Operators[0].Catalog)
+		assert.Equal(t, "oci://"+layoutDir, built.Mirror.Operators[1].Catalog)
+		assert.Empty(t, cfg.Mirror.Operators[1].Catalog)
+		assert.Empty(t, builder.built)
+	})
+
+	t.Run("should fail a dry run rather than leave out the catalogs", func(t *testing.T) {
+		opts := mirror.CopyOptions{Mode: mirror.MirrorToDisk, IsDryRun: true, Global: &mirror.GlobalOptions{WorkingDir: workingDir}}
+		builder := &fakeCatalogBuilder{built: map[string]string{}}
+		_, err := BuildTemplateCatalogs(t.Context(), clog.New("debug"), cfg, opts, builder)
+		require.EqualError(t, err, "a dry run does not build the catalogs from templates, and cannot list their images: "+cfg.Mirror.Operators[1].TargetCatalog+" (template "+cfg.Mirror.Operators[1].Template+")")
+		assert.Empty(t, builder.built)
+	})
+}
+
+const fooCSV = `apiVersion: operators.coreos.com/v1alpha1
+kind: ClusterServiceVersion
+metadata:
+  name: foo.v1.0.0
+spec:
+  version: 1.0.0
+  relatedImages:
+    - name: operand
+      image: quay.io/example/foo-operand:v1.0.0
+  install:
+    strategy: deployment
+    spec:
+      deployments:
+        - name: foo-operator
+          spec:
+            template:
+              spec:
+                containers:
+                  - name: manager
+                    image: quay.io/example/foo-operator:v1.0.0
+`
+
+func TestBundleToDeclcfg(t *testing.T) {
+	var csv unstructured.Unstructured
+	require.NoError(t, yaml.Unmarshal([]byte(fooCSV), &csv.Object))
+	bundle := orregistry.NewBundle("foo.v1.0.0", &orregistry.Annotations{PackageName: "foo", Channels: "stable"}, &csv)
+	bundle.BundleImage = "quay.io/example/foo-bundle:v1.0.0"
+
+	b, err := bundleToDeclcfg(bundle)
+	require.NoError(t, err)
+	assert.Equal(t, "foo.v1.0.0", b.Name)
+	assert.Equal(t, "foo", b.Package)
+	assert.Equal(t, "quay.io/example/foo-bundle:v1.0.0", b.Image)
+	// the related images of the CSV, the bundle image and the operator image, sorted
+	assert.Equal(t, []declcfg.RelatedImage{
+		{Image: "quay.io/example/foo-bundle:v1.0.0"},
+		{Name: "operand", Image: "quay.io/example/foo-operand:v1.0.0"},
+		{Image: "quay.io/example/foo-operator:v1.0.0"},
+	}, b.RelatedImages)
+	assert.Contains(t, b.CsvJSON, `"name":"foo.v1.0.0"`)
+	// the bundle objects are at the end of the properties
+	require.NotEmpty(t, b.Properties)
+	assert.Equal(t, property.TypePackage, b.Properties[0].Type)
+	assert.Equal(t, property.TypeBundleObject, b.Properties[len(b.Properties)-1].Type)
+}
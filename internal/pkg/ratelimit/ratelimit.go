@@ -0,0 +1,261 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.podman.io/image/v5/types"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/image"
+)
+
+// ProgressInterval is the interval of the progress reports the bandwidth of a copy is throttled with.
+const ProgressInterval = 100 * time.Millisecond
+
+// Options are the bandwidth and concurrency limits of the copies, set on the command line.
+type Options struct {
+	// MaxBandwidth caps the bandwidth of all the copies, in bytes per second (e.g. 50Mi)
+	MaxBandwidth string
+	// RegistryMaxBandwidth caps the bandwidth of the copies from or to a registry, in bytes per second
+	RegistryMaxBandwidth map[string]string
+	// RegistryMaxParallelImages caps the number of images copied from or to a registry at the same time
+	RegistryMaxParallelImages map[string]int
+}
+
+// Limiter enforces the limits of the copies between registries.
+// The local cache is never limited. A nil Limiter limits nothing.
+type Limiter struct {
+	localStorageFQDN  string
+	bandwidth         *rate.Limiter
+	registryBandwidth map[string]*rate.Limiter
+	registryParallel  map[string]chan struct{}
+
+	mu      sync.Mutex
+	backoff map[string]time.Time
+}
+
+// Validate checks the limits set on the command line.
+func (o Options) Validate() error {
+	_, err := New(o, "")
+	return err
+}
+
+// New returns the limiter of the copies, nil when no limit is set.
+func New(opts Options, localStorageFQDN string) (*Limiter, error) {
+	if opts.MaxBandwidth == "" && len(opts.RegistryMaxBandwidth) == 0 && len(opts.RegistryMaxParallelImages) == 0 {
+		return nil, nil
+	}
+	l := &Limiter{
+		localStorageFQDN:  localStorageFQDN,
+		registryBandwidth: map[string]*rate.Limiter{},
+		registryParallel:  map[string]chan struct{}{},
+		backoff:           map[string]time.Time{},
+	}
+	if opts.MaxBandwidth != "" {
+		limiter, err := bandwidthLimiter(opts.MaxBandwidth)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --max-bandwidth: %w", err)
+		}
+		l.bandwidth = limiter
+	}
+	for registry, bandwidth := range opts.RegistryMaxBandwidth {
+		if registry == "" {
+			return nil, fmt.Errorf("invalid --registry-max-bandwidth: the registry is missing")
+		}
+		limiter, err := bandwidthLimiter(bandwidth)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --registry-max-bandwidth for %s: %w", registry, err)
+		}
+		l.registryBandwidth[registry] = limiter
+	}
+	for registry, parallel := range opts.RegistryMaxParallelImages {
+		if registry == "" {
+			return nil, fmt.Errorf("invalid --registry-max-parallel-images: the registry is missing")
+		}
+		if parallel < 1 {
+			return nil, fmt.Errorf("invalid --registry-max-parallel-images for %s: %d images, at least 1 is expected", registry, parallel)
+		}
+		l.registryParallel[registry] = make(chan struct{}, parallel)
+	}
+	return l, nil
+}
+
+func bandwidthLimiter(bandwidth string) (*rate.Limiter, error) {
+	q, err := resource.ParseQuantity(bandwidth)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", bandwidth, err)
+	}
+	bytesPerSecond := q.Value()
+	if bytesPerSecond < 1 {
+		return nil, fmt.Errorf("%q: the bandwidth must be positive", bandwidth)
+	}
+	// a burst of a second of traffic
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), int(min(bytesPerSecond, math.MaxInt32))), nil
+}
+
+// registries returns the registries a copy from src to dest talks to, sorted, without the local cache.
+func (l *Limiter) registries(src, dest string) []string {
+	var registries []string
+	for _, ref := range []string{src, dest} {
+		if !strings.HasPrefix(ref, consts.DockerProtocol) {
+			continue
+		}
+		spec, err := image.ParseRef(ref)
+		if err != nil || spec.Domain == "" || spec.Domain == l.localStorageFQDN {
+			continue
+		}
+		if !slices.Contains(registries, spec.Domain) {
+			registries = append(registries, spec.Domain)
+		}
+	}
+	slices.Sort(registries)
+	return registries
+}
+
+// Acquire waits until the copy of an image from src to dest is within the concurrency limits
+// of its registries, and none of them is backed off. It returns the function releasing the copy.
+func (l *Limiter) Acquire(ctx context.Context, src, dest string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	registries := l.registries(src, dest)
+	if err := l.waitBackoff(ctx, registries); err != nil {
+		return nil, err
+	}
+
+	// the slots are always taken in the same order, so that copies between the same registries can't deadlock
+	var acquired []chan struct{}
+	release := func() {
+		for _, slots := range acquired {
+			<-slots
+		}
+	}
+	for _, registry := range registries {
+		slots, ok := l.registryParallel[registry]
+		if !ok {
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+			acquired = append(acquired, slots)
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+// Backoff keeps the copies away from the registries of a copy from src to dest for delay,
+// after they answered that they are overloaded.
+func (l *Limiter) Backoff(src, dest string, delay time.Duration) {
+	if l == nil {
+		return
+	}
+	until := time.Now().Add(delay)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, registry := range l.registries(src, dest) {
+		if until.After(l.backoff[registry]) {
+			l.backoff[registry] = until
+		}
+	}
+}
+
+func (l *Limiter) waitBackoff(ctx context.Context, registries []string) error {
+	for {
+		l.mu.Lock()
+		var until time.Time
+		for _, registry := range registries {
+			if l.backoff[registry].After(until) {
+				until = l.backoff[registry]
+			}
+		}
+		l.mu.Unlock()
+
+		wait := time.Until(until)
+		if wait <= 0 {
+			return nil
+		}
+		select {
+		case <-time.After(wait):
+			// the backoff may have been extended in the meantime
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Throttle returns the progress channel capping the bandwidth of a copy from src to dest,
+// to set with ProgressInterval in its copy options. It returns nil when the copy is not capped.
+// The reads of the copy block on the channel while the bandwidth is exceeded.
+// stop must be called once the copy is done.
+func (l *Limiter) Throttle(ctx context.Context, src, dest string) (progress chan types.ProgressProperties, stop func()) {
+	if l == nil {
+		return nil, func() {}
+	}
+	registries := l.registries(src, dest)
+	var limiters []*rate.Limiter
+	for _, registry := range registries {
+		if limiter, ok := l.registryBandwidth[registry]; ok {
+			limiters = append(limiters, limiter)
+		}
+	}
+	if l.bandwidth != nil && len(registries) > 0 {
+		limiters = append(limiters, l.bandwidth)
+	}
+	if len(limiters) == 0 {
+		return nil, func() {}
+	}
+
+	progress = make(chan types.ProgressProperties)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for p := range progress {
+			// keep draining the channel once cancelled, the copy is unblocked and returns
+			if p.OffsetUpdate > 0 && ctx.Err() == nil {
+				waitBandwidth(ctx, limiters, p.OffsetUpdate)
+			}
+		}
+	}()
+	return progress, func() {
+		close(progress)
+		<-done
+	}
+}
+
+// waitBandwidth waits until all the limiters allow the transfer of n bytes.
+func waitBandwidth(ctx context.Context, limiters []*rate.Limiter, n uint64) {
+	for n > 0 {
+		now := time.Now()
+		var delay time.Duration
+		var reservations []*rate.Reservation
+		chunk := n
+		for _, limiter := range limiters {
+			chunk = min(chunk, uint64(limiter.Burst()))
+		}
+		for _, limiter := range limiters {
+			r := limiter.ReserveN(now, int(chunk))
+			reservations = append(reservations, r)
+			delay = max(delay, r.DelayFrom(now))
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			for _, r := range reservations {
+				r.CancelAt(now)
+			}
+			return
+		}
+		n -= chunk
+	}
+}
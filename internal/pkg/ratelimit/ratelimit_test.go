@@ -0,0 +1,212 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/image/v5/types"
+)
+
+const (
+	quay  = "docker://quay.io/openshift-release-dev/ocp-release:4.18.1-x86_64"
+	cache = "docker://localhost:55000/openshift/release-images:4.18.1-x86_64"
+	local = "docker://mirror.acme.com:5000/openshift/release-images:4.18.1-x86_64"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		limited bool
+		err     string
+	}{
+		{
+			name: "no limit",
+		},
+		{
+			name:    "global bandwidth",
+			opts:    Options{MaxBandwidth: "50Mi"},
+			limited: true,
+		},
+		{
+			name:    "registry limits",
+			opts:    Options{RegistryMaxBandwidth: map[string]string{"quay.io": "20M"}, RegistryMaxParallelImages: map[string]int{"quay.io": 2}},
+			limited: true,
+		},
+		{
+			name: "invalid bandwidth",
+			opts: Options{MaxBandwidth: "fast"},
+			err:  `invalid --max-bandwidth: "fast": quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`,
+		},
+		{
+			name: "zero bandwidth",
+			opts: Options{MaxBandwidth: "0"},
+			err:  `invalid --max-bandwidth: "0": the bandwidth must be positive`,
+		},
+		{
+			name: "invalid registry bandwidth",
+			opts: Options{RegistryMaxBandwidth: map[string]string{"quay.io": "-1Mi"}},
+			err:  `invalid --registry-max-bandwidth for quay.io: "-1Mi": the bandwidth must be positive`,
+		},
+		{
+			name: "registry bandwidth without registry",
+			opts: Options{RegistryMaxBandwidth: map[string]string{"": "1Mi"}},
+			err:  "invalid --registry-max-bandwidth: the registry is missing",
+		},
+		{
+			name: "no parallel image",
+			opts: Options{RegistryMaxParallelImages: map[string]int{"quay.io": 0}},
+			err:  "invalid --registry-max-parallel-images for quay.io: 0 images, at least 1 is expected",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New(tt.opts, "localhost:55000")
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				assert.EqualError(t, tt.opts.Validate(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.limited, l != nil)
+		})
+	}
+}
+
+func TestRegistries(t *testing.T) {
+	l := &Limiter{localStorageFQDN: "localhost:55000"}
+	assert.Equal(t, []string{"mirror.acme.com:5000", "quay.io"}, l.registries(quay, local))
+	assert.Equal(t, []string{"quay.io"}, l.registries(quay, cache))
+	assert.Equal(t, []string{"quay.io"}, l.registries(quay, "oci:///tmp/ocp-release"))
+	assert.Empty(t, l.registries("file:///tmp/ocp-release", cache))
+}
+
+func TestAcquire(t *testing.T) {
+	t.Run("nil limiter", func(t *testing.T) {
+		var l *Limiter
+		release, err := l.Acquire(t.Context(), quay, local)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("registry parallel images", func(t *testing.T) {
+		l, err := New(Options{RegistryMaxParallelImages: map[string]int{"quay.io": 2}}, "localhost:55000")
+		require.NoError(t, err)
+
+		var running, maxRunning atomic.Int32
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				release, err := l.Acquire(t.Context(), quay, cache)
+				assert.NoError(t, err)
+				defer release()
+				n := running.Add(1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				running.Add(-1)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(2), maxRunning.Load())
+
+		// the other registries are not limited
+		release, err := l.Acquire(t.Context(), "docker://registry.redhat.io/ubi9/ubi:latest", local)
+		require.NoError(t, err)
+		release()
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		l, err := New(Options{RegistryMaxParallelImages: map[string]int{"quay.io": 1, "mirror.acme.com:5000": 1}}, "localhost:55000")
+		require.NoError(t, err)
+		release, err := l.Acquire(t.Context(), quay, cache)
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+		_, err = l.Acquire(ctx, quay, local)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		// the slot of mirror.acme.com:5000 taken before waiting for quay.io is given back
+		assert.Empty(t, l.registryParallel["mirror.acme.com:5000"])
+		assert.Len(t, l.registryParallel["quay.io"], 1)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	l, err := New(Options{RegistryMaxParallelImages: map[string]int{"quay.io": 4}}, "localhost:55000")
+	require.NoError(t, err)
+
+	l.Backoff(quay, cache, 200*time.Millisecond)
+	// a shorter backoff doesn't shorten the current one
+	l.Backoff(quay, cache, time.Millisecond)
+
+	start := time.Now()
+	release, err := l.Acquire(t.Context(), quay, local)
+	require.NoError(t, err)
+	release()
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+
+	start = time.Now()
+	release, err = l.Acquire(t.Context(), "docker://registry.redhat.io/ubi9/ubi:latest", local)
+	require.NoError(t, err)
+	release()
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestThrottle(t *testing.T) {
+	t.Run("not capped", func(t *testing.T) {
+		l, err := New(Options{RegistryMaxBandwidth: map[string]string{"quay.io": "1Ki"}}, "localhost:55000")
+		require.NoError(t, err)
+		progress, stop := l.Throttle(t.Context(), "docker://registry.redhat.io/ubi9/ubi:latest", cache)
+		assert.Nil(t, progress)
+		stop()
+	})
+
+	t.Run("registry bandwidth", func(t *testing.T) {
+		l, err := New(Options{MaxBandwidth: "1Mi", RegistryMaxBandwidth: map[string]string{"quay.io": "10Ki"}}, "localhost:55000")
+		require.NoError(t, err)
+		progress, stop := l.Throttle(t.Context(), quay, cache)
+		require.NotNil(t, progress)
+
+		// the first second of traffic is the burst, the next 5Ki take half a second
+		start := time.Now()
+		for range 15 {
+			progress <- types.ProgressProperties{Event: types.ProgressEventRead, OffsetUpdate: 1024}
+		}
+		stop()
+		elapsed := time.Since(start)
+		assert.GreaterOrEqual(t, elapsed, 400*time.Millisecond)
+		assert.Less(t, elapsed, 2*time.Second)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		l, err := New(Options{MaxBandwidth: "1Ki"}, "localhost:55000")
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(t.Context())
+		progress, stop := l.Throttle(ctx, quay, cache)
+		require.NotNil(t, progress)
+
+		progress <- types.ProgressProperties{Event: types.ProgressEventRead, OffsetUpdate: 1024}
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+		start := time.Now()
+		progress <- types.ProgressProperties{Event: types.ProgressEventRead, OffsetUpdate: 1 << 20}
+		progress <- types.ProgressProperties{Event: types.ProgressEventDone, OffsetUpdate: 1 << 20}
+		stop()
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
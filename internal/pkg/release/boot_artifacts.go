@@ -0,0 +1,248 @@
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/otiai10/copy"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+// bootArtifactArchName maps the Go/OCP architecture names used in the
+// ImageSetConfiguration to the keys used in the coreos-bootimages stream.
+// Contrary to kubevirt images, every architecture publishes boot artifacts.
+var bootArtifactArchName = map[string]string{
+	"amd64":   "x86_64",
+	"arm64":   "aarch64",
+	"ppc64le": "ppc64le",
+	"s390x":   "s390x",
+}
+
+// allBootArtifactArchKeys lists the coreos-bootimages stream architecture keys
+// used when the user selects the "multi" payload.
+var allBootArtifactArchKeys = []string{"x86_64", "aarch64", "ppc64le", "s390x"}
+
+// bootArtifact is a single file of the coreos-bootimages stream
+// selected for download.
+type bootArtifact struct {
+	v2alpha1.ArtifactFile
+	Arch     string
+	Format   v2alpha1.BootArtifactFormat
+	Role     string
+	FileName string
+}
+
+// streamFormatFiles returns the files published in the stream for a
+// boot artifact format, keyed by role (disk, kernel, initramfs, rootfs).
+func streamFormatFiles(ai v2alpha1.ArchImages, format v2alpha1.BootArtifactFormat) map[string]v2alpha1.ArtifactFile {
+	switch format {
+	case v2alpha1.BootArtifactISO:
+		return ai.Artifacts.Metal.Formats["iso"]
+	case v2alpha1.BootArtifactPXE:
+		return ai.Artifacts.Metal.Formats["pxe"]
+	case v2alpha1.BootArtifactQCOW2:
+		return ai.Artifacts.Qemu.Formats["qcow2.gz"]
+	case v2alpha1.BootArtifactOVA:
+		return ai.Artifacts.Vmware.Formats["ova"]
+	}
+	return nil
+}
+
+// selectBootArtifacts returns, in a deterministic order, the files of the
+// coreos-bootimages stream matching the requested formats and architectures.
+func (o LocalStorageCollector) selectBootArtifacts(releaseArtifactsDir string) ([]bootArtifact, error) {
+	ibi, err := loadInstallerBootableImages(releaseArtifactsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	archImages := map[string]v2alpha1.ArchImages{
+		"x86_64":  ibi.Architectures.X86_64,
+		"aarch64": ibi.Architectures.Aarch64,
+		"ppc64le": ibi.Architectures.Ppc64le,
+		"s390x":   ibi.Architectures.S390x,
+	}
+
+	var artifacts []bootArtifact
+	for _, archKey := range o.requestedArchKeys(bootArtifactArchName, allBootArtifactArchKeys, "boot artifacts") {
+		ai := archImages[archKey]
+		for _, format := range o.Config.Mirror.Platform.BootArtifacts {
+			files := streamFormatFiles(ai, format)
+			if len(files) == 0 {
+				o.Log.Warn("boot artifacts: no %s artifact for architecture %q in stream %s — skipping", format, archKey, ibi.Stream)
+				continue
+			}
+			for _, role := range slices.Sorted(maps.Keys(files)) {
+				file := files[role]
+				u, err := url.Parse(file.Location)
+				if err != nil || path.Base(u.Path) == "/" || path.Base(u.Path) == "." {
+					return nil, fmt.Errorf("boot artifacts: invalid location %q for %s %s (arch: %s)", file.Location, format, role, archKey)
+				}
+				if file.Sha256 == "" {
+					return nil, fmt.Errorf("boot artifacts: no sha256 for %s %s (arch: %s)", format, role, archKey)
+				}
+				artifacts = append(artifacts, bootArtifact{
+					ArtifactFile: file,
+					Arch:         archKey,
+					Format:       format,
+					Role:         role,
+					FileName:     path.Base(u.Path),
+				})
+			}
+		}
+	}
+
+	if len(artifacts) == 0 {
+		return nil, fmt.Errorf("could not find any of the requested boot artifacts %v in this release", o.Config.Mirror.Platform.BootArtifacts)
+	}
+	return artifacts, nil
+}
+
+// collectBootArtifacts downloads the RHCOS boot artifacts requested in
+// platform.bootArtifacts to the working-dir, so that they are packaged in the archive.
+// Files already present with a matching checksum are not downloaded again.
+func (o LocalStorageCollector) collectBootArtifacts(ctx context.Context, releaseArtifactsDir string) error {
+	artifacts, err := o.selectBootArtifacts(releaseArtifactsDir)
+	if err != nil {
+		return err
+	}
+
+	releaseTag := filepath.Base(releaseArtifactsDir)
+	for _, artifact := range artifacts {
+		downloadDir := filepath.Join(o.Opts.Global.WorkingDir, bootArtifactsDir, releaseTag, artifact.Arch)
+		if o.Opts.IsDryRun {
+			o.Log.Info("boot artifacts: %s would be downloaded to %s", artifact.Location, downloadDir)
+			continue
+		}
+		if err := o.downloadBootArtifact(ctx, artifact, downloadDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// downloadBootArtifact downloads a single boot artifact to dir and verifies
+// it against the sha256 published in the stream before making it visible.
+func (o LocalStorageCollector) downloadBootArtifact(ctx context.Context, artifact bootArtifact, dir string) error {
+	dest := filepath.Join(dir, artifact.FileName)
+	if err := verifySha256(dest, artifact.Sha256); err == nil {
+		o.Log.Debug(collectorPrefix+"boot artifact %s already downloaded", dest)
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create boot artifacts dir: %w", err)
+	}
+
+	o.Log.Info("downloading boot artifact %s (%s %s, arch: %s)", artifact.FileName, artifact.Format, artifact.Role, artifact.Arch)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, artifact.Location, nil)
+	if err != nil {
+		return fmt.Errorf("boot artifact %s: %w", artifact.Location, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("boot artifact %s: %w", artifact.Location, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("boot artifact %s: unexpected http status %s", artifact.Location, resp.Status)
+	}
+
+	tmp, err := os.CreateTemp(dir, artifact.FileName+".part-*")
+	if err != nil {
+		return fmt.Errorf("boot artifact %s: %w", artifact.FileName, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	_, copyErr := io.Copy(io.MultiWriter(tmp, hash), resp.Body)
+	if err := errors.Join(copyErr, tmp.Close()); err != nil {
+		return fmt.Errorf("boot artifact %s: %w", artifact.Location, err)
+	}
+
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != artifact.Sha256 {
+		return fmt.Errorf("boot artifact %s: sha256 mismatch, expected %s got %s", artifact.Location, artifact.Sha256, sum)
+	}
+
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return fmt.Errorf("boot artifact %s: %w", artifact.FileName, err)
+	}
+	return nil
+}
+
+// layoutBootArtifacts lays out the downloaded boot artifacts of a release under
+// cluster-resources/boot-artifacts/<release>/<arch>, together with a sha256sum.txt
+// per architecture, so that the directory can be served as is by an HTTP server.
+// Every file is verified against the stream checksum first.
+func (o LocalStorageCollector) layoutBootArtifacts(releaseArtifactsDir string) error {
+	artifacts, err := o.selectBootArtifacts(releaseArtifactsDir)
+	if err != nil {
+		return err
+	}
+
+	releaseTag := filepath.Base(releaseArtifactsDir)
+	checksums := map[string][]string{}
+	for _, artifact := range artifacts {
+		src := filepath.Join(o.Opts.Global.WorkingDir, bootArtifactsDir, releaseTag, artifact.Arch, artifact.FileName)
+		if err := verifySha256(src, artifact.Sha256); err != nil {
+			return fmt.Errorf("boot artifact %s (%s %s, arch: %s): %w", artifact.FileName, artifact.Format, artifact.Role, artifact.Arch, err)
+		}
+
+		destDir := filepath.Join(o.Opts.Global.WorkingDir, clusterResourcesDir, bootArtifactsDir, releaseTag, artifact.Arch)
+		if err := os.MkdirAll(destDir, 0o755); err != nil {
+			return fmt.Errorf("create boot artifacts http dir: %w", err)
+		}
+		dest := filepath.Join(destDir, artifact.FileName)
+		if err := os.Remove(dest); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("boot artifact %s: %w", dest, err)
+		}
+		// boot artifacts are large: prefer a hard link and only copy across filesystems
+		if err := os.Link(src, dest); err != nil {
+			if err := copy.Copy(src, dest); err != nil {
+				return fmt.Errorf("boot artifact %s: %w", dest, err)
+			}
+		}
+		checksums[destDir] = append(checksums[destDir], artifact.Sha256+"  "+artifact.FileName)
+	}
+
+	for dir, lines := range checksums {
+		content := strings.Join(lines, "\n") + "\n"
+		if err := os.WriteFile(filepath.Join(dir, bootArtifactsChecksums), []byte(content), 0o644); err != nil { //nolint:gosec // G306: no sensitive data
+			return fmt.Errorf("write boot artifacts checksums: %w", err)
+		}
+	}
+	o.Log.Info("boot artifacts for release %s available in %s", releaseTag, filepath.Join(o.Opts.Global.WorkingDir, clusterResourcesDir, bootArtifactsDir, releaseTag))
+	return nil
+}
+
+// verifySha256 returns an error when the file does not exist
+// or when its sha256 does not match the expected one
+func verifySha256(file, expected string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != expected {
+		return fmt.Errorf("sha256 mismatch, expected %s got %s", expected, sum)
+	}
+	return nil
+}
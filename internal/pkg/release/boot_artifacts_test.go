@@ -0,0 +1,173 @@
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+const bootImagesTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: coreos-bootimages
+  namespace: openshift-machine-config-operator
+data:
+  releaseVersion: 4.16.0
+  stream: |
+    {
+      "stream": "rhcos-4.16",
+      "architectures": {
+        "x86_64": {
+          "artifacts": {
+            "metal": {
+              "release": "416.94.0",
+              "formats": {
+                "iso": {"disk": {"location": "%[1]s/x86_64/rhcos-live.x86_64.iso", "sha256": "%[2]s"}},
+                "pxe": {
+                  "kernel": {"location": "%[1]s/x86_64/rhcos-live-kernel-x86_64", "sha256": "%[3]s"},
+                  "initramfs": {"location": "%[1]s/x86_64/rhcos-live-initramfs.x86_64.img", "sha256": "%[3]s"},
+                  "rootfs": {"location": "%[1]s/x86_64/rhcos-live-rootfs.x86_64.img", "sha256": "%[3]s"}
+                }
+              }
+            }
+          }
+        },
+        "aarch64": {
+          "artifacts": {
+            "metal": {
+              "release": "416.94.0",
+              "formats": {
+                "iso": {"disk": {"location": "%[1]s/aarch64/rhcos-live.aarch64.iso", "sha256": "%[2]s"}}
+              }
+            }
+          }
+        }
+      }
+    }
+`
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// setupBootArtifacts serves every requested path with the same content
+// and writes a coreos-bootimages ConfigMap pointing at the server.
+func setupBootArtifacts(t *testing.T, isoSha, pxeSha string) (releaseDir string, requests *int) {
+	t.Helper()
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if strings.Contains(r.URL.Path, "live.") {
+			fmt.Fprint(w, "iso-content")
+			return
+		}
+		fmt.Fprint(w, "pxe-content")
+	}))
+	t.Cleanup(server.Close)
+
+	releaseDir = filepath.Join(t.TempDir(), "working-dir", releaseImageExtractDir, "ocp-release", "4.16.0-x86_64")
+	require.NoError(t, os.MkdirAll(filepath.Join(releaseDir, releaseManifests), 0o755))
+	content := fmt.Sprintf(bootImagesTemplate, server.URL, isoSha, pxeSha)
+	require.NoError(t, os.WriteFile(filepath.Join(releaseDir, releaseBootableImagesFullPath), []byte(content), 0o600))
+	return releaseDir, &count
+}
+
+func newBootArtifactsCollector(workingDir string, mode string, formats []v2alpha1.BootArtifactFormat, archs ...string) LocalStorageCollector {
+	opts := mirror.CopyOptions{Mode: mode, Global: &mirror.GlobalOptions{WorkingDir: workingDir}}
+	return LocalStorageCollector{
+		Log:  clog.New("trace"),
+		Opts: opts,
+		Config: v2alpha1.ImageSetConfiguration{
+			ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+				Mirror: v2alpha1.Mirror{
+					Platform: v2alpha1.Platform{
+						Architectures: archs,
+						BootArtifacts: formats,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCollectBootArtifacts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("downloads, verifies and lays out the requested artifacts", func(t *testing.T) {
+		releaseDir, requests := setupBootArtifacts(t, sha256Hex("iso-content"), sha256Hex("pxe-content"))
+		workingDir := filepath.Dir(filepath.Dir(filepath.Dir(releaseDir)))
+
+		m2d := newBootArtifactsCollector(workingDir, mirror.MirrorToDisk, []v2alpha1.BootArtifactFormat{v2alpha1.BootArtifactISO, v2alpha1.BootArtifactPXE}, "amd64", "arm64")
+		require.NoError(t, m2d.collectBootArtifacts(ctx, releaseDir))
+		assert.Equal(t, 5, *requests)
+		for _, f := range []string{
+			"x86_64/rhcos-live.x86_64.iso",
+			"x86_64/rhcos-live-kernel-x86_64",
+			"x86_64/rhcos-live-initramfs.x86_64.img",
+			"x86_64/rhcos-live-rootfs.x86_64.img",
+			"aarch64/rhcos-live.aarch64.iso",
+		} {
+			assert.FileExists(t, filepath.Join(workingDir, bootArtifactsDir, "4.16.0-x86_64", f))
+		}
+
+		// files already downloaded are not fetched again
+		require.NoError(t, m2d.collectBootArtifacts(ctx, releaseDir))
+		assert.Equal(t, 5, *requests)
+
+		d2m := newBootArtifactsCollector(workingDir, mirror.DiskToMirror, []v2alpha1.BootArtifactFormat{v2alpha1.BootArtifactISO, v2alpha1.BootArtifactPXE}, "amd64", "arm64")
+		require.NoError(t, d2m.layoutBootArtifacts(releaseDir))
+		httpDir := filepath.Join(workingDir, clusterResourcesDir, bootArtifactsDir, "4.16.0-x86_64")
+		assert.FileExists(t, filepath.Join(httpDir, "aarch64", "rhcos-live.aarch64.iso"))
+		checksums, err := os.ReadFile(filepath.Join(httpDir, "x86_64", bootArtifactsChecksums))
+		require.NoError(t, err)
+		assert.Equal(t, sha256Hex("iso-content")+"  rhcos-live.x86_64.iso\n"+
+			sha256Hex("pxe-content")+"  rhcos-live-initramfs.x86_64.img\n"+
+			sha256Hex("pxe-content")+"  rhcos-live-kernel-x86_64\n"+
+			sha256Hex("pxe-content")+"  rhcos-live-rootfs.x86_64.img\n", string(checksums))
+	})
+
+	t.Run("fails on checksum mismatch and keeps nothing", func(t *testing.T) {
+		releaseDir, _ := setupBootArtifacts(t, sha256Hex("other-content"), sha256Hex("pxe-content"))
+		workingDir := filepath.Dir(filepath.Dir(filepath.Dir(releaseDir)))
+
+		m2d := newBootArtifactsCollector(workingDir, mirror.MirrorToDisk, []v2alpha1.BootArtifactFormat{v2alpha1.BootArtifactISO})
+		err := m2d.collectBootArtifacts(ctx, releaseDir)
+		assert.ErrorContains(t, err, "sha256 mismatch")
+		entries, err := os.ReadDir(filepath.Join(workingDir, bootArtifactsDir, "4.16.0-x86_64", "x86_64"))
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("layout fails when an artifact is missing from the archive", func(t *testing.T) {
+		releaseDir, _ := setupBootArtifacts(t, sha256Hex("iso-content"), sha256Hex("pxe-content"))
+		workingDir := filepath.Dir(filepath.Dir(filepath.Dir(releaseDir)))
+
+		d2m := newBootArtifactsCollector(workingDir, mirror.DiskToMirror, []v2alpha1.BootArtifactFormat{v2alpha1.BootArtifactISO})
+		err := d2m.layoutBootArtifacts(releaseDir)
+		assert.ErrorContains(t, err, "rhcos-live.x86_64.iso")
+	})
+
+	t.Run("fails when none of the requested formats are in the stream", func(t *testing.T) {
+		releaseDir, _ := setupBootArtifacts(t, sha256Hex("iso-content"), sha256Hex("pxe-content"))
+		workingDir := filepath.Dir(filepath.Dir(filepath.Dir(releaseDir)))
+
+		m2d := newBootArtifactsCollector(workingDir, mirror.MirrorToDisk, []v2alpha1.BootArtifactFormat{v2alpha1.BootArtifactOVA})
+		err := m2d.collectBootArtifacts(ctx, releaseDir)
+		assert.ErrorContains(t, err, "could not find any of the requested boot artifacts")
+	})
+}
@@ -3,6 +3,9 @@ package release
 const (
 	graphBaseImage                 = "registry.access.redhat.com/ubi9/ubi:latest"
 	graphURL                       = "https://api.openshift.com/api/upgrades_info/graph-data"
+	graphRevisionURL               = "https://github.com/openshift/cincinnati-graph-data/archive/%s.tar.gz"
+	graphBlockedEdgesDir           = "blocked-edges"
+	graphChannelsDir               = "channels"
 	graphArchive                   = "cincinnati-graph-data.tar"
 	graphPreparationDir            = "graph-preparation"
 	buildGraphDataDir              = "/var/lib/cincinnati-graph-data"
@@ -21,4 +24,12 @@ const (
 	errMsg                         = collectorPrefix + "%s"
 	releaseImagePathComponents     = "openshift/release-images"
 	releaseComponentPathComponents = "openshift/release"
+	bootArtifactsDir               = "boot-artifacts"
+	bootArtifactsChecksums         = "sha256sum.txt"
+	clusterResourcesDir            = "cluster-resources"
+	samplesOperatorName            = "cluster-samples-operator"
+	samplesOperatorAssets          = "opt/openshift/operator"
+	samplesOperatorImageDir        = "samples-operator"
+	samplesImageStreamsDir         = "imagestreams"
+	localSamplesFile               = "local-samples.json"
 )
@@ -20,17 +20,30 @@ import (
 // it follows https://docs.openshift.com/container-platform/4.13/updating/updating-restricted-network-cluster/restricted-network-update-osus.html#update-service-graph-data_updating-restricted-network-cluster-osus
 func (o *LocalStorageCollector) CreateGraphImage(ctx context.Context, url string) (string, error) {
 	// HTTP Get the graph updates from api endpoint
+	// or the pinned cincinnati-graph-data revision
+	url = o.graphDataURL(url)
 	resp, err := http.Get(url)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("graph data %s: unexpected http status %s", url, resp.Status)
+	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", err
 	}
 
+	if hasGraphDataOverlays(o.Config.Mirror.Platform.GraphData) {
+		o.Log.Info("merging graph data overlays from the ImageSetConfiguration")
+		body, err = o.overlayGraphData(body)
+		if err != nil {
+			return "", fmt.Errorf("graph data: %w", err)
+		}
+	}
+
 	// save graph data in a container layer modifying UID and GID to root.
 	archiveDestination := filepath.Join(o.Opts.Global.WorkingDir, graphArchive)
 	graphLayer, err := imagebuilder.LayerFromGzipByteArray(body, archiveDestination, buildGraphDataDir, 0644, 0, 0)
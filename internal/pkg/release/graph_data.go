@@ -0,0 +1,216 @@
+package release
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+// graphDataEntry is a file (or directory) of the graph data archive
+type graphDataEntry struct {
+	header *tar.Header
+	data   []byte
+}
+
+// graphDataURL returns the url of the graph data tar.gz archive:
+// the pinned cincinnati-graph-data revision when set, the latest graph data otherwise.
+func (o LocalStorageCollector) graphDataURL(defaultURL string) string {
+	if revision := o.Config.Mirror.Platform.GraphData.Revision; revision != "" {
+		return fmt.Sprintf(graphRevisionURL, revision)
+	}
+	return defaultURL
+}
+
+// hasGraphDataOverlays returns true when the graph data needs to be rewritten
+// before building the graph image.
+func hasGraphDataOverlays(gd v2alpha1.GraphData) bool {
+	return gd.Revision != "" || gd.OverlayDir != "" || len(gd.BlockedEdges) > 0 || len(gd.Channels) > 0
+}
+
+// overlayGraphData rewrites the graph data tar.gz archive according to
+// platform.graphData: the top level directory of git archives is removed,
+// then the overlay directory, the channel restrictions and the extra blocked
+// edges are merged, in that order.
+func (o LocalStorageCollector) overlayGraphData(content []byte) ([]byte, error) {
+	gd := o.Config.Mirror.Platform.GraphData
+
+	entries, err := readGraphData(content, gd.Revision != "")
+	if err != nil {
+		return nil, fmt.Errorf("read graph data: %w", err)
+	}
+
+	if gd.OverlayDir != "" {
+		if entries, err = mergeGraphDataOverlayDir(entries, gd.OverlayDir); err != nil {
+			return nil, fmt.Errorf("graph data overlay %s: %w", gd.OverlayDir, err)
+		}
+	}
+
+	for _, ch := range gd.Channels {
+		if err := o.restrictGraphDataChannel(entries, ch); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, edge := range gd.BlockedEdges {
+		data, err := yaml.Marshal(edge)
+		if err != nil {
+			return nil, fmt.Errorf("blocked edge to %s: %w", edge.To, err)
+		}
+		name := path.Join(graphBlockedEdgesDir, fmt.Sprintf("%s-oc-mirror-%d.yaml", edge.To, i))
+		o.Log.Debug(collectorPrefix+"adding blocked edge %s", name)
+		entries = putGraphDataFile(entries, name, data)
+	}
+
+	return writeGraphData(entries)
+}
+
+// restrictGraphDataChannel keeps only the requested versions in an upstream channel
+func (o LocalStorageCollector) restrictGraphDataChannel(entries []graphDataEntry, ch v2alpha1.GraphChannel) error {
+	name := path.Join(graphChannelsDir, ch.Name+".yaml")
+	idx := slices.IndexFunc(entries, func(e graphDataEntry) bool { return e.header.Name == name })
+	if idx == -1 {
+		return fmt.Errorf("channel %q not found in graph data", ch.Name)
+	}
+
+	var channel map[string]any
+	if err := yaml.Unmarshal(entries[idx].data, &channel); err != nil {
+		return fmt.Errorf("channel %q: %w", ch.Name, err)
+	}
+	upstream, _ := channel["versions"].([]any)
+
+	kept := []string{}
+	for _, v := range upstream {
+		if version, ok := v.(string); ok && slices.Contains(ch.Versions, version) {
+			kept = append(kept, version)
+		}
+	}
+	for _, version := range ch.Versions {
+		if !slices.Contains(kept, version) {
+			o.Log.Warn("graph data: version %s is not part of upstream channel %s — skipping", version, ch.Name)
+		}
+	}
+	channel["versions"] = kept
+
+	data, err := yaml.Marshal(channel)
+	if err != nil {
+		return fmt.Errorf("channel %q: %w", ch.Name, err)
+	}
+	entries[idx].data = data
+	entries[idx].header.Size = int64(len(data))
+	return nil
+}
+
+// readGraphData loads all the entries of a tar.gz archive in memory.
+// When stripTopDir is true, the first path element (added by git archives) is removed.
+func readGraphData(content []byte, stripTopDir bool) ([]graphDataEntry, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	var entries []graphDataEntry
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		if stripTopDir {
+			_, rest, _ := strings.Cut(strings.TrimPrefix(header.Name, "./"), "/")
+			if rest == "" {
+				continue
+			}
+			header.Name = rest
+		}
+		data, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, graphDataEntry{header: header, data: data})
+	}
+	return entries, nil
+}
+
+// mergeGraphDataOverlayDir adds the regular files of dir to the entries,
+// replacing the upstream files with the same relative path.
+func mergeGraphDataOverlayDir(entries []graphDataEntry, dir string) ([]graphDataEntry, error) {
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		entries = putGraphDataFile(entries, filepath.ToSlash(rel), data)
+		return nil
+	})
+	return entries, err
+}
+
+// putGraphDataFile replaces the content of the file name, or appends it
+func putGraphDataFile(entries []graphDataEntry, name string, data []byte) []graphDataEntry {
+	if idx := slices.IndexFunc(entries, func(e graphDataEntry) bool { return e.header.Name == name }); idx != -1 {
+		entries[idx].data = data
+		entries[idx].header.Size = int64(len(data))
+		return entries
+	}
+	return append(entries, graphDataEntry{
+		header: &tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+		},
+		data: data,
+	})
+}
+
+// writeGraphData writes the entries back to a tar.gz archive
+func writeGraphData(entries []graphDataEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		if err := tarWriter.WriteHeader(entry.header); err != nil {
+			return nil, err
+		}
+		if _, err := tarWriter.Write(entry.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
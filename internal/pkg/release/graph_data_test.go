@@ -0,0 +1,102 @@
+package release
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+)
+
+func buildGraphDataArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Typeflag: tar.TypeXGlobalHeader, Name: "pax_global_header"}))
+	for _, name := range []string{
+		"cincinnati-graph-data-abc/",
+		"cincinnati-graph-data-abc/channels/stable-4.14.yaml",
+		"cincinnati-graph-data-abc/blocked-edges/4.14.0.yaml",
+	} {
+		content, ok := files[name]
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if !ok {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Size = 0
+		}
+		require.NoError(t, tarWriter.WriteHeader(hdr))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buf.Bytes()
+}
+
+func TestOverlayGraphData(t *testing.T) {
+	archive := buildGraphDataArchive(t, map[string]string{
+		"cincinnati-graph-data-abc/channels/stable-4.14.yaml": "name: stable-4.14\nversions:\n- 4.14.0\n- 4.14.1\n- 4.14.2\n",
+		"cincinnati-graph-data-abc/blocked-edges/4.14.0.yaml": "to: 4.14.0\nfrom: .*\n",
+	})
+
+	overlayDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(overlayDir, "blocked-edges"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(overlayDir, "blocked-edges", "4.14.0.yaml"), []byte("to: 4.14.0\nfrom: 4\\.13\\..*\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(overlayDir, "raw.txt"), []byte("local"), 0o600))
+
+	collector := LocalStorageCollector{
+		Log: clog.New("trace"),
+		Config: v2alpha1.ImageSetConfiguration{
+			ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+				Mirror: v2alpha1.Mirror{
+					Platform: v2alpha1.Platform{
+						Graph: true,
+						GraphData: v2alpha1.GraphData{
+							Revision:     "abc",
+							OverlayDir:   overlayDir,
+							BlockedEdges: []v2alpha1.GraphBlockedEdge{{To: "4.14.2", From: "4\\.14\\.1"}},
+							Channels:     []v2alpha1.GraphChannel{{Name: "stable-4.14", Versions: []string{"4.14.0", "4.14.2", "4.14.9"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, "https://github.com/openshift/cincinnati-graph-data/archive/abc.tar.gz", collector.graphDataURL(graphURL))
+
+	out, err := collector.overlayGraphData(archive)
+	require.NoError(t, err)
+
+	entries, err := readGraphData(out, false)
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, e := range entries {
+		if e.header.Typeflag == tar.TypeReg {
+			files[e.header.Name] = string(e.data)
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"channels/stable-4.14.yaml":             "name: stable-4.14\nversions:\n- 4.14.0\n- 4.14.2\n",
+		"blocked-edges/4.14.0.yaml":             "to: 4.14.0\nfrom: 4\\.13\\..*\n",
+		"blocked-edges/4.14.2-oc-mirror-0.yaml": "from: 4\\.14\\.1\nto: 4.14.2\n",
+		"raw.txt":                               "local",
+	}, files)
+
+	t.Run("unknown channel should fail", func(t *testing.T) {
+		collector.Config.Mirror.Platform.GraphData = v2alpha1.GraphData{
+			Channels: []v2alpha1.GraphChannel{{Name: "fast-4.99", Versions: []string{"4.99.0"}}},
+		}
+		_, err := collector.overlayGraphData(archive)
+		assert.ErrorContains(t, err, `channel "fast-4.99" not found in graph data`)
+		assert.Equal(t, graphURL, collector.graphDataURL(graphURL))
+	})
+}
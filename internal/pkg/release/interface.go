@@ -23,6 +23,10 @@ type CollectorInterface interface {
 	// This works because oc-mirror doesn't know how to mix OKD and OCP
 	// release mirroring.
 	ReleaseImage(context.Context) (string, error)
+	// Returns the imagestreams of the cluster-samples-operator of the
+	// collected releases which are not mirrored.
+	// This is used to generate the samples operator Config.
+	SkippedImageStreams() []string
 }
 
 type GraphBuilderInterface interface {
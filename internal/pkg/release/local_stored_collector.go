@@ -37,6 +37,8 @@ type LocalStorageCollector struct {
 	Releases         []string
 	GraphDataImage   string
 	destReg          string
+	// imagestreams of the samples operator which are not mirrored
+	skippedImageStreams []string
 }
 
 func (o LocalStorageCollector) destinationRegistry() string {
@@ -85,6 +87,12 @@ func (o *LocalStorageCollector) ReleaseImageCollector(ctx context.Context) (v2al
 		return v2alpha1.CollectorSchema{}, err
 	}
 
+	localSamples, err := o.collectLocalSampleImages()
+	if err != nil {
+		return v2alpha1.CollectorSchema{}, err
+	}
+	allImages = append(allImages, localSamples...)
+
 	// OCPBUGS-43275: deduplicating
 	slices.SortFunc(allImages, compareByOriginSourceDest)
 	allImages = slices.Compact(allImages)
@@ -215,6 +223,29 @@ func (o *LocalStorageCollector) collectReleaseImages(ctx context.Context, releas
 		allRelatedImages = append(allRelatedImages, kvImages...)
 	}
 
+	if slices.ContainsFunc(o.Config.Mirror.Samples, isPayloadSample) {
+		if err := o.extractSamplesOperator(ctx, cacheDir, allRelatedImages); err != nil {
+			return []v2alpha1.RelatedImage{}, err
+		}
+		sampleImages, err := o.getSampleImages(cacheDir)
+		if err != nil {
+			return []v2alpha1.RelatedImage{}, err
+		}
+		allRelatedImages = append(allRelatedImages, sampleImages...)
+	}
+
+	if len(o.Config.Mirror.Platform.BootArtifacts) > 0 {
+		if err := o.collectBootArtifacts(ctx, cacheDir); err != nil {
+			return []v2alpha1.RelatedImage{}, err
+		}
+		// in mirrorToMirror there is no diskToMirror step to publish them
+		if o.Opts.IsMirrorToMirror() && !o.Opts.IsDryRun {
+			if err := o.layoutBootArtifacts(cacheDir); err != nil {
+				return []v2alpha1.RelatedImage{}, err
+			}
+		}
+	}
+
 	return allRelatedImages, nil
 }
 
@@ -325,6 +356,20 @@ func (o *LocalStorageCollector) prepareReleaseBatchFromDir(releaseDir string) ([
 		releaseRelatedImages = append(releaseRelatedImages, kvImages...)
 	}
 
+	if slices.ContainsFunc(o.Config.Mirror.Samples, isPayloadSample) {
+		sampleImages, err := o.getSampleImages(releaseDir)
+		if err != nil {
+			return []v2alpha1.CopyImageSchema{}, err
+		}
+		releaseRelatedImages = append(releaseRelatedImages, sampleImages...)
+	}
+
+	if len(o.Config.Mirror.Platform.BootArtifacts) > 0 && !o.Opts.IsDryRun {
+		if err := o.layoutBootArtifacts(releaseDir); err != nil {
+			return []v2alpha1.CopyImageSchema{}, err
+		}
+	}
+
 	return o.prepareD2MCopyBatch(releaseRelatedImages, releaseTag)
 }
 
@@ -490,6 +535,12 @@ func (o *LocalStorageCollector) GraphImage() (string, error) {
 	return o.GraphDataImage, nil
 }
 
+// SkippedImageStreams returns the imagestreams of the samples operator
+// which were found in the releases but are not part of mirror.samples.
+func (o *LocalStorageCollector) SkippedImageStreams() []string {
+	return o.skippedImageStreams
+}
+
 // assumes that this is called during DiskToMirror workflow.
 // it relies on the previously saved cincinnati-graph-data in order
 // to get the list of releases to mirror (saved during mirrorToDisk
@@ -558,22 +609,9 @@ var allKubeVirtArchKeys = []string{"x86_64", "s390x"}
 // for all requested architectures. Returns one RelatedImage per architecture
 // whose kubevirt digest-ref is non-empty in the bootimages ConfigMap.
 func (o LocalStorageCollector) getKubeVirtImages(releaseArtifactsDir string) ([]v2alpha1.RelatedImage, error) {
-	// parse the main yaml file
-	biFile := strings.Join([]string{releaseArtifactsDir, releaseBootableImagesFullPath}, "/")
-	icm, err := parser.ParseYamlFile[v2alpha1.InstallerConfigMap](biFile)
+	ibi, err := loadInstallerBootableImages(releaseArtifactsDir)
 	if err != nil {
-		// this should not break the release process
-		// we just report the error and continue
-		return nil, fmt.Errorf("marshalling kubevirt yaml file %w", err)
-	}
-
-	o.Log.Trace("data %v", icm.Data.Stream)
-	// now parse the json section
-	ibi, err := parser.ParseJsonReader[v2alpha1.InstallerBootableImages](strings.NewReader(icm.Data.Stream))
-	if err != nil {
-		// this should not break the release process
-		// we just report the error and continue
-		return nil, fmt.Errorf("parsing json from kubevirt configmap data %w", err)
+		return nil, err
 	}
 
 	// Build the set of bootimages JSON architecture keys to collect.
@@ -626,6 +664,24 @@ func (o LocalStorageCollector) getKubeVirtImages(releaseArtifactsDir string) ([]
 	return images, nil
 }
 
+// loadInstallerBootableImages parses the coreos-bootimages stream embedded in the
+// 0000_50_installer_coreos-bootimages ConfigMap of an extracted release.
+func loadInstallerBootableImages(releaseArtifactsDir string) (v2alpha1.InstallerBootableImages, error) {
+	// parse the main yaml file
+	biFile := strings.Join([]string{releaseArtifactsDir, releaseBootableImagesFullPath}, "/")
+	icm, err := parser.ParseYamlFile[v2alpha1.InstallerConfigMap](biFile)
+	if err != nil {
+		return v2alpha1.InstallerBootableImages{}, fmt.Errorf("marshalling kubevirt yaml file %w", err)
+	}
+
+	// now parse the json section
+	ibi, err := parser.ParseJsonReader[v2alpha1.InstallerBootableImages](strings.NewReader(icm.Data.Stream))
+	if err != nil {
+		return v2alpha1.InstallerBootableImages{}, fmt.Errorf("parsing json from kubevirt configmap data %w", err)
+	}
+	return ibi, nil
+}
+
 // requestedKubeVirtArchKeys returns the bootimages JSON architecture keys
 // that correspond to the architectures requested in the ImageSetConfiguration.
 // When platform.platforms is set (the "multi" sparse-manifest-list workflow)
@@ -633,17 +689,24 @@ func (o LocalStorageCollector) getKubeVirtImages(releaseArtifactsDir string) ([]
 // set each entry is mapped from its Go name (e.g. "amd64") to its JSON key
 // (e.g. "x86_64"). Defaults to ["x86_64"] for backward compatibility.
 func (o LocalStorageCollector) requestedKubeVirtArchKeys() []string {
+	return o.requestedArchKeys(kubeVirtArchName, allKubeVirtArchKeys, "kubevirt")
+}
+
+// requestedArchKeys maps the architectures requested in the ImageSetConfiguration
+// to bootimages JSON keys using archNames, falling back to allKeys for the
+// "multi" workflows. kind is only used to prefix log messages.
+func (o LocalStorageCollector) requestedArchKeys(archNames map[string]string, allKeys []string, kind string) []string {
 	cfg := o.Config.Mirror.Platform
 
 	// New path: platform.platforms was set — the release payload is the "multi"
-	// manifest list and we want kubevirt images for every architecture.
+	// manifest list and we want content for every requested architecture.
 	if len(cfg.Platforms) > 0 {
 		var keys []string
 		for _, pf := range cfg.Platforms {
-			if jsonKey, ok := kubeVirtArchName[pf.Architecture]; ok {
+			if jsonKey, ok := archNames[pf.Architecture]; ok {
 				keys = append(keys, jsonKey)
 			} else {
-				o.Log.Warn("kubevirt: no bootimages key for platform architecture %q — skipping", pf.Architecture)
+				o.Log.Warn("%s: no bootimages key for platform architecture %q — skipping", kind, pf.Architecture)
 			}
 		}
 		if len(keys) > 0 {
@@ -651,7 +714,7 @@ func (o LocalStorageCollector) requestedKubeVirtArchKeys() []string {
 		}
 		// Fall through: none of the requested architectures had a known mapping;
 		// return all keys so we still attempt to collect something.
-		return allKubeVirtArchKeys
+		return allKeys
 	}
 
 	// Deprecated path: platform.architectures was set.
@@ -660,12 +723,12 @@ func (o LocalStorageCollector) requestedKubeVirtArchKeys() []string {
 		var keys []string
 		for _, arch := range cfg.Architectures {
 			if arch == "multi" {
-				return allKubeVirtArchKeys
+				return allKeys
 			}
-			if jsonKey, ok := kubeVirtArchName[arch]; ok {
+			if jsonKey, ok := archNames[arch]; ok {
 				keys = append(keys, jsonKey)
 			} else {
-				o.Log.Warn("kubevirt: no bootimages key for architecture %q — skipping", arch)
+				o.Log.Warn("%s: no bootimages key for architecture %q — skipping", kind, arch)
 			}
 		}
 		if len(keys) > 0 {
@@ -682,6 +745,9 @@ func (o LocalStorageCollector) handleGraphImage(ctx context.Context) (v2alpha1.C
 	if updateURLOverride := os.Getenv("UPDATE_URL_OVERRIDE"); len(updateURLOverride) != 0 {
 		// OCPBUGS-38037: this indicates that the official cincinnati API is not reacheable
 		// and that graph image cannot be rebuilt on top the complete graph in tar.gz format
+		if hasGraphDataOverlays(o.Config.Mirror.Platform.GraphData) {
+			o.Log.Warn("UPDATE_URL_OVERRIDE is set: platform.graphData is ignored, the graph image is reused from the cache")
+		}
 
 		graphImgRef := consts.DockerProtocol + filepath.Join(o.destinationRegistry(), graphImageName) + ":latest"
 
@@ -753,7 +819,7 @@ func preparePathComponents(imgSpec image.ImageSpec, imgType v2alpha1.ImageType,
 	switch {
 	case imgType == v2alpha1.TypeOCPRelease:
 		pathComponents = releaseImagePathComponents
-	case imgType == v2alpha1.TypeCincinnatiGraph:
+	case imgType == v2alpha1.TypeCincinnatiGraph || imgType == v2alpha1.TypeSampleImage:
 		pathComponents = imgSpec.PathComponent
 	case imgType == v2alpha1.TypeOCPReleaseContent && imgName != "":
 		pathComponents = releaseComponentPathComponents
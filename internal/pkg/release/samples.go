@@ -0,0 +1,294 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/image"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/parser"
+)
+
+// samplesArchName maps the Go/OCP architecture names used in the
+// ImageSetConfiguration to the directories of the cluster-samples-operator assets.
+var samplesArchName = map[string]string{
+	"amd64":   "x86_64",
+	"arm64":   "aarch64",
+	"ppc64le": "ppc64le",
+	"s390x":   "s390x",
+}
+
+// allSamplesArchKeys lists the directories of the cluster-samples-operator
+// assets used when the user selects the "multi" payload.
+var allSamplesArchKeys = []string{"x86_64", "aarch64", "ppc64le", "s390x"}
+
+// imageStream holds the fields of an imagestream needed to find its images.
+// Items is only set for a list of imagestreams.
+type imageStream struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		Tags []imageStreamTag `json:"tags"`
+	} `json:"spec"`
+	Items []imageStream `json:"items,omitempty"`
+}
+
+type imageStreamTag struct {
+	Name string         `json:"name"`
+	From *v2alpha1.From `json:"from,omitempty"`
+}
+
+// isPayloadSample returns true for the samples discovered from the release payload,
+// as opposed to the samples read from local imagestream files.
+func isPayloadSample(sample v2alpha1.SampleImage) bool {
+	return sample.Path == ""
+}
+
+// extractSamplesOperator extracts the assets of the cluster-samples-operator of a release,
+// which hold the imagestreams of the samples, next to the release manifests.
+func (o *LocalStorageCollector) extractSamplesOperator(ctx context.Context, releaseArtifactsDir string, releaseImages []v2alpha1.RelatedImage) error {
+	if _, err := os.Stat(filepath.Join(releaseArtifactsDir, samplesOperatorAssets)); err == nil {
+		o.Log.Debug(collectorPrefix+"samples operator assets already extracted in %s", releaseArtifactsDir)
+		return nil
+	}
+
+	idx := slices.IndexFunc(releaseImages, func(img v2alpha1.RelatedImage) bool {
+		return img.Name == samplesOperatorName
+	})
+	if idx == -1 {
+		return fmt.Errorf("could not find %s in this release", samplesOperatorName)
+	}
+	operatorImage := releaseImages[idx].Image
+	imgSpec, err := image.ParseRef(operatorImage)
+	if err != nil {
+		return err
+	}
+	if imgSpec.Digest == "" {
+		return fmt.Errorf("%s image %s is not referenced by digest", samplesOperatorName, operatorImage)
+	}
+
+	dir := filepath.Join(o.Opts.Global.WorkingDir, releaseImageDir, samplesOperatorImageDir, imgSpec.Digest)
+	if err := o.ensureReleaseInOCIFormat(ctx, v2alpha1.CopyImageSchema{Source: operatorImage}, dir); err != nil {
+		return fmt.Errorf("%s: %w", samplesOperatorName, err)
+	}
+	img, err := o.Manifest.GetOCIImageFromIndex(dir)
+	if err != nil {
+		return fmt.Errorf("failed to find %s image in index: %w", samplesOperatorName, err)
+	}
+	if err := o.Manifest.ExtractOCILayers(img, releaseArtifactsDir, samplesOperatorAssets); err != nil {
+		return fmt.Errorf("extract %s assets: %w", samplesOperatorName, err)
+	}
+	return nil
+}
+
+// getSampleImages returns the images of the imagestreams of the cluster-samples-operator
+// selected in mirror.samples, for all requested architectures.
+// The imagestreams which are not selected are recorded, so that the samples operator can skip them.
+func (o *LocalStorageCollector) getSampleImages(releaseArtifactsDir string) ([]v2alpha1.RelatedImage, error) {
+	var streams []imageStream
+	for _, archKey := range o.requestedArchKeys(samplesArchName, allSamplesArchKeys, "samples") {
+		archDir := filepath.Join(releaseArtifactsDir, samplesOperatorAssets, archKey)
+		archStreams, err := loadImageStreams(archDir, samplesImageStreamsDir)
+		if errors.Is(err, fs.ErrNotExist) {
+			o.Log.Warn("samples: no imagestreams for architecture %q in this release — skipping", archKey)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		streams = append(streams, archStreams...)
+	}
+
+	found := sets.New[string]()
+	var images []v2alpha1.RelatedImage
+	for _, is := range streams {
+		idx := slices.IndexFunc(o.Config.Mirror.Samples, func(sample v2alpha1.SampleImage) bool {
+			return isPayloadSample(sample) && sample.Name == is.Metadata.Name
+		})
+		if idx == -1 {
+			o.skippedImageStreams = append(o.skippedImageStreams, is.Metadata.Name)
+			continue
+		}
+		found.Insert(is.Metadata.Name)
+		images = append(images, imageStreamImages(is, o.Config.Mirror.Samples[idx].Tags)...)
+	}
+
+	for _, sample := range o.Config.Mirror.Samples {
+		if isPayloadSample(sample) && !found.Has(sample.Name) {
+			return nil, fmt.Errorf("could not find imagestream %q in the samples of this release", sample.Name)
+		}
+	}
+	return images, nil
+}
+
+// collectLocalSampleImages returns the copies of the images of the
+// imagestreams read from the local files of mirror.samples.
+// The local files are only read on the connected side: mirrorToDisk saves their imagestreams
+// in the working-dir, where diskToMirror finds them in the archive.
+func (o LocalStorageCollector) collectLocalSampleImages() ([]v2alpha1.CopyImageSchema, error) {
+	var localSamples []v2alpha1.SampleImage
+	for _, sample := range o.Config.Mirror.Samples {
+		if !isPayloadSample(sample) {
+			localSamples = append(localSamples, sample)
+		}
+	}
+	if len(localSamples) == 0 {
+		return nil, nil
+	}
+
+	archivedPath := filepath.Join(o.Opts.Global.WorkingDir, releaseImageDir, localSamplesFile)
+	var archived map[string][]imageStream
+	if o.Opts.IsDiskToMirror() {
+		var err error
+		archived, err = parser.ParseJsonFile[map[string][]imageStream](archivedPath)
+		if err != nil {
+			return nil, fmt.Errorf("samples: the imagestreams of the local files are not in the archive: %w", err)
+		}
+	}
+
+	saved := make(map[string][]imageStream, len(localSamples))
+	var images []v2alpha1.RelatedImage
+	for _, sample := range localSamples {
+		key := localSampleKey(sample)
+		var streams []imageStream
+		if o.Opts.IsDiskToMirror() {
+			var ok bool
+			if streams, ok = archived[key]; !ok {
+				return nil, fmt.Errorf("samples: the imagestreams of %s are not in the archive", sample.Path)
+			}
+		} else {
+			var err error
+			if streams, err = loadImageStreams(sample.Path, ""); err != nil {
+				return nil, fmt.Errorf("samples: %w", err)
+			}
+		}
+		streams = slices.DeleteFunc(streams, func(is imageStream) bool {
+			return sample.Name != "" && is.Metadata.Name != sample.Name
+		})
+		if len(streams) == 0 {
+			return nil, fmt.Errorf("samples: could not find imagestream %q in %s", sample.Name, sample.Path)
+		}
+		saved[key] = streams
+		for _, is := range streams {
+			images = append(images, imageStreamImages(is, sample.Tags)...)
+		}
+	}
+
+	if o.Opts.IsMirrorToDisk() {
+		data, err := json.Marshal(saved)
+		if err != nil {
+			return nil, fmt.Errorf("samples: %w", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(archivedPath), 0o755); err != nil {
+			return nil, fmt.Errorf("samples: %w", err)
+		}
+		if err := os.WriteFile(archivedPath, data, 0o644); err != nil { //nolint:gosec // G306: no sensitive data
+			return nil, fmt.Errorf("samples: %w", err)
+		}
+	}
+
+	if len(images) == 0 {
+		return nil, nil
+	}
+	if o.Opts.IsDiskToMirror() {
+		return o.prepareD2MCopyBatch(images, "")
+	}
+	return o.prepareM2DCopyBatch(images, "")
+}
+
+// localSampleKey identifies a sample of mirror.samples read from local files
+// among the imagestreams saved in the working-dir.
+func localSampleKey(sample v2alpha1.SampleImage) string {
+	return sample.Path + "#" + sample.Name
+}
+
+// imageStreamImages returns the images the tags of an imagestream point to,
+// optionally limited to tags.
+func imageStreamImages(is imageStream, tags []string) []v2alpha1.RelatedImage {
+	var images []v2alpha1.RelatedImage
+	for _, tag := range is.Spec.Tags {
+		// tags of kind ImageStreamTag are aliases of another tag of the imagestream
+		if tag.From == nil || tag.From.Kind != "DockerImage" {
+			continue
+		}
+		if len(tags) > 0 && !slices.Contains(tags, tag.Name) {
+			continue
+		}
+		images = append(images, v2alpha1.RelatedImage{
+			Image: tag.From.Name,
+			Name:  is.Metadata.Name + ":" + tag.Name,
+			Type:  v2alpha1.TypeSampleImage,
+		})
+	}
+	return images
+}
+
+// loadImageStreams reads the imagestreams of path, a file or a directory.
+// When dirName is set, only the files of the directories named dirName are read.
+// Files which don't hold imagestreams (i.e templates) are ignored.
+func loadImageStreams(path, dirName string) ([]imageStream, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return parseImageStreams(path)
+	}
+
+	var streams []imageStream
+	err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (dirName != "" && filepath.Base(filepath.Dir(file)) != dirName) {
+			return nil
+		}
+		switch filepath.Ext(file) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return nil
+		}
+		fileStreams, err := parseImageStreams(file)
+		if err != nil {
+			return err
+		}
+		streams = append(streams, fileStreams...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return streams, nil
+}
+
+// parseImageStreams returns the imagestreams of a file holding an
+// imagestream or a list of imagestreams.
+func parseImageStreams(file string) ([]imageStream, error) {
+	is, err := parser.ParseYamlFile[imageStream](file)
+	if err != nil {
+		return nil, fmt.Errorf("imagestream %s: %w", file, err)
+	}
+	switch is.Kind {
+	case "ImageStream":
+		return []imageStream{is}, nil
+	case "List", "ImageStreamList":
+		var streams []imageStream
+		for _, item := range is.Items {
+			if item.Kind == "" || item.Kind == "ImageStream" {
+				streams = append(streams, item)
+			}
+		}
+		return streams, nil
+	}
+	return nil, nil
+}
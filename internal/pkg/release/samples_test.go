@@ -0,0 +1,229 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	clog "github.com/openshift/oc-mirror/v2/internal/pkg/log"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/mirror"
+)
+
+const rubyImageStream = `{
+  "kind": "ImageStream",
+  "apiVersion": "image.openshift.io/v1",
+  "metadata": {"name": "ruby"},
+  "spec": {
+    "tags": [
+      {"name": "latest", "from": {"kind": "ImageStreamTag", "name": "3.3-ubi9"}},
+      {"name": "3.3-ubi9", "from": {"kind": "DockerImage", "name": "registry.redhat.io/ubi9/ruby-33:latest"}},
+      {"name": "3.1-ubi8", "from": {"kind": "DockerImage", "name": "registry.redhat.io/ubi8/ruby-31@sha256:1c9a6e7f44ae2b6d9e2d4a2b1b7c4b7c9a1e3a39e8bb3e8fb4c2b7b8a7c6d5e4"}}
+    ]
+  }
+}`
+
+const pythonImageStreams = `apiVersion: v1
+kind: List
+items:
+  - kind: ImageStream
+    metadata:
+      name: python
+    spec:
+      tags:
+        - name: 3.12-ubi9
+          from:
+            kind: DockerImage
+            name: registry.redhat.io/ubi9/python-312:latest
+  - kind: ImageStream
+    metadata:
+      name: nodejs
+    spec:
+      tags:
+        - name: 20-ubi9
+          from:
+            kind: DockerImage
+            name: registry.redhat.io/ubi9/nodejs-20:latest
+`
+
+const rubyTemplate = `{"kind": "Template", "apiVersion": "template.openshift.io/v1", "metadata": {"name": "rails-postgresql-example"}}`
+
+// writeSamplesAssets lays out the assets of the cluster-samples-operator for arch in releaseArtifactsDir
+func writeSamplesAssets(t *testing.T, releaseArtifactsDir, arch string) {
+	t.Helper()
+	files := map[string]string{
+		"ruby/imagestreams/ruby-rhel.json":   rubyImageStream,
+		"ruby/templates/rails-postgres.json": rubyTemplate,
+		"python/imagestreams/python.yaml":    pythonImageStreams,
+	}
+	for name, content := range files {
+		path := filepath.Join(releaseArtifactsDir, samplesOperatorAssets, arch, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func newSamplesCollector(t *testing.T, mode string, samples []v2alpha1.SampleImage) *LocalStorageCollector {
+	t.Helper()
+	return &LocalStorageCollector{
+		Log:              clog.New("debug"),
+		Mirror:           MockMirror{},
+		Manifest:         MockManifest{},
+		LocalStorageFQDN: "localhost:9999",
+		Config: v2alpha1.ImageSetConfiguration{ImageSetConfigurationSpec: v2alpha1.ImageSetConfigurationSpec{
+			Mirror: v2alpha1.Mirror{Samples: samples},
+		}},
+		Opts: mirror.CopyOptions{
+			Mode:        mode,
+			Destination: "docker://myregistry/mynamespace",
+			Global:      &mirror.GlobalOptions{WorkingDir: t.TempDir()},
+		},
+	}
+}
+
+func TestGetSampleImages(t *testing.T) {
+	releaseArtifactsDir := t.TempDir()
+	writeSamplesAssets(t, releaseArtifactsDir, "x86_64")
+	writeSamplesAssets(t, releaseArtifactsDir, "aarch64")
+
+	t.Run("should return the images of the selected imagestreams and skip the others", func(t *testing.T) {
+		col := newSamplesCollector(t, mirror.MirrorToDisk, []v2alpha1.SampleImage{{Name: "ruby"}})
+		images, err := col.getSampleImages(releaseArtifactsDir)
+		require.NoError(t, err)
+		assert.Equal(t, []v2alpha1.RelatedImage{
+			{Name: "ruby:3.3-ubi9", Image: "registry.redhat.io/ubi9/ruby-33:latest", Type: v2alpha1.TypeSampleImage},
+			{Name: "ruby:3.1-ubi8", Image: "registry.redhat.io/ubi8/ruby-31@sha256:1c9a6e7f44ae2b6d9e2d4a2b1b7c4b7c9a1e3a39e8bb3e8fb4c2b7b8a7c6d5e4", Type: v2alpha1.TypeSampleImage},
+		}, images)
+		assert.ElementsMatch(t, []string{"python", "nodejs"}, col.SkippedImageStreams())
+	})
+
+	t.Run("should limit the images to the requested tags", func(t *testing.T) {
+		col := newSamplesCollector(t, mirror.MirrorToDisk, []v2alpha1.SampleImage{{Name: "python"}, {Name: "ruby", Tags: []string{"3.3-ubi9"}}})
+		images, err := col.getSampleImages(releaseArtifactsDir)
+		require.NoError(t, err)
+		refs := []string{}
+		for _, img := range images {
+			refs = append(refs, img.Image)
+		}
+		assert.ElementsMatch(t, []string{"registry.redhat.io/ubi9/ruby-33:latest", "registry.redhat.io/ubi9/python-312:latest"}, refs)
+		assert.Equal(t, []string{"nodejs"}, col.SkippedImageStreams())
+	})
+
+	t.Run("should read the imagestreams of all the requested architectures", func(t *testing.T) {
+		col := newSamplesCollector(t, mirror.MirrorToDisk, []v2alpha1.SampleImage{{Name: "nodejs"}})
+		col.Config.Mirror.Platform.Architectures = []string{"amd64", "arm64", "s390x"} //nolint:staticcheck // SA1019: testing the deprecated field
+		images, err := col.getSampleImages(releaseArtifactsDir)
+		require.NoError(t, err)
+		assert.Len(t, images, 2)
+	})
+
+	t.Run("should fail when an imagestream is not in the release", func(t *testing.T) {
+		col := newSamplesCollector(t, mirror.MirrorToDisk, []v2alpha1.SampleImage{{Name: "perl"}})
+		_, err := col.getSampleImages(releaseArtifactsDir)
+		assert.ErrorContains(t, err, `could not find imagestream "perl"`)
+	})
+}
+
+func TestExtractSamplesOperator(t *testing.T) {
+	releaseImages := []v2alpha1.RelatedImage{
+		{Name: "cluster-samples-operator", Image: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:955faaa822dc107f4dffa6a7e457f8d57a65d10949f74f6780ddd63c115e31e5", Type: v2alpha1.TypeOCPReleaseContent},
+	}
+
+	t.Run("should copy and extract the samples operator image", func(t *testing.T) {
+		col := newSamplesCollector(t, mirror.MirrorToDisk, []v2alpha1.SampleImage{{Name: "ruby"}})
+		require.NoError(t, col.extractSamplesOperator(t.Context(), t.TempDir(), releaseImages))
+	})
+
+	t.Run("should not copy the samples operator image when already extracted", func(t *testing.T) {
+		col := newSamplesCollector(t, mirror.MirrorToDisk, []v2alpha1.SampleImage{{Name: "ruby"}})
+		col.Mirror = MockMirror{Fail: true}
+		releaseArtifactsDir := t.TempDir()
+		writeSamplesAssets(t, releaseArtifactsDir, "x86_64")
+		require.NoError(t, col.extractSamplesOperator(t.Context(), releaseArtifactsDir, releaseImages))
+	})
+
+	t.Run("should fail when the release has no samples operator", func(t *testing.T) {
+		col := newSamplesCollector(t, mirror.MirrorToDisk, []v2alpha1.SampleImage{{Name: "ruby"}})
+		err := col.extractSamplesOperator(t.Context(), t.TempDir(), releaseImages[1:])
+		assert.ErrorContains(t, err, "could not find cluster-samples-operator in this release")
+	})
+}
+
+func TestCollectLocalSampleImages(t *testing.T) {
+	samplesDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(samplesDir, "ruby.json"), []byte(rubyImageStream), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(samplesDir, "python.yaml"), []byte(pythonImageStreams), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(samplesDir, "template.json"), []byte(rubyTemplate), 0o600))
+
+	t.Run("should prepare the copies of a local imagestream to the cache", func(t *testing.T) {
+		col := newSamplesCollector(t, mirror.MirrorToDisk, []v2alpha1.SampleImage{{Path: filepath.Join(samplesDir, "ruby.json"), Tags: []string{"3.3-ubi9", "3.1-ubi8"}}})
+		images, err := col.collectLocalSampleImages()
+		require.NoError(t, err)
+		assert.Equal(t, []v2alpha1.CopyImageSchema{
+			{
+				Origin:      "registry.redhat.io/ubi9/ruby-33:latest",
+				Source:      "docker://registry.redhat.io/ubi9/ruby-33:latest",
+				Destination: "docker://localhost:9999/ubi9/ruby-33:latest",
+				Type:        v2alpha1.TypeSampleImage,
+			},
+			{
+				Origin:      "registry.redhat.io/ubi8/ruby-31@sha256:1c9a6e7f44ae2b6d9e2d4a2b1b7c4b7c9a1e3a39e8bb3e8fb4c2b7b8a7c6d5e4",
+				Source:      "docker://registry.redhat.io/ubi8/ruby-31@sha256:1c9a6e7f44ae2b6d9e2d4a2b1b7c4b7c9a1e3a39e8bb3e8fb4c2b7b8a7c6d5e4",
+				Destination: "docker://localhost:9999/ubi8/ruby-31:sha256-1c9a6e7f44ae2b6d9e2d4a2b1b7c4b7c9a1e3a39e8bb3e8fb4c2b7b8a7c6d5e4",
+				Type:        v2alpha1.TypeSampleImage,
+			},
+		}, images)
+	})
+
+	t.Run("should prepare the copies of the imagestreams of a directory from the cache", func(t *testing.T) {
+		connectedDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(connectedDir, "python.yaml"), []byte(pythonImageStreams), 0o600))
+		samples := []v2alpha1.SampleImage{{Path: connectedDir, Name: "nodejs"}}
+		m2d := newSamplesCollector(t, mirror.MirrorToDisk, samples)
+		_, err := m2d.collectLocalSampleImages()
+		require.NoError(t, err)
+		// the local files are only on the connected host, the imagestreams are read from the archive
+		require.NoError(t, os.RemoveAll(connectedDir))
+
+		col := newSamplesCollector(t, mirror.DiskToMirror, samples)
+		col.Opts.Global.WorkingDir = m2d.Opts.Global.WorkingDir
+		images, err := col.collectLocalSampleImages()
+		require.NoError(t, err)
+		assert.Equal(t, []v2alpha1.CopyImageSchema{
+			{
+				Origin:      "registry.redhat.io/ubi9/nodejs-20:latest",
+				Source:      "docker://localhost:9999/ubi9/nodejs-20:latest",
+				Destination: "docker://myregistry/mynamespace/ubi9/nodejs-20:latest",
+				Type:        v2alpha1.TypeSampleImage,
+			},
+		}, images)
+	})
+
+	t.Run("should fail when the imagestreams of the local files are not in the archive", func(t *testing.T) {
+		col := newSamplesCollector(t, mirror.DiskToMirror, []v2alpha1.SampleImage{{Path: samplesDir, Name: "nodejs"}})
+		_, err := col.collectLocalSampleImages()
+		assert.ErrorContains(t, err, "the imagestreams of the local files are not in the archive")
+
+		m2d := newSamplesCollector(t, mirror.MirrorToDisk, []v2alpha1.SampleImage{{Path: samplesDir, Name: "ruby"}})
+		_, err = m2d.collectLocalSampleImages()
+		require.NoError(t, err)
+		col.Opts.Global.WorkingDir = m2d.Opts.Global.WorkingDir
+		_, err = col.collectLocalSampleImages()
+		assert.ErrorContains(t, err, "the imagestreams of "+samplesDir+" are not in the archive")
+	})
+
+	t.Run("should fail when the imagestream is not in the local files", func(t *testing.T) {
+		col := newSamplesCollector(t, mirror.MirrorToDisk, []v2alpha1.SampleImage{{Path: samplesDir, Name: "perl"}})
+		_, err := col.collectLocalSampleImages()
+		assert.ErrorContains(t, err, `could not find imagestream "perl"`)
+	})
+
+	t.Run("should fail when the local path does not exist", func(t *testing.T) {
+		col := newSamplesCollector(t, mirror.MirrorToDisk, []v2alpha1.SampleImage{{Path: filepath.Join(samplesDir, "missing")}})
+		_, err := col.collectLocalSampleImages()
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,144 @@
+package rewrite
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/consts"
+	"github.com/openshift/oc-mirror/v2/internal/pkg/image"
+)
+
+// Rewriter applies the destination rewrite rules of an ImageSetConfiguration.
+type Rewriter struct {
+	rules []rule
+}
+
+type rule struct {
+	v2alpha1.DestinationRewrite
+	match *regexp.Regexp
+}
+
+// New compiles the destination rewrite rules.
+func New(rules []v2alpha1.DestinationRewrite) (*Rewriter, error) {
+	r := &Rewriter{}
+	for i, rewrite := range rules {
+		compiled := rule{DestinationRewrite: rewrite}
+		if rewrite.Match != "" {
+			re, err := regexp.Compile("^(?:" + rewrite.Match + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("destination rewrite %d: invalid match %q: %w", i, rewrite.Match, err)
+			}
+			compiled.match = re
+		}
+		r.rules = append(r.rules, compiled)
+	}
+	return r, nil
+}
+
+// ContentType returns the content type the rules select an image type with,
+// false for the image types which are never rewritten.
+func ContentType(imgType v2alpha1.ImageType) (v2alpha1.RewriteContentType, bool) {
+	switch {
+	case imgType.IsOperator():
+		return v2alpha1.RewriteOperator, true
+	case imgType.IsArtifact():
+		return v2alpha1.RewriteArtifact, true
+	case imgType.IsAdditionalImage():
+		return v2alpha1.RewriteAdditionalImage, true
+	case imgType.IsHelmImage():
+		return v2alpha1.RewriteHelm, true
+	}
+	return "", false
+}
+
+// Destinations rewrites the repository of the destinations of the images mirrored under
+// destination, the registry (and optional namespace) of the mirror.
+// The images copied elsewhere, such as the local cache, are left untouched.
+func (r *Rewriter) Destinations(images []v2alpha1.CopyImageSchema, destination string) ([]v2alpha1.CopyImageSchema, error) {
+	if len(r.rules) == 0 {
+		return images, nil
+	}
+	destination = strings.TrimSuffix(destination, "/")
+	out := make([]v2alpha1.CopyImageSchema, 0, len(images))
+	for _, img := range images {
+		if strings.HasPrefix(img.Destination, destination+"/") {
+			dest, err := r.destination(img, destination)
+			if err != nil {
+				return nil, err
+			}
+			img.Destination = dest
+		}
+		out = append(out, img)
+	}
+	return out, nil
+}
+
+func (r *Rewriter) destination(img v2alpha1.CopyImageSchema, destination string) (string, error) {
+	repo, ok, err := r.Repository(img.Origin, img.Type)
+	if err != nil || !ok {
+		return img.Destination, err
+	}
+	destSpec, err := image.ParseRef(img.Destination)
+	if err != nil {
+		return "", err
+	}
+	// the tag or digest of the destination
+	ref := strings.TrimPrefix(img.Destination, destSpec.Transport+destSpec.Name)
+	return destination + "/" + repo + ref, nil
+}
+
+// Repository returns the repository path, relative to the destination, the first rule selecting
+// the origin of an image rewrites it to. It returns false when no rule selects the image.
+func (r *Rewriter) Repository(origin string, imgType v2alpha1.ImageType) (string, bool, error) {
+	contentType, ok := ContentType(imgType)
+	if !ok {
+		return "", false, nil
+	}
+	spec, err := image.ParseRef(origin)
+	if err != nil {
+		return "", false, err
+	}
+	if spec.Transport != consts.DockerProtocol || spec.Domain == "" {
+		// only the images pulled from registries have a registry and a namespace to select
+		return "", false, nil
+	}
+	namespace, name := path.Split(spec.PathComponent)
+	namespace = strings.TrimSuffix(namespace, "/")
+	source := spec.Domain + "/" + spec.PathComponent
+
+	for i, rl := range r.rules {
+		if rl.Registry != "" && rl.Registry != spec.Domain {
+			continue
+		}
+		if rl.Namespace != "" && namespace != rl.Namespace && !strings.HasPrefix(namespace, rl.Namespace+"/") {
+			continue
+		}
+		if len(rl.ContentTypes) > 0 && !slices.Contains(rl.ContentTypes, contentType) {
+			continue
+		}
+		target := rl.Target
+		if rl.match != nil {
+			submatches := rl.match.FindStringSubmatchIndex(source)
+			if submatches == nil {
+				continue
+			}
+			target = string(rl.match.ExpandString(nil, target, source, submatches))
+		}
+		target = strings.NewReplacer(
+			"{registry}", spec.Domain,
+			"{namespace}", namespace,
+			"{name}", name,
+			"{repository}", spec.PathComponent,
+		).Replace(target)
+		target = strings.Trim(target, "/")
+		if !v2alpha1.IsValidPathComponent(target) {
+			return "", false, fmt.Errorf("destination rewrite %d: %s is rewritten to the invalid repository %q", i, source, target)
+		}
+		return target, true, nil
+	}
+	return "", false, nil
+}
@@ -0,0 +1,136 @@
+package rewrite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/oc-mirror/v2/internal/pkg/api/v2alpha1"
+)
+
+func TestRepository(t *testing.T) {
+	rules := []v2alpha1.DestinationRewrite{
+		{Registry: "registry.redhat.io", ContentTypes: []v2alpha1.RewriteContentType{v2alpha1.RewriteOperator}, Target: "redhat-operators/{namespace}-{name}"},
+		{Match: `registry\.redhat\.io/(?P<ns>[^/]+)/(.+)`, Target: "redhat/${ns}-$2"},
+		{Registry: "quay.io", Namespace: "ourorg", Target: "virtual-quay/{repository}"},
+		{Registry: "ghcr.io", Target: "{registry}/{name}"},
+		{Registry: "docker.io", Target: "Docker/{name}"},
+	}
+	r, err := New(rules)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		origin   string
+		imgType  v2alpha1.ImageType
+		expected string
+		err      string
+	}{
+		{
+			name:     "template selected by registry and content type",
+			origin:   "registry.redhat.io/rhbk/keycloak-rhel9@sha256:c4b775cbe8eec55de2c163919c6008599e2aebe789ed93ada9a307e800e3f1e2",
+			imgType:  v2alpha1.TypeOperatorRelatedImage,
+			expected: "redhat-operators/rhbk-keycloak-rhel9",
+		},
+		{
+			name:     "the first matching rule applies",
+			origin:   "docker://registry.redhat.io/ubi9/ruby-33:latest",
+			imgType:  v2alpha1.TypeGeneric,
+			expected: "redhat/ubi9-ruby-33",
+		},
+		{
+			name:     "nested namespaces are selected",
+			origin:   "quay.io/ourorg/team/web:1.0",
+			imgType:  v2alpha1.TypeHelmImage,
+			expected: "virtual-quay/ourorg/team/web",
+		},
+		{
+			name:     "registry placeholder",
+			origin:   "ghcr.io/ourorg/filters/ratelimit:1.0",
+			imgType:  v2alpha1.TypeArtifact,
+			expected: "ghcr.io/ratelimit",
+		},
+		{
+			name:    "other namespace",
+			origin:  "quay.io/otherorg/web:1.0",
+			imgType: v2alpha1.TypeGeneric,
+		},
+		{
+			name:    "release images are never rewritten",
+			origin:  "quay.io/ourorg/release:4.18.1-x86_64",
+			imgType: v2alpha1.TypeOCPRelease,
+		},
+		{
+			name:    "images of OCI layouts are never rewritten",
+			origin:  "oci:///images/ourorg/web",
+			imgType: v2alpha1.TypeGeneric,
+		},
+		{
+			name:    "invalid rewritten repository",
+			origin:  "docker.io/library/nginx:latest",
+			imgType: v2alpha1.TypeGeneric,
+			err:     `destination rewrite 4: docker.io/library/nginx is rewritten to the invalid repository "Docker/nginx"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, ok, err := r.Repository(tt.origin, tt.imgType)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected != "", ok)
+			assert.Equal(t, tt.expected, repo)
+		})
+	}
+}
+
+func TestDestinations(t *testing.T) {
+	r, err := New([]v2alpha1.DestinationRewrite{{Registry: "registry.redhat.io", Target: "redhat/{namespace}-{name}"}})
+	require.NoError(t, err)
+
+	images := []v2alpha1.CopyImageSchema{
+		{
+			Source:      "docker://localhost:55000/ubi9/ruby-33:latest",
+			Destination: "docker://mirror.acme.com/ocp/ubi9/ruby-33:latest",
+			Origin:      "registry.redhat.io/ubi9/ruby-33:latest",
+			Type:        v2alpha1.TypeGeneric,
+		},
+		{
+			Source:      "docker://localhost:55000/rhbk/keycloak-rhel9:sha256-c4b775cbe8eec55de2c163919c6008599e2aebe789ed93ada9a307e800e3f1e2",
+			Destination: "docker://mirror.acme.com/ocp/rhbk/keycloak-rhel9@sha256:c4b775cbe8eec55de2c163919c6008599e2aebe789ed93ada9a307e800e3f1e2",
+			Origin:      "docker://registry.redhat.io/rhbk/keycloak-rhel9@sha256:c4b775cbe8eec55de2c163919c6008599e2aebe789ed93ada9a307e800e3f1e2",
+			Type:        v2alpha1.TypeOperatorRelatedImage,
+		},
+		{
+			Source:      "docker://registry.redhat.io/ubi9/ubi:latest",
+			Destination: "docker://localhost:55000/ubi9/ubi:latest",
+			Origin:      "registry.redhat.io/ubi9/ubi:latest",
+			Type:        v2alpha1.TypeGeneric,
+		},
+		{
+			Source:      "docker://localhost:55000/openshift/release:4.18.1-x86_64",
+			Destination: "docker://mirror.acme.com/ocp/openshift/release:4.18.1-x86_64",
+			Origin:      "quay.io/openshift-release-dev/ocp-release:4.18.1-x86_64",
+			Type:        v2alpha1.TypeOCPRelease,
+		},
+	}
+
+	res, err := r.Destinations(images, "docker://mirror.acme.com/ocp/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"docker://mirror.acme.com/ocp/redhat/ubi9-ruby-33:latest",
+		"docker://mirror.acme.com/ocp/redhat/rhbk-keycloak-rhel9@sha256:c4b775cbe8eec55de2c163919c6008599e2aebe789ed93ada9a307e800e3f1e2",
+		// copied to the cache
+		"docker://localhost:55000/ubi9/ubi:latest",
+		"docker://mirror.acme.com/ocp/openshift/release:4.18.1-x86_64",
+	}, []string{res[0].Destination, res[1].Destination, res[2].Destination, res[3].Destination})
+	assert.Equal(t, images[0].Source, res[0].Source)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New([]v2alpha1.DestinationRewrite{{Match: "registry.redhat.io/(.*", Target: "redhat/$1"}})
+	assert.ErrorContains(t, err, `destination rewrite 0: invalid match "registry.redhat.io/(.*"`)
+}